@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// aggregateFuncs 汇总支持的聚合方式
+var aggregateFuncs = map[string]string{
+	"sum":           "SUM(%s)",
+	"count":         "COUNT(%s)",
+	"avg":           "AVG(%s)",
+	"min":           "MIN(%s)",
+	"max":           "MAX(%s)",
+	"countDistinct": "COUNT(DISTINCT %s)",
+}
+
+// measure 一个汇总指标：对某列做某种聚合，label 为输出列名
+type measure struct {
+	column string
+	agg    string
+	label  string
+}
+
+// expr 指标的 SQL 表达式；count 不指定列时统计行数
+func (m measure) expr() string {
+	if m.column == "" {
+		return "COUNT(*)"
+	}
+	return fmt.Sprintf(aggregateFuncs[m.agg], quoteIdent(m.column))
+}
+
+// parseMeasures 解析前端传来的 [{column, agg, label}]，label 为空时命名为 <agg>_<列名>（不指定列的 count 为 count）
+func parseMeasures(specs []map[string]interface{}, columns []string) ([]measure, error) {
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("请至少指定一个汇总指标")
+	}
+	measures := make([]measure, 0, len(specs))
+	used := make(map[string]bool)
+	for _, spec := range specs {
+		var m measure
+		m.column, _ = spec["column"].(string)
+		m.agg, _ = spec["agg"].(string)
+		m.label, _ = spec["label"].(string)
+		m.column = strings.TrimSpace(m.column)
+		if m.column == "*" {
+			m.column = ""
+		}
+		if m.agg == "" {
+			m.agg = "sum"
+		}
+		if _, ok := aggregateFuncs[m.agg]; !ok {
+			return nil, fmt.Errorf("不支持的聚合方式: %s（可选 sum、count、avg、min、max、countDistinct）", m.agg)
+		}
+		if m.column == "" && m.agg != "count" {
+			return nil, fmt.Errorf("%s 需要指定列", m.agg)
+		}
+		if m.column != "" && !containsString(columns, m.column) {
+			return nil, fmt.Errorf("不存在列 %s", m.column)
+		}
+		if m.label == "" {
+			if m.column == "" {
+				m.label = m.agg
+			} else {
+				m.label = m.agg + "_" + m.column
+			}
+		}
+		if used[m.label] {
+			return nil, fmt.Errorf("汇总指标名称重复: %s", m.label)
+		}
+		used[m.label] = true
+		measures = append(measures, m)
+	}
+	return measures, nil
+}
+
+// conditionalExpr 只统计满足条件的行的指标表达式，用于交叉表按列取值拆分
+func (m measure) conditionalExpr(cond string) string {
+	if m.column == "" {
+		return fmt.Sprintf("COUNT(CASE WHEN %s THEN 1 END)", cond)
+	}
+	return fmt.Sprintf(aggregateFuncs[m.agg], fmt.Sprintf("CASE WHEN %s THEN %s END", cond, quoteIdent(m.column)))
+}
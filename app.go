@@ -3,81 +3,178 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
-	_ "github.com/mattn/go-sqlite3"
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 	"github.com/xuri/excelize/v2"
 )
 
 // App 核心结构体（移除 fullResult 缓存）
 type App struct {
-	ctx             context.Context
-	db              *sql.DB
-	currentPage     int    // 当前页码
-	currentPageSize int    // 当前页大小
-	currentSQL      string // 保存当前执行的 SQL（用于分页）
+	ctx            context.Context
+	handles        atomic.Pointer[dbHandles] // 数据库连接、只读状态和打开的分享包，切换时整体替换（见 dbconn.go）
+	switchMu       sync.Mutex                // 串行化连接切换（重连、打开和关闭只读分享包）
+	session        *querySession             // 当前查询的 SQL 和分页状态（见 session.go）
+	cache          *resultCache              // 查询结果缓存（翻页、切换标签时复用）
+	stats          *statsCache               // 按表数据版本缓存的列统计（见 colstats.go）
+	exports        *exportJobs               // 后台导出任务
+	includeDeleted atomic.Bool               // 按表名查询时是否包含软删除的行
+	instanceLock   *sql.Conn                 // 单写者锁（见 instance.go）
+	background     atomic.Bool               // 窗口是否在后台（由前端通过 SetWindowBackground 报告）
 }
 
 // NewApp 创建 App 实例（完善数据库初始化）
 func NewApp() *App {
-	// 初始化 SQLite 数据库
-	db, err := sql.Open("sqlite3", "./data.db")
+	// 同一个 data.db 只允许一个实例写入，后启动的实例以只读方式打开
+	lock, err := acquireInstanceLock()
+	readOnly := err == errInstanceLocked
+	if readOnly {
+		fmt.Println(readOnlyMessage)
+	} else if err != nil {
+		fmt.Println(err)
+	}
+
+	app := &App{
+		session:      newQuerySession(20),
+		cache:        newResultCache(),
+		exports:      newExportJobs(),
+		instanceLock: lock,
+	}
+	app.stats = newStatsCache(app.statsVersion)
+	app.handles.Store(&dbHandles{readOnly: readOnly})
+	db, err := openDatabase(readOnly)
+	if err != nil {
+		// 连接失败时 db 保持为 nil，由健康检查定时重连（见 health.go）
+		fmt.Println(err)
+		return app
+	}
+	if _, err := app.attachDB(db); err != nil {
+		fmt.Println(err)
+		db.Close()
+		return app
+	}
+	app.session.setPageSize(app.settingInt("page_size"))
+	if !readOnly {
+		app.markInterruptedImports()
+	}
+	return app
+}
+
+// openDatabase 打开并验证 SQLite 数据库（readOnly 时以只读方式打开），可写时创建缺失的元数据表
+func openDatabase(readOnly bool) (*sql.DB, error) {
+	// 并发导入和查询时等待锁释放，而不是立即返回 database is locked
+	// WAL 模式下读连接不会被写事务阻塞（见 readpool.go）；只读实例沿用写入实例设置的日志模式
+	dsn := fmt.Sprintf("file:./data.db?_busy_timeout=%d", sqliteBusyTimeoutMs)
+	if readOnly {
+		dsn += "&mode=ro"
+	} else {
+		dsn += "&_journal_mode=WAL"
+	}
+	db, err := sql.Open(sqliteDriverName, dsn)
 	if err != nil {
 		fmt.Printf("数据库连接失败: %v\n", err)
 		// 创建数据库目录（避免路径不存在）
 		os.MkdirAll(filepath.Dir("./data.db"), 0755)
-		db, err = sql.Open("sqlite3", "./data.db")
+		db, err = sql.Open(sqliteDriverName, dsn)
 		if err != nil {
-			fmt.Printf("数据库重试连接失败: %v\n", err)
-			return &App{db: nil}
+			return nil, fmt.Errorf("数据库重试连接失败: %v", err)
 		}
 	}
 
+	// 写入串行化：写连接池只有一个连接，并发的导入和修改在连接池中排队，而不是在 SQLite 层按 busy_timeout 争锁；
+	// 查询和元数据读取走只读连接池，不占用写连接（持有写连接时不要再通过 writeDB 读写，否则会互相等待）
+	if !readOnly {
+		db.SetMaxOpenConns(1)
+	}
+
 	// 验证数据库连接
 	if err := db.Ping(); err != nil {
-		fmt.Printf("数据库 Ping 失败: %v\n", err)
-		return &App{db: nil}
+		db.Close()
+		return nil, fmt.Errorf("数据库 Ping 失败: %v", err)
 	}
 
-	return &App{
-		db:              db,
-		currentPage:     1,
-		currentPageSize: 20,
-		currentSQL:      "",
+	// 创建应用元数据表（只读实例使用持有写锁的实例已创建的表）
+	if !readOnly {
+		if err := initMetaTables(db); err != nil {
+			db.Close()
+			return nil, err
+		}
 	}
+	return db, nil
+}
+
+// attachDB 使用新打开的数据库连接，并同步设置、汇率等依赖数据库的运行时状态，返回被替换的连接。
+// 只读连接池打不开时不切换：写连接池只有一个连接，持有写事务时读取设置等操作会等待自己而卡死
+func (a *App) attachDB(db *sql.DB) (*dbHandles, error) {
+	readOnly := a.readOnly()
+	reader, err := openReadPool(readOnly)
+	if err != nil {
+		return nil, err
+	}
+	old := a.swapHandles(&dbHandles{db: db, reader: reader, readOnly: readOnly})
+	a.applySettings()
+	if err := a.loadExchangeRates(); err != nil {
+		fmt.Println(err)
+	}
+	if err := checkJSONSupport(db); err != nil {
+		fmt.Println(err)
+	}
+	return old, nil
 }
 
 // Startup 应用启动时执行
 func (a *App) Startup(ctx context.Context) {
 	a.ctx = ctx
+	go watchDataChanges(ctx)
+	go a.watchExchangeRates(ctx)
+	go a.watchSchemaChanges(ctx)
+	go a.watchHealth(ctx)
+	go a.checkStaleTablesOnStartup(ctx)
 }
 
 // OpenExcel 导入 Excel 文件（原有逻辑保留）
+// 返回 {status, message}：status 为 success、cancelled（未选择文件）或 error，error 时 code 说明失败原因（见 status.go）
 // wails:export OpenExcel
-func (a *App) OpenExcel() string {
-	if a.db == nil {
-		return "错误：数据库连接未初始化，请重启应用！"
+func (a *App) OpenExcel() map[string]interface{} {
+	if a.writeDB() == nil {
+		return errorResult(codeDBNotReady, "错误：数据库连接未初始化，请重启应用！")
 	}
 
 	filePath, err := runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
 		Title:                "选择 Excel 文件",
-		Filters:              []runtime.FileFilter{{Pattern: "*.xlsx;*.xls", DisplayName: "Excel 文件"}},
+		Filters:              []runtime.FileFilter{{Pattern: "*.xlsx;*.xls;*.xlsb", DisplayName: "Excel 文件"}},
 		CanCreateDirectories: false,
 	})
 	if err != nil {
-		return fmt.Sprintf("文件选择失败: %v", err)
+		return errorResult(codeDialogFailed, fmt.Sprintf("文件选择失败: %v", err))
 	}
 	if filePath == "" {
-		return "未选择文件"
+		return cancelledResult("未选择文件")
+	}
+
+	message, err := a.importWorkbook(filePath)
+	if err != nil {
+		return errorResult(codeImportFailed, err.Error())
+	}
+	return successResult(message)
+}
+
+// importWorkbook 将工作簿的每个 Sheet 导入为 sheet1..N 表
+func (a *App) importWorkbook(filePath string) (string, error) {
+	if strings.EqualFold(filepath.Ext(filePath), ".xlsb") {
+		return a.importXLSB(filePath)
 	}
 
 	f, err := excelize.OpenFile(filePath)
 	if err != nil {
-		return fmt.Sprintf("Excel 解析失败: %v", err)
+		return "", fmt.Errorf("Excel 解析失败: %v", err)
 	}
 	defer f.Close()
 
@@ -87,87 +184,53 @@ func (a *App) OpenExcel() string {
 		tableName := fmt.Sprintf("sheet%d", sheetIdx+1)
 		rows, err := f.GetRows(sheetName)
 		if err != nil {
-			return fmt.Sprintf("读取 Sheet %s 失败: %v", sheetName, err)
+			return "", fmt.Errorf("读取 Sheet %s 失败: %v", sheetName, err)
 		}
 		if len(rows) == 0 {
 			continue
 		}
 
-		// 删除旧表
-		_, err = a.db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", tableName))
-		if err != nil {
-			return fmt.Sprintf("删除表 %s 失败: %v", tableName, err)
-		}
-
-		// 创建新表
-		colCount := len(rows[0])
-		columns := make([]string, colCount)
-		for i := 0; i < colCount; i++ {
-			columns[i] = fmt.Sprintf("column%d", i+1)
+		// 创建新表（列名为 column1..N，首行作为表头不入库；含超链接的列追加 _url 伴随列，按设置追加来源列）
+		columns := defaultColumnNames(len(rows[0]))
+		dataRows := rows[1:]
+		if a.setting("import_hyperlinks") == "true" {
+			columns, dataRows = appendHyperlinkColumns(f, filePath, sheetName, columns, rows)
 		}
-
-		createSQL := fmt.Sprintf(
-			"CREATE TABLE %s (%s)",
-			tableName,
-			strings.Join(columns, " TEXT, ")+" TEXT",
-		)
-		_, err = a.db.Exec(createSQL)
-		if err != nil {
-			return fmt.Sprintf("创建表 %s 失败: %v", tableName, err)
+		if a.importSourceColumns() {
+			columns, dataRows = appendSourceColumns(columns, dataRows, filePath, sheetName, 2)
 		}
-
-		// 批量插入数据
-		tx, err := a.db.Begin()
-		if err != nil {
-			return fmt.Sprintf("开启事务失败: %v", err)
+		if err := a.writeTable(tableName, columns, dataRows); err != nil {
+			return "", err
 		}
-
-		insertSQL := fmt.Sprintf(
-			"INSERT INTO %s (%s) VALUES (%s)",
-			tableName,
-			strings.Join(columns, ", "),
-			strings.Repeat("?,", colCount)[:len(strings.Repeat("?,", colCount))-1],
-		)
-		stmt, err := tx.Prepare(insertSQL)
-		if err != nil {
-			tx.Rollback()
-			return fmt.Sprintf("预编译插入语句失败: %v", err)
-		}
-		defer stmt.Close()
-
-		for rowIdx := 1; rowIdx < len(rows); rowIdx++ {
-			row := rows[rowIdx]
-			for len(row) < colCount {
-				row = append(row, "")
-			}
-
-			values := make([]interface{}, colCount)
-			for i := 0; i < colCount; i++ {
-				values[i] = row[i]
-			}
-
-			_, err := stmt.Exec(values...)
-			if err != nil {
-				tx.Rollback()
-				return fmt.Sprintf("插入第 %d 行数据失败: %v", rowIdx, err)
-			}
-		}
-
-		if err := tx.Commit(); err != nil {
-			return fmt.Sprintf("提交事务失败: %v", err)
+		a.recordTableSource(tableName, filePath+"/"+sheetName)
+		a.recordTableHeader(tableName, positionalHeader(rows[0]))
+		if err := a.importCellComments(f, sheetName, tableName, columns); err != nil {
+			return "", err
 		}
 		successCount++
 	}
 
-	return fmt.Sprintf("成功导入 %d 个 Sheet 到数据库（共 %d 个 Sheet）", successCount, len(sheets))
+	return fmt.Sprintf("成功导入 %d 个 Sheet 到数据库（共 %d 个 Sheet）", successCount, len(sheets)), nil
 }
 
 // ExecuteSQLWithPage 执行分页 SQL 查询（保留分页功能）
+// 修改类语句不分页：普通语句返回影响行数，带 RETURNING 的语句把返回的行作为结果集
+// 过长的文本单元格会被截断，truncatedCells 列出被截断的单元格，完整内容用 GetCellValue 读取
+// pageSize <= 0 时取 page_size 设置；每次执行第一页或修改类语句时记录耗时、行数和查询计划（见 history.go）
 // wails:export ExecuteSQLWithPage
 func (a *App) ExecuteSQLWithPage(sqlStr string, pageNum int, pageSize int) map[string]interface{} {
+	start := time.Now()
+	result := a.executeSQLWithPage(sqlStr, pageNum, pageSize)
+	if pageNum <= 1 || result["statementType"] != nil {
+		a.recordQueryHistory(sqlStr, time.Since(start), result)
+	}
+	return result
+}
+
+func (a *App) executeSQLWithPage(sqlStr string, pageNum int, pageSize int) map[string]interface{} {
 	result := make(map[string]interface{})
 
-	if a.db == nil {
+	if a.writeDB() == nil {
 		result["error"] = "错误：数据库连接未初始化，请重启应用！"
 		return result
 	}
@@ -178,59 +241,44 @@ func (a *App) ExecuteSQLWithPage(sqlStr string, pageNum int, pageSize int) map[s
 		return result
 	}
 
-	// 保存当前执行的 SQL（用于分页跳转）
-	a.currentSQL = sqlStr
-	a.currentPage = pageNum
-	a.currentPageSize = pageSize
-
-	// 执行原始 SQL 获取全量数据（用于计算总数和内存分页）
-	fullRows, err := a.db.Query(sqlStr)
-	if err != nil {
-		result["error"] = fmt.Sprintf("SQL 执行失败: %v", err)
-		return result
+	if pageSize <= 0 {
+		pageSize = a.settingInt("page_size")
 	}
-	defer fullRows.Close()
 
-	// 获取列名
-	columns, err := fullRows.Columns()
-	if err != nil {
-		result["error"] = fmt.Sprintf("获取列名失败: %v", err)
-		return result
+	// 去掉末尾分号和注释，识别语句类型
+	stmt := parseStatement(sqlStr)
+	if !stmt.multiple {
+		sqlStr = stmt.text
 	}
 
-	// 解析全量数据
-	var fullData []map[string]interface{}
-	values := make([]interface{}, len(columns))
-	valuePtrs := make([]interface{}, len(columns))
-	for i := range values {
-		valuePtrs[i] = &values[i]
+	// 修改类语句通过 Exec 在事务中执行，返回影响行数（不作为当前 SQL 保存，避免翻页时重复执行）
+	if stmt.isWrite() {
+		if stmt.returning {
+			return a.executeReturning(stmt, sqlStr)
+		}
+		return a.executeWrite(stmt, sqlStr)
 	}
 
-	for fullRows.Next() {
-		err := fullRows.Scan(valuePtrs...)
-		if err != nil {
-			result["error"] = fmt.Sprintf("读取数据失败: %v", err)
-			return result
-		}
+	// 保存当前执行的 SQL（用于分页跳转）
+	a.session.set(sqlStr, pageNum, pageSize)
 
-		row := make(map[string]interface{})
-		for i, col := range columns {
-			val := values[i]
-			if b, ok := val.([]byte); ok {
-				row[col] = string(b)
-			} else if val == nil {
-				row[col] = ""
-			} else {
-				row[col] = val
-			}
-		}
-		fullData = append(fullData, row)
+	// 直接引用的表隐藏软删除的行（见 softdelete.go）
+	if live := a.liveSQL(sqlStr); live != sqlStr {
+		sqlStr, stmt.text = live, live
+	}
+
+	// 关闭结果缓存时，查询语句由数据库分页，避免每次翻页读取全量数据
+	if a.setting("query_cache") != "true" && stmt.wrappable() == nil && pageNum > 0 && pageSize > 0 {
+		return a.executePagedQuery(stmt, pageNum, pageSize)
 	}
 
-	if err = fullRows.Err(); err != nil {
-		result["error"] = fmt.Sprintf("遍历数据失败: %v", err)
+	// 执行原始 SQL 获取全量数据（用于计算总数和内存分页），数据未变化时直接使用缓存
+	entry, err := a.queryFullResult(sqlStr)
+	if err != nil {
+		result["error"] = err.Error()
 		return result
 	}
+	columns, fullData := entry.columns, entry.data
 
 	// 计算分页参数
 	total := len(fullData)
@@ -249,124 +297,381 @@ func (a *App) ExecuteSQLWithPage(sqlStr string, pageNum int, pageSize int) map[s
 
 	// 返回分页结果
 	result["columns"] = columns
+	result["columnTypes"] = entry.columnTypes
+	pageData, truncated := truncateLongCells(pageData, columns, a.settingInt("cell_display_length"))
 	result["data"] = pageData
+	if len(truncated) > 0 {
+		result["truncatedCells"] = truncated
+	}
 	result["total"] = total
 	result["totalPages"] = totalPages
 	result["currentPage"] = pageNum
 	result["pageSize"] = pageSize
 	result["message"] = fmt.Sprintf("查询到 %d 条记录，当前第 %d 页（共 %d 页）", total, pageNum, totalPages)
+	if len(entry.warnings) > 0 {
+		result["warnings"] = entry.warnings
+	}
 	return result
 }
 
 // ExportExcelBySQL 根据 SQL 实时查询并导出 Excel（核心重构）
+// 选择保存路径后在后台使用独立连接执行导出，立即返回任务 ID；完成后发送 export-finished 事件，也可用 GetExportJob 查询
+// 返回的 status 为 success（已开始导出，含 jobId）、cancelled（未选择保存路径）或 error（code 说明原因，见 status.go）；
+// 导出本身失败时任务状态为 failed，code 为 export_failed
 // wails:export ExportExcelBySQL
-func (a *App) ExportExcelBySQL(sqlStr string) string {
+func (a *App) ExportExcelBySQL(sqlStr string) map[string]interface{} {
 	// 1. 前置检查
-	if a.db == nil {
-		return "错误：数据库连接未初始化，请重启应用！"
+	if a.writeDB() == nil {
+		return errorResult(codeDBNotReady, "错误：数据库连接未初始化，请重启应用！")
 	}
 
 	sqlStr = strings.TrimSpace(sqlStr)
 	if sqlStr == "" {
-		return "错误：SQL 语句不能为空！"
+		return errorResult(codeInvalidInput, "错误：SQL 语句不能为空！")
 	}
 
-	// 2. 实时执行 SQL 获取全量数据（无分页）
-	fullRows, err := a.db.Query(sqlStr)
+	// 2. 选择保存路径后在后台执行导出
+	return a.startExportJob(sqlStr, "查询结果", exportOptions{})
+}
+
+// exportExcel 在独立连接上执行查询并写出 Excel 文件（按 opts 设置列顺序、列宽、冻结列和高亮），返回结果说明和行数
+func (a *App) exportExcel(ctx context.Context, sqlStr string, savePath string, opts exportOptions) (string, int, error) {
+	sqlStr = a.liveSQL(sqlStr)
+	conn, err := a.readDB().Conn(ctx)
 	if err != nil {
-		return fmt.Sprintf("SQL 执行失败: %v", err)
+		return "", 0, fmt.Errorf("获取数据库连接失败: %v", err)
+	}
+	defer conn.Close()
+	if opts.csv {
+		return a.exportCSV(ctx, conn, sqlStr, savePath)
 	}
-	defer fullRows.Close()
 
-	// 3. 获取列名
-	columns, err := fullRows.Columns()
+	// 1. 实时执行 SQL 获取全量数据（无分页），要求经临时文件导出或内存紧张时改为流式导出
+	op := operationFrom(ctx)
+	op.progress("query", 0, 0, "正在执行查询")
+	if opts.spill || memoryUnderPressure() {
+		return a.exportExcelStreaming(ctx, conn, sqlStr, savePath, opts)
+	}
+	columns, fullData, err := a.queryExportData(ctx, conn, sqlStr)
+	if errors.Is(err, errMemoryLimit) {
+		return a.exportExcelStreaming(ctx, conn, sqlStr, savePath, opts)
+	}
 	if err != nil {
-		return fmt.Sprintf("获取列名失败: %v", err)
+		return "", 0, err
 	}
 
-	// 4. 解析全量数据
-	var fullData []map[string]interface{}
-	values := make([]interface{}, len(columns))
-	valuePtrs := make([]interface{}, len(columns))
-	for i := range values {
-		valuePtrs[i] = &values[i]
+	// 2. 检查数据是否为空
+	if len(fullData) == 0 {
+		return "", 0, fmt.Errorf("导出失败：SQL 查询结果为空！")
+	}
+	rowCount := len(fullData)
+	var groupHeaders []bool
+	if opts.outline != "" {
+		if !containsString(columns, opts.outline) {
+			return "", 0, fmt.Errorf("分组列 %s 不在结果中", opts.outline)
+		}
+		fullData, groupHeaders = outlineGroups(fullData, opts.outline)
+	}
+	if len(fullData)+1 > excelMaxRows {
+		return "", 0, errExcelRowLimit(len(fullData))
 	}
 
-	for fullRows.Next() {
-		err := fullRows.Scan(valuePtrs...)
-		if err != nil {
-			return fmt.Sprintf("读取数据失败: %v", err)
+	fmt.Printf("[DEBUG] 共读取到 %d 行数据\n", rowCount)
+
+	// 3. 生成 Excel 文件
+	op.progress("write", 0, len(fullData), fmt.Sprintf("正在生成 Excel（%d 行）", len(fullData)))
+	columns = layoutColumns(columns, opts.layout)
+	columns, frozen := pinColumns(columns, opts.pinned)
+	formulas, err := newFormulaPlan(columns, opts.formulas, opts.totals)
+	if err != nil {
+		return "", 0, err
+	}
+	f, err := a.buildExportWorkbook(columns, fullData)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+	applyColumnWidths(f, exportSheetName, columns, opts.layout)
+	if err := freezeColumns(f, exportSheetName, frozen); err != nil {
+		return "", 0, fmt.Errorf("冻结关键列失败: %v", err)
+	}
+	applyHighlightRules(f, exportSheetName, columns, fullData, opts.rules)
+	applyColumnFormats(f, exportSheetName, columns, fullData, opts.formats)
+	if err := formulas.applyFormulas(f, exportSheetName, len(fullData), groupHeaders); err != nil {
+		return "", 0, err
+	}
+	if groupHeaders != nil {
+		if err := applyOutline(f, exportSheetName, len(columns), groupHeaders); err != nil {
+			return "", 0, fmt.Errorf("设置分组大纲失败: %v", err)
+		}
+	}
+	if err := applyValidations(f, exportSheetName, columns, opts.validations); err != nil {
+		return "", 0, err
+	}
+	if err := opts.protect.applyProtection(f, exportSheetName, columns, len(fullData)); err != nil {
+		return "", 0, err
+	}
+	if err := hideColumns(f, exportSheetName, columns, opts.hidden); err != nil {
+		return "", 0, err
+	}
+	if opts.roundTrip != nil {
+		if err := a.writeRoundTripSheet(f, opts.roundTrip); err != nil {
+			return "", 0, fmt.Errorf("生成往返信息失败: %v", err)
+		}
+	}
+	if a.setting("export_provenance") == "true" {
+		if err := a.addProvenanceSheet(f, sqlStr, rowCount); err != nil {
+			return "", 0, fmt.Errorf("生成来源信息失败: %v", err)
 		}
+	}
+
+	// 4. 保存文件（先检查目标磁盘空间，xlsx 压缩后通常小于原始文本）
+	if err := checkDiskSpace(filepath.Dir(savePath), estimateDataSize(columns, fullData)); err != nil {
+		return "", 0, err
+	}
+	op.progress("save", len(fullData), len(fullData), "正在保存文件")
+	if err := f.SaveAs(savePath); err != nil {
+		return "", 0, fmt.Errorf("导出 Excel 失败: %v", err)
+	}
+
+	message := fmt.Sprintf("Excel 导出成功: %s（共 %d 条数据）", savePath, rowCount)
 
-		row := make(map[string]interface{})
-		for i, col := range columns {
-			val := values[i]
-			// 处理特殊类型，避免 Excel 写入空值
-			if b, ok := val.([]byte); ok {
-				row[col] = string(b)
-			} else if val == nil {
-				row[col] = ""
-			} else {
-				row[col] = val
-			}
+	// 5. 敏感列提示
+	if warning := exportSensitiveWarning(columns, fullData); warning != "" {
+		message += "\n" + warning
+	}
+	return message, rowCount, nil
+}
+
+// executeWrite 执行修改类语句：入库操作放在一个事务中（多条语句要么全部成功要么全部回滚），
+// 返回 {statementType, rowsAffected, lastInsertId, message}（后两项仅 DML 有），不含 columns/data
+func (a *App) executeWrite(stmt sqlStatement, sqlStr string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.readOnly() {
+		result["error"] = readOnlyMessage
+		return result
+	}
+
+	// 连接池中的 BEGIN/COMMIT 可能落在不同连接上，不允许手动控制事务
+	if stmt.kind == stmtTransaction {
+		result["error"] = "事务由应用自动管理：每次执行的语句会在同一个事务中提交，请去掉 BEGIN/COMMIT 等语句"
+		return result
+	}
+
+	var res sql.Result
+	err := withBusyRetry(func() error {
+		var err error
+		if stmt.kind == stmtOther {
+			// VACUUM 等语句不能在事务中执行
+			res, err = a.writeDB().Exec(sqlStr)
+			return err
+		}
+		tx, err := a.writeDB().Begin()
+		if err != nil {
+			return fmt.Errorf("开启事务失败: %v", err)
 		}
-		fullData = append(fullData, row)
+		if res, err = tx.Exec(sqlStr); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err = tx.Commit(); err != nil {
+			return fmt.Errorf("提交事务失败: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		result["error"] = fmt.Sprintf("SQL 执行失败: %v", err)
+		return result
 	}
 
-	// 检查遍历错误
-	if err = fullRows.Err(); err != nil {
-		return fmt.Sprintf("遍历数据失败: %v", err)
+	if stmt.kind == stmtDDL || stmt.kind == stmtOther {
+		a.recordSchemaVersion(sqlStr)
 	}
 
-	// 5. 检查数据是否为空
-	if len(fullData) == 0 {
-		return "导出失败：SQL 查询结果为空！"
+	// 影响行数和自增 ID 只对 DML 有意义（DDL 时 SQLite 返回的是连接上一次 DML 的值）
+	result["statementType"] = stmt.kind
+	result["message"] = "执行成功"
+	affected, _ := res.RowsAffected()
+	switch stmt.kind {
+	case stmtInsert:
+		lastID, _ := res.LastInsertId()
+		result["rowsAffected"] = affected
+		result["lastInsertId"] = lastID
+		result["message"] = fmt.Sprintf("执行成功，插入 %d 行", affected)
+	case stmtUpdate:
+		result["rowsAffected"] = affected
+		result["message"] = fmt.Sprintf("执行成功，更新 %d 行", affected)
+	case stmtDelete:
+		result["rowsAffected"] = affected
+		result["message"] = fmt.Sprintf("执行成功，删除 %d 行", affected)
+	}
+	return result
+}
+
+// executeReturning 在事务中执行带 RETURNING 的修改语句，把返回的行作为结果集（一页全部返回）；
+// 与 executeWrite 一样拒绝只读模式，遇到锁冲突时重试
+func (a *App) executeReturning(stmt sqlStatement, sqlStr string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.readOnly() {
+		result["error"] = readOnlyMessage
+		return result
 	}
 
-	fmt.Printf("[DEBUG] 共读取到 %d 行数据\n", len(fullData))
+	var columns []string
+	var colTypes []*sql.ColumnType
+	var data []map[string]interface{}
+	nullValue := a.nullValue()
+	err := withBusyRetry(func() error {
+		tx, err := a.writeDB().Begin()
+		if err != nil {
+			return fmt.Errorf("开启事务失败: %v", err)
+		}
+		defer tx.Rollback()
 
-	// 6. 选择保存路径
-	savePath, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
-		Title:           "导出 Excel 文件",
-		DefaultFilename: "查询结果.xlsx",
-		Filters:         []runtime.FileFilter{{Pattern: "*.xlsx", DisplayName: "Excel 文件"}},
+		rows, err := tx.Query(sqlStr)
+		if err != nil {
+			return fmt.Errorf("SQL 执行失败: %v", err)
+		}
+		columns, err = rows.Columns()
+		if err != nil {
+			rows.Close()
+			return fmt.Errorf("获取列名失败: %v", err)
+		}
+		colTypes, _ = rows.ColumnTypes()
+		data, err = scanRowMaps(rows, columns, nullValue)
+		rows.Close()
+		if err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("提交事务失败: %v", err)
+		}
+		return nil
 	})
 	if err != nil {
-		return fmt.Sprintf("文件保存失败: %v", err)
+		result["error"] = err.Error()
+		return result
+	}
+
+	verb := map[string]string{stmtInsert: "插入", stmtUpdate: "更新", stmtDelete: "删除"}[stmt.kind]
+	if verb == "" {
+		verb = "影响"
+	}
+	result["statementType"] = stmt.kind
+	result["rowsAffected"] = len(data)
+	result["columns"] = columns
+	result["columnTypes"] = describeColumns(colTypes, columns, data)
+	result["data"] = data
+	result["total"] = len(data)
+	result["totalPages"] = 1
+	result["currentPage"] = 1
+	result["pageSize"] = len(data)
+	result["message"] = fmt.Sprintf("执行成功，%s %d 行，结果为 RETURNING 返回的数据", verb, len(data))
+	return result
+}
+
+// executePagedQuery 用 COUNT 和 LIMIT/OFFSET 包装查询语句，只读取当前页
+func (a *App) executePagedQuery(stmt sqlStatement, pageNum int, pageSize int) map[string]interface{} {
+	result := make(map[string]interface{})
+
+	query := a.beginQuery(stmt.text)
+	defer query.end()
+	countSQL, _ := stmt.countSQL()
+	var total int
+	if err := a.readDB().QueryRowContext(query.context(), countSQL).Scan(&total); err != nil {
+		result["error"] = fmt.Sprintf("SQL 执行失败: %v", err)
+		return result
 	}
-	if savePath == "" {
-		return "取消导出"
+
+	pagedSQL, _ := stmt.pagedSQL(pageSize, (pageNum-1)*pageSize)
+	rows, err := a.readDB().QueryContext(query.context(), pagedSQL)
+	if err != nil {
+		result["error"] = fmt.Sprintf("SQL 执行失败: %v", err)
+		return result
 	}
+	defer rows.Close()
 
-	// 7. 生成 Excel 文件
-	f := excelize.NewFile()
-	defer f.Close()
-	sheetName := "Sheet1"
+	columns, err := rows.Columns()
+	if err != nil {
+		result["error"] = fmt.Sprintf("获取列名失败: %v", err)
+		return result
+	}
+	colTypes, _ := rows.ColumnTypes()
+	pageData, err := scanRowMaps(rows, columns, a.nullValue())
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
 
-	// 写入表头
-	for colIdx, colName := range columns {
-		cell := fmt.Sprintf("%c1", 'A'+(colIdx))
-		f.SetCellValue(sheetName, cell, colName)
+	totalPages := (total + pageSize - 1) / pageSize
+	result["columns"] = columns
+	result["columnTypes"] = describeColumns(colTypes, columns, pageData)
+	pageData, truncated := truncateLongCells(pageData, columns, a.settingInt("cell_display_length"))
+	result["data"] = pageData
+	if len(truncated) > 0 {
+		result["truncatedCells"] = truncated
 	}
+	result["total"] = total
+	result["totalPages"] = totalPages
+	result["currentPage"] = pageNum
+	result["pageSize"] = pageSize
+	result["message"] = fmt.Sprintf("查询到 %d 条记录，当前第 %d 页（共 %d 页）", total, pageNum, totalPages)
+	if warnings := append(a.queryPlanWarnings(stmt.text), a.typeComparisonWarnings(stmt.text)...); len(warnings) > 0 {
+		result["warnings"] = warnings
+	}
+	return result
+}
 
-	// 写入全量数据
-	for rowIdx, rowData := range fullData {
-		for colIdx, colName := range columns {
-			cell := fmt.Sprintf("%c%d", 'A'+(colIdx), rowIdx+2)
-			f.SetCellValue(sheetName, cell, rowData[colName])
+// queryFullResult 执行查询并读取全量结果；开启 query_cache 时优先使用与当前数据版本一致的缓存，
+// 开始读取时有数据提交尚未完成（读到的可能是提交前的快照）则不缓存；只读实例看不到另一个实例的写入，不使用缓存
+func (a *App) queryFullResult(sqlStr string) (*cachedResult, error) {
+	useCache := a.cache != nil && a.statsCacheable() && a.setting("query_cache") == "true"
+	if useCache {
+		if entry, ok := a.cache.get(sqlStr); ok {
+			return entry, nil
 		}
 	}
 
-	// 8. 保存文件
-	if err := f.SaveAs(savePath); err != nil {
-		return fmt.Sprintf("导出 Excel 失败: %v", err)
+	version, settled := readSnapshot()
+	query := a.beginQuery(sqlStr)
+	defer query.end()
+	fullRows, err := a.readDB().QueryContext(query.context(), sqlStr)
+	if err != nil {
+		return nil, fmt.Errorf("SQL 执行失败: %v", err)
 	}
+	defer fullRows.Close()
 
-	return fmt.Sprintf("Excel 导出成功: %s（共 %d 条数据）", savePath, len(fullData))
+	// 获取列名
+	columns, err := fullRows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("获取列名失败: %v", err)
+	}
+
+	// 列类型须在遍历结果集之前获取
+	colTypes, _ := fullRows.ColumnTypes()
+
+	// 解析全量数据
+	fullData, err := scanRowMaps(fullRows, columns, a.nullValue())
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &cachedResult{
+		sql:         sqlStr,
+		version:     version,
+		columns:     columns,
+		columnTypes: describeColumns(colTypes, columns, fullData),
+		data:        fullData,
+		warnings:    append(a.queryPlanWarnings(sqlStr), a.typeComparisonWarnings(sqlStr)...),
+	}
+	if useCache && settled {
+		a.cache.put(entry)
+	}
+	return entry, nil
 }
 
 // GetCurrentSQL 获取当前执行的 SQL（用于前端导出）
 // wails:export GetCurrentSQL
 func (a *App) GetCurrentSQL() string {
-	return a.currentSQL
+	return a.session.currentSQL()
 }
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ExportAppendSheet 把查询结果作为新工作表追加到已有的 xlsx 文件中（如每周的结果累积在同一个跟踪工作簿里）
+// existingPath 为空时弹出文件选择框；sheetName 为空时使用当天日期（如 2024-05-20），与已有工作表重名时报错；
+// 先写入同目录的临时文件再替换原文件，失败时原文件保持不变；结果记录到导出历史，可用 ReRunExport 再次追加
+// wails:export ExportAppendSheet
+func (a *App) ExportAppendSheet(existingPath string, sheetName string, sqlStr string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	sqlStr = strings.TrimSpace(sqlStr)
+	if sqlStr == "" {
+		result["error"] = "错误：SQL 语句不能为空！"
+		return result
+	}
+	path, err := a.chooseFile(existingPath, "选择要追加的 Excel 文件", "*.xlsx", "Excel 文件")
+	if err != nil {
+		result["error"] = fmt.Sprintf("选择文件失败: %v", err)
+		return result
+	}
+	if path == "" {
+		result["error"] = "取消导出"
+		return result
+	}
+	sheetName = strings.TrimSpace(sheetName)
+	// 选择了文件后的结果记录到导出历史；记录传入的工作表名称，为空时重新导出同样使用当天日期
+	requested, startedAt := sheetName, time.Now()
+	defer func() {
+		status, errorText := "done", ""
+		if e, failed := result["error"].(string); failed {
+			status, errorText = "failed", e
+		}
+		rows, _ := result["rows"].(int)
+		options := map[string]interface{}{"appendSheet": map[string]interface{}{"sheetName": requested}}
+		a.insertExportHistory(sqlStr, path, options, status, rows, startedAt, time.Now(), errorText)
+	}()
+	if sheetName == "" {
+		sheetName = time.Now().Format("2006-01-02")
+	}
+
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		result["error"] = fmt.Sprintf("打开 Excel 文件失败: %v", err)
+		return result
+	}
+	defer f.Close()
+	if idx, _ := f.GetSheetIndex(sheetName); idx >= 0 {
+		result["error"] = fmt.Sprintf("工作表 %s 已存在，请换一个名称", sheetName)
+		return result
+	}
+	index, err := f.NewSheet(sheetName)
+	if err != nil {
+		result["error"] = fmt.Sprintf("工作表名称 %s 无效: %v", sheetName, err)
+		return result
+	}
+
+	op := a.beginOperation("", "export", fmt.Sprintf("追加工作表 %s 到 %s", sheetName, filepath.Base(path)))
+	ctx := withOperation(op.context(), op)
+	op.progress("query", 0, 0, "正在执行查询")
+	columns, fullData, err := a.queryExportData(ctx, a.readDB(), sqlStr)
+	if err != nil {
+		result["error"] = err.Error()
+		op.finishResult(result)
+		return result
+	}
+	if len(fullData) == 0 {
+		result["error"] = "导出失败：SQL 查询结果为空！"
+		op.finishResult(result)
+		return result
+	}
+	if len(fullData)+1 > excelMaxRows {
+		result["error"] = errExcelRowLimit(len(fullData)).Error()
+		op.finishResult(result)
+		return result
+	}
+
+	op.progress("write", 0, len(fullData), fmt.Sprintf("正在写入工作表（%d 行）", len(fullData)))
+	if err := a.writeExportSheet(f, sheetName, columns, fullData); err != nil {
+		result["error"] = err.Error()
+		op.finishResult(result)
+		return result
+	}
+	f.SetActiveSheet(index)
+
+	// 先保存到临时文件再替换，避免写入中途失败损坏原有的工作簿
+	op.progress("save", len(fullData), len(fullData), "正在保存文件")
+	if err := checkDiskSpace(filepath.Dir(path), estimateDataSize(columns, fullData)); err != nil {
+		result["error"] = err.Error()
+		op.finishResult(result)
+		return result
+	}
+	tmpPath := path + ".tmp.xlsx"
+	if err := f.SaveAs(tmpPath); err != nil {
+		os.Remove(tmpPath)
+		result["error"] = fmt.Sprintf("保存 Excel 失败: %v", err)
+		op.finishResult(result)
+		return result
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		result["error"] = fmt.Sprintf("替换原文件失败（文件是否正在 Excel 中打开？）: %v", err)
+		op.finishResult(result)
+		return result
+	}
+
+	result["path"] = path
+	result["sheetName"] = sheetName
+	result["rows"] = len(fullData)
+	result["sheets"] = f.GetSheetList()
+	result["message"] = fmt.Sprintf("已在 %s 中追加工作表 %s（共 %d 条数据）", path, sheetName, len(fullData))
+	op.finishResult(result)
+	return result
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// backupDir 数据库备份文件所在目录（与 data.db 同级）
+const backupDir = "./backups"
+
+// backupAlias 恢复单表时挂载备份数据库使用的名称
+const backupAlias = "backup_src"
+
+// CreateBackup 使用在线备份 API 把当前数据库完整备份到 backups 目录，并登记到备份列表，note 为备注
+// wails:export CreateBackup
+func (a *App) CreateBackup(note string) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	if a.readOnly() {
+		return readOnlyMessage
+	}
+	if err := os.MkdirAll(backupDir, 0o755); err != nil {
+		return fmt.Sprintf("创建备份目录失败: %v", err)
+	}
+	now := time.Now()
+	path := filepath.Join(backupDir, fmt.Sprintf("data-%s.db", now.Format("20060102-150405")))
+	if err := checkDiskSpace(backupDir, databaseFileSize()); err != nil {
+		return err.Error()
+	}
+	op := a.beginOperation("", "maintenance", "备份数据库")
+	err := a.backupDatabase(path, nil)
+	if err != nil {
+		op.finish(err.Error(), err)
+		return err.Error()
+	}
+	var size int64
+	if info, err := os.Stat(path); err == nil {
+		size = info.Size()
+	}
+	res, err := a.writeDB().Exec("INSERT INTO _app_backups (path, size, note, created_at) VALUES (?, ?, ?, ?)",
+		path, size, strings.TrimSpace(note), now.Format("2006-01-02 15:04:05"))
+	if err != nil {
+		op.finish(err.Error(), err)
+		return fmt.Sprintf("登记备份失败: %v", err)
+	}
+	id, _ := res.LastInsertId()
+	message := fmt.Sprintf("已备份数据库（备份 %d）：%s", id, path)
+	op.finish(message, nil)
+	return message
+}
+
+// databaseFileSize 当前数据库文件（含 WAL）的大小，用于备份前检查磁盘空间
+func databaseFileSize() uint64 {
+	var total uint64
+	for _, name := range []string{"./data.db", "./data.db-wal"} {
+		if info, err := os.Stat(name); err == nil {
+			total += uint64(info.Size())
+		}
+	}
+	return total
+}
+
+// ListBackups 列出已登记的备份（最近的在前）：id、path、size、note、createdAt，exists 表示备份文件是否仍在
+// wails:export ListBackups
+func (a *App) ListBackups() []map[string]interface{} {
+	list := []map[string]interface{}{}
+	if a.writeDB() == nil {
+		return list
+	}
+	rows, err := a.readDB().Query("SELECT id, path, size, note, created_at FROM _app_backups ORDER BY id DESC")
+	if err != nil {
+		fmt.Printf("读取备份列表失败: %v\n", err)
+		return list
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, size int64
+		var path, note, createdAt string
+		if err := rows.Scan(&id, &path, &size, &note, &createdAt); err != nil {
+			fmt.Printf("读取备份列表失败: %v\n", err)
+			return list
+		}
+		_, statErr := os.Stat(path)
+		list = append(list, map[string]interface{}{
+			"id": id, "path": path, "size": size, "note": note, "createdAt": createdAt, "exists": statErr == nil,
+		})
+	}
+	return list
+}
+
+// RestoreTableFromBackup 从备份中恢复一张表：用备份中的表结构、数据和索引替换当前同名的表或视图（不存在时新建），
+// 其他表不受影响；在一个事务中完成，失败时当前表保持原样
+// wails:export RestoreTableFromBackup
+func (a *App) RestoreTableFromBackup(backupID int, table string) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	if a.readOnly() {
+		return readOnlyMessage
+	}
+	if strings.HasPrefix(table, "_app_") || strings.HasPrefix(table, "sqlite_") {
+		return fmt.Sprintf("不能单独恢复内部表 %s", table)
+	}
+	var path, createdAt string
+	err := a.readDB().QueryRow("SELECT path, created_at FROM _app_backups WHERE id = ?", backupID).Scan(&path, &createdAt)
+	if err == sql.ErrNoRows {
+		return fmt.Sprintf("备份 %d 不存在", backupID)
+	}
+	if err != nil {
+		return fmt.Sprintf("读取备份列表失败: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Sprintf("备份文件 %s 不存在", path)
+	}
+
+	op := a.beginOperation("", "maintenance", fmt.Sprintf("从备份 %d 恢复表 %s", backupID, table))
+	rows, err := a.restoreTable(path, table)
+	if err != nil {
+		op.finish(err.Error(), err)
+		return err.Error()
+	}
+	a.cache.clear()
+	a.touchTables(table)
+	a.recordSchemaVersion(fmt.Sprintf("从备份 %d 恢复表 %s", backupID, table))
+	message := fmt.Sprintf("已从 %s 的备份恢复表 %s（%d 行）", createdAt, table, rows)
+	op.finish(message, nil)
+	return message
+}
+
+// restoreTable 在同一连接上挂载备份文件，事务内重建表并复制数据和索引
+func (a *App) restoreTable(path string, table string) (int64, error) {
+	ctx := context.Background()
+	conn, err := a.writeDB().Conn(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("获取数据库连接失败: %v", err)
+	}
+	defer conn.Close()
+	uri := "file:" + filepath.ToSlash(path) + "?mode=ro"
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("ATTACH DATABASE %s AS %s", quoteLiteral(uri), backupAlias)); err != nil {
+		return 0, fmt.Errorf("打开备份文件失败: %v", err)
+	}
+	defer conn.ExecContext(ctx, "DETACH DATABASE "+backupAlias)
+
+	var createSQL string
+	err = conn.QueryRowContext(ctx, fmt.Sprintf("SELECT sql FROM %s.sqlite_master WHERE type = 'table' AND name = ?", backupAlias), table).Scan(&createSQL)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("备份中没有表 %s", table)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("读取备份失败: %v", err)
+	}
+	var columns []string
+	colRows, err := conn.QueryContext(ctx, "SELECT name FROM pragma_table_info(?, ?)", table, backupAlias)
+	if err != nil {
+		return 0, fmt.Errorf("读取备份失败: %v", err)
+	}
+	for colRows.Next() {
+		var name string
+		if colRows.Scan(&name) == nil {
+			columns = append(columns, quoteIdent(name))
+		}
+	}
+	colRows.Close()
+	// 连同 rowid 一起复制，批注等按 rowid 关联的元数据仍能对应到原来的行；
+	// WITHOUT ROWID 表和有名为 rowid 的列的表只复制各列
+	columnList := strings.Join(columns, ", ")
+	if createSQLHasRowid(createSQL) {
+		columnList = "rowid, " + columnList
+	}
+
+	var indexes []string
+	idxRows, err := conn.QueryContext(ctx, fmt.Sprintf("SELECT sql FROM %s.sqlite_master WHERE type = 'index' AND tbl_name = ? AND sql IS NOT NULL", backupAlias), table)
+	if err != nil {
+		return 0, fmt.Errorf("读取备份失败: %v", err)
+	}
+	for idxRows.Next() {
+		var s string
+		if idxRows.Scan(&s) == nil {
+			indexes = append(indexes, s)
+		}
+	}
+	idxRows.Close()
+
+	var copied int64
+	err = withBusyRetry(func() error {
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("开启事务失败: %v", err)
+		}
+		if err := dropTableOrView(tx, table); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("删除当前表 %s 失败: %v", table, err)
+		}
+		// 备份中的建表语句不带库名，在主库中执行
+		if _, err := tx.Exec(createSQL); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("重建表 %s 失败: %v", table, err)
+		}
+		res, err := tx.Exec(fmt.Sprintf("INSERT INTO main.%s (%s) SELECT %s FROM %s.%s",
+			quoteIdent(table), columnList, columnList, backupAlias, quoteIdent(table)))
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("复制表 %s 的数据失败: %v", table, err)
+		}
+		copied, _ = res.RowsAffected()
+		for _, s := range indexes {
+			if _, err := tx.Exec(s); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("重建索引失败: %v", err)
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("提交事务失败: %v", err)
+		}
+		return nil
+	})
+	return copied, err
+}
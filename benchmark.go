@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// 基准测试的默认行数和最大行数
+const (
+	defaultBenchmarkRows = 10000
+	maxBenchmarkRows     = 200000
+)
+
+// benchmarkTable 基准测试使用的临时表（以 _app_ 开头，不出现在表列表中，测试结束后删除）
+const benchmarkTable = "_app_benchmark"
+
+// benchmarkQueries 基准测试执行的标准查询
+var benchmarkQueries = []struct{ name, sql string }{
+	{"计数", "SELECT COUNT(*) FROM " + benchmarkTable},
+	{"分组汇总", "SELECT column3, COUNT(*), SUM(CAST(column4 AS REAL)) FROM " + benchmarkTable + " GROUP BY column3"},
+	{"排序取前 100 行", "SELECT * FROM " + benchmarkTable + " ORDER BY CAST(column4 AS REAL) DESC LIMIT 100"},
+	{"模糊查找", "SELECT COUNT(*) FROM " + benchmarkTable + " WHERE column2 LIKE '%99%'"},
+}
+
+// writeBenchmarkWorkbook 生成包含 n 行合成数据的工作簿：编号、名称、类别、金额、日期
+func writeBenchmarkWorkbook(path string, n int) error {
+	f := excelize.NewFile()
+	defer f.Close()
+	sw, err := f.NewStreamWriter("Sheet1")
+	if err != nil {
+		return err
+	}
+	if err := sw.SetRow("A1", []interface{}{"编号", "名称", "类别", "金额", "日期"}); err != nil {
+		return err
+	}
+	categories := []string{"华东", "华南", "华北", "西南", "东北"}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 1; i <= n; i++ {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		row := []interface{}{
+			i,
+			fmt.Sprintf("客户%06d", i),
+			categories[i%len(categories)],
+			float64(i*37%100000) / 100,
+			start.AddDate(0, 0, i%366).Format("2006-01-02"),
+		}
+		if err := sw.SetRow(cell, row); err != nil {
+			return err
+		}
+	}
+	if err := sw.Flush(); err != nil {
+		return err
+	}
+	return f.SaveAs(path)
+}
+
+// pragmaValue 读取一个 PRAGMA 的当前值，失败时返回空字符串
+func (a *App) pragmaValue(name string) string {
+	var value string
+	if err := a.writeDB().QueryRow("PRAGMA " + name).Scan(&value); err != nil {
+		return ""
+	}
+	return value
+}
+
+// RunBenchmark 性能自检：生成 rows 行（<= 0 时 10000 行，最多 200000 行）的合成工作簿，依次测试解析、导入、标准查询和导出的耗时
+// 返回 {rows, steps: [{name, ms, error?}], totalMs, environment, pragmas}，用于对比不同机器或确认设置调整是否有效；
+// 测试数据写入临时表和临时目录，结束后删除
+// wails:export RunBenchmark
+func (a *App) RunBenchmark(rows int) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	if a.readOnly() {
+		result["error"] = readOnlyMessage
+		return result
+	}
+	if rows <= 0 {
+		rows = defaultBenchmarkRows
+	}
+	if rows > maxBenchmarkRows {
+		rows = maxBenchmarkRows
+	}
+
+	dir, err := os.MkdirTemp("", "benchmark-*")
+	if err != nil {
+		result["error"] = fmt.Sprintf("创建临时目录失败: %v", err)
+		return result
+	}
+	defer os.RemoveAll(dir)
+	defer dropTableOrView(a.writeDB(), benchmarkTable)
+
+	var steps []map[string]interface{}
+	failed := false
+	stepCount := 5 + len(benchmarkQueries)
+	op := a.beginOperation("", "benchmark", "性能自检")
+	step := func(name string, fn func() error) {
+		if failed {
+			return
+		}
+		op.progress(name, len(steps), stepCount, name)
+		start := time.Now()
+		err := fn()
+		item := map[string]interface{}{"name": name, "ms": time.Since(start).Milliseconds()}
+		if err != nil {
+			item["error"] = err.Error()
+			failed = true
+		}
+		steps = append(steps, item)
+	}
+
+	total := time.Now()
+	workbook := filepath.Join(dir, "benchmark.xlsx")
+	step("生成工作簿", func() error { return writeBenchmarkWorkbook(workbook, rows) })
+
+	var data [][]string
+	step("解析工作簿", func() error {
+		f, err := excelize.OpenFile(workbook)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		data, err = f.GetRows("Sheet1")
+		if err != nil {
+			return err
+		}
+		if len(data) == 0 {
+			return errors.New("工作簿为空")
+		}
+		return nil
+	})
+
+	step("导入数据库", func() error {
+		if err := checkDiskSpace(filepath.Dir("./data.db"), estimateRowsSize(data)*importSpaceFactor); err != nil {
+			return err
+		}
+		return a.writeTableRows(benchmarkTable, defaultColumnNames(len(data[0])), data[1:], nil)
+	})
+
+	for _, q := range benchmarkQueries {
+		step("查询："+q.name, func() error {
+			rows, err := a.readDB().Query(q.sql)
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+			for rows.Next() {
+			}
+			return rows.Err()
+		})
+	}
+
+	exportSQL := "SELECT * FROM " + benchmarkTable
+	step("导出 Excel", func() error {
+		_, _, err := a.exportExcel(context.Background(), exportSQL, filepath.Join(dir, "export.xlsx"), exportOptions{})
+		return err
+	})
+	step("流式导出 Excel", func() error {
+		_, _, err := a.exportExcel(context.Background(), exportSQL, filepath.Join(dir, "export-stream.xlsx"), exportOptions{spill: true})
+		return err
+	})
+
+	pragmas := make(map[string]string)
+	for _, name := range []string{"journal_mode", "synchronous", "cache_size", "temp_store", "mmap_size", "page_size"} {
+		pragmas[name] = a.pragmaValue(name)
+	}
+
+	result["rows"] = rows
+	result["steps"] = steps
+	result["totalMs"] = time.Since(total).Milliseconds()
+	result["pragmas"] = pragmas
+	result["environment"] = map[string]interface{}{
+		"os":            runtime.GOOS,
+		"arch":          runtime.GOARCH,
+		"cpus":          runtime.NumCPU(),
+		"goVersion":     runtime.Version(),
+		"memoryLimitMB": memoryLimit.Load() >> 20,
+	}
+	if failed {
+		result["error"] = "性能自检未完成，出错的步骤见 steps"
+		op.finish(result["error"].(string), errors.New(steps[len(steps)-1]["error"].(string)))
+		return result
+	}
+	result["message"] = fmt.Sprintf("性能自检完成：%d 行数据，共耗时 %d ms", rows, result["totalMs"])
+	op.finish(result["message"].(string), nil)
+	return result
+}
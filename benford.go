@@ -0,0 +1,126 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// benfordMinSamples 样本少于该数量时首位数字分布没有统计意义，只给出提示
+const benfordMinSamples = 100
+
+// benfordConformity 首位数字平均绝对偏差（MAD）的符合度分档（Nigrini 标准）
+var benfordConformity = []struct {
+	limit float64
+	label string
+}{
+	{0.006, "高度符合"},
+	{0.012, "基本符合"},
+	{0.015, "勉强符合"},
+	{math.Inf(1), "不符合"},
+}
+
+// leadingDigit 数值的首位有效数字，0 或非有限数返回 0
+func leadingDigit(v float64) int {
+	v = math.Abs(v)
+	if v == 0 || math.IsInf(v, 0) || math.IsNaN(v) {
+		return 0
+	}
+	s := strconv.FormatFloat(v, 'e', -1, 64)
+	return int(s[0] - '0')
+}
+
+// BenfordAudit 统计数值列的首位数字分布并与本福特定律比较，用于费用、报销等数据的审计抽查
+// 返回每个数字的实际/期望占比、卡方值和 MAD 符合度；无法解析为数值或为 0 的单元格不参与统计
+// wails:export BenfordAudit
+func (a *App) BenfordAudit(tableName string, column string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+
+	columns, err := a.tableColumns(tableName)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	if !containsString(columns, column) {
+		result["error"] = fmt.Sprintf("表 %s 中不存在列 %s", tableName, column)
+		return result
+	}
+
+	from, _ := a.liveSource(tableName, columns)
+	rows, err := a.readDB().Query(fmt.Sprintf("SELECT CAST(%s AS TEXT) FROM %s WHERE %s IS NOT NULL",
+		quoteIdent(column), from, quoteIdent(column)))
+	if err != nil {
+		result["error"] = fmt.Sprintf("读取数据失败: %v", err)
+		return result
+	}
+	var counts [10]int
+	total, skipped := 0, 0
+	for rows.Next() {
+		var s sql.NullString
+		if err := rows.Scan(&s); err != nil {
+			rows.Close()
+			result["error"] = fmt.Sprintf("读取数据失败: %v", err)
+			return result
+		}
+		v, ok := parseNumberText(s.String)
+		d := leadingDigit(v)
+		if !ok || d == 0 {
+			skipped++
+			continue
+		}
+		counts[d]++
+		total++
+	}
+	rows.Close()
+	if total == 0 {
+		result["error"] = fmt.Sprintf("列 %s 中没有可用于统计的非零数值", column)
+		return result
+	}
+
+	var digits []map[string]interface{}
+	chiSquare, mad := 0.0, 0.0
+	for d := 1; d <= 9; d++ {
+		expected := math.Log10(1 + 1/float64(d))
+		observed := float64(counts[d]) / float64(total)
+		diff := observed - expected
+		chiSquare += diff * diff * float64(total) / expected
+		mad += math.Abs(diff)
+		digits = append(digits, map[string]interface{}{
+			"digit":         d,
+			"count":         counts[d],
+			"observed":      observed,
+			"expected":      expected,
+			"expectedCount": expected * float64(total),
+			"deviation":     diff,
+		})
+	}
+	mad /= 9
+
+	conformity := benfordConformity[len(benfordConformity)-1].label
+	for _, c := range benfordConformity {
+		if mad <= c.limit {
+			conformity = c.label
+			break
+		}
+	}
+
+	result["digits"] = digits
+	result["total"] = total
+	result["skipped"] = skipped
+	result["chiSquare"] = chiSquare
+	result["mad"] = mad
+	result["conformity"] = conformity
+	// 自由度 8，显著性水平 0.05 的卡方临界值为 15.507
+	result["significant"] = chiSquare > 15.507
+	message := fmt.Sprintf("共统计 %d 个数值（跳过 %d 个），MAD=%.4f，%s本福特定律", total, skipped, mad, conformity)
+	if total < benfordMinSamples {
+		message += fmt.Sprintf("；样本少于 %d 个，结论仅供参考", benfordMinSamples)
+	}
+	result["message"] = message
+	return result
+}
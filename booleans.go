@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// booleanDistinctLimit 判断布尔列时最多读取的不同取值数，超过即不是布尔列
+const booleanDistinctLimit = 8
+
+var booleanTrueValues = map[string]bool{
+	"是": true, "y": true, "yes": true, "true": true, "t": true, "1": true, "对": true, "√": true, "有": true,
+}
+
+var booleanFalseValues = map[string]bool{
+	"否": true, "n": true, "no": true, "false": true, "f": true, "0": true, "错": true, "×": true, "无": true,
+}
+
+// booleanKey 布尔取值比较前的规范化（去空白、全角转半角、转小写）
+func booleanKey(v string) string {
+	return foldKey(strings.TrimSpace(v))
+}
+
+// detectBooleanColumns 找出所有非空取值都属于 是/否、Y/N、TRUE/FALSE、1/0 等布尔词表的列
+// 仅含 1/0 的列必须两种取值都出现，避免误判只有 1 的计数列
+func (a *App) detectBooleanColumns(db sqlExecutor, tableName string) ([]map[string]interface{}, error) {
+	infos, err := tableColumnInfos(db, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []map[string]interface{}
+	for _, info := range infos {
+		if strings.EqualFold(info.declType, "INTEGER") {
+			continue
+		}
+		rows, err := db.Query(fmt.Sprintf(
+			"SELECT DISTINCT CAST(%s AS TEXT) FROM %s WHERE %s IS NOT NULL AND TRIM(%s) <> '' LIMIT %d",
+			quoteIdent(info.name), quoteIdent(tableName), quoteIdent(info.name), quoteIdent(info.name), booleanDistinctLimit+1,
+		))
+		if err != nil {
+			return nil, fmt.Errorf("读取列 %s 失败: %v", info.name, err)
+		}
+		var values []string
+		for rows.Next() {
+			var v string
+			if err := rows.Scan(&v); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("读取列 %s 失败: %v", info.name, err)
+			}
+			values = append(values, v)
+		}
+		rows.Close()
+
+		if len(values) == 0 || len(values) > booleanDistinctLimit {
+			continue
+		}
+		hasTrue, hasFalse, onlyDigits, ok := false, false, true, true
+		for _, v := range values {
+			key := booleanKey(v)
+			switch {
+			case booleanTrueValues[key]:
+				hasTrue = true
+			case booleanFalseValues[key]:
+				hasFalse = true
+			default:
+				ok = false
+			}
+			if key != "0" && key != "1" {
+				onlyDigits = false
+			}
+		}
+		if !ok || (onlyDigits && !(hasTrue && hasFalse)) {
+			continue
+		}
+		sort.Strings(values)
+		found = append(found, map[string]interface{}{
+			"column": info.name,
+			"values": values,
+		})
+	}
+	return found, nil
+}
+
+// booleanCaseExpr 生成将布尔词表取值转为 1/0 的 CASE 表达式，其他值（含空值）转为 NULL
+func booleanCaseExpr(column string) string {
+	var trues, falses []string
+	for v := range booleanTrueValues {
+		trues = append(trues, quoteLiteral(v))
+	}
+	for v := range booleanFalseValues {
+		falses = append(falses, quoteLiteral(v))
+	}
+	sort.Strings(trues)
+	sort.Strings(falses)
+	// 与 booleanKey 一致：全角转半角并转小写后比较
+	key := fmt.Sprintf("LOWER(TRIM(CAST(%s AS TEXT))) COLLATE FOLD", quoteIdent(column))
+	return fmt.Sprintf("CASE WHEN %s IN (%s) THEN 1 WHEN %s IN (%s) THEN 0 ELSE NULL END",
+		key, strings.Join(trues, ", "), key, strings.Join(falses, ", "))
+}
+
+// normalizeBooleanColumns 将指定列转为 INTEGER 0/1
+func normalizeBooleanColumns(db sqlExecutor, tableName string, columns []string) error {
+	types := make(map[string]string)
+	exprs := make(map[string]string)
+	for _, col := range columns {
+		types[col] = "INTEGER"
+		exprs[col] = booleanCaseExpr(col)
+	}
+	return rebuildTable(db, tableName, types, exprs)
+}
+
+// DetectBooleanColumns 检测表中的布尔型列（是/否、Y/N、TRUE/FALSE、1/0）
+// wails:export DetectBooleanColumns
+func (a *App) DetectBooleanColumns(tableName string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+
+	found, err := a.detectBooleanColumns(a.writeDB(), tableName)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	result["columns"] = found
+	result["message"] = fmt.Sprintf("表 %s 中检测到 %d 个布尔型列", tableName, len(found))
+	return result
+}
+
+// NormalizeBooleanColumns 将指定的布尔型列转为 INTEGER 0/1（无法识别的取值转为 NULL）
+// wails:export NormalizeBooleanColumns
+func (a *App) NormalizeBooleanColumns(tableName string, columns []string) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	if len(columns) == 0 {
+		return "请选择要转换的列"
+	}
+
+	existing, err := a.tableColumns(tableName)
+	if err != nil {
+		return err.Error()
+	}
+	for _, col := range columns {
+		if !containsString(existing, col) {
+			return fmt.Sprintf("表 %s 中不存在列 %s", tableName, col)
+		}
+	}
+
+	tx, err := a.writeDB().Begin()
+	if err != nil {
+		return fmt.Sprintf("开启事务失败: %v", err)
+	}
+	if err := normalizeBooleanColumns(tx, tableName, columns); err != nil {
+		tx.Rollback()
+		return err.Error()
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Sprintf("提交事务失败: %v", err)
+	}
+	return fmt.Sprintf("已将表 %s 的 %d 个列转为 0/1 整数", tableName, len(columns))
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// 分享包（zip）中的文件名
+const (
+	bundleResultFile   = "result.xlsx"
+	bundleQueryFile    = "query.sql"
+	bundleSchemaFile   = "schema.sql"
+	bundleManifestFile = "manifest.json"
+)
+
+// bundleTable 分享包清单中记录的一个来源表
+type bundleTable struct {
+	Name        string   `json:"name"`
+	Label       string   `json:"label,omitempty"`
+	Columns     []string `json:"columns"`
+	ReadColumns []string `json:"readColumns"`
+	RowCount    int      `json:"rowCount"`
+	Source      string   `json:"source,omitempty"`
+	ImportedAt  string   `json:"importedAt,omitempty"`
+	Sampling    string   `json:"sampling,omitempty"`
+}
+
+// bundleManifest 分享包的 manifest.json
+type bundleManifest struct {
+	CreatedAt string        `json:"createdAt"`
+	Database  string        `json:"database"`
+	SQL       string        `json:"sql"`
+	RowCount  int           `json:"rowCount"`
+	Files     []string      `json:"files"`
+	Tables    []bundleTable `json:"tables"`
+}
+
+// ExportShareBundle 将查询结果打包为一个 zip：result.xlsx（查询结果）、query.sql（SQL）、
+// schema.sql（来源表的建表语句及索引、触发器）和 manifest.json（来源表、行数、导入来源等），便于他人复现分析
+// wails:export ExportShareBundle
+func (a *App) ExportShareBundle(sqlStr string) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	sqlStr = strings.TrimSpace(sqlStr)
+	if sqlStr == "" {
+		return "错误：SQL 语句不能为空！"
+	}
+	stmt := parseStatement(sqlStr)
+	if stmt.kind != stmtSelect || stmt.multiple {
+		return "错误：分享包只支持单条查询语句！"
+	}
+	sqlStr = stmt.text
+
+	lineage, err := a.queryLineage(sqlStr)
+	if err != nil {
+		return fmt.Sprintf("分析来源表失败: %v", err)
+	}
+
+	savePath, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "导出分享包",
+		DefaultFilename: "分析分享包.zip",
+		Filters:         []runtime.FileFilter{{Pattern: "*.zip", DisplayName: "压缩包"}},
+	})
+	if err != nil {
+		return fmt.Sprintf("文件保存失败: %v", err)
+	}
+	if savePath == "" {
+		return "取消导出"
+	}
+
+	// 结果先写到临时文件，再放入压缩包
+	tmp, err := os.CreateTemp("", "share-*.xlsx")
+	if err != nil {
+		return fmt.Sprintf("创建临时文件失败: %v", err)
+	}
+	tmpPath := tmp.Name()
+	tmp.Close()
+	defer os.Remove(tmpPath)
+	if _, _, err := a.exportExcel(context.Background(), sqlStr, tmpPath, exportOptions{}); err != nil {
+		return err.Error()
+	}
+
+	manifest, schema, err := a.bundleManifest(sqlStr, lineage)
+	if err != nil {
+		return fmt.Sprintf("生成清单失败: %v", err)
+	}
+	if err := writeShareBundle(savePath, tmpPath, sqlStr, schema, manifest); err != nil {
+		os.Remove(savePath)
+		return fmt.Sprintf("导出分享包失败: %v", err)
+	}
+	return fmt.Sprintf("分享包导出成功: %s（%d 行结果，%d 个来源表）", savePath, manifest.RowCount, len(manifest.Tables))
+}
+
+// bundleManifest 收集来源表的结构、行数和导入来源，返回清单和 schema.sql 内容
+func (a *App) bundleManifest(sqlStr string, lineage map[string][]string) (bundleManifest, string, error) {
+	dbPath, _ := filepath.Abs("./data.db")
+	manifest := bundleManifest{
+		CreatedAt: time.Now().Format("2006-01-02 15:04:05"),
+		Database:  dbPath,
+		SQL:       sqlStr,
+		Files:     []string{bundleResultFile, bundleQueryFile, bundleSchemaFile, bundleManifestFile},
+		Tables:    []bundleTable{},
+	}
+	if err := a.readDB().QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM (%s)", sqlStr)).Scan(&manifest.RowCount); err != nil {
+		return manifest, "", err
+	}
+
+	tables := make([]string, 0, len(lineage))
+	for table := range lineage {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+
+	var schema strings.Builder
+	schema.WriteString("-- 来源表结构，导出时间 " + manifest.CreatedAt + "\n")
+	for _, table := range tables {
+		// 表本身在前，其后是索引和触发器
+		rows, err := a.readDB().Query(`SELECT sql FROM sqlite_master WHERE tbl_name = ? AND sql IS NOT NULL
+			ORDER BY CASE type WHEN 'table' THEN 0 WHEN 'index' THEN 1 ELSE 2 END, name`, table)
+		if err != nil {
+			return manifest, "", err
+		}
+		schema.WriteString("\n")
+		for rows.Next() {
+			var ddl string
+			if err := rows.Scan(&ddl); err != nil {
+				rows.Close()
+				return manifest, "", err
+			}
+			schema.WriteString(ddl + ";\n")
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return manifest, "", err
+		}
+
+		info := bundleTable{Name: table, ReadColumns: lineage[table]}
+		info.Columns, err = a.tableColumns(table)
+		if err != nil {
+			return manifest, "", err
+		}
+		a.readDB().QueryRow("SELECT COUNT(*) FROM " + quoteIdent(table)).Scan(&info.RowCount)
+		a.readDB().QueryRow("SELECT source, imported_at, sampling FROM _app_table_sources WHERE table_name = ?", table).Scan(&info.Source, &info.ImportedAt, &info.Sampling)
+		a.readDB().QueryRow("SELECT label FROM _app_dictionary WHERE table_name = ? AND column_name = ''", table).Scan(&info.Label)
+		manifest.Tables = append(manifest.Tables, info)
+	}
+	return manifest, schema.String(), nil
+}
+
+// writeShareBundle 写出分享包 zip
+func writeShareBundle(savePath string, resultPath string, sqlStr string, schema string, manifest bundleManifest) error {
+	out, err := os.Create(savePath)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	zw := zip.NewWriter(out)
+
+	result, err := os.Open(resultPath)
+	if err != nil {
+		return err
+	}
+	defer result.Close()
+	w, err := zw.Create(bundleResultFile)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, result); err != nil {
+		return err
+	}
+
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	texts := []struct {
+		name    string
+		content string
+	}{
+		{bundleQueryFile, sqlStr + "\n"},
+		{bundleSchemaFile, schema},
+		{bundleManifestFile, string(manifestJSON)},
+	}
+	for _, t := range texts {
+		w, err := zw.Create(t.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, t.content); err != nil {
+			return err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
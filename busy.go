@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// sqliteBusyTimeoutMs 连接等待其他连接释放锁的最长时间（毫秒），超时后才返回 database is locked
+const sqliteBusyTimeoutMs = 10000
+
+// busyRetryBackoff 写入遇到锁冲突时整体重试的等待间隔：事务中途由读锁升级为写锁失败时 SQLite 不会等待，只能回滚后重来
+var busyRetryBackoff = []time.Duration{200 * time.Millisecond, 500 * time.Millisecond, time.Second, 2 * time.Second}
+
+// isBusyError 判断是否为锁冲突（database is locked / database table is locked）；错误经 fmt.Errorf 包装后按文本判断
+func isBusyError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "database is locked") || strings.Contains(msg, "database table is locked")
+}
+
+// withBusyRetry 执行一次完整的写入（fn 须在失败时回滚，可安全重复执行），遇到锁冲突时按 busyRetryBackoff 等待后重试
+func withBusyRetry(fn func() error) error {
+	err := fn()
+	for _, wait := range busyRetryBackoff {
+		if !isBusyError(err) {
+			return err
+		}
+		time.Sleep(wait)
+		err = fn()
+	}
+	if isBusyError(err) {
+		return fmt.Errorf("数据库正忙（其他操作正在写入），重试 %d 次后仍失败，请稍后再试: %v", len(busyRetryBackoff), err)
+	}
+	return err
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"container/list"
+	"sync"
+)
+
+// 查询结果缓存的容量限制
+const (
+	maxCachedQueries = 8      // 最多缓存的查询数
+	maxCachedRows    = 200000 // 超过该行数的结果不缓存
+)
+
+// cachedResult 一条缓存的查询结果，version 为执行查询前的数据版本
+type cachedResult struct {
+	sql         string
+	version     int64
+	columns     []string
+	columnTypes []map[string]interface{}
+	data        []map[string]interface{}
+	warnings    []map[string]interface{} // 慢查询、文本列与数字比较等提示
+}
+
+// resultCache 按 (SQL, 数据版本) 缓存全量查询结果，容量满时淘汰最久未使用的条目
+type resultCache struct {
+	mu      sync.Mutex
+	order   *list.List // 元素为 *cachedResult，表头为最近使用
+	entries map[string]*list.Element
+}
+
+func newResultCache() *resultCache {
+	return &resultCache{order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// get 取出与当前数据版本一致的缓存结果，版本过期的条目直接丢弃
+func (c *resultCache) get(sqlStr string) (*cachedResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[sqlStr]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*cachedResult)
+	if entry.version != dataVersion.Load() {
+		c.order.Remove(elem)
+		delete(c.entries, sqlStr)
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return entry, true
+}
+
+// put 保存查询结果；查询期间数据版本发生变化（如 SQL 本身修改了数据）时不缓存，内存紧张时清空缓存且不再缓存
+func (c *resultCache) put(entry *cachedResult) {
+	if len(entry.data) > maxCachedRows || entry.version != dataVersion.Load() {
+		return
+	}
+	if memoryUnderPressure() {
+		c.clear()
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[entry.sql]; ok {
+		c.order.Remove(elem)
+	}
+	c.entries[entry.sql] = c.order.PushFront(entry)
+	for c.order.Len() > maxCachedQueries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedResult).sql)
+	}
+}
+
+// len 当前缓存的查询数
+func (c *resultCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// clear 清空缓存
+func (c *resultCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	c.entries = make(map[string]*list.Element)
+}
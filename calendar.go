@@ -0,0 +1,265 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxCalendarDays 生成日历表允许的最大天数（约 200 年）
+const maxCalendarDays = 200 * 366
+
+// calendarColumns 日历表的列
+var calendarColumns = []referenceColumn{
+	{"date", "TEXT", "日期"},
+	{"year", "INTEGER", "年"},
+	{"quarter", "INTEGER", "季度"},
+	{"month", "INTEGER", "月"},
+	{"year_month", "TEXT", "年月"},
+	{"day", "INTEGER", "日"},
+	{"weekday", "INTEGER", "星期（1=周一）"},
+	{"weekday_name", "TEXT", "星期"},
+	{"iso_year", "INTEGER", "ISO 周所属年"},
+	{"iso_week", "INTEGER", "ISO 周数"},
+	{"is_weekend", "INTEGER", "是否周末"},
+	{"is_holiday", "INTEGER", "是否节假日"},
+	{"holiday_name", "TEXT", "节假日名称"},
+	{"is_workday", "INTEGER", "是否工作日"},
+}
+
+var weekdayNames = []string{"周日", "周一", "周二", "周三", "周四", "周五", "周六"}
+
+// holiday 节假日设置中的一天
+type holiday struct {
+	name      string
+	isWorkday bool
+}
+
+// 内置法定节假日安排覆盖的年份范围
+const (
+	builtinHolidayFrom = 2023
+	builtinHolidayTo   = 2026
+)
+
+// builtinHolidayData 国务院办公厅公布的法定节假日安排：放假日期（或 起~止）|名称，调休上班日以 |上班 结尾
+var builtinHolidayData = []string{
+	"2022-12-31~2023-01-02|元旦",
+	"2023-01-21~2023-01-27|春节", "2023-01-28|春节|上班", "2023-01-29|春节|上班",
+	"2023-04-05|清明节",
+	"2023-04-29~2023-05-03|劳动节", "2023-04-23|劳动节|上班", "2023-05-06|劳动节|上班",
+	"2023-06-22~2023-06-24|端午节", "2023-06-25|端午节|上班",
+	"2023-09-29~2023-10-06|中秋节、国庆节", "2023-10-07|国庆节|上班", "2023-10-08|国庆节|上班",
+
+	"2024-01-01|元旦",
+	"2024-02-10~2024-02-17|春节", "2024-02-04|春节|上班", "2024-02-18|春节|上班",
+	"2024-04-04~2024-04-06|清明节", "2024-04-07|清明节|上班",
+	"2024-05-01~2024-05-05|劳动节", "2024-04-28|劳动节|上班", "2024-05-11|劳动节|上班",
+	"2024-06-10|端午节",
+	"2024-09-15~2024-09-17|中秋节", "2024-09-14|中秋节|上班",
+	"2024-10-01~2024-10-07|国庆节", "2024-09-29|国庆节|上班", "2024-10-12|国庆节|上班",
+
+	"2025-01-01|元旦",
+	"2025-01-28~2025-02-04|春节", "2025-01-26|春节|上班", "2025-02-08|春节|上班",
+	"2025-04-04~2025-04-06|清明节",
+	"2025-05-01~2025-05-05|劳动节", "2025-04-27|劳动节|上班",
+	"2025-05-31~2025-06-02|端午节",
+	"2025-10-01~2025-10-08|国庆节、中秋节", "2025-09-28|国庆节|上班", "2025-10-11|国庆节|上班",
+
+	"2026-01-01~2026-01-03|元旦", "2026-01-04|元旦|上班",
+	"2026-02-15~2026-02-23|春节", "2026-02-14|春节|上班", "2026-02-28|春节|上班",
+	"2026-04-04~2026-04-06|清明节",
+	"2026-05-01~2026-05-05|劳动节", "2026-05-09|劳动节|上班",
+	"2026-06-19~2026-06-21|端午节",
+	"2026-09-25~2026-09-27|中秋节",
+	"2026-10-01~2026-10-07|国庆节", "2026-09-20|国庆节|上班", "2026-10-10|国庆节|上班",
+}
+
+// builtinHolidays 展开内置的法定节假日安排：日期 -> 节假日/调休
+func builtinHolidays() map[string]holiday {
+	result := make(map[string]holiday)
+	for _, line := range builtinHolidayData {
+		parts := strings.Split(line, "|")
+		h := holiday{name: parts[1], isWorkday: len(parts) > 2}
+		first, last, _ := strings.Cut(parts[0], "~")
+		if last == "" {
+			last = first
+		}
+		from, _ := time.Parse("2006-01-02", first)
+		to, _ := time.Parse("2006-01-02", last)
+		for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+			result[d.Format("2006-01-02")] = h
+		}
+	}
+	return result
+}
+
+// holidays 节假日和调休：日期 -> 节假日/调休；内置的法定节假日安排在前，节假日设置中的日期覆盖内置安排
+func (a *App) holidays() (map[string]holiday, error) {
+	rows, err := a.readDB().Query("SELECT date, name, is_workday FROM _app_holidays")
+	if err != nil {
+		return nil, fmt.Errorf("读取节假日设置失败: %v", err)
+	}
+	defer rows.Close()
+	result := builtinHolidays()
+	for rows.Next() {
+		var date string
+		var h holiday
+		if err := rows.Scan(&date, &h.name, &h.isWorkday); err != nil {
+			return nil, fmt.Errorf("读取节假日设置失败: %v", err)
+		}
+		result[date] = h
+	}
+	return result, rows.Err()
+}
+
+// yearsWithoutHolidays from 到 to 之间既不在内置安排范围内、节假日设置中也没有任何日期的年份，
+// 这些年份的日历只能按周末标注工作日
+func (a *App) yearsWithoutHolidays(from, to time.Time) ([]int, error) {
+	rows, err := a.readDB().Query("SELECT DISTINCT CAST(substr(date, 1, 4) AS INTEGER) FROM _app_holidays")
+	if err != nil {
+		return nil, fmt.Errorf("读取节假日设置失败: %v", err)
+	}
+	defer rows.Close()
+	configured := make(map[int]bool)
+	for rows.Next() {
+		var year int
+		if err := rows.Scan(&year); err != nil {
+			return nil, fmt.Errorf("读取节假日设置失败: %v", err)
+		}
+		configured[year] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("读取节假日设置失败: %v", err)
+	}
+	var missing []int
+	for year := from.Year(); year <= to.Year(); year++ {
+		if !configured[year] && (year < builtinHolidayFrom || year > builtinHolidayTo) {
+			missing = append(missing, year)
+		}
+	}
+	return missing, nil
+}
+
+// calendarRows 生成 from 到 to（含）的逐日日历行；工作日为周一至周五，节假日设置中的日期按设置覆盖
+func (a *App) calendarRows(from, to time.Time) ([][]interface{}, error) {
+	holidays, err := a.holidays()
+	if err != nil {
+		return nil, err
+	}
+	var rows [][]interface{}
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		weekday := int(d.Weekday())
+		isoWeekday := weekday
+		if isoWeekday == 0 {
+			isoWeekday = 7
+		}
+		isoYear, week := d.ISOWeek()
+		weekend := isoWeekday >= 6
+		workday := !weekend
+		date := d.Format("2006-01-02")
+		isHoliday := false
+		var holidayName interface{}
+		if h, ok := holidays[date]; ok {
+			workday = h.isWorkday
+			isHoliday = !h.isWorkday
+			if h.name != "" {
+				holidayName = h.name
+			}
+		}
+		rows = append(rows, []interface{}{
+			date, d.Year(), (int(d.Month())-1)/3 + 1, int(d.Month()), d.Format("2006-01"), d.Day(),
+			isoWeekday, weekdayNames[weekday], isoYear, week, weekend, isHoliday, holidayName, workday,
+		})
+	}
+	return rows, nil
+}
+
+// parseCalendarDate 解析日历起止日期（支持常见日期写法）
+func parseCalendarDate(s string) (time.Time, error) {
+	t, err := time.Parse("2006-01-02", normalizeDate(s))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("日期格式错误: %s", s)
+	}
+	return t, nil
+}
+
+// GenerateCalendarTable 生成日期维度表 calendar（年、季度、月、周、星期、节假日/工作日标记），
+// 按日期与业务表关联即可按时间分组，无需在每个查询中写 strftime；此前生成的 calendar 重新生成，
+// 同名的用户表或视图不会被覆盖。节假日和调休按内置的法定节假日安排和节假日设置标注，没有节假日数据的年份在结果中提示
+// wails:export GenerateCalendarTable
+func (a *App) GenerateCalendarTable(start string, end string) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	from, err := parseCalendarDate(start)
+	if err != nil {
+		return err.Error()
+	}
+	to, err := parseCalendarDate(end)
+	if err != nil {
+		return err.Error()
+	}
+	if to.Before(from) {
+		return "结束日期不能早于开始日期"
+	}
+	if days := int(to.Sub(from).Hours()/24) + 1; days > maxCalendarDays {
+		return fmt.Sprintf("日期范围过大（%d 天），最多 %d 天", days, maxCalendarDays)
+	}
+
+	missing, err := a.yearsWithoutHolidays(from, to)
+	if err != nil {
+		return err.Error()
+	}
+
+	ref := referenceTable{
+		name:        "calendar",
+		generator:   generatorCalendar,
+		label:       "日历",
+		description: fmt.Sprintf("%s 至 %s 的日期维度表", from.Format("2006-01-02"), to.Format("2006-01-02")),
+		columns:     calendarColumns,
+		rows: func(a *App) ([][]interface{}, error) {
+			return a.calendarRows(from, to)
+		},
+	}
+	n, err := a.installReferenceTable(ref)
+	if err != nil {
+		return err.Error()
+	}
+	message := fmt.Sprintf("已生成日历表 calendar：%s 至 %s，共 %d 天", from.Format("2006-01-02"), to.Format("2006-01-02"), n)
+	if len(missing) > 0 {
+		years := make([]string, len(missing))
+		for i, year := range missing {
+			years[i] = fmt.Sprint(year)
+		}
+		message += fmt.Sprintf("；%s 年没有节假日数据，工作日只按周末标注，请先用 SetHoliday 设置节假日和调休后重新生成", strings.Join(years, "、"))
+	}
+	return message
+}
+
+// SetHoliday 设置节假日或调休（覆盖内置的法定节假日安排）：isWorkday 为 true 表示该日调休上班；name 为空且 isWorkday 为 false 时删除该日设置
+// 修改后需重新生成日历表才会生效
+// wails:export SetHoliday
+func (a *App) SetHoliday(date string, name string, isWorkday bool) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	d, err := parseCalendarDate(date)
+	if err != nil {
+		return err.Error()
+	}
+	key := d.Format("2006-01-02")
+	if name == "" && !isWorkday {
+		if _, err := a.writeDB().Exec("DELETE FROM _app_holidays WHERE date = ?", key); err != nil {
+			return fmt.Sprintf("删除节假日设置失败: %v", err)
+		}
+		return fmt.Sprintf("已删除 %s 的节假日设置", key)
+	}
+	if _, err := a.writeDB().Exec(`INSERT INTO _app_holidays (date, name, is_workday) VALUES (?, ?, ?)
+		ON CONFLICT(date) DO UPDATE SET name = excluded.name, is_workday = excluded.is_workday`, key, name, isWorkday); err != nil {
+		return fmt.Sprintf("保存节假日设置失败: %v", err)
+	}
+	if isWorkday {
+		return fmt.Sprintf("已将 %s 设为调休工作日", key)
+	}
+	return fmt.Sprintf("已将 %s 设为节假日 %s", key, name)
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// cleanViewSuffix 清洗视图名后缀：sheet1 的清洗视图为 sheet1_clean
+const cleanViewSuffix = "_clean"
+
+// cleanColumn 清洗视图中的一列：kind 为推断出的类型（number/date/text），expr 为清洗表达式
+type cleanColumn struct {
+	column string
+	kind   string
+	expr   string
+}
+
+// parseNumberFunc SQL 函数 PARSE_NUMBER(value)：去掉首尾空白和千分位逗号后解析数字（含中文数字），
+// 整数返回 INTEGER，无法解析时返回 NULL
+func parseNumberFunc(v interface{}) interface{} {
+	switch x := v.(type) {
+	case int64, float64:
+		return x
+	}
+	s, ok := udfText(v)
+	if !ok {
+		return nil
+	}
+	n, ok := parseNumberText(s)
+	if !ok {
+		return nil
+	}
+	if n == math.Trunc(n) && math.Abs(n) < 1<<53 {
+		return int64(n)
+	}
+	return n
+}
+
+// isCodeText 判断是否为以 0 开头的编码（如 00123、010-8888），这类值即使能解析为数字也按文本处理
+func isCodeText(s string) bool {
+	return len(s) > 1 && s[0] == '0' && s[1] != '.'
+}
+
+// inferCleanKind 抽样检查列的非空值（去掉首尾空白后）：可解析为数字的比例不低于 minRatio 时为 number，
+// 可识别为日期的比例不低于 minRatio 时为 date，否则为 text
+func (a *App) inferCleanKind(tableName string, column string, minRatio float64) (string, error) {
+	q := quoteIdent(column)
+	rows, err := a.readDB().Query(fmt.Sprintf("SELECT %s FROM %s WHERE %s IS NOT NULL AND TRIM(%s) <> '' LIMIT %d",
+		q, quoteIdent(tableName), q, q, columnTypeSampleRows))
+	if err != nil {
+		return "", fmt.Errorf("读取列 %s 的取值失败: %v", column, err)
+	}
+	defer rows.Close()
+	numbers, dates, total := 0, 0, 0
+	for rows.Next() {
+		var v interface{}
+		if err := rows.Scan(&v); err != nil {
+			return "", fmt.Errorf("读取列 %s 的取值失败: %v", column, err)
+		}
+		total++
+		switch x := v.(type) {
+		case int64, float64:
+			numbers++
+			continue
+		case []byte:
+			v = string(x)
+		}
+		text := strings.TrimSpace(sqlValueText(v))
+		if _, ok := parseNumberText(text); ok && !isCodeText(text) {
+			numbers++
+		} else if _, _, ok := parseDateText(text); ok {
+			dates++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("读取列 %s 的取值失败: %v", column, err)
+	}
+	switch {
+	case total > 0 && float64(numbers) >= minRatio*float64(total):
+		return "number", nil
+	case total > 0 && float64(dates) >= minRatio*float64(total):
+		return "date", nil
+	}
+	return "text", nil
+}
+
+// cleanColumns 生成表各列的清洗表达式：数字列转为数值，日期列转为 ISO 日期，文本列去掉首尾空白、空串视为 NULL；
+// 软删除标记和整行哈希等内部列不出现在视图中
+func (a *App) cleanColumns(tableName string) ([]cleanColumn, error) {
+	columns, err := a.tableColumns(tableName)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]cleanColumn, 0, len(columns))
+	for _, col := range columns {
+		if col == deletedFlagColumn || col == rowHashColumn {
+			continue
+		}
+		kind, err := a.inferCleanKind(tableName, col, 1)
+		if err != nil {
+			return nil, err
+		}
+		q := quoteIdent(col)
+		c := cleanColumn{column: col, kind: kind}
+		switch kind {
+		case "number":
+			c.expr = fmt.Sprintf("PARSE_NUMBER(%s)", q)
+		case "date":
+			c.expr = fmt.Sprintf("PARSE_DATE(TRIM(%s))", q)
+		default:
+			c.expr = fmt.Sprintf("NULLIF(TRIM(%s), '')", q)
+		}
+		result = append(result, c)
+	}
+	return result, nil
+}
+
+// createCleanView 创建（或重建）表的清洗视图 <表名>_clean，列名与原表相同，原始数据不做任何修改；软删除的行不出现在视图中
+func (a *App) createCleanView(tableName string) (string, []cleanColumn, error) {
+	viewName := tableName + cleanViewSuffix
+	var kind string
+	if a.readDB().QueryRow("SELECT type FROM sqlite_master WHERE name = ?", viewName).Scan(&kind) == nil && kind != "view" {
+		return "", nil, fmt.Errorf("已存在名为 %s 的表，无法创建清洗视图", viewName)
+	}
+	columns, err := a.cleanColumns(tableName)
+	if err != nil {
+		return "", nil, err
+	}
+	items := make([]string, len(columns))
+	for i, c := range columns {
+		items[i] = fmt.Sprintf("%s AS %s", c.expr, quoteIdent(c.column))
+	}
+	where := ""
+	if all, _ := a.tableColumns(tableName); containsString(all, deletedFlagColumn) {
+		where = fmt.Sprintf(" WHERE %s = 0", quoteIdent(deletedFlagColumn))
+	}
+	if _, err := a.writeDB().Exec(fmt.Sprintf("DROP VIEW IF EXISTS %s", quoteIdent(viewName))); err != nil {
+		return "", nil, fmt.Errorf("删除旧视图 %s 失败: %v", viewName, err)
+	}
+	ddl := fmt.Sprintf("CREATE VIEW %s AS SELECT %s FROM %s%s", quoteIdent(viewName), strings.Join(items, ", "), quoteIdent(tableName), where)
+	if _, err := a.writeDB().Exec(ddl); err != nil {
+		return "", nil, fmt.Errorf("创建视图 %s 失败: %v", viewName, err)
+	}
+	return viewName, columns, nil
+}
+
+// CreateCleanView 为表创建清洗视图 <表名>_clean：按抽样推断每列类型，数字列去掉千分位并转为数值，日期列统一为 ISO 日期，
+// 文本列去掉首尾空白；原表保持原样，原始数据和清洗后的数据都可查询（开启 clean_views 设置时导入和刷新后自动创建）
+// 返回 view 和 columns（[{column, kind, expression}]）
+// wails:export CreateCleanView
+func (a *App) CreateCleanView(tableName string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	if a.readOnly() {
+		result["error"] = readOnlyMessage
+		return result
+	}
+	viewName, columns, err := a.createCleanView(tableName)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	list := make([]map[string]string, len(columns))
+	counts := map[string]int{}
+	for i, c := range columns {
+		list[i] = map[string]string{"column": c.column, "kind": c.kind, "expression": c.expr}
+		counts[c.kind]++
+	}
+	result["view"] = viewName
+	result["columns"] = list
+	result["message"] = fmt.Sprintf("已创建清洗视图 %s：%d 列转为数值，%d 列转为日期，%d 列去除首尾空白",
+		viewName, counts["number"], counts["date"], counts["text"])
+	return result
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// closeWaitTimeout 关闭窗口时等待已取消的操作回滚事务、关闭文件的最长时间
+const closeWaitTimeout = 10 * time.Second
+
+// closePromptItems 关闭确认框中最多列出的操作数
+const closePromptItems = 5
+
+// operationKindNames 关闭确认框中各类操作的名称
+var operationKindNames = map[string]string{
+	"import":      "导入",
+	"export":      "导出",
+	"profile":     "表概况",
+	"maintenance": "维护",
+	"benchmark":   "性能自检",
+	"query":       "查询",
+}
+
+// BeforeClose 窗口关闭前执行（Wails OnBeforeClose）：有查询、导入、导出等操作正在进行时请用户确认，
+// 确认关闭则取消这些操作并等待其回滚事务、关闭文件后再退出，返回 true 表示保留窗口
+func (a *App) BeforeClose(ctx context.Context) bool {
+	ops := activeOperations.list()
+	if len(ops) == 0 {
+		return false
+	}
+	var lines []string
+	for i, op := range ops {
+		if i == closePromptItems {
+			lines = append(lines, fmt.Sprintf("……等共 %d 个操作", len(ops)))
+			break
+		}
+		title := []rune(op.title)
+		if len(title) > 60 {
+			title = append(title[:60], []rune("…")...)
+		}
+		lines = append(lines, fmt.Sprintf("%s：%s（已进行 %s）", operationKindNames[op.kind], string(title),
+			time.Since(op.startedAt).Round(time.Second)))
+	}
+	choice, err := runtime.MessageDialog(ctx, runtime.MessageDialogOptions{
+		Type:    runtime.QuestionDialog,
+		Title:   "仍有操作正在进行",
+		Message: strings.Join(lines, "\n") + "\n\n关闭窗口将取消这些操作，已写入一半的导入会回滚。确定要关闭吗？",
+	})
+	if err != nil || choice != "Yes" {
+		return true
+	}
+	activeOperations.cancelAll()
+	if !activeOperations.wait(closeWaitTimeout) {
+		fmt.Println("部分操作未能在关闭前结束")
+	}
+	return false
+}
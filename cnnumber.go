@@ -0,0 +1,268 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+var cnDigits = map[rune]float64{
+	'零': 0, '〇': 0, '○': 0,
+	'一': 1, '壹': 1, '幺': 1,
+	'二': 2, '贰': 2, '貳': 2, '两': 2, '兩': 2,
+	'三': 3, '叁': 3, '參': 3,
+	'四': 4, '肆': 4,
+	'五': 5, '伍': 5,
+	'六': 6, '陆': 6, '陸': 6,
+	'七': 7, '柒': 7,
+	'八': 8, '捌': 8,
+	'九': 9, '玖': 9,
+}
+
+var cnSmallUnits = map[rune]float64{
+	'十': 10, '拾': 10,
+	'百': 100, '佰': 100,
+	'千': 1000, '仟': 1000,
+}
+
+// parseChineseNumber 将中文数字（含大写数字及与阿拉伯数字混写）解析为数值
+// 支持 "一万二千"、"3.5万"、"壹仟贰佰元"、"负十二点五"、"二〇二三"、"1,234" 等写法
+func parseChineseNumber(s string) (float64, bool) {
+	s = trimChineseAmount(s)
+	if s == "" {
+		return 0, false
+	}
+
+	sign := 1.0
+	if strings.HasPrefix(s, "负") || strings.HasPrefix(s, "-") {
+		sign = -1
+		s = strings.TrimPrefix(strings.TrimPrefix(s, "负"), "-")
+	}
+
+	if v, err := strconv.ParseFloat(s, 64); err == nil {
+		return sign * v, true
+	}
+
+	runes := []rune(s)
+
+	// 不含单位的中文数字按位读出，如 "二〇二三"
+	hasUnit := false
+	for _, r := range runes {
+		if _, ok := cnSmallUnits[r]; ok || r == '万' || r == '萬' || r == '亿' || r == '億' {
+			hasUnit = true
+			break
+		}
+	}
+	if !hasUnit {
+		var sb strings.Builder
+		for _, r := range runes {
+			if d, ok := cnDigits[r]; ok {
+				sb.WriteByte(byte('0' + int(d)))
+			} else if r == '点' || r == '點' {
+				sb.WriteByte('.')
+			} else {
+				return 0, false
+			}
+		}
+		v, err := strconv.ParseFloat(sb.String(), 64)
+		if err != nil {
+			return 0, false
+		}
+		return sign * v, true
+	}
+
+	total, section, number := 0.0, 0.0, 0.0
+	seenDigit := false
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r >= '0' && r <= '9' || r == '.':
+			j := i
+			for j < len(runes) && (runes[j] >= '0' && runes[j] <= '9' || runes[j] == '.') {
+				j++
+			}
+			v, err := strconv.ParseFloat(string(runes[i:j]), 64)
+			if err != nil {
+				return 0, false
+			}
+			number = v
+			seenDigit = true
+			i = j - 1
+		case r == '点' || r == '點':
+			// 小数部分，如 "十二点五"
+			frac := "0."
+			for j := i + 1; j < len(runes); j++ {
+				d, ok := cnDigits[runes[j]]
+				if !ok {
+					return 0, false
+				}
+				frac += strconv.Itoa(int(d))
+			}
+			v, _ := strconv.ParseFloat(frac, 64)
+			number += v
+			i = len(runes)
+		default:
+			if d, ok := cnDigits[r]; ok {
+				number = d
+				seenDigit = true
+			} else if unit, ok := cnSmallUnits[r]; ok {
+				// "十二" 中省略的 "一"
+				if number == 0 && unit == 10 {
+					number = 1
+					seenDigit = true
+				}
+				section += number * unit
+				number = 0
+			} else if r == '万' || r == '萬' {
+				section = (section + number) * 1e4
+				number = 0
+			} else if r == '亿' || r == '億' {
+				total = (total + section + number) * 1e8
+				section, number = 0, 0
+			} else {
+				return 0, false
+			}
+		}
+	}
+	if !seenDigit {
+		return 0, false
+	}
+	return sign * (total + section + number), true
+}
+
+// trimChineseAmount 去掉数字中的空白、千分位逗号和金额后缀（"元"、"元整"）
+func trimChineseAmount(s string) string {
+	s = strings.NewReplacer(" ", "", ",", "", "，", "", "　", "").Replace(strings.TrimSpace(s))
+	s = strings.TrimSuffix(s, "整")
+	return strings.TrimSuffix(s, "元")
+}
+
+// cnZeros 按位写法中的零
+var cnZeros = map[rune]bool{'零': true, '〇': true, '○': true}
+
+// cnBigUnits 分节单位
+var cnBigUnits = map[rune]float64{'万': 1e4, '萬': 1e4, '亿': 1e8, '億': 1e8}
+
+// wellFormedChineseNumber 整个单元格是否为规范的中文数字：带单位的写法（如 "一万二千"、"3.5万"、"壹仟贰佰元整"、"负十二点五"），
+// 或含零的按位写法（如 "二〇二三"、"零点五"）。单个字和 "万一"、"一一"、"一五一十"、"三三两两" 这样的普通文字不算
+func wellFormedChineseNumber(s string) bool {
+	s = strings.TrimPrefix(trimChineseAmount(s), "负")
+	runes := []rune(s)
+	if len(runes) < 2 {
+		return false
+	}
+	hasUnit := false
+	for _, r := range runes {
+		if _, ok := cnSmallUnits[r]; ok {
+			hasUnit = true
+		} else if _, ok := cnBigUnits[r]; ok {
+			hasUnit = true
+		}
+	}
+	if !hasUnit {
+		return wellFormedPositional(runes)
+	}
+
+	// 带单位：每个单位前是一个数字（开头的 "十" 可省略 "一"），同一节内单位从大到小，零只用于补位
+	lastSmall, lastBig := 1e4, 1e9
+	digit, section, zero := false, false, false
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch small, isSmall := cnSmallUnits[r]; {
+		case r >= '0' && r <= '9' || r == '.':
+			if digit {
+				return false
+			}
+			for i+1 < len(runes) && (runes[i+1] >= '0' && runes[i+1] <= '9' || runes[i+1] == '.') {
+				i++
+			}
+			digit = true
+		case cnZeros[r]:
+			if digit || zero {
+				return false
+			}
+			zero = true
+		case isSmall:
+			if !digit && !(small == 10 && i == 0) || small >= lastSmall {
+				return false
+			}
+			lastSmall, digit, section, zero = small, false, true, false
+		case cnBigUnits[r] > 0:
+			big := cnBigUnits[r]
+			if !digit && !section || big >= lastBig || zero {
+				return false
+			}
+			lastBig, lastSmall, digit, section = big, 1e4, false, false
+		case r == '点' || r == '點':
+			return (digit || section && lastSmall == 10) && wellFormedFraction(runes[i+1:])
+		default:
+			if _, ok := cnDigits[r]; !ok || digit {
+				return false
+			}
+			digit, zero = true, false
+		}
+	}
+	return !zero
+}
+
+// wellFormedPositional 不带单位的按位写法：整数部分须含零且不以零开头（如 "二〇二三"），
+// 或为一位数加小数（如 "三点五"、"零点五"）
+func wellFormedPositional(runes []rune) bool {
+	integer := runes
+	var fraction []rune
+	for i, r := range runes {
+		if r == '点' || r == '點' {
+			integer, fraction = runes[:i], runes[i+1:]
+			if !wellFormedFraction(fraction) {
+				return false
+			}
+			break
+		}
+	}
+	hasZero := false
+	for _, r := range integer {
+		if _, ok := cnDigits[r]; !ok || r == '两' || r == '兩' {
+			return false
+		}
+		hasZero = hasZero || cnZeros[r]
+	}
+	if fraction != nil {
+		return len(integer) == 1 || len(integer) > 1 && hasZero && !cnZeros[integer[0]]
+	}
+	return len(integer) >= 3 && hasZero && !cnZeros[integer[0]]
+}
+
+// wellFormedFraction 小数部分：至少一位，逐位读出
+func wellFormedFraction(runes []rune) bool {
+	if len(runes) == 0 {
+		return false
+	}
+	for _, r := range runes {
+		if _, ok := cnDigits[r]; !ok || r == '两' || r == '兩' {
+			return false
+		}
+	}
+	return true
+}
+
+// cnNumberFunc SQL 函数 CN_NUMBER(text)：无法解析时返回 NULL
+func cnNumberFunc(v interface{}) interface{} {
+	s, ok := udfText(v)
+	if !ok {
+		return nil
+	}
+	if n, ok := parseChineseNumber(s); ok {
+		return n
+	}
+	return nil
+}
+
+// normalizeChineseNumber 导入时的规范化：整个单元格是规范的中文数字（见 wellFormedChineseNumber）时转为阿拉伯数字，其余原样返回
+func normalizeChineseNumber(s string) string {
+	if !wellFormedChineseNumber(s) {
+		return s
+	}
+	if n, ok := parseChineseNumber(s); ok {
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	}
+	return s
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// columnAffinity 按 SQLite 的规则由声明类型得到列亲和性：INTEGER、TEXT、BLOB、REAL、NUMERIC
+func columnAffinity(declType string) string {
+	t := strings.ToUpper(declType)
+	switch {
+	case strings.Contains(t, "INT"):
+		return "INTEGER"
+	case strings.Contains(t, "CHAR"), strings.Contains(t, "CLOB"), strings.Contains(t, "TEXT"):
+		return "TEXT"
+	case t == "" || strings.Contains(t, "BLOB"):
+		return "BLOB"
+	case strings.Contains(t, "REAL"), strings.Contains(t, "FLOA"), strings.Contains(t, "DOUB"):
+		return "REAL"
+	default:
+		return "NUMERIC"
+	}
+}
+
+// parseNumberText 解析数字文本，允许千分位逗号、首尾空白和规范的中文数字（见 wellFormedChineseNumber）
+func parseNumberText(s string) (float64, bool) {
+	s = strings.TrimSpace(strings.ReplaceAll(s, ",", ""))
+	if v, err := strconv.ParseFloat(s, 64); err == nil {
+		return v, true
+	}
+	if !wellFormedChineseNumber(s) {
+		return 0, false
+	}
+	return parseChineseNumber(s)
+}
+
+// coerceValue 将导入的文本转换为目标列类型的值，无法转换时返回错误
+// 声明类型含 DATE/TIME 的列统一转为 ISO 日期文本，含 BOOL 的列按布尔词表转为 0/1
+func coerceValue(s string, declType string, emptyAsNull bool) (interface{}, error) {
+	upper := strings.ToUpper(declType)
+	affinity := columnAffinity(declType)
+	isText := affinity == "TEXT" || affinity == "BLOB"
+	if isText && s == "" {
+		if emptyAsNull {
+			return nil, nil
+		}
+		return "", nil
+	}
+	if !isText && strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	switch {
+	case strings.Contains(upper, "DATE") || strings.Contains(upper, "TIME"):
+		if t, hasTime, ok := parseDateText(s); ok {
+			return formatISODate(t, hasTime), nil
+		}
+		return nil, fmt.Errorf("无法转换为日期")
+	case strings.Contains(upper, "BOOL"):
+		key := booleanKey(s)
+		if booleanTrueValues[key] {
+			return int64(1), nil
+		}
+		if booleanFalseValues[key] {
+			return int64(0), nil
+		}
+		return nil, fmt.Errorf("无法转换为布尔值")
+	}
+
+	switch affinity {
+	case "INTEGER":
+		v, ok := parseNumberText(s)
+		if !ok {
+			return nil, fmt.Errorf("无法转换为整数")
+		}
+		if v != math.Trunc(v) || math.Abs(v) > 1<<53 {
+			return nil, fmt.Errorf("不是整数")
+		}
+		return int64(v), nil
+	case "REAL", "NUMERIC":
+		v, ok := parseNumberText(s)
+		if !ok {
+			return nil, fmt.Errorf("无法转换为数字")
+		}
+		if affinity == "NUMERIC" && v == math.Trunc(v) && math.Abs(v) <= 1<<53 {
+			return int64(v), nil
+		}
+		return v, nil
+	}
+	return s, nil
+}
+
+// convertibleTypes ConvertColumnType 支持的目标类型
+var convertibleTypes = []string{"INTEGER", "REAL", "TEXT", "DATE", "DATETIME", "BOOLEAN"}
+
+// ConvertColumnType 将列转换为指定类型：可转换的值按新类型改写，
+// 无法转换的行整行移入 <表名>_rejected 隔离表，修正后可用 ReapplyRejectedRows 写回
+// wails:export ConvertColumnType
+func (a *App) ConvertColumnType(tableName string, column string, targetType string) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	targetType = strings.ToUpper(strings.TrimSpace(targetType))
+	if !containsString(convertibleTypes, targetType) {
+		return fmt.Sprintf("不支持的目标类型: %s（可选 %s）", targetType, strings.Join(convertibleTypes, "、"))
+	}
+
+	tx, err := a.writeDB().Begin()
+	if err != nil {
+		return fmt.Sprintf("开启事务失败: %v", err)
+	}
+	defer tx.Rollback()
+
+	infos, err := tableColumnInfos(tx, tableName)
+	if err != nil {
+		return err.Error()
+	}
+	columns := make([]string, len(infos))
+	colIdx := -1
+	for i, info := range infos {
+		columns[i] = info.name
+		if info.name == column {
+			colIdx = i
+		}
+	}
+	if colIdx < 0 {
+		return fmt.Sprintf("表 %s 中不存在列 %s", tableName, column)
+	}
+
+	rows, err := tx.Query(fmt.Sprintf("SELECT rowid, * FROM %s", quoteIdent(tableName)))
+	if err != nil {
+		return fmt.Sprintf("读取数据失败: %v", err)
+	}
+	type converted struct {
+		rowid int64
+		value interface{}
+	}
+	var updates []converted
+	var rejects []rejectedRow
+	var rejectedIDs []int64
+	emptyAsNull := a.importEmptyAsNull()
+	for rows.Next() {
+		values := make([]interface{}, len(columns)+1)
+		ptrs := make([]interface{}, len(values))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			rows.Close()
+			return fmt.Sprintf("读取数据失败: %v", err)
+		}
+		rowid, _ := values[0].(int64)
+		current := values[colIdx+1]
+		if current == nil {
+			continue
+		}
+		text := sqlValueText(current)
+		v, err := coerceValue(text, targetType, emptyAsNull)
+		if err != nil {
+			raw := make([]string, len(columns))
+			for i := range columns {
+				raw[i] = sqlValueText(values[i+1])
+			}
+			rejects = append(rejects, rejectedRow{row: int(rowid), raw: raw, errors: []coercionError{{row: int(rowid), column: column, value: text, err: err}}})
+			rejectedIDs = append(rejectedIDs, rowid)
+			continue
+		}
+		updates = append(updates, converted{rowid: rowid, value: v})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Sprintf("遍历数据失败: %v", err)
+	}
+
+	update := fmt.Sprintf("UPDATE %s SET %s = ? WHERE rowid = ?", quoteIdent(tableName), quoteIdent(column))
+	for _, u := range updates {
+		if _, err := tx.Exec(update, u.value, u.rowid); err != nil {
+			return fmt.Sprintf("转换数据失败: %v", err)
+		}
+	}
+	source := fmt.Sprintf("类型转换 %s -> %s", column, targetType)
+	if err := quarantineRows(tx, tableName, source, columns, rejects); err != nil {
+		return err.Error()
+	}
+	for _, id := range rejectedIDs {
+		if _, err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE rowid = ?", quoteIdent(tableName)), id); err != nil {
+			return fmt.Sprintf("删除无法转换的行失败: %v", err)
+		}
+	}
+	if err := rebuildTable(tx, tableName, map[string]string{column: targetType}, nil); err != nil {
+		return err.Error()
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Sprintf("提交事务失败: %v", err)
+	}
+
+	message := fmt.Sprintf("已将表 %s 的列 %s 转为 %s", tableName, column, targetType)
+	if len(rejects) > 0 {
+		message += fmt.Sprintf("，%d 行无法转换已移入隔离表 %s", len(rejects), rejectedTableName(tableName))
+	}
+	return message
+}
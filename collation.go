@@ -0,0 +1,34 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/width"
+)
+
+// pinyinCompare PINYIN 排序规则：按 GB18030 编码比较
+// GB2312 一级汉字（3755 个常用字）按拼音排列，二级汉字按部首排列，ASCII 字符排在汉字之前
+func pinyinCompare(a, b string) int {
+	return bytes.Compare(pinyinKey(a), pinyinKey(b))
+}
+
+// pinyinKey 生成排序键，无法编码时退回原始 UTF-8 字节
+func pinyinKey(s string) []byte {
+	key, err := simplifiedchinese.GB18030.NewEncoder().Bytes([]byte(s))
+	if err != nil {
+		return []byte(s)
+	}
+	return key
+}
+
+// foldCompare FOLD 排序规则：忽略大小写和全角/半角差异，使 "ABC"、"ＡＢＣ"、"abc" 相等
+func foldCompare(a, b string) int {
+	return strings.Compare(foldKey(a), foldKey(b))
+}
+
+// foldKey 全角字符转半角后统一转为小写
+func foldKey(s string) string {
+	return strings.ToLower(width.Fold.String(s))
+}
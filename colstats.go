@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+)
+
+// maxStatsEntries 列统计缓存的最大条目数，超出时清空重新累积
+const maxStatsEntries = 500
+
+// 列取值列表（筛选下拉框）的默认和最大条数
+const (
+	columnValuesDefaultLimit = 200
+	columnValuesMaxLimit     = 1000
+)
+
+// statsEntry 一条缓存的统计结果及计算时表的数据版本
+type statsEntry struct {
+	version tableVersion
+	value   interface{}
+}
+
+// statsCache 按表的数据版本缓存列概况、取值分布和行数，表被修改后自动失效，
+// 避免反复计算概况、填充筛选下拉框和分析查询计划时重复扫描大表
+// version 返回表或视图当前的数据版本（见 App.statsVersion），无法确定时不缓存
+type statsCache struct {
+	mu      sync.Mutex
+	entries map[string]statsEntry
+	version func(table string) (tableVersion, bool)
+}
+
+func newStatsCache(version func(table string) (tableVersion, bool)) *statsCache {
+	return &statsCache{entries: make(map[string]statsEntry), version: version}
+}
+
+// get 取出与表当前数据版本一致的统计结果，版本过期的条目直接丢弃
+func (c *statsCache) get(table string, key string) (interface{}, bool) {
+	current, ok := c.version(table)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	k := table + "\x00" + key
+	entry, found := c.entries[k]
+	if !found {
+		return nil, false
+	}
+	if !ok || entry.version != current {
+		delete(c.entries, k)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// snapshot 开始计算前的表数据版本；无法确定版本或有数据提交尚未完成（读到的可能是提交前的快照）时返回 false，结果不缓存
+func (c *statsCache) snapshot(table string) (tableVersion, bool) {
+	version, ok := c.version(table)
+	return version, ok && commitsInFlight.Load() == 0
+}
+
+// put 保存统计结果，version 为开始计算前的数据版本；计算期间表被修改时不缓存
+func (c *statsCache) put(table string, key string, version tableVersion, value interface{}) {
+	if current, ok := c.version(table); !ok || version != current || memoryUnderPressure() {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= maxStatsEntries {
+		c.entries = make(map[string]statsEntry)
+	}
+	c.entries[table+"\x00"+key] = statsEntry{version: version, value: value}
+}
+
+// clear 清空缓存（切换数据库连接时调用）
+func (c *statsCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]statsEntry)
+}
+
+// statsVersion 统计结果依赖的数据版本。表取自身的版本；视图（如 _clean、_header 视图）自身没有写入，
+// 取来源表版本之和，各表版本只增不减，任一来源表被修改时和都会变化。无法确定来源时返回 false
+func (a *App) statsVersion(name string) (tableVersion, bool) {
+	var kind string
+	if err := a.readDB().QueryRow("SELECT type FROM sqlite_master WHERE name = ? AND type IN ('table', 'view')", name).Scan(&kind); err != nil {
+		return tableVersion{}, false
+	}
+	if kind == "table" {
+		return currentTableVersion(name), true
+	}
+	lineage, err := a.queryLineage("SELECT * FROM " + quoteIdent(name))
+	if err != nil || len(lineage) == 0 {
+		return tableVersion{}, false
+	}
+	version := currentTableVersion(name)
+	for table := range lineage {
+		version.version += currentTableVersion(table).version
+	}
+	return version, true
+}
+
+// statsCacheable 是否可以缓存统计结果：只读实例看不到另一个实例写入引起的版本变化，不缓存
+func (a *App) statsCacheable() bool {
+	return !a.readOnly() || a.viewer() != nil
+}
+
+// cachedRowCount 表的行数，数据未变化时使用缓存
+func (a *App) cachedRowCount(table string) (int64, error) {
+	cacheable := a.statsCacheable()
+	if cacheable {
+		if v, ok := a.stats.get(table, "rows"); ok {
+			return v.(int64), nil
+		}
+	}
+	version, settled := a.stats.snapshot(table)
+	var count int64
+	if err := a.readDB().QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s", quoteIdent(table))).Scan(&count); err != nil {
+		return 0, err
+	}
+	if cacheable && settled {
+		a.stats.put(table, "rows", version, count)
+	}
+	return count, nil
+}
+
+// copyResult 复制缓存的结果，调用方追加字段时不影响缓存
+func copyResult(result map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(result)+1)
+	for k, v := range result {
+		out[k] = v
+	}
+	return out
+}
+
+// GetColumnValues 列出列中出现次数最多的取值及次数，用于填充筛选下拉框：values 为 [{value, count}]，
+// truncated 表示还有更多取值未列出；表数据未变化时直接使用缓存，cached 为 true
+// wails:export GetColumnValues
+func (a *App) GetColumnValues(tableName string, column string, limit int) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	columns, err := a.tableColumns(tableName)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	from, columns := a.liveSource(tableName, columns)
+	if !containsString(columns, column) {
+		result["error"] = fmt.Sprintf("表 %s 中不存在列 %s", tableName, column)
+		return result
+	}
+	if limit <= 0 {
+		limit = columnValuesDefaultLimit
+	}
+	limit = min(limit, columnValuesMaxLimit)
+
+	key := fmt.Sprintf("values\x00%s\x00%d\x00%t", column, limit, a.includeDeleted.Load())
+	cacheable := a.statsCacheable()
+	if cacheable {
+		if v, ok := a.stats.get(tableName, key); ok {
+			result = copyResult(v.(map[string]interface{}))
+			result["cached"] = true
+			return result
+		}
+	}
+	version, settled := a.stats.snapshot(tableName)
+	q := quoteIdent(column)
+	rows, err := a.readDB().Query(fmt.Sprintf("SELECT %s, COUNT(*) AS n FROM %s GROUP BY %s ORDER BY n DESC, %s LIMIT %d",
+		q, from, q, q, limit+1))
+	if err != nil {
+		result["error"] = fmt.Sprintf("读取列 %s 的取值失败: %v", column, err)
+		return result
+	}
+	defer rows.Close()
+	values := []map[string]interface{}{}
+	for rows.Next() {
+		var value interface{}
+		var count int64
+		if err := rows.Scan(&value, &count); err != nil {
+			result["error"] = fmt.Sprintf("读取列 %s 的取值失败: %v", column, err)
+			return result
+		}
+		values = append(values, map[string]interface{}{"value": profileValue(value), "count": count})
+	}
+	if err := rows.Err(); err != nil {
+		result["error"] = fmt.Sprintf("读取列 %s 的取值失败: %v", column, err)
+		return result
+	}
+	truncated := len(values) > limit
+	if truncated {
+		values = values[:limit]
+	}
+	result["table"] = tableName
+	result["column"] = column
+	result["values"] = values
+	result["truncated"] = truncated
+	if cacheable && settled {
+		a.stats.put(tableName, key, version, copyResult(result))
+	}
+	result["cached"] = false
+	return result
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+)
+
+// columnTypeSampleRows 推断列类型时最多检查的行数
+const columnTypeSampleRows = 1000
+
+// describeColumns 返回每列的类型信息：
+// declType 为建表时声明的类型（表达式列为空），goType 为驱动扫描使用的 Go 类型，
+// inferredType 根据实际数据推断（integer/real/date/datetime/text/empty），供前端对齐数字、格式化日期
+// colTypes 须在遍历结果集之前通过 rows.ColumnTypes() 获取，获取失败时传 nil
+func describeColumns(colTypes []*sql.ColumnType, columns []string, data []map[string]interface{}) []map[string]interface{} {
+	declTypes := make([]string, len(columns))
+	goTypes := make([]string, len(columns))
+	if len(colTypes) == len(columns) {
+		for i, ct := range colTypes {
+			declTypes[i] = ct.DatabaseTypeName()
+			if st := ct.ScanType(); st != nil {
+				goTypes[i] = st.String()
+			}
+		}
+	}
+
+	sample := data
+	if len(sample) > columnTypeSampleRows {
+		sample = sample[:columnTypeSampleRows]
+	}
+
+	described := make([]map[string]interface{}, len(columns))
+	for i, col := range columns {
+		described[i] = map[string]interface{}{
+			"name":         col,
+			"declType":     declTypes[i],
+			"goType":       goTypes[i],
+			"inferredType": inferColumnType(sample, col),
+		}
+	}
+	return described
+}
+
+// inferColumnType 根据非空值推断列类型：全部为整数时为 integer，整数与小数混合为 real，全部为日期时为 date/datetime，否则为 text
+func inferColumnType(data []map[string]interface{}, col string) string {
+	counts := map[string]int{}
+	total := 0
+	for _, row := range data {
+		kind := valueKind(row[col])
+		if kind == "" {
+			continue
+		}
+		counts[kind]++
+		total++
+	}
+
+	switch {
+	case total == 0:
+		return "empty"
+	case counts["integer"] == total:
+		return "integer"
+	case counts["integer"]+counts["real"] == total:
+		return "real"
+	case counts["date"] == total:
+		return "date"
+	case counts["date"]+counts["datetime"] == total:
+		return "datetime"
+	}
+	return "text"
+}
+
+// valueKind 判断单个值的类型，空值返回空字符串
+func valueKind(v interface{}) string {
+	if isNullDisplay(v) {
+		return ""
+	}
+	switch x := v.(type) {
+	case int64:
+		return "integer"
+	case float64:
+		return "real"
+	case string:
+		s := strings.TrimSpace(x)
+		if s == "" {
+			return ""
+		}
+		if _, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return "integer"
+		}
+		if _, err := strconv.ParseFloat(s, 64); err == nil {
+			return "real"
+		}
+		if _, hasTime, ok := parseDateText(s); ok {
+			if hasTime {
+				return "datetime"
+			}
+			return "date"
+		}
+	}
+	return "text"
+}
@@ -0,0 +1,30 @@
+package main
+
+// columnarResult 将结果中的 data（每行一个 map）转为按列存放的 columnData：columnData[i] 为 columns[i] 列的全部值，
+// rowCount 为行数；没有 data 的结果（出错、修改类语句）原样返回
+func columnarResult(result map[string]interface{}) map[string]interface{} {
+	data, ok := result["data"].([]map[string]interface{})
+	columns, hasColumns := result["columns"].([]string)
+	if !hasColumns || (!ok && result["data"] != nil) {
+		return result
+	}
+	columnData := make([][]interface{}, len(columns))
+	for i, col := range columns {
+		values := make([]interface{}, len(data))
+		for r, row := range data {
+			values[r] = row[col]
+		}
+		columnData[i] = values
+	}
+	delete(result, "data")
+	result["columnData"] = columnData
+	result["rowCount"] = len(data)
+	return result
+}
+
+// ExecuteSQLColumnar 与 ExecuteSQLWithPage 相同，但结果按列返回（columnData + rowCount，见 columnarResult），
+// 不再为每一行重复列名，大表格的序列化时间和传输体积明显减小
+// wails:export ExecuteSQLColumnar
+func (a *App) ExecuteSQLColumnar(sqlStr string, pageNum int, pageSize int) map[string]interface{} {
+	return columnarResult(a.ExecuteSQLWithPage(sqlStr, pageNum, pageSize))
+}
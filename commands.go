@@ -0,0 +1,198 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+)
+
+// 命令分类
+const (
+	commandImport  = "导入"
+	commandExport  = "导出"
+	commandQuery   = "查询"
+	commandSaved   = "已保存的查询"
+	commandTable   = "表操作"
+	commandClean   = "数据清洗"
+	commandAnalyze = "分析"
+	commandSystem  = "系统"
+)
+
+// commandInfo 命令面板中的一项：binding 为执行该命令的后端方法名，params 为方法参数（按顺序），
+// writes 表示会修改数据库（只读实例中不可用），dialog 表示会弹出文件选择框
+type commandInfo struct {
+	id          string
+	title       string
+	category    string
+	binding     string
+	params      []string
+	description string
+	keywords    []string
+	writes      bool
+	dialog      bool
+}
+
+// commandRegistry 全部可在命令面板中执行的后端操作
+var commandRegistry = []commandInfo{
+	{id: "import.excel", title: "导入 Excel 文件", category: commandImport, binding: "OpenExcel", description: "选择 Excel 文件，每个 Sheet 导入为一张表", keywords: []string{"xlsx", "xls", "xlsb", "打开"}, writes: true, dialog: true},
+	{id: "import.sheetInto", title: "追加 Sheet 到已有表", category: commandImport, binding: "ImportSheetInto", params: []string{"filePath", "sheet", "targetTable", "columnMapping"}, description: "将一个 Sheet 的数据追加到已有的表", keywords: []string{"append", "追加"}, writes: true, dialog: true},
+	{id: "import.union", title: "合并导入多个 Sheet", category: commandImport, binding: "ImportUnion", params: []string{"filePaths", "tableName"}, description: "表头相同的 Sheet 合并为一张表", keywords: []string{"union", "合并"}, writes: true},
+	{id: "import.namedRanges", title: "导入命名区域", category: commandImport, binding: "ImportNamedRanges", params: []string{"filePath"}, description: "将工作簿中定义的名称分别导入为表", keywords: []string{"名称", "range"}, writes: true, dialog: true},
+	{id: "import.fixedWidth", title: "导入定长文本", category: commandImport, binding: "ImportFixedWidth", params: []string{"filePath", "tableName", "layout", "hasHeader"}, description: "导入主机或银行导出的定长 .txt 文件", keywords: []string{"txt", "定长"}, writes: true, dialog: true},
+	{id: "import.html", title: "导入网页表格", category: commandImport, binding: "ImportHTMLTables", params: []string{"source"}, description: "从网址或本地 HTML 文件中提取表格", keywords: []string{"html", "url", "网页"}, writes: true, dialog: true},
+	{id: "import.pdf", title: "导入 PDF 表格", category: commandImport, binding: "ImportPDFTable", params: []string{"filePath", "pages", "region", "tableName", "hasHeader"}, description: "识别 PDF 中的表格并导入", keywords: []string{"pdf"}, writes: true, dialog: true},
+	{id: "import.database", title: "从外部数据库导入", category: commandImport, binding: "ImportFromDatabase", params: []string{"driverName", "dsn", "query", "tableName"}, description: "通过驱动和 DSN 执行查询并导入结果", keywords: []string{"dsn", "odbc", "mysql"}, writes: true},
+	{id: "import.unfinished", title: "查看未完成的导入", category: commandImport, binding: "ListUnfinishedImports", description: "列出中断或失败的导入", keywords: []string{"中断", "失败", "journal"}},
+	{id: "import.refresh", title: "从来源刷新表", category: commandImport, binding: "RefreshTable", params: []string{"tableName", "strategy"}, description: "重新读取导入来源，检测新增、删除、重命名的列后按策略刷新数据", keywords: []string{"刷新", "重新导入", "结构变化", "drift"}, writes: true},
+	{id: "import.selectedColumns", title: "只导入选中的列", category: commandImport, binding: "ImportSelectedColumns", params: []string{"filePath", "columns"}, description: "按列名、序号范围、通配符或正则选择宽表中要导入的列", keywords: []string{"列", "宽表", "筛选", "裁剪"}, writes: true, dialog: true},
+	{id: "import.sampled", title: "抽样导入大文件", category: commandImport, binding: "ImportSampled", params: []string{"filePath", "options"}, description: "每 N 行取 1 行或按比例随机抽样导入，快速了解数据", keywords: []string{"抽样", "sample", "大文件"}, writes: true, dialog: true},
+	{id: "import.linkCSV", title: "链接 CSV 文件（不导入）", category: commandImport, binding: "LinkCSVFile", params: []string{"filePath", "tableName", "options"}, description: "把超大 CSV 链接为只读虚拟表，查询时直接流式读取文件", keywords: []string{"csv", "虚拟表", "大文件", "链接"}, writes: true, dialog: true},
+	{id: "import.unlinkCSV", title: "取消 CSV 链接", category: commandImport, binding: "UnlinkCSVFile", params: []string{"tableName"}, description: "删除链接 CSV 文件的虚拟表，文件本身不受影响", keywords: []string{"csv", "虚拟表", "链接"}, writes: true},
+	{id: "import.workspace", title: "导入工作区配置", category: commandImport, binding: "ImportWorkspaceConfig", params: []string{"filePath"}, description: "导入团队共享的设置、查询和数据字典", keywords: []string{"workspace", "配置"}, writes: true, dialog: true},
+
+	{id: "export.excel", title: "导出查询结果为 Excel", category: commandExport, binding: "ExportExcelBySQL", params: []string{"sql"}, description: "在后台执行查询并导出 Excel", keywords: []string{"xlsx", "保存"}, dialog: true},
+	{id: "export.formatted", title: "按列格式导出", category: commandExport, binding: "ExportExcelFormatted", params: []string{"sql", "formats"}, description: "为各列指定百分比、两位小数、日期、文本等数字格式后导出 Excel", keywords: []string{"格式", "百分比", "小数", "文本"}, dialog: true},
+	{id: "export.formulas", title: "导出带公式的 Excel", category: commandExport, binding: "ExportExcelWithFormulas", params: []string{"sql", "formulas", "totals"}, description: "把计算列写为 Excel 公式并追加合计行，修改输入值后自动重新计算", keywords: []string{"公式", "合计", "sum", "formula"}, dialog: true},
+	{id: "export.estimate", title: "预估导出大小", category: commandExport, binding: "EstimateExport", params: []string{"sql"}, description: "统计行列数并估算文件大小和耗时，超过 Excel 上限时建议改用 CSV 或抽样", keywords: []string{"行数", "耗时", "上限"}},
+	{id: "export.csv", title: "导出查询结果为 CSV", category: commandExport, binding: "ExportCSVBySQL", params: []string{"sql"}, description: "逐行写出 CSV，不受 Excel 行数上限限制", keywords: []string{"csv", "大结果"}, dialog: true},
+	{id: "export.sample", title: "抽样导出", category: commandExport, binding: "ExportSample", params: []string{"sql", "n"}, description: "随机保留约 n 行后导出 Excel", keywords: []string{"样例", "随机"}, dialog: true},
+	{id: "export.appendSheet", title: "追加到已有工作簿", category: commandExport, binding: "ExportAppendSheet", params: []string{"existingPath", "sheetName", "sql"}, description: "把查询结果作为新工作表追加到已有的 xlsx 文件", keywords: []string{"工作表", "累积", "跟踪"}, dialog: true},
+	{id: "export.delta", title: "导出与快照相比的变化", category: commandExport, binding: "ExportDelta", params: []string{"sqlOrTable", "snapshotName"}, description: "只导出新增、修改、删除的行，首列为变更状态", keywords: []string{"差异", "变更", "快照"}, dialog: true},
+	{id: "snapshot.save", title: "保存结果快照", category: commandExport, binding: "SaveSnapshot", params: []string{"sqlOrTable", "snapshotName", "keyColumns"}, description: "保存表或查询的当前结果，供之后导出变化", keywords: []string{"快照", "基线"}, writes: true},
+	{id: "snapshot.list", title: "查看结果快照", category: commandExport, binding: "ListSnapshots", description: "列出已保存的快照", keywords: []string{"快照"}},
+	{id: "snapshot.delete", title: "删除结果快照", category: commandExport, binding: "DeleteSnapshot", params: []string{"snapshotName"}, description: "删除快照及其数据", keywords: []string{"快照"}, writes: true},
+	{id: "export.large", title: "导出大结果（流式）", category: commandExport, binding: "ExportExcelLarge", params: []string{"sql"}, description: "经临时文件流式写入，内存中不保留全量结果", keywords: []string{"大文件", "stream"}, dialog: true},
+	{id: "export.pinned", title: "导出并冻结关键列", category: commandExport, binding: "ExportExcelPinned", params: []string{"sql", "keyColumns"}, description: "关键列移到最左侧并冻结", keywords: []string{"冻结", "freeze"}, dialog: true},
+	{id: "export.outlined", title: "分组折叠导出明细", category: commandExport, binding: "ExportExcelOutlined", params: []string{"sql", "groupColumn"}, description: "按分组列插入组标题行，明细行可在 Excel 中折叠", keywords: []string{"大纲", "折叠", "outline"}, dialog: true},
+	{id: "export.fillTemplate", title: "导出填写模板", category: commandExport, binding: "ExportFillTemplate", params: []string{"sql", "options"}, description: "为列添加下拉列表并可锁定关键列，便于他人按规范填写后重新导入", keywords: []string{"下拉", "数据验证", "模板", "保护", "锁定"}, dialog: true},
+	{id: "export.roundTrip", title: "导出表供修改后回填", category: commandExport, binding: "ExportForRoundTrip", params: []string{"tableName", "options"}, description: "带隐藏行键导出，收回后用回填命令写回源表", keywords: []string{"回填", "往返", "收集"}, dialog: true},
+	{id: "import.reimportFilled", title: "回填修改后的文件", category: commandImport, binding: "ReimportFilled", params: []string{"filePath"}, description: "按行键把修改写回源表并报告冲突", keywords: []string{"回填", "往返", "对账"}, writes: true, dialog: true},
+	{id: "import.dryRun", title: "试运行导入", category: commandImport, binding: "DryRunImport", params: []string{"binding", "args"}, description: "完整解析和校验一次导入但不写入数据库，返回将生成的表结构、行数和问题", keywords: []string{"预览", "检查", "试运行", "dry run"}, dialog: true},
+	{id: "export.grouped", title: "分组汇总导出", category: commandExport, binding: "ExportGroupedExcel", params: []string{"source", "groupColumns", "measures"}, description: "按分组列生成带小计的 Excel", keywords: []string{"小计", "group"}, dialog: true},
+	{id: "export.bundle", title: "导出分享包", category: commandExport, binding: "ExportShareBundle", params: []string{"sql"}, description: "结果、SQL、表结构打包为 zip", keywords: []string{"zip", "分享"}, dialog: true},
+	{id: "export.email", title: "通过邮件发送结果", category: commandExport, binding: "SendExportByEmail", params: []string{"sql", "recipients", "format", "allowSensitive"}, description: "将查询结果作为附件发送", keywords: []string{"email", "邮件"}},
+	{id: "export.databaseCopy", title: "导出数据库副本", category: commandExport, binding: "ExportDatabaseCopy", params: []string{"savePath", "tables"}, description: "将所选表导出为独立的 SQLite 文件", keywords: []string{"sqlite", "备份"}, dialog: true},
+	{id: "export.viewerPackage", title: "导出只读分享包", category: commandExport, binding: "ExportViewerPackage", params: []string{"savePath", "title", "tables", "queries"}, description: "将所选表和精选查询打包为只读 .db，接收者可查看和运行查询但不能修改", keywords: []string{"分享", "只读", "查看"}, dialog: true},
+	{id: "export.workspace", title: "导出工作区配置", category: commandExport, binding: "ExportWorkspaceConfig", description: "导出设置、已保存的查询和数据字典", keywords: []string{"workspace", "配置"}, dialog: true},
+	{id: "export.jobs", title: "查看导出任务", category: commandExport, binding: "ListExportJobs", description: "列出本次运行中的导出任务", keywords: []string{"任务", "job"}},
+	{id: "export.history", title: "查看导出历史", category: commandExport, binding: "GetExportHistory", params: []string{"limit"}, description: "列出历次导出的 SQL、保存路径、选项、耗时和行数", keywords: []string{"历史", "记录", "报表"}},
+	{id: "export.rerun", title: "重新导出", category: commandExport, binding: "ReRunExport", params: []string{"id"}, description: "按导出历史中的记录，用相同的 SQL 和选项覆盖写入原文件", keywords: []string{"重跑", "定期", "报表"}},
+
+	{id: "query.run", title: "执行 SQL", category: commandQuery, binding: "ExecuteSQLWithPage", params: []string{"sql", "pageNum", "pageSize"}, description: "执行 SQL 并分页显示结果", keywords: []string{"sql", "运行"}},
+	{id: "query.findRow", title: "跳转到匹配行", category: commandQuery, binding: "FindRowInResult", params: []string{"sql", "column", "value"}, description: "按查询的排序查找某列等于给定值的第一行，返回所在页和页内位置", keywords: []string{"查找", "跳转", "定位", "订单号"}},
+	{id: "query.fixScript", title: "执行数据修复脚本", category: commandQuery, binding: "RunFixScript", params: []string{"statements", "dryRun"}, description: "在一个事务中执行多条 UPDATE / DELETE，逐条报告影响行数；可先试运行估算", keywords: []string{"修复", "批量", "事务", "试运行"}, writes: true},
+	{id: "query.matchSchemas", title: "对齐两张表的列", category: commandQuery, binding: "MatchSchemas", params: []string{"tableA", "tableB"}, description: "按列名相似度和取值分布对齐两次导入的列，生成列映射和合并 SQL", keywords: []string{"对齐", "映射", "合并", "union", "schema"}},
+	{id: "query.gridSQL", title: "由表格操作生成 SQL", category: commandQuery, binding: "BuildGridSQL", params: []string{"source", "columns", "filters", "sorts"}, description: "根据选中的列、筛选和排序生成 SQL", keywords: []string{"筛选", "排序"}},
+	{id: "query.history", title: "查询历史", category: commandQuery, binding: "GetQueryHistory", params: []string{"limit"}, description: "最近执行的查询及耗时", keywords: []string{"history", "历史"}},
+	{id: "query.slowest", title: "慢查询报告", category: commandQuery, binding: "GetSlowestQueries", params: []string{"n"}, description: "耗时最长的查询及查询计划", keywords: []string{"慢", "性能", "slow"}},
+
+	{id: "saved.save", title: "保存查询", category: commandSaved, binding: "SaveQuery", params: []string{"name", "sql", "rules"}, description: "保存查询及条件高亮规则", keywords: []string{"保存"}, writes: true},
+	{id: "saved.list", title: "已保存的查询", category: commandSaved, binding: "ListSavedQueries", description: "列出已保存的查询", keywords: []string{"列表"}},
+	{id: "saved.run", title: "执行已保存的查询", category: commandSaved, binding: "RunSavedQuery", params: []string{"name", "pageNum", "pageSize"}, description: "执行已保存的查询并应用高亮规则", keywords: []string{"运行"}},
+	{id: "saved.export", title: "导出已保存的查询", category: commandSaved, binding: "ExportSavedQuery", params: []string{"name"}, description: "按保存的列设置导出 Excel", keywords: []string{"导出"}, dialog: true},
+	{id: "saved.formats", title: "设置已保存查询的列格式", category: commandSaved, binding: "SetSavedQueryFormats", params: []string{"name", "formats"}, description: "保存导出时各列的数字格式，每次导出该查询格式一致", keywords: []string{"格式", "百分比", "日期"}, writes: true},
+	{id: "saved.delete", title: "删除已保存的查询", category: commandSaved, binding: "DeleteSavedQuery", params: []string{"name"}, description: "删除已保存的查询", keywords: []string{"删除"}, writes: true},
+
+	{id: "table.list", title: "列出全部表", category: commandTable, binding: "ListTables", description: "全部用户表及显示名、标签和文件夹", keywords: []string{"表", "tables"}},
+	{id: "table.tree", title: "按文件夹浏览表", category: commandTable, binding: "GetTableTree", description: "按文件夹分组显示全部表", keywords: []string{"文件夹", "folder"}},
+	{id: "table.preview", title: "预览表", category: commandTable, binding: "PreviewTable", params: []string{"tableName", "n"}, description: "查看表的前几行和表结构", keywords: []string{"预览", "查看"}},
+	{id: "table.headerMapping", title: "查看原表头对应", category: commandTable, binding: "GetHeaderMapping", params: []string{"tableName"}, description: "导入时的原表头与列名的对应关系", keywords: []string{"表头", "列名", "header"}},
+	{id: "table.headerView", title: "创建原表头视图", category: commandTable, binding: "CreateHeaderView", params: []string{"tableName"}, description: "创建以原表头为列名的视图 <表名>_headers，可直接用原表头查询", keywords: []string{"表头", "视图", "view", "header"}, writes: true},
+	{id: "table.cleanView", title: "创建清洗视图", category: commandTable, binding: "CreateCleanView", params: []string{"tableName"}, description: "创建去除空白、统一日期并转换数字的视图 <表名>_clean，原表不变", keywords: []string{"清洗", "视图", "clean", "trim"}, writes: true},
+	{id: "table.typedLayer", title: "生成类型化表", category: commandTable, binding: "BuildTypedLayer", params: []string{"tableName"}, description: "保留原始文本表，另外生成按类型转换的 <表名>_typed 表", keywords: []string{"类型", "转换", "typed", "原始"}, writes: true},
+	{id: "table.typeErrors", title: "查看类型转换错误", category: commandTable, binding: "GetTypeConversionErrors", params: []string{"tableName", "limit"}, description: "类型化表中无法转换的值及其原始单元格文本", keywords: []string{"类型", "错误", "追溯"}},
+	{id: "table.analyze", title: "更新统计信息", category: commandTable, binding: "UpdateStatistics", params: []string{"tableName"}, description: "执行 ANALYZE，使多表连接选用合适的顺序；表名为空时更新全部表", keywords: []string{"analyze", "统计", "性能", "优化"}, writes: true},
+	{id: "table.stale", title: "长期未使用的表", category: commandTable, binding: "GetStaleTables", params: []string{"days"}, description: "列出超过指定天数未导入、未被查询的表", keywords: []string{"清理", "过期", "未使用", "stale", "空间"}},
+	{id: "table.dropStale", title: "清理长期未使用的表", category: commandTable, binding: "DropStaleTables", params: []string{"tables", "days"}, description: "删除确认过的长期未使用的表及其派生视图和元数据", keywords: []string{"清理", "删除", "空间"}, writes: true},
+	{id: "table.schema", title: "查看表结构", category: commandTable, binding: "GetTableSchema", params: []string{"tableName"}, description: "列名、类型及数据字典", keywords: []string{"结构", "schema"}},
+	{id: "table.traceCell", title: "追溯单元格来源", category: commandTable, binding: "TraceSourceCell", params: []string{"tableName", "rowid", "column"}, description: "按导入时记录的来源列定位值在原工作簿中的文件、工作表和单元格", keywords: []string{"来源", "出处", "追溯", "单元格"}},
+	{id: "table.schemaHistory", title: "查看结构历史", category: commandTable, binding: "GetSchemaHistory", params: []string{"limit"}, description: "列出每次导入或 DDL 后记录的表结构版本及其变化", keywords: []string{"结构", "版本", "schema", "变更"}},
+	{id: "table.diffSchemas", title: "比较结构版本", category: commandTable, binding: "DiffSchemas", params: []string{"v1", "v2"}, description: "比较两个结构版本（或与当前结构）之间新增、删除的表和列", keywords: []string{"结构", "差异", "schema", "diff"}},
+	{id: "table.describe", title: "设置表说明", category: commandTable, binding: "SetTableDescription", params: []string{"tableName", "label", "description"}, description: "设置表的显示名和说明", keywords: []string{"字典", "说明"}, writes: true},
+	{id: "table.headerTranslations", title: "查看表头翻译", category: commandTable, binding: "GetHeaderTranslations", params: []string{"tableName"}, description: "列出各列的显示名和各语言的导出表头", keywords: []string{"翻译", "英文", "表头", "字典"}},
+	{id: "table.translateHeaders", title: "设置表头翻译", category: commandTable, binding: "SetHeaderTranslations", params: []string{"tableName", "language", "headers"}, description: "为各列设置某种语言的导出表头，配合 export_header_language 设置使用", keywords: []string{"翻译", "英文", "表头", "本地化"}, writes: true},
+	{id: "table.tags", title: "设置表标签", category: commandTable, binding: "SetTableTags", params: []string{"tableName", "tags"}, description: "覆盖设置表的标签", keywords: []string{"标签", "tag"}, writes: true},
+	{id: "table.folder", title: "移动表到文件夹", category: commandTable, binding: "SetTableFolder", params: []string{"tableName", "folder"}, description: "整理表到文件夹", keywords: []string{"文件夹", "folder"}, writes: true},
+	{id: "table.convertType", title: "转换列类型", category: commandTable, binding: "ConvertColumnType", params: []string{"tableName", "column", "targetType"}, description: "将列转换为数值、日期等类型", keywords: []string{"类型", "cast"}, writes: true},
+	{id: "table.partition", title: "按列值拆分表", category: commandTable, binding: "PartitionTable", params: []string{"tableName", "column", "asView"}, description: "每个取值生成一张表或视图", keywords: []string{"拆分", "partition"}, writes: true},
+	{id: "table.enrich", title: "按键补充列（VLOOKUP）", category: commandTable, binding: "EnrichTable", params: []string{"target", "lookupTable", "keyMapping", "columnsToAdd"}, description: "把查找表的列追加到目标表", keywords: []string{"vlookup", "关联"}, writes: true},
+	{id: "table.explode", title: "拆分多值单元格", category: commandTable, binding: "ExplodeColumn", params: []string{"tableName", "column", "delimiter"}, description: "按分隔符拆分为子表", keywords: []string{"分隔符", "拆分"}, writes: true},
+	{id: "table.jsonFlatten", title: "展开 JSON 列", category: commandTable, binding: "JSONFlatten", params: []string{"tableName", "column"}, description: "将单元格中的 JSON 展开为新表", keywords: []string{"json"}, writes: true},
+	{id: "table.rowHash", title: "计算整行哈希", category: commandTable, binding: "AddRowHash", params: []string{"tableName"}, description: "补充 _row_hash 列便于比对", keywords: []string{"hash", "比对"}, writes: true},
+	{id: "table.softDelete", title: "软删除行", category: commandTable, binding: "MarkRowsDeleted", params: []string{"tableName", "rowIDs"}, description: "将行标记为已删除", keywords: []string{"删除"}, writes: true},
+	{id: "table.restoreRows", title: "恢复软删除的行", category: commandTable, binding: "RestoreRows", params: []string{"tableName", "rowIDs"}, description: "恢复已标记删除的行", keywords: []string{"恢复"}, writes: true},
+
+	{id: "clean.booleans", title: "规范布尔列", category: commandClean, binding: "NormalizeBooleanColumns", params: []string{"tableName", "columns"}, description: "是/否、Y/N 等转为 0/1", keywords: []string{"布尔", "boolean"}, writes: true},
+	{id: "clean.cluster", title: "相似取值聚类", category: commandClean, binding: "ClusterSimilarValues", params: []string{"tableName", "column"}, description: "找出写法不同的相同取值", keywords: []string{"聚类", "去重"}},
+	{id: "clean.standardize", title: "统一取值", category: commandClean, binding: "ApplyStandardization", params: []string{"tableName", "column", "mapping"}, description: "按映射改写列中的取值", keywords: []string{"标准化", "替换"}, writes: true},
+	{id: "clean.address", title: "拆分地址列", category: commandClean, binding: "SplitAddressColumn", params: []string{"tableName", "column"}, description: "拆分为省、市、区县三列", keywords: []string{"地址", "省市"}, writes: true},
+	{id: "clean.rejected", title: "查看隔离数据", category: commandClean, binding: "ListRejectedRows", params: []string{"tableName"}, description: "查看类型转换失败的行", keywords: []string{"隔离", "错误"}},
+
+	{id: "analyze.profile", title: "表概况", category: commandAnalyze, binding: "ProfileTable", params: []string{"tableName", "exact"}, description: "每列的非空数、去重数和取值范围", keywords: []string{"统计", "profile"}},
+	{id: "analyze.columnValues", title: "列取值分布", category: commandAnalyze, binding: "GetColumnValues", params: []string{"tableName", "column", "limit"}, description: "列出列中最常见的取值及次数，用于筛选下拉框", keywords: []string{"筛选", "下拉", "取值", "distinct"}},
+	{id: "analyze.crosstab", title: "交叉表", category: commandAnalyze, binding: "Crosstab", params: []string{"source", "rowColumns", "pivotColumn", "measures"}, description: "按列的取值展开成列汇总", keywords: []string{"透视", "pivot"}},
+	{id: "analyze.cumulative", title: "累计求和", category: commandAnalyze, binding: "CumulativeSum", params: []string{"tableName", "orderColumn", "valueColumn", "partitionColumn"}, description: "按顺序逐行累加", keywords: []string{"累计", "running"}},
+	{id: "analyze.benford", title: "本福特定律检验", category: commandAnalyze, binding: "BenfordAudit", params: []string{"tableName", "column"}, description: "首位数字分布检验", keywords: []string{"审计", "benford"}},
+	{id: "analyze.sensitive", title: "检测敏感信息列", category: commandAnalyze, binding: "DetectSensitiveColumns", params: []string{"tableName"}, description: "手机号、身份证号等", keywords: []string{"隐私", "pii"}},
+	{id: "analyze.calendar", title: "生成日历表", category: commandAnalyze, binding: "GenerateCalendarTable", params: []string{"start", "end"}, description: "生成含节假日的日期维度表", keywords: []string{"日期", "calendar"}, writes: true},
+	{id: "analyze.reference", title: "安装参考表", category: commandAnalyze, binding: "InstallReferenceTables", params: []string{"names"}, description: "行政区划、币种代码等", keywords: []string{"参考", "行政区划"}, writes: true},
+
+	{id: "system.openViewer", title: "打开只读分享包", category: commandSystem, binding: "OpenViewerPackage", params: []string{"path"}, description: "以只读方式打开他人导出的分享包，运行其中的精选查询", keywords: []string{"分享", "只读", "查看"}, dialog: true},
+	{id: "system.closeViewer", title: "关闭只读分享包", category: commandSystem, binding: "CloseViewerPackage", description: "关闭分享包，回到本机数据库", keywords: []string{"分享", "只读"}},
+	{id: "system.operations", title: "查看后台任务", category: commandSystem, binding: "ListActiveOperations", description: "列出正在进行的导入、查询、导出等操作及其进度和耗时", keywords: []string{"任务", "进度", "任务管理器"}},
+	{id: "system.cancelOperation", title: "取消后台任务", category: commandSystem, binding: "CancelOperation", params: []string{"operationID"}, description: "中断正在进行的导入、查询或导出", keywords: []string{"取消", "停止", "中断"}},
+	{id: "backup.create", title: "备份数据库", category: commandSystem, binding: "CreateBackup", params: []string{"note"}, description: "把当前数据库完整备份到 backups 目录", keywords: []string{"备份", "backup"}, writes: true},
+	{id: "backup.list", title: "查看备份", category: commandSystem, binding: "ListBackups", description: "列出已登记的数据库备份", keywords: []string{"备份", "backup"}},
+	{id: "backup.restoreTable", title: "从备份恢复单张表", category: commandSystem, binding: "RestoreTableFromBackup", params: []string{"backupID", "table"}, description: "用备份中的表结构和数据替换当前表，其他表不受影响", keywords: []string{"恢复", "还原", "误删", "backup"}, writes: true},
+	{id: "system.settings", title: "设置", category: commandSystem, binding: "GetSettings", description: "查看和修改全部设置项", keywords: []string{"设置", "settings"}},
+	{id: "system.ping", title: "检查数据库连接", category: commandSystem, binding: "Ping", description: "检查连接和响应时间", keywords: []string{"连接", "ping"}},
+	{id: "system.reconnect", title: "重新连接数据库", category: commandSystem, binding: "Reconnect", description: "无需重启应用重新打开数据库", keywords: []string{"重连", "reconnect"}},
+	{id: "system.memory", title: "内存诊断", category: commandSystem, binding: "GetMemoryUsage", description: "内存占用和缓存情况", keywords: []string{"内存", "memory"}},
+	{id: "system.benchmark", title: "性能自检", category: commandSystem, binding: "RunBenchmark", params: []string{"rows"}, description: "测试导入、查询和导出的耗时", keywords: []string{"性能", "benchmark"}},
+	{id: "system.instance", title: "实例状态", category: commandSystem, binding: "GetInstanceStatus", description: "是否为只读实例", keywords: []string{"只读", "锁"}},
+}
+
+// Commands 列出命令面板可用的全部后端操作：{id, title, category, binding, params, description, keywords, writes, dialog, enabled}
+// binding 为前端调用的方法名；只读实例中会修改数据库的命令 enabled 为 false。query 非空时按标题、说明、关键字和分类过滤（不区分大小写）
+// wails:export Commands
+func (a *App) Commands(query string) []map[string]interface{} {
+	query = strings.ToLower(strings.TrimSpace(query))
+	app := reflect.ValueOf(a)
+	list := []map[string]interface{}{}
+	for _, c := range commandRegistry {
+		// 方法已改名或删除的命令不出现在面板中
+		if !app.MethodByName(c.binding).IsValid() {
+			continue
+		}
+		if query != "" && !c.matches(query) {
+			continue
+		}
+		params := c.params
+		if params == nil {
+			params = []string{}
+		}
+		list = append(list, map[string]interface{}{
+			"id":          c.id,
+			"title":       c.title,
+			"category":    c.category,
+			"binding":     c.binding,
+			"params":      params,
+			"description": c.description,
+			"keywords":    c.keywords,
+			"writes":      c.writes,
+			"dialog":      c.dialog,
+			"enabled":     !(c.writes && a.readOnly()),
+		})
+	}
+	return list
+}
+
+// matches 命令的标题、说明、分类、方法名或关键字中是否包含 query（已转为小写）
+func (c commandInfo) matches(query string) bool {
+	fields := append([]string{c.title, c.description, c.category, c.binding, c.id}, c.keywords...)
+	for _, f := range fields {
+		if strings.Contains(strings.ToLower(f), query) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// importCellComments 替换表的批注记录：先清除旧批注，开启 import_comments 时再写入 Sheet 中的批注
+func (a *App) importCellComments(f *excelize.File, sheetName string, tableName string, columns []string) error {
+	if _, err := a.writeDB().Exec("DELETE FROM _app_cell_comments WHERE table_name = ?", tableName); err != nil {
+		return fmt.Errorf("清除表 %s 的批注失败: %v", tableName, err)
+	}
+	if a.setting("import_comments") != "true" {
+		return nil
+	}
+
+	comments, err := f.GetComments(sheetName)
+	if err != nil {
+		return fmt.Errorf("读取 Sheet %s 的批注失败: %v", sheetName, err)
+	}
+	if len(comments) == 0 {
+		return nil
+	}
+
+	tx, err := a.writeDB().Begin()
+	if err != nil {
+		return fmt.Errorf("开启事务失败: %v", err)
+	}
+	for _, comment := range comments {
+		col, row, err := excelize.CellNameToCoordinates(comment.Cell)
+		if err != nil || col > len(columns) {
+			continue
+		}
+		text := comment.Text
+		if text == "" {
+			var sb strings.Builder
+			for _, run := range comment.Paragraph {
+				sb.WriteString(run.Text)
+			}
+			text = sb.String()
+		}
+		_, err = tx.Exec(`INSERT OR REPLACE INTO _app_cell_comments (table_name, row_num, column_name, author, text)
+			VALUES (?, ?, ?, ?, ?)`, tableName, row-1, columns[col-1], comment.Author, strings.TrimSpace(text))
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("保存批注失败: %v", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("提交事务失败: %v", err)
+	}
+	return nil
+}
+
+// GetCellComments 获取表的单元格批注，row 对应数据行的 rowid（0 为表头行）
+// wails:export GetCellComments
+func (a *App) GetCellComments(tableName string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+
+	rows, err := a.readDB().Query(`SELECT row_num, column_name, author, text FROM _app_cell_comments
+		WHERE table_name = ? ORDER BY row_num, column_name`, tableName)
+	if err != nil {
+		result["error"] = fmt.Sprintf("读取批注失败: %v", err)
+		return result
+	}
+	defer rows.Close()
+
+	var comments []map[string]interface{}
+	for rows.Next() {
+		var rowNum int
+		var column, author, text string
+		if err := rows.Scan(&rowNum, &column, &author, &text); err != nil {
+			result["error"] = fmt.Sprintf("读取批注失败: %v", err)
+			return result
+		}
+		comments = append(comments, map[string]interface{}{
+			"row":    rowNum,
+			"column": column,
+			"author": author,
+			"text":   text,
+		})
+	}
+	result["table"] = tableName
+	result["comments"] = comments
+	result["message"] = fmt.Sprintf("表 %s 共有 %d 条批注", tableName, len(comments))
+	return result
+}
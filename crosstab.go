@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxCrosstabValues 交叉表列字段最多展开的取值个数
+const maxCrosstabValues = 100
+
+// Crosstab 交叉表：按 rowColumns 分行、按 pivotColumn 的取值展开成列，每个取值下计算 measures 中的全部指标
+// source 为表名或查询语句；measures 为 [{column, agg, label}]，同 ExportGroupedExcel
+// 输出列名为 <取值>_<指标名>，只有一个指标时直接用取值作列名
+// wails:export Crosstab
+func (a *App) Crosstab(source string, rowColumns []string, pivotColumn string, measures []map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	if len(rowColumns) == 0 {
+		result["error"] = "请至少选择一个行字段"
+		return result
+	}
+
+	from, columns, err := a.resolveSource(strings.TrimSpace(source))
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	for _, col := range append(append([]string{}, rowColumns...), pivotColumn) {
+		if !containsString(columns, col) {
+			result["error"] = fmt.Sprintf("不存在列 %s", col)
+			return result
+		}
+	}
+	ms, err := parseMeasures(measures, columns)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+
+	pivot := quoteIdent(pivotColumn)
+	values, err := a.queryValueRows(fmt.Sprintf("SELECT DISTINCT %s FROM %s ORDER BY 1 LIMIT %d", pivot, from, maxCrosstabValues+1))
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	if len(values) > maxCrosstabValues {
+		result["error"] = fmt.Sprintf("列字段 %s 的取值超过 %d 个，请先筛选或换一个列字段", pivotColumn, maxCrosstabValues)
+		return result
+	}
+
+	items := make([]string, 0, len(rowColumns)+len(values)*len(ms))
+	keys := make([]string, len(rowColumns))
+	used := make(map[string]bool)
+	for i, col := range rowColumns {
+		keys[i] = quoteIdent(col)
+		items = append(items, keys[i])
+		used[col] = true
+	}
+	for _, row := range values {
+		cond := pivot + " IS NULL"
+		if row[0] != nil {
+			cond = fmt.Sprintf("%s = %s", pivot, sqlValueLiteral(row[0]))
+		}
+		for _, m := range ms {
+			name := groupLabel(row[0])
+			if len(ms) > 1 {
+				name += "_" + m.label
+			}
+			if used[name] {
+				result["error"] = fmt.Sprintf("输出列名重复: %s", name)
+				return result
+			}
+			used[name] = true
+			items = append(items, fmt.Sprintf("%s AS %s", m.conditionalExpr(cond), quoteIdent(name)))
+		}
+	}
+	groupBy := strings.Join(keys, ", ")
+	sqlText := fmt.Sprintf("SELECT %s\nFROM %s\nGROUP BY %s\nORDER BY %s", strings.Join(items, ",\n       "), from, groupBy, groupBy)
+
+	pageSize := a.session.currentPageSize()
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	result = a.ExecuteSQLWithPage(sqlText, 1, pageSize)
+	if _, failed := result["error"]; failed {
+		return result
+	}
+	result["sql"] = sqlText
+	result["pivotValues"] = len(values)
+	return result
+}
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/transform"
+)
+
+// csvModuleName 直接查询 CSV 文件的虚拟表模块名，建表语句为
+// CREATE VIRTUAL TABLE t USING csv_file('路径', '编码', '分隔符', '空值', '表头', '列1', '列2', ...)
+const csvModuleName = "csv_file"
+
+// csvSniffBytes 判断编码和分隔符时读取的文件开头字节数
+const csvSniffBytes = 64 << 10
+
+// csvDelimiters 分隔符名称（写入建表语句）与字符的对应关系
+var csvDelimiters = map[string]rune{"comma": ',', "tab": '\t', "semicolon": ';', "pipe": '|'}
+
+// csvSource 虚拟表模块参数描述的 CSV 文件
+type csvSource struct {
+	path      string
+	encoding  string // utf-8 或 gb18030
+	delimiter string // csvDelimiters 的键
+	emptyNull bool   // 空字段作为 NULL（链接时的 null_policy 设置）
+	header    bool   // 首行为表头，扫描时跳过
+	columns   []string
+}
+
+// args 生成建表语句中 USING csv_file(...) 的参数
+func (s csvSource) args() string {
+	nulls := "empty"
+	if s.emptyNull {
+		nulls = "null"
+	}
+	header := "noheader"
+	if s.header {
+		header = "header"
+	}
+	parts := []string{quoteLiteral(s.path), quoteLiteral(s.encoding), quoteLiteral(s.delimiter), quoteLiteral(nulls), quoteLiteral(header)}
+	for _, col := range s.columns {
+		parts = append(parts, quoteLiteral(col))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// parseCSVSourceArgs 解析模块参数（SQLite 原样传入带引号的参数文本）
+func parseCSVSourceArgs(args []string) (csvSource, error) {
+	values := make([]string, len(args))
+	for i, arg := range args {
+		arg = strings.TrimSpace(arg)
+		if len(arg) >= 2 && arg[0] == '\'' && arg[len(arg)-1] == '\'' {
+			arg = strings.ReplaceAll(arg[1:len(arg)-1], "''", "'")
+		}
+		values[i] = arg
+	}
+	if len(values) < 6 {
+		return csvSource{}, fmt.Errorf("%s 需要文件路径、编码、分隔符、空值、表头和至少一个列名", csvModuleName)
+	}
+	s := csvSource{
+		path:      values[0],
+		encoding:  values[1],
+		delimiter: values[2],
+		emptyNull: values[3] == "null",
+		header:    values[4] == "header",
+		columns:   values[5:],
+	}
+	if _, ok := csvDelimiters[s.delimiter]; !ok {
+		return s, fmt.Errorf("不支持的分隔符 %s", s.delimiter)
+	}
+	return s, nil
+}
+
+// open 打开文件并返回逐行读取的 CSV 读取器（已去除 BOM、转码为 UTF-8，需要时跳过表头行）
+func (s csvSource) open() (*os.File, *csv.Reader, error) {
+	file, err := os.Open(s.path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("打开文件 %s 失败（文件是否已移动或删除？）: %v", s.path, err)
+	}
+	var r io.Reader = bufio.NewReaderSize(file, 1<<20)
+	if s.encoding == "gb18030" {
+		r = transform.NewReader(r, simplifiedchinese.GB18030.NewDecoder())
+	} else {
+		br := r.(*bufio.Reader)
+		if bom, _ := br.Peek(3); bytes.Equal(bom, []byte("\xEF\xBB\xBF")) {
+			br.Discard(3)
+		}
+	}
+	reader := csv.NewReader(r)
+	reader.Comma = csvDelimiters[s.delimiter]
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.ReuseRecord = true
+	if s.header {
+		if _, err := reader.Read(); err != nil && err != io.EOF {
+			file.Close()
+			return nil, nil, fmt.Errorf("读取 %s 的表头失败: %v", s.path, err)
+		}
+	}
+	return file, reader, nil
+}
+
+// sniffCSV 根据文件开头判断编码（非合法 UTF-8 时按 GB18030）和分隔符（首行中出现最多的候选分隔符）
+func sniffCSV(path string) (encoding string, delimiter string, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", "", fmt.Errorf("打开文件失败: %v", err)
+	}
+	defer file.Close()
+	head := make([]byte, csvSniffBytes)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", "", fmt.Errorf("读取文件失败: %v", err)
+	}
+	head = head[:n]
+	if n == csvSniffBytes {
+		// 截断处可能切开了一个多字节字符
+		for i := 0; i < utf8.UTFMax && len(head) > 0 && !utf8.Valid(head); i++ {
+			head = head[:len(head)-1]
+		}
+	}
+	encoding = "utf-8"
+	if !utf8.Valid(head) {
+		encoding = "gb18030"
+	}
+	firstLine := head
+	if i := bytes.IndexByte(head, '\n'); i >= 0 {
+		firstLine = head[:i]
+	}
+	delimiter, best := "comma", 0
+	for _, name := range []string{"comma", "tab", "semicolon", "pipe"} {
+		if c := bytes.Count(firstLine, []byte(string(csvDelimiters[name]))); c > best {
+			delimiter, best = name, c
+		}
+	}
+	return encoding, delimiter, nil
+}
+
+// csvColumnNames 由表头（或首行的列数）生成列名：空白表头用 columnN，重名的加 _2、_3 后缀
+func csvColumnNames(first []string, header bool) []string {
+	if !header {
+		return defaultColumnNames(len(first))
+	}
+	columns := make([]string, len(first))
+	seen := make(map[string]int, len(first))
+	for i, h := range first {
+		name := strings.TrimSpace(h)
+		if name == "" {
+			name = fmt.Sprintf("column%d", i+1)
+		}
+		key := strings.ToLower(name)
+		if seen[key] > 0 {
+			name = fmt.Sprintf("%s_%d", name, seen[key]+1)
+		}
+		seen[key]++
+		columns[i] = name
+	}
+	return columns
+}
+
+// csvFieldValue 把 CSV 字段转为查询用的值：规范写法的整数和小数转为数字（便于比较和排序），
+// 以 0 开头的编号等保持文本；空字段按 emptyNull 返回 NULL 或空字符串
+func csvFieldValue(s string, emptyNull bool) interface{} {
+	if s == "" {
+		if emptyNull {
+			return nil
+		}
+		return ""
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil && strconv.FormatInt(n, 10) == s {
+		return n
+	}
+	digits := strings.TrimPrefix(s, "-")
+	if strings.Trim(digits, "0123456789.eE+-") == "" && !(len(digits) > 1 && digits[0] == '0' && digits[1] != '.') {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+	return s
+}
+
+// LinkCSVFile 把 CSV 文件链接为只读的虚拟表，不导入数据即可用 SQL 查询（适合对超大文件做一次性统计）
+// 每次查询都从头流式读取文件，不占用数据库空间；filePath 为空时弹出文件选择框，tableName 为空时按文件名生成；
+// options.hasHeader（缺省 true）表示首行为表头，options.delimiter 可指定 comma/tab/semicolon/pipe（缺省自动判断）；
+// 已存在同名的普通表时报错，同名的已链接文件则替换为新的链接
+// wails:export LinkCSVFile
+func (a *App) LinkCSVFile(filePath string, tableName string, options map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	if a.readOnly() {
+		result["error"] = readOnlyMessage
+		return result
+	}
+	if !csvModuleAvailable {
+		result["error"] = "当前版本未启用虚拟表支持（需使用 sqlite_vtable 构建标签编译），请改用导入"
+		return result
+	}
+	path, err := a.chooseFile(filePath, "选择要链接的 CSV 文件", "*.csv;*.tsv;*.txt", "CSV 文件")
+	if err != nil {
+		result["error"] = fmt.Sprintf("文件选择失败: %v", err)
+		return result
+	}
+	if path == "" {
+		result["error"] = "未选择文件"
+		return result
+	}
+	if tableName = strings.TrimSpace(tableName); tableName == "" {
+		tableName = tableNameFromFile(path)
+	}
+
+	src := csvSource{path: path, emptyNull: a.importEmptyAsNull(), header: true}
+	if hasHeader, ok := options["hasHeader"].(bool); ok {
+		src.header = hasHeader
+	}
+	if src.encoding, src.delimiter, err = sniffCSV(path); err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	if name, _ := options["delimiter"].(string); name != "" {
+		if _, ok := csvDelimiters[name]; !ok {
+			result["error"] = fmt.Sprintf("不支持的分隔符 %s，可选 comma、tab、semicolon、pipe", name)
+			return result
+		}
+		src.delimiter = name
+	}
+
+	// 读取首行得到列名
+	first := src
+	first.header = false
+	file, reader, err := first.open()
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	record, err := reader.Read()
+	file.Close()
+	if err != nil {
+		result["error"] = fmt.Sprintf("读取文件首行失败: %v", err)
+		return result
+	}
+	src.columns = csvColumnNames(record, src.header)
+
+	existing, err := a.linkedCSVTable(tableName)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	if !existing {
+		var count int
+		a.readDB().QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name = ?", tableName).Scan(&count)
+		if count > 0 {
+			result["error"] = fmt.Sprintf("表 %s 已存在，请换一个名称", tableName)
+			return result
+		}
+	}
+	if _, err := a.writeDB().Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", quoteIdent(tableName))); err != nil {
+		result["error"] = fmt.Sprintf("删除旧的链接 %s 失败: %v", tableName, err)
+		return result
+	}
+	if _, err := a.writeDB().Exec(fmt.Sprintf("CREATE VIRTUAL TABLE %s USING %s(%s)", quoteIdent(tableName), csvModuleName, src.args())); err != nil {
+		result["error"] = fmt.Sprintf("链接文件失败: %v", err)
+		return result
+	}
+	a.recordTableSource(tableName, path)
+
+	result["tableName"] = tableName
+	result["columns"] = src.columns
+	result["encoding"] = src.encoding
+	result["delimiter"] = src.delimiter
+	result["message"] = fmt.Sprintf("已将 %s 链接为表 %s（%d 列），查询时直接读取文件，文件修改后结果随之变化", path, tableName, len(src.columns))
+	return result
+}
+
+// linkedCSVTable 判断表是否为 LinkCSVFile 创建的虚拟表
+func (a *App) linkedCSVTable(tableName string) (bool, error) {
+	var ddl sql.NullString
+	err := a.readDB().QueryRow("SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?", tableName).Scan(&ddl)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("读取表 %s 失败: %v", tableName, err)
+	}
+	return strings.Contains(strings.ToLower(ddl.String), "using "+csvModuleName+"("), nil
+}
+
+// UnlinkCSVFile 取消 CSV 文件的链接（只删除虚拟表，不影响文件本身）
+// wails:export UnlinkCSVFile
+func (a *App) UnlinkCSVFile(tableName string) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	if a.readOnly() {
+		return readOnlyMessage
+	}
+	linked, err := a.linkedCSVTable(tableName)
+	if err != nil {
+		return err.Error()
+	}
+	if !linked {
+		return fmt.Sprintf("表 %s 不是链接的 CSV 文件", tableName)
+	}
+	if _, err := a.writeDB().Exec(fmt.Sprintf("DROP TABLE %s", quoteIdent(tableName))); err != nil {
+		return fmt.Sprintf("取消链接失败: %v", err)
+	}
+	return fmt.Sprintf("已取消链接 %s", tableName)
+}
@@ -0,0 +1,135 @@
+//go:build sqlite_vtable
+
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// csvModuleAvailable 是否编译了 CSV 虚拟表模块（见 csvvtab_stub.go）
+const csvModuleAvailable = true
+
+// registerCSVModule 在连接上注册 csv_file 虚拟表模块
+func registerCSVModule(conn *sqlite3.SQLiteConn) error {
+	return conn.CreateModule(csvModuleName, csvModule{})
+}
+
+// csvModule csv_file 虚拟表模块：每次扫描从头流式读取文件，只读
+type csvModule struct{}
+
+func (m csvModule) Create(c *sqlite3.SQLiteConn, args []string) (sqlite3.VTab, error) {
+	return m.Connect(c, args)
+}
+
+// Connect 按建表语句中记录的列名声明表结构，不读取文件（文件移走后表结构仍可读取，查询时才报错）
+func (m csvModule) Connect(c *sqlite3.SQLiteConn, args []string) (sqlite3.VTab, error) {
+	// args 依次为模块名、数据库名、表名和建表语句中的参数
+	if len(args) < 3 {
+		return nil, fmt.Errorf("%s 参数不足", csvModuleName)
+	}
+	src, err := parseCSVSourceArgs(args[3:])
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]string, len(src.columns))
+	for i, col := range src.columns {
+		columns[i] = quoteIdent(col)
+	}
+	if err := c.DeclareVTab(fmt.Sprintf("CREATE TABLE x(%s)", strings.Join(columns, ", "))); err != nil {
+		return nil, err
+	}
+	return &csvTable{src: src}, nil
+}
+
+func (m csvModule) DestroyModule() {}
+
+type csvTable struct {
+	src csvSource
+}
+
+// BestIndex 只支持全表扫描，条件由 SQLite 逐行判断
+func (t *csvTable) BestIndex(cst []sqlite3.InfoConstraint, ob []sqlite3.InfoOrderBy) (*sqlite3.IndexResult, error) {
+	return &sqlite3.IndexResult{Used: make([]bool, len(cst)), EstimatedCost: 1e9}, nil
+}
+
+func (t *csvTable) Disconnect() error { return nil }
+func (t *csvTable) Destroy() error    { return nil }
+
+func (t *csvTable) Open() (sqlite3.VTabCursor, error) {
+	return &csvCursor{src: t.src}, nil
+}
+
+// csvCursor 一次扫描：Filter 打开文件，Next 读取下一条记录，rowid 为数据行序号
+type csvCursor struct {
+	src    csvSource
+	file   *os.File
+	reader *csv.Reader
+	record []string
+	rowid  int64
+	eof    bool
+}
+
+func (c *csvCursor) Filter(idxNum int, idxStr string, vals []any) error {
+	c.Close()
+	file, reader, err := c.src.open()
+	if err != nil {
+		return err
+	}
+	c.file, c.reader, c.rowid, c.eof = file, reader, 0, false
+	return c.Next()
+}
+
+func (c *csvCursor) Next() error {
+	record, err := c.reader.Read()
+	if err == io.EOF {
+		c.eof = true
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("读取 %s 第 %d 行失败: %v", c.src.path, c.rowid+1, err)
+	}
+	c.record = record
+	c.rowid++
+	return nil
+}
+
+func (c *csvCursor) EOF() bool {
+	return c.eof
+}
+
+// Column 缺少的字段按空字段处理，多出的字段忽略
+func (c *csvCursor) Column(ctx *sqlite3.SQLiteContext, col int) error {
+	field := ""
+	if col < len(c.record) {
+		field = c.record[col]
+	}
+	switch v := csvFieldValue(field, c.src.emptyNull).(type) {
+	case nil:
+		ctx.ResultNull()
+	case int64:
+		ctx.ResultInt64(v)
+	case float64:
+		ctx.ResultDouble(v)
+	case string:
+		ctx.ResultText(v)
+	}
+	return nil
+}
+
+func (c *csvCursor) Rowid() (int64, error) {
+	return c.rowid, nil
+}
+
+func (c *csvCursor) Close() error {
+	if c.file != nil {
+		c.file.Close()
+		c.file = nil
+	}
+	return nil
+}
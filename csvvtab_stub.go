@@ -0,0 +1,12 @@
+//go:build !sqlite_vtable
+
+package main
+
+import "github.com/mattn/go-sqlite3"
+
+// csvModuleAvailable 未使用 sqlite_vtable 构建标签时 go-sqlite3 不提供虚拟表接口，LinkCSVFile 不可用
+const csvModuleAvailable = false
+
+func registerCSVModule(conn *sqlite3.SQLiteConn) error {
+	return nil
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// exchangeRatesTable 汇率表
+const exchangeRatesTable = "_app_exchange_rates"
+
+// exchangeRates 汇率表在内存中的快照（自定义 SQL 函数在连接回调中运行，无法查询数据库），
+// 汇率表的任何修改（包括在 SQL 编辑器中直接修改）提交后由 watchExchangeRates 重新加载
+// rates 为 币种 -> 按日期升序的汇率，汇率表示 1 单位该币种折合多少基准币种
+var exchangeRates = struct {
+	sync.RWMutex
+	base  string
+	rates map[string][]datedRate
+}{base: "CNY"}
+
+type datedRate struct {
+	date string
+	rate float64
+}
+
+// setBaseCurrency 应用 base_currency 设置
+func setBaseCurrency(code string) {
+	exchangeRates.Lock()
+	exchangeRates.base = strings.ToUpper(code)
+	exchangeRates.Unlock()
+}
+
+// exchangeRatesChanged 提交钩子发现汇率表被修改时发出通知
+var exchangeRatesChanged = make(chan struct{}, 1)
+
+// noteExchangeRatesChanged 在提交钩子中调用，metadata 为本次提交修改的元数据表
+func noteExchangeRatesChanged(metadata map[string]bool) {
+	if !metadata[exchangeRatesTable] {
+		return
+	}
+	select {
+	case exchangeRatesChanged <- struct{}{}:
+	default:
+	}
+}
+
+// watchExchangeRates 汇率表被修改后重新加载汇率快照，直到 ctx 结束；
+// 提交钩子在事务完成提交之前运行，等待一段时间再读取，才能读到提交后的汇率
+func (a *App) watchExchangeRates(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-exchangeRatesChanged:
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(dataChangeDebounce):
+		}
+		if err := a.loadExchangeRates(); err != nil {
+			fmt.Println(err)
+		}
+	}
+}
+
+// checkBaseCurrency 汇率都是相对基准币种保存的，已有汇率时修改基准币种会使全部汇率失去意义，因此拒绝修改
+func (a *App) checkBaseCurrency(code string) error {
+	exchangeRates.RLock()
+	base := exchangeRates.base
+	exchangeRates.RUnlock()
+	if strings.ToUpper(code) == base {
+		return nil
+	}
+	var n int
+	if err := a.readDB().QueryRow("SELECT COUNT(*) FROM " + exchangeRatesTable).Scan(&n); err != nil {
+		return fmt.Errorf("读取汇率失败: %v", err)
+	}
+	if n > 0 {
+		return fmt.Errorf("汇率表中已有 %d 条以 %s 为基准的汇率，请先删除这些汇率再修改基准币种", n, base)
+	}
+	return nil
+}
+
+// validateCurrencyCode 校验三位字母的币种代码
+func validateCurrencyCode(v string) error {
+	if len(v) != 3 || strings.ToUpper(v) != v || strings.Trim(v, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return fmt.Errorf("币种代码应为 3 位大写字母，如 CNY")
+	}
+	return nil
+}
+
+// loadExchangeRates 从 _app_exchange_rates 重新加载汇率快照
+func (a *App) loadExchangeRates() error {
+	rows, err := a.readDB().Query("SELECT currency, date, rate FROM _app_exchange_rates ORDER BY currency, date")
+	if err != nil {
+		return fmt.Errorf("读取汇率失败: %v", err)
+	}
+	defer rows.Close()
+	rates := make(map[string][]datedRate)
+	for rows.Next() {
+		var currency string
+		var r datedRate
+		if err := rows.Scan(&currency, &r.date, &r.rate); err != nil {
+			return fmt.Errorf("读取汇率失败: %v", err)
+		}
+		rates[currency] = append(rates[currency], r)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("读取汇率失败: %v", err)
+	}
+	exchangeRates.Lock()
+	exchangeRates.rates = rates
+	exchangeRates.Unlock()
+	return nil
+}
+
+// rateOn 币种在指定日期适用的汇率：取该日期（含）之前最近的一条，date 为空时取最新一条；基准币种汇率为 1
+func rateOn(currency string, date string) (float64, bool) {
+	exchangeRates.RLock()
+	defer exchangeRates.RUnlock()
+	if currency == exchangeRates.base {
+		return 1, true
+	}
+	list := exchangeRates.rates[currency]
+	if len(list) == 0 {
+		return 0, false
+	}
+	if date == "" {
+		return list[len(list)-1].rate, true
+	}
+	i := sort.Search(len(list), func(i int) bool { return list[i].date > date })
+	if i == 0 {
+		return 0, false
+	}
+	return list[i-1].rate, true
+}
+
+// convertCurrencyFunc SQL 函数 CONVERT_CURRENCY(amount, from, to[, date])：按汇率表换算金额
+// 指定日期时使用当日或之前最近的汇率；金额无法解析或缺少汇率时返回 NULL
+func convertCurrencyFunc(args ...interface{}) (interface{}, error) {
+	if len(args) != 3 && len(args) != 4 {
+		return nil, fmt.Errorf("CONVERT_CURRENCY 需要 3 或 4 个参数")
+	}
+	text, ok := udfText(args[0])
+	if !ok {
+		return nil, nil
+	}
+	amount, ok := parseNumberText(text)
+	if !ok {
+		return nil, nil
+	}
+	from, ok1 := udfText(args[1])
+	to, ok2 := udfText(args[2])
+	if !ok1 || !ok2 {
+		return nil, nil
+	}
+	date := ""
+	if len(args) == 4 {
+		d, ok := udfText(args[3])
+		if !ok {
+			return nil, nil
+		}
+		date = normalizeDate(d)
+	}
+	fromRate, ok1 := rateOn(strings.ToUpper(strings.TrimSpace(from)), date)
+	toRate, ok2 := rateOn(strings.ToUpper(strings.TrimSpace(to)), date)
+	if !ok1 || !ok2 || toRate == 0 {
+		return nil, nil
+	}
+	return amount * fromRate / toRate, nil
+}
+
+// SetExchangeRates 批量保存汇率 [{currency, date, rate}]：rate 为 1 单位该币种折合多少基准币种（base_currency 设置），
+// date 为生效日期（YYYY-MM-DD）；rate 小于等于 0 时删除该条汇率
+// wails:export SetExchangeRates
+func (a *App) SetExchangeRates(rates []map[string]interface{}) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+
+	tx, err := a.writeDB().Begin()
+	if err != nil {
+		return fmt.Sprintf("开启事务失败: %v", err)
+	}
+	defer tx.Rollback()
+
+	saved, removed := 0, 0
+	for i, item := range rates {
+		currency, _ := item["currency"].(string)
+		currency = strings.ToUpper(strings.TrimSpace(currency))
+		date, _ := item["date"].(string)
+		rate, _ := item["rate"].(float64)
+		if currency == "" {
+			return fmt.Sprintf("第 %d 条汇率缺少币种", i+1)
+		}
+		d, err := parseCalendarDate(date)
+		if err != nil {
+			return fmt.Sprintf("第 %d 条汇率%s", i+1, err.Error())
+		}
+		if rate <= 0 {
+			if _, err := tx.Exec("DELETE FROM _app_exchange_rates WHERE currency = ? AND date = ?", currency, d.Format("2006-01-02")); err != nil {
+				return fmt.Sprintf("删除汇率失败: %v", err)
+			}
+			removed++
+			continue
+		}
+		if _, err := tx.Exec(`INSERT INTO _app_exchange_rates (currency, date, rate) VALUES (?, ?, ?)
+			ON CONFLICT(currency, date) DO UPDATE SET rate = excluded.rate`, currency, d.Format("2006-01-02"), rate); err != nil {
+			return fmt.Sprintf("保存汇率失败: %v", err)
+		}
+		saved++
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Sprintf("提交事务失败: %v", err)
+	}
+	if err := a.loadExchangeRates(); err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("已保存 %d 条汇率，删除 %d 条", saved, removed)
+}
+
+// ListExchangeRates 列出汇率表中的全部汇率（按币种、日期排序）
+// wails:export ListExchangeRates
+func (a *App) ListExchangeRates() map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	rows, err := a.readDB().Query("SELECT currency, date, rate FROM _app_exchange_rates ORDER BY currency, date")
+	if err != nil {
+		result["error"] = fmt.Sprintf("读取汇率失败: %v", err)
+		return result
+	}
+	defer rows.Close()
+	var rates []map[string]interface{}
+	for rows.Next() {
+		var currency, date string
+		var rate float64
+		if err := rows.Scan(&currency, &date, &rate); err != nil {
+			result["error"] = fmt.Sprintf("读取汇率失败: %v", err)
+			return result
+		}
+		rates = append(rates, map[string]interface{}{"currency": currency, "date": date, "rate": rate})
+	}
+	result["base"] = a.setting("base_currency")
+	result["rates"] = rates
+	result["message"] = fmt.Sprintf("共 %d 条汇率", len(rates))
+	return result
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// dataChangedEvent 数据变化时发送给前端的事件名，负载为 {version, tables}；
+// tables 为空表示无法确定具体的表（如 DDL 或清空表），前端应全部刷新
+const dataChangedEvent = "data-changed"
+
+// dataChangeDebounce 合并短时间内连续提交的变化，并保证事件发出时事务已经提交完成
+const dataChangeDebounce = 200 * time.Millisecond
+
+// rebuildSuffix rebuildTable 使用的临时表后缀，记录变化时归到原表
+const rebuildSuffix = "__rebuild"
+
+// dataVersion 数据库的数据版本，任何写事务提交时递增（由连接上的提交钩子维护）
+var dataVersion atomic.Int64
+
+// commitsInFlight 已触发提交钩子、但连接还没归还连接池的数据提交数。提交钩子在提交对其他连接可见之前运行，
+// 这期间读到新版本号的查询仍可能读到旧快照，结果不能按新版本缓存（见 readSnapshot）
+var commitsInFlight atomic.Int64
+
+// readSnapshot 开始读取前调用：返回当前数据版本，以及读到的结果能否按该版本缓存。
+// 先读版本再读未完成提交数：钩子先递增 commitsInFlight 再递增版本，读到新版本时一定也能看到未完成的提交
+func readSnapshot() (version int64, settled bool) {
+	version = dataVersion.Load()
+	return version, commitsInFlight.Load() == 0
+}
+
+// dataVersions 每张表的数据版本（应用启动后累计），以及等待通知前端的变化
+var dataVersions = struct {
+	sync.Mutex
+	tables  map[string]int64
+	pending map[string]bool
+	unknown bool
+	// epoch 无法确定具体表的提交（DDL、清空表等）次数，按表缓存的数据需要同时比较它
+	epoch  int64
+	notify chan struct{}
+}{
+	tables:  make(map[string]int64),
+	pending: make(map[string]bool),
+	notify:  make(chan struct{}, 1),
+}
+
+// metadataTablePrefix 应用元数据表（查询历史、导出历史、设置等）的前缀
+const metadataTablePrefix = "_app_"
+
+// connChanges 单个连接当前事务中被修改的表（由更新钩子和授权回调收集，提交或回滚时清空）；
+// 元数据表单独记录，只改了元数据的事务不算数据变化；后台导入的暂存表（见 throttle.go）在并入目标表时才算变化
+type connChanges struct {
+	tables   map[string]bool
+	metadata map[string]bool
+	schema   bool // 修改了用户表、视图、索引或触发器的结构
+	staging  bool // 写入或新建、删除了暂存表
+	// unsettled 该连接上已触发提交钩子、尚未完成的数据提交数，连接归还连接池时清零
+	unsettled atomic.Int64
+}
+
+func (c *connChanges) add(dbName string, table string) {
+	if dbName != "main" || strings.HasPrefix(table, "sqlite_") {
+		return
+	}
+	if strings.HasSuffix(table, importStagingSuffix) {
+		c.staging = true
+		return
+	}
+	if strings.HasPrefix(table, metadataTablePrefix) {
+		if c.metadata == nil {
+			c.metadata = make(map[string]bool)
+		}
+		c.metadata[table] = true
+		return
+	}
+	if c.tables == nil {
+		c.tables = make(map[string]bool)
+	}
+	c.tables[strings.TrimSuffix(table, rebuildSuffix)] = true
+}
+
+// authorize 授权回调：DDL 和 DELETE 在语句编译时记录。更新钩子看不到表结构的变化，
+// 也看不到 SQLite 对不带条件的 DELETE 做的整表清空
+func (c *connChanges) authorize(op int, arg1, arg2, dbName string) {
+	var table string
+	switch op {
+	case sqlite3.SQLITE_CREATE_TABLE, sqlite3.SQLITE_DROP_TABLE, sqlite3.SQLITE_CREATE_VIEW, sqlite3.SQLITE_DROP_VIEW,
+		sqlite3.SQLITE_CREATE_VTABLE, sqlite3.SQLITE_DROP_VTABLE:
+		table = arg1
+	case sqlite3.SQLITE_CREATE_INDEX, sqlite3.SQLITE_DROP_INDEX, sqlite3.SQLITE_CREATE_TRIGGER, sqlite3.SQLITE_DROP_TRIGGER:
+		table = arg2
+	case sqlite3.SQLITE_ALTER_TABLE:
+		// ALTER TABLE 的第一个参数是数据库名
+		dbName, table = arg1, arg2
+	case sqlite3.SQLITE_DELETE:
+		if !strings.HasPrefix(arg1, "sqlite_") {
+			c.add(dbName, arg1)
+		}
+		return
+	default:
+		return
+	}
+	if dbName != "main" || strings.HasPrefix(table, "sqlite_") {
+		return
+	}
+	// 新建、删除暂存表不算结构变化，暂存表改名为目标表才是
+	if strings.HasPrefix(table, metadataTablePrefix) || op != sqlite3.SQLITE_ALTER_TABLE && strings.HasSuffix(table, importStagingSuffix) {
+		c.add(dbName, table)
+		return
+	}
+	c.schema = true
+}
+
+func (c *connChanges) take() (tables map[string]bool, metadata map[string]bool, schema bool, staging bool) {
+	tables, metadata, schema, staging = c.tables, c.metadata, c.schema, c.staging
+	c.tables, c.metadata, c.schema, c.staging = nil, nil, false, false
+	return tables, metadata, schema, staging
+}
+
+// commit 提交钩子，返回 0 表示允许提交；数据提交在递增版本之前记为未完成，直到连接归还连接池
+func (c *connChanges) commit() int {
+	tables, metadata, schema, staging := c.take()
+	commitDataChanges(tables, metadata, schema, staging, func() {
+		c.unsettled.Add(1)
+		commitsInFlight.Add(1)
+	})
+	return 0
+}
+
+// settle 连接上的语句和事务都已结束，之前触发钩子的提交已经对其他连接可见
+func (c *connChanges) settle() {
+	if n := c.unsettled.Swap(0); n > 0 {
+		commitsInFlight.Add(-n)
+	}
+}
+
+// commitDataChanges 递增全局和各表的数据版本并通知前端，begin 在递增版本之前调用；
+// 只修改了元数据表或暂存表的提交（如每次查询记录的查询历史、后台导入的每一批）只递增元数据表自己的版本，
+// 不使查询结果缓存失效，也不通知前端
+func commitDataChanges(tables map[string]bool, metadata map[string]bool, schema bool, staging bool, begin func()) {
+	noteExchangeRatesChanged(metadata)
+	noteSchemaChanged(schema)
+	if !schema && len(tables) == 0 && (len(metadata) > 0 || staging) {
+		dataVersions.Lock()
+		for table := range metadata {
+			dataVersions.tables[table]++
+		}
+		dataVersions.Unlock()
+		return
+	}
+	begin()
+	dataVersion.Add(1)
+
+	dataVersions.Lock()
+	if schema || len(tables) == 0 {
+		dataVersions.unknown = true
+		dataVersions.epoch++
+	}
+	for table := range tables {
+		dataVersions.tables[table]++
+		dataVersions.pending[table] = true
+	}
+	for table := range metadata {
+		dataVersions.tables[table]++
+	}
+	dataVersions.Unlock()
+
+	select {
+	case dataVersions.notify <- struct{}{}:
+	default:
+	}
+}
+
+// noteConnectionSwitched 切换数据库连接（打开、关闭分享包，重新连接）：换成另一个库后所有表都算变化，
+// 递增全局版本和 epoch，按版本缓存的查询结果和统计结果全部失效
+func noteConnectionSwitched() {
+	dataVersion.Add(1)
+	dataVersions.Lock()
+	dataVersions.epoch++
+	dataVersions.Unlock()
+}
+
+// tableVersion 表的数据版本，与 epoch 一起比较才能发现 DDL 等无法归到具体表的变化
+type tableVersion struct {
+	epoch   int64
+	version int64
+}
+
+// currentTableVersion 表的当前数据版本
+func currentTableVersion(table string) tableVersion {
+	dataVersions.Lock()
+	defer dataVersions.Unlock()
+	return tableVersion{epoch: dataVersions.epoch, version: dataVersions.tables[table]}
+}
+
+// watchDataChanges 将合并后的数据变化以事件发送给前端，直到 ctx 结束
+func watchDataChanges(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-dataVersions.notify:
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(dataChangeDebounce):
+		}
+
+		dataVersions.Lock()
+		tables := make([]string, 0, len(dataVersions.pending))
+		if !dataVersions.unknown {
+			for table := range dataVersions.pending {
+				tables = append(tables, table)
+			}
+		}
+		dataVersions.pending = make(map[string]bool)
+		dataVersions.unknown = false
+		dataVersions.Unlock()
+
+		sort.Strings(tables)
+		runtime.EventsEmit(ctx, dataChangedEvent, map[string]interface{}{
+			"version": dataVersion.Load(),
+			"tables":  tables,
+		})
+	}
+}
+
+// GetDataVersions 获取全局数据版本和各表的数据版本，用于判断缓存或已打开的结果是否需要刷新
+// wails:export GetDataVersions
+func (a *App) GetDataVersions() map[string]interface{} {
+	dataVersions.Lock()
+	tables := make(map[string]interface{}, len(dataVersions.tables))
+	for table, v := range dataVersions.tables {
+		tables[table] = v
+	}
+	dataVersions.Unlock()
+
+	return map[string]interface{}{
+		"version": dataVersion.Load(),
+		"tables":  tables,
+	}
+}
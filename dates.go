@@ -0,0 +1,221 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// dateConfig 日期解析/格式化使用的时区与区域设置（自定义 SQL 函数在连接回调中运行，无法访问 App，故放在包级变量）
+var dateConfig = struct {
+	sync.RWMutex
+	location *time.Location
+	locale   string
+}{location: time.Local, locale: "zh-CN"}
+
+// setDateLocation 应用 timezone 设置
+func setDateLocation(name string) {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		fmt.Printf("加载时区 %s 失败: %v\n", name, err)
+		return
+	}
+	dateConfig.Lock()
+	dateConfig.location = loc
+	dateConfig.Unlock()
+}
+
+// setDateLocale 应用 locale 设置
+func setDateLocale(locale string) {
+	dateConfig.Lock()
+	dateConfig.locale = locale
+	dateConfig.Unlock()
+}
+
+func currentDateConfig() (*time.Location, string) {
+	dateConfig.RLock()
+	defer dateConfig.RUnlock()
+	return dateConfig.location, dateConfig.locale
+}
+
+// validateTimezone 校验 IANA 时区名（如 Asia/Shanghai），Local 表示系统时区
+func validateTimezone(name string) error {
+	if _, err := time.LoadLocation(name); err != nil {
+		return fmt.Errorf("无法识别的时区: %s", name)
+	}
+	return nil
+}
+
+// 带时区偏移的格式解析后转换到设置的时区，其余格式按设置的时区解释
+var dateTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-1-2 15:04:05",
+	"2006-1-2 15:04",
+	"2006/1/2 15:04:05",
+	"2006/1/2 15:04",
+	"2006年1月2日 15:04:05",
+	"2006年1月2日 15:04",
+	"2006年1月2日15:04:05",
+	"2006年1月2日15:04",
+}
+
+var dateLayouts = []string{
+	"2006-1-2",
+	"2006/1/2",
+	"2006.1.2",
+	"2006年1月2日",
+	"2006年1月2号",
+	"20060102",
+}
+
+// 日/月/年 顺序有歧义的格式，按 locale 决定
+var slashDatePattern = regexp.MustCompile(`^(\d{1,2})[/.-](\d{1,2})[/.-](\d{4})$`)
+
+// excelEpoch Excel 日期序列号的起点（兼容 1900 闰年错误）
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// parseDateText 解析常见的日期/日期时间文本，hasTime 表示是否包含时间部分
+func parseDateText(s string) (t time.Time, hasTime bool, ok bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, false, false
+	}
+	loc, locale := currentDateConfig()
+
+	for _, layout := range dateTimeLayouts {
+		if parsed, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return parsed.In(loc), true, true
+		}
+	}
+	for _, layout := range dateLayouts {
+		if parsed, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return parsed, false, true
+		}
+	}
+
+	if m := slashDatePattern.FindStringSubmatch(s); m != nil {
+		day, month := m[1], m[2]
+		if locale == "en-US" {
+			day, month = m[2], m[1]
+		}
+		if parsed, err := time.ParseInLocation("2006-1-2", m[3]+"-"+month+"-"+day, loc); err == nil {
+			return parsed, false, true
+		}
+	}
+	return time.Time{}, false, false
+}
+
+// formatISODate 以 ISO 格式输出，用于入库和 PARSE_DATE
+func formatISODate(t time.Time, hasTime bool) string {
+	if hasTime {
+		return t.Format("2006-01-02 15:04:05")
+	}
+	return t.Format("2006-01-02")
+}
+
+// localeDateLayout 按 locale 返回展示用的 Go 日期格式
+func localeDateLayout(locale string, hasTime bool) string {
+	layout := "2006-01-02"
+	switch locale {
+	case "en-US":
+		layout = "01/02/2006"
+	case "en-GB":
+		layout = "02/01/2006"
+	}
+	if hasTime {
+		layout += " 15:04:05"
+	}
+	return layout
+}
+
+// localeExcelDateFormat 按 locale 返回导出 Excel 时使用的日期数字格式
+func localeExcelDateFormat(locale string, hasTime bool) string {
+	format := "yyyy-mm-dd"
+	switch locale {
+	case "en-US":
+		format = "mm/dd/yyyy"
+	case "en-GB":
+		format = "dd/mm/yyyy"
+	}
+	if hasTime {
+		format += " hh:mm:ss"
+	}
+	return format
+}
+
+// parseDateFunc SQL 函数 PARSE_DATE(value)：返回 ISO 格式日期，数值参数按 Excel 日期序列号处理，无法解析时返回 NULL
+func parseDateFunc(v interface{}) interface{} {
+	switch x := v.(type) {
+	case int64:
+		return excelSerialDate(float64(x))
+	case float64:
+		return excelSerialDate(x)
+	}
+	s, ok := udfText(v)
+	if !ok {
+		return nil
+	}
+	t, hasTime, ok := parseDateText(s)
+	if !ok {
+		return nil
+	}
+	return formatISODate(t, hasTime)
+}
+
+func excelSerialDate(serial float64) interface{} {
+	if serial < 1 || serial > 2958465 {
+		return nil
+	}
+	t := excelEpoch.Add(time.Duration(serial * 24 * float64(time.Hour))).Round(time.Second)
+	return formatISODate(t, serial != float64(int64(serial)))
+}
+
+// formatDateFunc SQL 函数 FORMAT_DATE(value)：按 locale 设置格式化日期，无法解析时返回 NULL
+func formatDateFunc(v interface{}) interface{} {
+	s, ok := udfText(v)
+	if !ok {
+		return nil
+	}
+	t, hasTime, ok := parseDateText(s)
+	if !ok {
+		return nil
+	}
+	_, locale := currentDateConfig()
+	return t.Format(localeDateLayout(locale, hasTime))
+}
+
+// normalizeDate 导入时的规范化：带分隔符的日期文本转为 ISO 格式，纯数字不转换（避免误伤金额、编号）
+func normalizeDate(s string) string {
+	if !strings.ContainsAny(s, "-/.年") {
+		return s
+	}
+	if t, hasTime, ok := parseDateText(s); ok {
+		return formatISODate(t, hasTime)
+	}
+	return s
+}
+
+var isoDatePattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}( \d{2}:\d{2}:\d{2})?$`)
+
+// isoDateValue 识别导出数据中的 ISO 日期字符串，用于写入真正的 Excel 日期单元格
+func isoDateValue(v interface{}) (time.Time, bool, bool) {
+	s, ok := v.(string)
+	if !ok || !isoDatePattern.MatchString(s) {
+		return time.Time{}, false, false
+	}
+	hasTime := len(s) > 10
+	layout := "2006-01-02"
+	if hasTime {
+		layout = "2006-01-02 15:04:05"
+	}
+	t, err := time.Parse(layout, s)
+	if err != nil {
+		return time.Time{}, false, false
+	}
+	return t, hasTime, true
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// retireGrace 旧连接上登记的操作全部结束后，再等待这段时间才关闭，留给未登记为操作的短查询完成
+const retireGrace = 2 * time.Second
+
+// dbHandles 一组同时切换的数据库连接及其状态：重连、打开和关闭分享包时整体替换，不会读到新旧混合的状态
+// active 为开始时使用这组连接、尚未结束的操作数（见 progress.go），被替换后等它们结束再关闭
+type dbHandles struct {
+	db       *sql.DB        // 写连接池：导入、修改和元数据读写
+	reader   *sql.DB        // 只读连接池：查询和导出（见 readpool.go）
+	readOnly bool           // 另一个实例持有写锁，或正在查看只读分享包
+	viewer   *viewerSession // 打开的只读分享包（见 viewer.go），为 nil 时使用 data.db
+
+	mu     sync.Mutex
+	active int
+	idle   chan struct{} // drain 等待时创建，active 降为 0 时关闭
+}
+
+// acquire 登记一个使用这组连接的操作
+func (h *dbHandles) acquire() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.active++
+}
+
+// release 注销 acquire 登记的操作
+func (h *dbHandles) release() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.active--
+	if h.active == 0 && h.idle != nil {
+		close(h.idle)
+		h.idle = nil
+	}
+}
+
+// drain 等待登记的操作全部结束
+func (h *dbHandles) drain() {
+	h.mu.Lock()
+	if h.active == 0 {
+		h.mu.Unlock()
+		return
+	}
+	if h.idle == nil {
+		h.idle = make(chan struct{})
+	}
+	idle := h.idle
+	h.mu.Unlock()
+	<-idle
+}
+
+// close 关闭这组连接
+func (h *dbHandles) close() {
+	if h.db != nil {
+		h.db.Close()
+	}
+	if h.reader != nil {
+		h.reader.Close()
+	}
+}
+
+// noHandles 数据库连接未初始化时 conn 返回的空连接
+var noHandles = &dbHandles{}
+
+// conn 当前使用的一组连接，从不返回 nil
+func (a *App) conn() *dbHandles {
+	if h := a.handles.Load(); h != nil {
+		return h
+	}
+	return noHandles
+}
+
+// writeDB 写连接池，连接未初始化时为 nil
+func (a *App) writeDB() *sql.DB {
+	return a.conn().db
+}
+
+// readOnly 是否只读（另一个实例持有写锁或正在查看只读分享包）
+func (a *App) readOnly() bool {
+	return a.conn().readOnly
+}
+
+// viewer 打开的只读分享包，未打开时为 nil
+func (a *App) viewer() *viewerSession {
+	return a.conn().viewer
+}
+
+// swapHandles 换上新的一组连接，返回被替换的连接；调用方须持有 switchMu。
+// 切换前后各递增一次数据版本（见 noteConnectionSwitched）：切换前使旧库的缓存立即失效，
+// 切换后使用旧连接读取中的查询和统计不会再按新版本存入缓存
+func (a *App) swapHandles(next *dbHandles) *dbHandles {
+	noteConnectionSwitched()
+	old := a.handles.Swap(next)
+	noteConnectionSwitched()
+	return old
+}
+
+// retireHandles 在后台等待仍在使用旧连接的操作结束后关闭旧连接，不阻塞切换
+func retireHandles(h *dbHandles) {
+	if h == nil || h == noHandles {
+		return
+	}
+	go func() {
+		h.drain()
+		time.Sleep(retireGrace)
+		h.close()
+		fmt.Println("旧的数据库连接已关闭")
+	}()
+}
+
+// Shutdown 应用退出时执行（Wails OnShutdown）：关闭写连接池和只读连接池（查看分享包时还有原来的 data.db 连接），并释放实例锁。
+// 关闭的连接留在原处，仍在后台执行的导出等操作得到“连接已关闭”的错误，而不是读到 nil 连接
+func (a *App) Shutdown(ctx context.Context) {
+	a.switchMu.Lock()
+	defer a.switchMu.Unlock()
+	if h := a.handles.Load(); h != nil {
+		if h.viewer != nil && h.viewer.previous != nil {
+			h.viewer.previous.close()
+		}
+		h.close()
+	}
+	if a.instanceLock != nil {
+		a.instanceLock.Close()
+	}
+}
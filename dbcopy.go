@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// liveDatabaseFiles 正在使用的数据库文件：data.db 及其 -wal、-shm 和实例锁文件，打开只读分享包时还有分享包本身
+func (a *App) liveDatabaseFiles() []string {
+	files := []string{"./data.db", "./data.db-wal", "./data.db-shm", "./data.db-journal", instanceLockPath}
+	if v := a.viewer(); v != nil {
+		files = append(files, v.path)
+	}
+	return files
+}
+
+// checkBackupTarget 拒绝把备份或导出的数据库写到正在使用的数据库文件上（覆盖会删除当前数据）
+func (a *App) checkBackupTarget(destPath string) error {
+	dest, err := filepath.Abs(destPath)
+	if err != nil {
+		return fmt.Errorf("无效的保存路径 %s: %v", destPath, err)
+	}
+	destInfo, statErr := os.Stat(dest)
+	for _, f := range a.liveDatabaseFiles() {
+		live, err := filepath.Abs(f)
+		if err != nil {
+			continue
+		}
+		same := strings.EqualFold(filepath.Clean(live), filepath.Clean(dest))
+		if !same && statErr == nil {
+			// 大小写不敏感的文件系统或符号链接下路径不同也可能是同一个文件
+			if liveInfo, err := os.Stat(live); err == nil {
+				same = os.SameFile(liveInfo, destInfo)
+			}
+		}
+		if same {
+			return fmt.Errorf("不能保存到正在使用的数据库文件 %s，请选择其他位置", filepath.Base(dest))
+		}
+	}
+	return nil
+}
+
+// backupDatabase 使用 SQLite 在线备份 API 将当前数据库完整复制到 destPath（目标文件已存在时会被覆盖）：
+// 先写入同目录下的临时文件，prepare 不为 nil 时在临时文件上完成裁剪等处理，全部成功后再替换目标文件，
+// 失败时目标文件保持原样，也不会留下未裁剪的完整副本
+func (a *App) backupDatabase(destPath string, prepare func(tmpPath string) error) error {
+	if err := a.checkBackupTarget(destPath); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(destPath), filepath.Base(destPath)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("创建目标数据库失败: %v", err)
+	}
+	tmpPath := tmp.Name()
+	tmp.Close()
+	err = a.backupTo(tmpPath)
+	if err == nil && prepare != nil {
+		err = prepare(tmpPath)
+	}
+	if err != nil {
+		for _, suffix := range []string{"", "-journal", "-wal", "-shm"} {
+			os.Remove(tmpPath + suffix)
+		}
+		return err
+	}
+	if err := os.Rename(tmpPath, destPath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("保存数据库文件失败: %v", err)
+	}
+	return nil
+}
+
+// backupTo 将当前数据库备份到空文件 destPath
+func (a *App) backupTo(destPath string) error {
+	ctx := context.Background()
+	destDB, err := sql.Open(scratchDriverName, destPath)
+	if err != nil {
+		return fmt.Errorf("创建目标数据库失败: %v", err)
+	}
+	defer destDB.Close()
+
+	destConn, err := destDB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("创建目标数据库失败: %v", err)
+	}
+	defer destConn.Close()
+	srcConn, err := a.writeDB().Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("获取数据库连接失败: %v", err)
+	}
+	defer srcConn.Close()
+
+	return destConn.Raw(func(destRaw interface{}) error {
+		return srcConn.Raw(func(srcRaw interface{}) error {
+			dest, ok1 := rawSQLiteConn(destRaw)
+			src, ok2 := rawSQLiteConn(srcRaw)
+			if !ok1 || !ok2 {
+				return fmt.Errorf("数据库驱动不支持备份")
+			}
+			backup, err := dest.Backup("main", src, "main")
+			if err != nil {
+				return fmt.Errorf("开始备份失败: %v", err)
+			}
+			for {
+				done, err := backup.Step(-1)
+				if err != nil {
+					backup.Finish()
+					return fmt.Errorf("备份失败: %v", err)
+				}
+				if done {
+					break
+				}
+			}
+			return backup.Finish()
+		})
+	})
+}
+
+// pruneDatabaseCopy 在数据库副本中只保留指定的表：
+// 删除其他用户表和视图；带 table_name 列的元数据表只保留所选表的记录，其余元数据（设置、历史等）清空。
+// 副本不是当前数据库，使用不安装提交钩子的驱动，裁剪不会改变数据版本或通知前端
+func pruneDatabaseCopy(copyPath string, keep []string) error {
+	db, err := sql.Open(scratchDriverName, copyPath)
+	if err != nil {
+		return fmt.Errorf("打开数据库副本失败: %v", err)
+	}
+	defer db.Close()
+
+	keepSet := make(map[string]bool)
+	for _, t := range keep {
+		keepSet[t] = true
+	}
+
+	rows, err := db.Query(`SELECT name, type FROM sqlite_master
+		WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite\_%' ESCAPE '\'`)
+	if err != nil {
+		return fmt.Errorf("读取数据库副本失败: %v", err)
+	}
+	type relation struct{ name, kind string }
+	var relations []relation
+	for rows.Next() {
+		var r relation
+		if err := rows.Scan(&r.name, &r.kind); err != nil {
+			rows.Close()
+			return fmt.Errorf("读取数据库副本失败: %v", err)
+		}
+		relations = append(relations, r)
+	}
+	rows.Close()
+
+	// 先删视图再删表，避免视图引用已删除的表
+	for _, kind := range []string{"view", "table"} {
+		for _, r := range relations {
+			if r.kind != kind {
+				continue
+			}
+			if strings.HasPrefix(r.name, "_app_") {
+				if kind == "table" {
+					if err := pruneMetaTable(db, r.name, keep); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if keepSet[r.name] {
+				continue
+			}
+			if _, err := db.Exec(fmt.Sprintf("DROP %s %s", strings.ToUpper(kind), quoteIdent(r.name))); err != nil {
+				return fmt.Errorf("删除 %s 失败: %v", r.name, err)
+			}
+		}
+	}
+
+	if _, err := db.Exec("VACUUM"); err != nil {
+		return fmt.Errorf("压缩数据库副本失败: %v", err)
+	}
+	return nil
+}
+
+// pruneMetaTable 元数据表只保留所选表相关的记录
+func pruneMetaTable(db *sql.DB, metaTable string, keep []string) error {
+	columns, err := tableColumnInfos(db, metaTable)
+	if err != nil {
+		return err
+	}
+	hasTableName := false
+	for _, c := range columns {
+		if c.name == "table_name" {
+			hasTableName = true
+		}
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s", quoteIdent(metaTable))
+	var args []interface{}
+	if hasTableName && len(keep) > 0 {
+		query += " WHERE table_name NOT IN (" + strings.TrimSuffix(strings.Repeat("?,", len(keep)), ",") + ")"
+		for _, t := range keep {
+			args = append(args, t)
+		}
+	}
+	if _, err := db.Exec(query, args...); err != nil {
+		return fmt.Errorf("清理元数据 %s 失败: %v", metaTable, err)
+	}
+	return nil
+}
+
+// ExportDatabaseCopy 将所选表（连同其数据字典、标签等元数据）导出为独立的 SQLite 数据库文件，便于分享给同样使用本工具的同事
+// savePath 为空时弹出保存对话框
+// wails:export ExportDatabaseCopy
+func (a *App) ExportDatabaseCopy(savePath string, tables []string) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	if len(tables) == 0 {
+		return "请选择要导出的表"
+	}
+	for _, t := range tables {
+		if _, err := a.tableColumns(t); err != nil {
+			return err.Error()
+		}
+	}
+
+	if savePath == "" {
+		var err error
+		savePath, err = runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+			Title:           "导出数据库副本",
+			DefaultFilename: "分析数据.db",
+			Filters:         []runtime.FileFilter{{Pattern: "*.db;*.sqlite", DisplayName: "SQLite 数据库"}},
+		})
+		if err != nil {
+			return fmt.Sprintf("文件保存失败: %v", err)
+		}
+		if savePath == "" {
+			return "取消导出"
+		}
+	}
+
+	if err := a.backupDatabase(savePath, func(tmpPath string) error {
+		return pruneDatabaseCopy(tmpPath, tables)
+	}); err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("数据库副本导出成功: %s（共 %d 张表）", savePath, len(tables))
+}
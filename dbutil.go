@@ -0,0 +1,470 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// quoteIdent 为 SQLite 标识符加双引号，内部双引号转义
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// stringPtr 返回字符串指针（excelize 的样式字段需要）
+func stringPtr(s string) *string {
+	return &s
+}
+
+// quoteLiteral 生成 SQL 字符串字面量（用于无法绑定参数的视图定义等场景）
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// sanitizeName 将任意文本转为可用作表名的形式（非字母数字字符替换为下划线，数字开头时加 t_ 前缀）
+func sanitizeName(s string) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, s)
+	if name == "" || unicode.IsDigit([]rune(name)[0]) {
+		name = "t_" + name
+	}
+	return name
+}
+
+// tableNameFromFile 根据文件名生成表名
+func tableNameFromFile(filePath string) string {
+	return sanitizeName(strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath)))
+}
+
+// defaultColumnNames 生成 column1..N 形式的默认列名
+func defaultColumnNames(n int) []string {
+	columns := make([]string, n)
+	for i := 0; i < n; i++ {
+		columns[i] = fmt.Sprintf("column%d", i+1)
+	}
+	return columns
+}
+
+// writeTable 删除同名旧表，按给定列名建表（全部为 TEXT，排序规则取 default_collation 设置）并在事务中批量写入数据
+// 行长度不足时补空值（按 null_policy 写入空字符串或 NULL），超出部分丢弃；导入过程记录在导入日志中（见 importjournal.go）
+func (a *App) writeTable(tableName string, columns []string, rows [][]string) error {
+	if a.readOnly() {
+		return errors.New(readOnlyMessage)
+	}
+	if err := checkDiskSpace(filepath.Dir("./data.db"), estimateRowsSize(rows)*importSpaceFactor); err != nil {
+		return err
+	}
+	journalID := a.beginImportJournal(tableName, len(rows))
+	op := a.beginOperation("", "import", fmt.Sprintf("导入表 %s", tableName))
+	err := withBusyRetry(func() error { return a.writeTableRows(tableName, columns, rows, op) })
+	a.finishImportJournal(journalID, err)
+	if err == nil {
+		a.analyzeAfterImport(tableName, len(rows))
+	}
+	op.finish(fmt.Sprintf("表 %s 导入完成（%d 行）", tableName, len(rows)), err)
+	return err
+}
+
+// writeTableRows writeTable 的实际写入过程，op 不为 nil 时报告写入进度
+// 删除旧表、建表和写入在一个事务中完成；后台优先级时先分批写入暂存表（见 throttle.go），最后的事务中删除旧表并把暂存表改名
+func (a *App) writeTableRows(tableName string, columns []string, rows [][]string, op *operation) error {
+	withHash := a.setting("row_hash") == "true"
+	background := a.backgroundImport()
+	staging := stagingTableName(tableName)
+	if background {
+		err := a.stageInBatches(staging, a.textTableSQL(staging, columns, withHash), len(rows), func(tx *sql.Tx, start, end int) error {
+			return a.insertTextRows(tx, staging, columns, rows[start:end], withHash, nil)
+		}, op)
+		if err != nil {
+			return err
+		}
+	}
+
+	tx, err := a.writeDB().Begin()
+	if err != nil {
+		if background {
+			a.dropStagingTable(staging)
+		}
+		return fmt.Errorf("开启事务失败: %v", err)
+	}
+	fail := func(err error) error {
+		tx.Rollback()
+		if background {
+			a.dropStagingTable(staging)
+		}
+		return err
+	}
+	// 删除旧表
+	if _, err := tx.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", quoteIdent(tableName))); err != nil {
+		return fail(fmt.Errorf("删除表 %s 失败: %v", tableName, err))
+	}
+	if background {
+		// 引用原表的视图在原表删除后暂时失效，按旧规则改名时不检查视图（同 rebuildTable）
+		for _, stmt := range []string{
+			"PRAGMA legacy_alter_table = ON",
+			fmt.Sprintf("ALTER TABLE %s RENAME TO %s", quoteIdent(staging), quoteIdent(tableName)),
+			"PRAGMA legacy_alter_table = OFF",
+		} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return fail(fmt.Errorf("创建表 %s 失败: %v", tableName, err))
+			}
+		}
+	} else {
+		if _, err := tx.Exec(a.textTableSQL(tableName, columns, withHash)); err != nil {
+			return fail(fmt.Errorf("创建表 %s 失败: %v", tableName, err))
+		}
+		if err := a.insertTextRows(tx, tableName, columns, rows, withHash, op); err != nil {
+			return fail(err)
+		}
+	}
+
+	// 开启 normalize_booleans 时在同一事务内转换布尔型列
+	if a.setting("normalize_booleans") == "true" {
+		found, err := a.detectBooleanColumns(tx, tableName)
+		if err != nil {
+			return fail(err)
+		}
+		if len(found) > 0 {
+			boolColumns := make([]string, len(found))
+			for i, item := range found {
+				boolColumns[i] = item["column"].(string)
+			}
+			if err := normalizeBooleanColumns(tx, tableName, boolColumns); err != nil {
+				return fail(err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		if background {
+			a.dropStagingTable(staging)
+		}
+		return fmt.Errorf("提交事务失败: %v", err)
+	}
+	return nil
+}
+
+// textTableSQL 导入文本行的建表语句：列类型见 importColumnType，withHash 时带整行哈希列
+func (a *App) textTableSQL(tableName string, columns []string, withHash bool) string {
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = quoteIdent(col)
+	}
+	colType := a.importColumnType()
+	colDefs := strings.Join(quoted, " "+colType+", ") + " " + colType
+	if withHash {
+		colDefs += ", " + quoteIdent(rowHashColumn) + " TEXT"
+	}
+	return fmt.Sprintf("CREATE TABLE %s (%s)", quoteIdent(tableName), colDefs)
+}
+
+// importColumnType 导入建表时的列类型：TEXT，排序规则取 default_collation 设置
+func (a *App) importColumnType() string {
+	colType := "TEXT"
+	if collation := a.setting("default_collation"); collation != "BINARY" {
+		colType += " COLLATE " + collation
+	}
+	return colType
+}
+
+// insertTextRows 在事务内写入导入的文本行：空值按 null_policy 写入空字符串或 NULL，按导入设置规范化日期和中文数字，
+// withHash 时同时写入整行哈希列；行长度不足时补空值，超出部分丢弃
+func (a *App) insertTextRows(tx *sql.Tx, tableName string, columns []string, rows [][]string, withHash bool, op *operation) error {
+	colCount := len(columns)
+	quoted := make([]string, colCount, colCount+1)
+	for i, col := range columns {
+		quoted[i] = quoteIdent(col)
+	}
+	if withHash {
+		quoted = append(quoted, quoteIdent(rowHashColumn))
+	}
+	insertSQL := fmt.Sprintf(
+		"INSERT INTO %s (%s) VALUES (%s)",
+		quoteIdent(tableName),
+		strings.Join(quoted, ", "),
+		strings.TrimSuffix(strings.Repeat("?,", len(quoted)), ","),
+	)
+	stmt, err := tx.Prepare(insertSQL)
+	if err != nil {
+		return fmt.Errorf("预编译插入语句失败: %v", err)
+	}
+	defer stmt.Close()
+
+	normalizeCN := a.setting("normalize_cn_numbers") == "true"
+	normalizeDates := a.setting("normalize_dates") == "true"
+	emptyAsNull := a.importEmptyAsNull()
+	values := make([]interface{}, len(quoted))
+	for rowIdx, row := range rows {
+		if err := op.canceled(); err != nil {
+			return err
+		}
+		for i := 0; i < colCount; i++ {
+			if i >= len(row) || row[i] == "" {
+				if emptyAsNull {
+					values[i] = nil
+				} else {
+					values[i] = ""
+				}
+				continue
+			}
+			v := row[i]
+			if normalizeDates {
+				v = normalizeDate(v)
+			}
+			if normalizeCN {
+				v = normalizeChineseNumber(v)
+			}
+			values[i] = v
+		}
+		if withHash {
+			values[colCount] = rowHash(values[:colCount])
+		}
+		if _, err := stmt.Exec(values...); err != nil {
+			return fmt.Errorf("插入第 %d 行数据失败: %v", rowIdx+1, err)
+		}
+		op.progress("write", rowIdx+1, len(rows), fmt.Sprintf("已写入 %d/%d 行", rowIdx+1, len(rows)))
+	}
+	return nil
+}
+
+// tableColumns 获取表的列名（按定义顺序），表不存在时返回错误
+func (a *App) tableColumns(tableName string) ([]string, error) {
+	rows, err := a.readDB().Query(fmt.Sprintf("PRAGMA table_info(%s)", quoteIdent(tableName)))
+	if err != nil {
+		return nil, fmt.Errorf("读取表 %s 结构失败: %v", tableName, err)
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var dflt interface{}
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dflt, &pk); err != nil {
+			return nil, fmt.Errorf("读取表 %s 结构失败: %v", tableName, err)
+		}
+		columns = append(columns, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("读取表 %s 结构失败: %v", tableName, err)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("表 %s 不存在", tableName)
+	}
+	return columns, nil
+}
+
+// scanRowMaps 读取结果集全部行，每行转为 列名 -> 值 的 map（[]byte 转字符串，NULL 转为 nullValue），内存接近上限时返回 errMemoryLimit
+func scanRowMaps(rows *sql.Rows, columns []string, nullValue interface{}) ([]map[string]interface{}, error) {
+	var data []map[string]interface{}
+	values := make([]interface{}, len(columns))
+	valuePtrs := make([]interface{}, len(columns))
+	for i := range values {
+		valuePtrs[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return nil, fmt.Errorf("读取数据失败: %v", err)
+		}
+
+		row := make(map[string]interface{})
+		for i, col := range columns {
+			val := values[i]
+			if b, ok := val.([]byte); ok {
+				row[col] = string(b)
+			} else if val == nil {
+				row[col] = nullValue
+			} else {
+				row[col] = val
+			}
+		}
+		data = append(data, row)
+		if len(data)%memoryCheckRows == 0 && memoryUnderPressure() {
+			return nil, errMemoryLimit
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历数据失败: %v", err)
+	}
+	return data, nil
+}
+
+// userTables 列出用户表（排除 SQLite 内部表和 _app_ 元数据表），按表名排序
+func (a *App) userTables() ([]string, error) {
+	rows, err := a.readDB().Query(`SELECT name FROM sqlite_master
+		WHERE type = 'table' AND name NOT LIKE 'sqlite\_%' ESCAPE '\' AND name NOT LIKE '\_app\_%' ESCAPE '\'
+		ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("读取表列表失败: %v", err)
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("读取表列表失败: %v", err)
+		}
+		tables = append(tables, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("读取表列表失败: %v", err)
+	}
+	return tables, nil
+}
+
+// containsString 判断切片中是否包含指定字符串
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// sqlExecutor *sql.DB 与 *sql.Tx 的公共方法，便于辅助函数同时用于事务内外
+type sqlExecutor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// dropTableOrView 删除同名的表或视图（不存在时不报错）
+func dropTableOrView(db sqlExecutor, name string) error {
+	var kind string
+	err := db.QueryRow("SELECT type FROM sqlite_master WHERE name = ? AND type IN ('table', 'view')", name).Scan(&kind)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec(fmt.Sprintf("DROP %s %s", strings.ToUpper(kind), quoteIdent(name)))
+	return err
+}
+
+// columnInfo PRAGMA table_info 中的列名与声明类型
+type columnInfo struct {
+	name     string
+	declType string
+}
+
+// tableColumnInfos 获取表的列名及声明类型（按定义顺序）
+func tableColumnInfos(db sqlExecutor, tableName string) ([]columnInfo, error) {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", quoteIdent(tableName)))
+	if err != nil {
+		return nil, fmt.Errorf("读取表 %s 结构失败: %v", tableName, err)
+	}
+	defer rows.Close()
+
+	var infos []columnInfo
+	for rows.Next() {
+		var cid, notNull, pk int
+		var info columnInfo
+		var dflt interface{}
+		if err := rows.Scan(&cid, &info.name, &info.declType, &notNull, &dflt, &pk); err != nil {
+			return nil, fmt.Errorf("读取表 %s 结构失败: %v", tableName, err)
+		}
+		infos = append(infos, info)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("读取表 %s 结构失败: %v", tableName, err)
+	}
+	if len(infos) == 0 {
+		return nil, fmt.Errorf("表 %s 不存在", tableName)
+	}
+	return infos, nil
+}
+
+// rebuildTable 保持列顺序重建表，用于修改列类型：
+// types 指定新的声明类型，exprs 指定新值的 SELECT 表达式，未指定的列保持原类型和原值
+// 列定义取自原建表语句，只替换类型，排序规则、NOT NULL、DEFAULT、主键等约束保持不变；
+// 按 rowid 复制数据（批注、软删除、来源行号等以 rowid 关联的元数据仍然有效），并重新创建原有的索引和触发器
+// 调用方负责事务（legacy_alter_table 须与各语句在同一连接上设置）
+func rebuildTable(db sqlExecutor, tableName string, types map[string]string, exprs map[string]string) error {
+	infos, err := tableColumnInfos(db, tableName)
+	if err != nil {
+		return err
+	}
+	var createSQL string
+	if err := db.QueryRow("SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?", tableName).Scan(&createSQL); err != nil {
+		return fmt.Errorf("读取表 %s 的建表语句失败: %v", tableName, err)
+	}
+	defs, options, err := splitTableDefinition(createSQL)
+	if err != nil {
+		return err
+	}
+	for i, def := range defs {
+		name, _, constraints, ok := splitColumnDefinition(def)
+		if !ok {
+			continue
+		}
+		for col, t := range types {
+			if strings.EqualFold(col, name) {
+				defs[i] = strings.TrimSpace(quoteIdent(name) + " " + t + " " + constraints)
+			}
+		}
+	}
+
+	// 原有的索引和触发器随原表删除，重建后按原语句重新创建
+	var schema []string
+	rows, err := db.Query("SELECT sql FROM sqlite_master WHERE type IN ('index', 'trigger') AND tbl_name = ? AND sql IS NOT NULL ORDER BY type, name", tableName)
+	if err != nil {
+		return fmt.Errorf("读取表 %s 的索引失败: %v", tableName, err)
+	}
+	for rows.Next() {
+		var stmt string
+		if err := rows.Scan(&stmt); err != nil {
+			rows.Close()
+			return fmt.Errorf("读取表 %s 的索引失败: %v", tableName, err)
+		}
+		schema = append(schema, stmt)
+	}
+	rows.Close()
+
+	tmpName := tableName + rebuildSuffix
+	names := make([]string, len(infos))
+	selects := make([]string, len(infos))
+	for i, info := range infos {
+		names[i] = quoteIdent(info.name)
+		if expr, ok := exprs[info.name]; ok {
+			selects[i] = expr
+		} else {
+			selects[i] = quoteIdent(info.name)
+		}
+	}
+	// WITHOUT ROWID 表没有 rowid，按主键保留行
+	if !withoutRowid(options) {
+		names = append([]string{"rowid"}, names...)
+		selects = append([]string{"rowid"}, selects...)
+	}
+	create := fmt.Sprintf("CREATE TABLE %s (%s%s", quoteIdent(tmpName), strings.Join(defs, ", "), options)
+
+	stmts := []string{
+		fmt.Sprintf("DROP TABLE IF EXISTS %s", quoteIdent(tmpName)),
+		create,
+		fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s", quoteIdent(tmpName), strings.Join(names, ", "), strings.Join(selects, ", "), quoteIdent(tableName)),
+		fmt.Sprintf("DROP TABLE %s", quoteIdent(tableName)),
+		// 引用原表的视图在原表删除后暂时失效，按旧规则改名时不检查视图，改名后视图重新指向新表
+		"PRAGMA legacy_alter_table = ON",
+		fmt.Sprintf("ALTER TABLE %s RENAME TO %s", quoteIdent(tmpName), quoteIdent(tableName)),
+		"PRAGMA legacy_alter_table = OFF",
+	}
+	stmts = append(stmts, schema...)
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			return fmt.Errorf("重建表 %s 失败: %v", tableName, err)
+		}
+	}
+	return nil
+}
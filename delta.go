@@ -0,0 +1,309 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 差异导出附加的列：变更状态（新增、修改、删除）和修改行中取值变化的列
+const (
+	deltaStatusColumn  = "变更状态"
+	deltaChangedColumn = "变更列"
+)
+
+// snapshotInfo 一个已保存的结果快照
+type snapshotInfo struct {
+	id        int64
+	name      string
+	source    string
+	keys      []string
+	rows      int
+	createdAt string
+}
+
+// table 快照数据所在的表
+func (s snapshotInfo) table() string {
+	return fmt.Sprintf("_app_snapshot_%d", s.id)
+}
+
+// loadSnapshot 按名称读取快照信息
+func (a *App) loadSnapshot(name string) (snapshotInfo, error) {
+	s := snapshotInfo{name: name}
+	var keysJSON string
+	err := a.readDB().QueryRow("SELECT id, source, key_columns, row_count, created_at FROM _app_snapshots WHERE name = ?", name).
+		Scan(&s.id, &s.source, &keysJSON, &s.rows, &s.createdAt)
+	if err == sql.ErrNoRows {
+		return s, fmt.Errorf("快照 %s 不存在", name)
+	}
+	if err != nil {
+		return s, fmt.Errorf("读取快照失败: %v", err)
+	}
+	json.Unmarshal([]byte(keysJSON), &s.keys)
+	return s, nil
+}
+
+// SaveSnapshot 把表或查询的当前结果保存为快照，供之后用 ExportDelta 导出变化；同名快照会被覆盖
+// keyColumns 为识别同一行的主键列，为空时按整行比对（只能区分新增和删除，修改的行表现为一删一增）
+// wails:export SaveSnapshot
+func (a *App) SaveSnapshot(sqlOrTable string, snapshotName string, keyColumns []string) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	if a.readOnly() {
+		return readOnlyMessage
+	}
+	snapshotName = strings.TrimSpace(snapshotName)
+	if snapshotName == "" {
+		return "错误：快照名称不能为空！"
+	}
+	sqlOrTable = strings.TrimSpace(sqlOrTable)
+	if sqlOrTable == "" {
+		return "错误：SQL 语句不能为空！"
+	}
+	from, _, err := a.resolveSource(sqlOrTable)
+	if err != nil {
+		return err.Error()
+	}
+
+	tx, err := a.writeDB().Begin()
+	if err != nil {
+		return fmt.Sprintf("开启事务失败: %v", err)
+	}
+	defer tx.Rollback()
+
+	var oldID int64
+	if err := tx.QueryRow("SELECT id FROM _app_snapshots WHERE name = ?", snapshotName).Scan(&oldID); err == nil {
+		if err := dropTableOrView(tx, snapshotInfo{id: oldID}.table()); err != nil {
+			return fmt.Sprintf("删除旧快照失败: %v", err)
+		}
+		if _, err := tx.Exec("DELETE FROM _app_snapshots WHERE id = ?", oldID); err != nil {
+			return fmt.Sprintf("删除旧快照失败: %v", err)
+		}
+	}
+
+	if keyColumns == nil {
+		keyColumns = []string{}
+	}
+	keysJSON, _ := json.Marshal(keyColumns)
+	res, err := tx.Exec("INSERT INTO _app_snapshots (name, source, key_columns, created_at) VALUES (?, ?, ?, ?)",
+		snapshotName, sqlOrTable, string(keysJSON), time.Now().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return fmt.Sprintf("保存快照失败: %v", err)
+	}
+	id, _ := res.LastInsertId()
+	snap := snapshotInfo{id: id}
+	if _, err := tx.Exec(fmt.Sprintf("CREATE TABLE %s AS SELECT * FROM %s", snap.table(), from)); err != nil {
+		return fmt.Sprintf("保存快照失败: %v", err)
+	}
+
+	infos, err := tableColumnInfos(tx, snap.table())
+	if err != nil {
+		return fmt.Sprintf("读取快照列信息失败: %v", err)
+	}
+	columns := make([]string, len(infos))
+	for i, info := range infos {
+		columns[i] = info.name
+	}
+	if len(keyColumns) > 0 {
+		quoted := make([]string, len(keyColumns))
+		for i, key := range keyColumns {
+			if !containsString(columns, key) {
+				return fmt.Sprintf("主键列 %s 不在结果中", key)
+			}
+			quoted[i] = quoteIdent(key)
+		}
+		if _, err := tx.Exec(fmt.Sprintf("CREATE INDEX %s ON %s (%s)", quoteIdent(snap.table()+"_key"), snap.table(), strings.Join(quoted, ", "))); err != nil {
+			return fmt.Sprintf("创建快照索引失败: %v", err)
+		}
+	}
+
+	var count int
+	tx.QueryRow("SELECT COUNT(*) FROM " + snap.table()).Scan(&count)
+	if _, err := tx.Exec("UPDATE _app_snapshots SET row_count = ? WHERE id = ?", count, id); err != nil {
+		return fmt.Sprintf("保存快照失败: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Sprintf("提交事务失败: %v", err)
+	}
+	return fmt.Sprintf("已保存快照 %s（%d 行）", snapshotName, count)
+}
+
+// ListSnapshots 列出已保存的快照 [{name, source, keyColumns, rows, createdAt}]，最近的在前
+// wails:export ListSnapshots
+func (a *App) ListSnapshots() []map[string]interface{} {
+	list := []map[string]interface{}{}
+	if a.writeDB() == nil {
+		return list
+	}
+	rows, err := a.readDB().Query("SELECT name FROM _app_snapshots ORDER BY created_at DESC, id DESC")
+	if err != nil {
+		return list
+	}
+	var names []string
+	for rows.Next() {
+		var name string
+		if rows.Scan(&name) == nil {
+			names = append(names, name)
+		}
+	}
+	rows.Close()
+	for _, name := range names {
+		s, err := a.loadSnapshot(name)
+		if err != nil {
+			continue
+		}
+		list = append(list, map[string]interface{}{
+			"name":       s.name,
+			"source":     s.source,
+			"keyColumns": s.keys,
+			"rows":       s.rows,
+			"createdAt":  s.createdAt,
+		})
+	}
+	return list
+}
+
+// DeleteSnapshot 删除快照及其数据
+// wails:export DeleteSnapshot
+func (a *App) DeleteSnapshot(snapshotName string) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	if a.readOnly() {
+		return readOnlyMessage
+	}
+	s, err := a.loadSnapshot(snapshotName)
+	if err != nil {
+		return err.Error()
+	}
+	if err := dropTableOrView(a.writeDB(), s.table()); err != nil {
+		return fmt.Sprintf("删除快照失败: %v", err)
+	}
+	if _, err := a.writeDB().Exec("DELETE FROM _app_snapshots WHERE id = ?", s.id); err != nil {
+		return fmt.Sprintf("删除快照失败: %v", err)
+	}
+	return fmt.Sprintf("已删除快照 %s", snapshotName)
+}
+
+// deltaSQL 生成当前结果（from，见 resolveSource，别名 c）与快照（别名 s）的差异查询：变更状态、变更列，其后为当前结果的各列；
+// 有主键时按主键对应，取值不同的为修改；没有主键时按整行哈希比对，只有新增和删除。只在一侧存在的列不参与比对
+func deltaSQL(from string, snapTable string, columns []string, snapColumns []string, keys []string) string {
+	var common, cCommon, sCommon, removed []string
+	for _, col := range columns {
+		if containsString(snapColumns, col) {
+			common = append(common, col)
+			cCommon = append(cCommon, "c."+quoteIdent(col))
+			sCommon = append(sCommon, "s."+quoteIdent(col))
+			removed = append(removed, "s."+quoteIdent(col)+" AS "+quoteIdent(col))
+		} else {
+			removed = append(removed, "NULL AS "+quoteIdent(col))
+		}
+	}
+	from += " c"
+	status := func(s string) string {
+		return quoteLiteral(s) + " AS " + quoteIdent(deltaStatusColumn)
+	}
+
+	var parts []string
+	if len(keys) > 0 {
+		match := make([]string, len(keys))
+		for i, key := range keys {
+			match[i] = fmt.Sprintf("s.%s IS c.%s", quoteIdent(key), quoteIdent(key))
+		}
+		on := strings.Join(match, " AND ")
+		changed := make([]string, len(common))
+		for i, col := range common {
+			changed[i] = fmt.Sprintf("CASE WHEN c.%s IS NOT s.%s THEN %s ELSE '' END", quoteIdent(col), quoteIdent(col), quoteLiteral(", "+col))
+		}
+		parts = append(parts,
+			fmt.Sprintf("SELECT %s, '' AS %s, c.* FROM %s WHERE NOT EXISTS (SELECT 1 FROM %s s WHERE %s)",
+				status("新增"), quoteIdent(deltaChangedColumn), from, snapTable, on),
+			fmt.Sprintf("SELECT %s, SUBSTR(%s, 3), c.* FROM %s JOIN %s s ON %s WHERE ROW_HASH(%s) IS NOT ROW_HASH(%s)",
+				status("修改"), strings.Join(changed, " || "), from, snapTable, on, strings.Join(cCommon, ", "), strings.Join(sCommon, ", ")),
+			fmt.Sprintf("SELECT %s, '', %s FROM %s s WHERE NOT EXISTS (SELECT 1 FROM %s WHERE %s)",
+				status("删除"), strings.Join(removed, ", "), snapTable, from, on))
+	} else {
+		parts = append(parts,
+			fmt.Sprintf("SELECT %s, '' AS %s, c.* FROM %s WHERE ROW_HASH(%s) NOT IN (SELECT ROW_HASH(%s) FROM %s s)",
+				status("新增"), quoteIdent(deltaChangedColumn), from, strings.Join(cCommon, ", "), strings.Join(sCommon, ", "), snapTable),
+			fmt.Sprintf("SELECT %s, '', %s FROM %s s WHERE ROW_HASH(%s) NOT IN (SELECT ROW_HASH(%s) FROM %s)",
+				status("删除"), strings.Join(removed, ", "), snapTable, strings.Join(sCommon, ", "), strings.Join(cCommon, ", "), from))
+	}
+	return strings.Join(parts, "\nUNION ALL\n")
+}
+
+// ExportDelta 只导出与快照相比的变化（新增、修改、删除的行），首列为变更状态，用于发给相关人员的变更报告
+// 比对方式由保存快照时的主键列决定（见 SaveSnapshot）；返回值同 ExportExcelBySQL，另含 added、changed、removed 行数
+// wails:export ExportDelta
+func (a *App) ExportDelta(sqlOrTable string, snapshotName string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	sqlOrTable = strings.TrimSpace(sqlOrTable)
+	if sqlOrTable == "" {
+		result["error"] = "错误：SQL 语句不能为空！"
+		return result
+	}
+	from, columns, err := a.resolveSource(sqlOrTable)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	snap, err := a.loadSnapshot(strings.TrimSpace(snapshotName))
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+
+	infos, err := tableColumnInfos(a.writeDB(), snap.table())
+	if err != nil {
+		result["error"] = fmt.Sprintf("读取快照列信息失败: %v", err)
+		return result
+	}
+	snapColumns := make([]string, len(infos))
+	for i, info := range infos {
+		snapColumns[i] = info.name
+	}
+	for _, key := range snap.keys {
+		if !containsString(columns, key) {
+			result["error"] = fmt.Sprintf("当前结果中没有快照的主键列 %s", key)
+			return result
+		}
+	}
+
+	delta := deltaSQL(from, snap.table(), columns, snapColumns, snap.keys)
+	counts := map[string]int{}
+	countRows, err := a.readDB().Query(fmt.Sprintf("SELECT %s, COUNT(*) FROM (\n%s\n) GROUP BY 1", quoteIdent(deltaStatusColumn), delta))
+	if err != nil {
+		result["error"] = fmt.Sprintf("比对失败: %v", err)
+		return result
+	}
+	for countRows.Next() {
+		var status string
+		var n int
+		if countRows.Scan(&status, &n) == nil {
+			counts[status] = n
+		}
+	}
+	countRows.Close()
+	if len(counts) == 0 {
+		result["error"] = fmt.Sprintf("与快照 %s（%s）相比没有变化", snap.name, snap.createdAt)
+		return result
+	}
+
+	result = a.startExportJob(delta, snap.name+"_变更", exportOptions{})
+	if !exportStarted(result) {
+		return result
+	}
+	result["added"] = counts["新增"]
+	result["changed"] = counts["修改"]
+	result["removed"] = counts["删除"]
+	result["message"] = fmt.Sprintf("%s（与快照 %s 相比：新增 %d 行，修改 %d 行，删除 %d 行）",
+		result["message"], snap.name, counts["新增"], counts["修改"], counts["删除"])
+	return result
+}
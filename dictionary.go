@@ -0,0 +1,214 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// dictionaryEntry 数据字典中的一条显示名/说明
+type dictionaryEntry struct {
+	label       string
+	description string
+}
+
+// dictionaryEntries 读取表及其列的数据字典，键为列名（表本身的键为空字符串）
+func (a *App) dictionaryEntries(tableName string) (map[string]dictionaryEntry, error) {
+	rows, err := a.readDB().Query(
+		"SELECT column_name, label, description FROM _app_dictionary WHERE table_name = ?",
+		tableName,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("读取数据字典失败: %v", err)
+	}
+	defer rows.Close()
+
+	entries := make(map[string]dictionaryEntry)
+	for rows.Next() {
+		var col string
+		var entry dictionaryEntry
+		if err := rows.Scan(&col, &entry.label, &entry.description); err != nil {
+			return nil, fmt.Errorf("读取数据字典失败: %v", err)
+		}
+		entries[col] = entry
+	}
+	return entries, rows.Err()
+}
+
+// saveDictionaryEntry 保存显示名和说明，两者都为空时删除该条目
+func (a *App) saveDictionaryEntry(tableName string, column string, label string, description string) error {
+	label, description = strings.TrimSpace(label), strings.TrimSpace(description)
+	if label == "" && description == "" {
+		_, err := a.writeDB().Exec("DELETE FROM _app_dictionary WHERE table_name = ? AND column_name = ?", tableName, column)
+		return err
+	}
+	_, err := a.writeDB().Exec(`INSERT INTO _app_dictionary (table_name, column_name, label, description) VALUES (?, ?, ?, ?)
+		ON CONFLICT(table_name, column_name) DO UPDATE SET label = excluded.label, description = excluded.description`,
+		tableName, column, label, description)
+	return err
+}
+
+// SetTableDescription 设置表的显示名和说明
+// wails:export SetTableDescription
+func (a *App) SetTableDescription(tableName string, label string, description string) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	if _, err := a.tableColumns(tableName); err != nil {
+		return err.Error()
+	}
+	if err := a.saveDictionaryEntry(tableName, "", label, description); err != nil {
+		return fmt.Sprintf("保存表说明失败: %v", err)
+	}
+	return fmt.Sprintf("已更新表 %s 的说明", tableName)
+}
+
+// SetColumnDescription 设置列的显示名和说明
+// wails:export SetColumnDescription
+func (a *App) SetColumnDescription(tableName string, column string, label string, description string) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	columns, err := a.tableColumns(tableName)
+	if err != nil {
+		return err.Error()
+	}
+	if !containsString(columns, column) {
+		return fmt.Sprintf("表 %s 中不存在列 %s", tableName, column)
+	}
+	if err := a.saveDictionaryEntry(tableName, column, label, description); err != nil {
+		return fmt.Sprintf("保存列说明失败: %v", err)
+	}
+	return fmt.Sprintf("已更新列 %s.%s 的说明", tableName, column)
+}
+
+// tableSummaries 汇总表的显示名、说明、列数、标签和文件夹，供表列表类接口使用
+func (a *App) tableSummaries(names []string) ([]map[string]interface{}, error) {
+	tables := make([]map[string]interface{}, 0, len(names))
+	for _, name := range names {
+		columns, err := a.tableColumns(name)
+		if err != nil {
+			return nil, err
+		}
+		entries, err := a.dictionaryEntries(name)
+		if err != nil {
+			return nil, err
+		}
+		tags, err := a.tableTags(name)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, map[string]interface{}{
+			"name":        name,
+			"label":       entries[""].label,
+			"description": entries[""].description,
+			"columnCount": len(columns),
+			"tags":        tags,
+			"folder":      a.tableFolder(name),
+		})
+	}
+	return tables, nil
+}
+
+// ListTables 列出全部用户表及其显示名、说明、列数、标签和文件夹
+// wails:export ListTables
+func (a *App) ListTables() map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+
+	names, err := a.userTables()
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+
+	tables, err := a.tableSummaries(names)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+
+	result["tables"] = tables
+	result["message"] = fmt.Sprintf("共 %d 张表", len(tables))
+	return result
+}
+
+// GetTableSchema 获取表结构及数据字典（列名、声明类型、显示名、说明）
+// wails:export GetTableSchema
+func (a *App) GetTableSchema(tableName string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+
+	rows, err := a.readDB().Query(fmt.Sprintf("PRAGMA table_info(%s)", quoteIdent(tableName)))
+	if err != nil {
+		result["error"] = fmt.Sprintf("读取表 %s 结构失败: %v", tableName, err)
+		return result
+	}
+	defer rows.Close()
+
+	entries, err := a.dictionaryEntries(tableName)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+
+	var columns []map[string]interface{}
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var dflt interface{}
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dflt, &pk); err != nil {
+			result["error"] = fmt.Sprintf("读取表 %s 结构失败: %v", tableName, err)
+			return result
+		}
+		columns = append(columns, map[string]interface{}{
+			"name":        name,
+			"type":        colType,
+			"label":       entries[name].label,
+			"description": entries[name].description,
+		})
+	}
+	if len(columns) == 0 {
+		result["error"] = fmt.Sprintf("表 %s 不存在", tableName)
+		return result
+	}
+
+	result["table"] = tableName
+	result["label"] = entries[""].label
+	result["description"] = entries[""].description
+	result["columns"] = columns
+	return result
+}
+
+// columnLabels 生成 列名 -> 显示名 的映射，用于导出表头
+// 同一列名在不同表中有不同显示名时视为歧义，不做替换
+func (a *App) columnLabels() map[string]string {
+	rows, err := a.readDB().Query("SELECT column_name, label FROM _app_dictionary WHERE column_name <> '' AND label <> ''")
+	if err != nil {
+		fmt.Printf("读取数据字典失败: %v\n", err)
+		return nil
+	}
+	defer rows.Close()
+
+	labels := make(map[string]string)
+	ambiguous := make(map[string]bool)
+	for rows.Next() {
+		var col, label string
+		if err := rows.Scan(&col, &label); err != nil {
+			return nil
+		}
+		if existing, ok := labels[col]; ok && existing != label {
+			ambiguous[col] = true
+		}
+		labels[col] = label
+	}
+	for col := range ambiguous {
+		delete(labels, col)
+	}
+	return labels
+}
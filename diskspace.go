@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// diskSpaceReserve 预检时额外保留的磁盘空间，避免写满磁盘导致 data.db 损坏
+const diskSpaceReserve = 100 << 20
+
+// diskEstimateSampleRows 估算写入体积时抽样的行数
+const diskEstimateSampleRows = 1000
+
+// importSpaceFactor 导入时每字节数据需要的磁盘空间：数据页和索引开销，加上回滚日志/WAL 中的副本
+const importSpaceFactor = 3
+
+// formatBytes 将字节数格式化为 KB/MB/GB
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// checkDiskSpace 检查 path 所在磁盘的可用空间是否足够写入 required 字节（另需保留 diskSpaceReserve）
+// 无法获取可用空间时不阻止操作
+func checkDiskSpace(path string, required uint64) error {
+	dir := path
+	if abs, err := filepath.Abs(path); err == nil {
+		dir = abs
+	}
+	free, err := freeDiskSpace(dir)
+	if err != nil {
+		fmt.Printf("获取磁盘可用空间失败（跳过空间检查）: %v\n", err)
+		return nil
+	}
+	if free < required+diskSpaceReserve {
+		return fmt.Errorf("磁盘空间不足：预计需要 %s（另需保留 %s），%s 所在磁盘仅剩 %s，请清理磁盘后重试",
+			formatBytes(required), formatBytes(diskSpaceReserve), dir, formatBytes(free))
+	}
+	return nil
+}
+
+// estimateRowsSize 按抽样行的平均宽度估算全部行的字节数
+func estimateRowsSize(rows [][]string) uint64 {
+	if len(rows) == 0 {
+		return 0
+	}
+	sample := rows
+	if len(sample) > diskEstimateSampleRows {
+		sample = sample[:diskEstimateSampleRows]
+	}
+	var total uint64
+	for _, row := range sample {
+		for _, cell := range row {
+			total += uint64(len(cell)) + 1
+		}
+	}
+	return total * uint64(len(rows)) / uint64(len(sample))
+}
+
+// estimateValuesSize 按抽样行的平均宽度估算已转换的行的字节数
+func estimateValuesSize(values [][]interface{}) uint64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sample := values
+	if len(sample) > diskEstimateSampleRows {
+		sample = sample[:diskEstimateSampleRows]
+	}
+	var total uint64
+	for _, row := range sample {
+		for _, v := range row {
+			total += uint64(len(sqlValueText(v))) + 1
+		}
+	}
+	return total * uint64(len(values)) / uint64(len(sample))
+}
+
+// estimateDataSize 按抽样行的平均宽度估算查询结果的字节数
+func estimateDataSize(columns []string, data []map[string]interface{}) uint64 {
+	if len(data) == 0 {
+		return 0
+	}
+	sample := data
+	if len(sample) > diskEstimateSampleRows {
+		sample = sample[:diskEstimateSampleRows]
+	}
+	var total uint64
+	for _, row := range sample {
+		for _, col := range columns {
+			total += uint64(len(sqlValueText(row[col]))) + 1
+		}
+	}
+	return total * uint64(len(data)) / uint64(len(sample))
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"database/sql"
+	"database/sql/driver"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// sqliteDriverName 注册了自定义排序规则和函数的 SQLite 驱动名
+const sqliteDriverName = "sqlite3_ext"
+
+// scratchDriverName 临时数据库（如导入试运行、实例锁文件）使用的驱动：注册同样的排序规则和函数，但不安装提交钩子，
+// 临时库中的写入不会改变数据版本或通知前端
+const scratchDriverName = "sqlite3_scratch"
+
+func init() {
+	sql.Register(sqliteDriverName, &trackedDriver{})
+	sql.Register(scratchDriverName, &sqlite3.SQLiteDriver{ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+		if err := registerFunctions(conn); err != nil {
+			return err
+		}
+		registerAuthorizer(conn, nil)
+		return nil
+	}})
+}
+
+// trackedDriver 主数据库使用的驱动：在 SQLite 驱动外包一层，连接归还连接池时提交已经完成、
+// 对其他连接可见，这时才把提交钩子记下的提交标记为完成（见 commitsInFlight）
+type trackedDriver struct {
+	sqlite3.SQLiteDriver
+}
+
+// trackedConn 带提交跟踪的连接，其余方法直接使用 SQLiteConn 的实现
+type trackedConn struct {
+	*sqlite3.SQLiteConn
+	changes *connChanges
+}
+
+func (d *trackedDriver) Open(dsn string) (driver.Conn, error) {
+	conn, err := d.SQLiteDriver.Open(dsn)
+	if err != nil {
+		return nil, err
+	}
+	c := conn.(*sqlite3.SQLiteConn)
+	changes, err := registerExtensions(c)
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	return &trackedConn{SQLiteConn: c, changes: changes}, nil
+}
+
+// IsValid database/sql 把连接放回连接池前调用，此时连接上的语句和事务都已结束
+func (c *trackedConn) IsValid() bool {
+	c.changes.settle()
+	return true
+}
+
+func (c *trackedConn) Close() error {
+	c.changes.settle()
+	return c.SQLiteConn.Close()
+}
+
+// rawSQLiteConn 取出 Conn.Raw 回调中的 SQLite 连接（主数据库的连接包在 trackedConn 中）
+func rawSQLiteConn(driverConn interface{}) (*sqlite3.SQLiteConn, bool) {
+	switch c := driverConn.(type) {
+	case *trackedConn:
+		return c.SQLiteConn, true
+	case *sqlite3.SQLiteConn:
+		return c, true
+	}
+	return nil, false
+}
+
+// registerExtensions 在每个新连接上注册自定义排序规则、函数和提交钩子，返回收集连接改动的 connChanges
+func registerExtensions(conn *sqlite3.SQLiteConn) (*connChanges, error) {
+	if err := registerFunctions(conn); err != nil {
+		return nil, err
+	}
+	// 收集事务中被修改的表，提交时递增数据版本（使查询结果缓存失效）并通知前端
+	changes := &connChanges{}
+	conn.RegisterUpdateHook(func(op int, dbName string, table string, rowid int64) {
+		changes.add(dbName, table)
+	})
+	conn.RegisterCommitHook(changes.commit)
+	conn.RegisterRollbackHook(func() {
+		changes.take()
+	})
+	registerAuthorizer(conn, changes)
+	return changes, nil
+}
+
+// registerAuthorizer 安装授权回调（只观察，不拒绝任何操作）：changes 不为 nil 时记录更新钩子看不到的改动
+// （DDL、不带条件的 DELETE），并转发给 queryLineage 的来源分析（见 provenance.go）
+func registerAuthorizer(conn *sqlite3.SQLiteConn, changes *connChanges) {
+	conn.RegisterAuthorizer(func(op int, arg1, arg2, arg3 string) int {
+		if changes != nil {
+			changes.authorize(op, arg1, arg2, arg3)
+		}
+		observeLineage(conn, op, arg1, arg2)
+		return sqlite3.SQLITE_OK
+	})
+}
+
+// registerFunctions 注册自定义排序规则、函数和 CSV 虚拟表模块
+func registerFunctions(conn *sqlite3.SQLiteConn) error {
+	if err := conn.RegisterCollation("PINYIN", pinyinCompare); err != nil {
+		return err
+	}
+	if err := conn.RegisterCollation("FOLD", foldCompare); err != nil {
+		return err
+	}
+	if err := conn.RegisterFunc("CN_NUMBER", cnNumberFunc, true); err != nil {
+		return err
+	}
+	if err := conn.RegisterFunc("PARSE_NUMBER", parseNumberFunc, true); err != nil {
+		return err
+	}
+	// 结果依赖时区/区域设置，不能标记为确定性函数
+	if err := conn.RegisterFunc("PARSE_DATE", parseDateFunc, false); err != nil {
+		return err
+	}
+	if err := conn.RegisterFunc("FORMAT_DATE", formatDateFunc, false); err != nil {
+		return err
+	}
+	if err := conn.RegisterFunc("ROW_HASH", rowHashFunc, true); err != nil {
+		return err
+	}
+	if err := conn.RegisterFunc("HAVERSINE", haversineFunc, true); err != nil {
+		return err
+	}
+	if err := conn.RegisterFunc("POINT_IN_POLYGON", pointInPolygonFunc, true); err != nil {
+		return err
+	}
+	if err := conn.RegisterFunc("JSON_GET", jsonGetFunc, true); err != nil {
+		return err
+	}
+	if err := conn.RegisterFunc("CONVERT_UNIT", convertUnitFunc, true); err != nil {
+		return err
+	}
+	// 汇率可随时修改，不能标记为确定性函数
+	if err := conn.RegisterFunc("CONVERT_CURRENCY", convertCurrencyFunc, false); err != nil {
+		return err
+	}
+	addressParts := map[string]func(parsedAddress) string{
+		"ADDRESS_PROVINCE": func(p parsedAddress) string { return p.province },
+		"ADDRESS_CITY":     func(p parsedAddress) string { return p.city },
+		"ADDRESS_DISTRICT": func(p parsedAddress) string { return p.district },
+	}
+	for name, part := range addressParts {
+		if err := conn.RegisterFunc(name, addressPartFunc(part), true); err != nil {
+			return err
+		}
+	}
+	return registerCSVModule(conn)
+}
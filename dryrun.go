@@ -0,0 +1,453 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// dryRunImportBindings 支持试运行的导入方法，值为导入方式：append 追加到已有表，refresh 保留表结构替换数据，
+// update 按行键修改已有的行，其余新建或替换同名表
+var dryRunImportBindings = map[string]string{
+	"OpenExcel":             "replace",
+	"ImportSelectedColumns": "replace",
+	"ImportNamedRanges":     "replace",
+	"ImportFixedWidth":      "replace",
+	"ImportHTMLTables":      "replace",
+	"ImportFromDatabase":    "replace",
+	"ImportPDFTable":        "replace",
+	"ImportSampled":         "replace",
+	"ImportUnion":           "replace",
+	"ImportSheetInto":       "append",
+	"RefreshTable":          "refresh",
+	"ReimportFilled":        "update",
+}
+
+// dryRunCopiedMeta 试运行时复制到临时库的元数据表：导入设置、数据字典（按显示名匹配列时使用）和导入来源（刷新时使用）
+var dryRunCopiedMeta = []string{"_app_settings", "_app_dictionary", "_app_table_sources"}
+
+// dryRunTarget 写入已有表的导入方法的目标表：试运行时复制该表的全部行（保留 rowid），
+// 才能发现与已有数据的唯一约束冲突、按行键找不到的行等问题；新建或替换表的方法返回空
+func dryRunTarget(binding string, args []interface{}) string {
+	arg := func(i int) string {
+		if i < len(args) {
+			s, _ := args[i].(string)
+			return s
+		}
+		return ""
+	}
+	switch binding {
+	case "ImportSheetInto":
+		return arg(2)
+	case "RefreshTable":
+		return arg(0)
+	case "ReimportFilled":
+		f, err := excelize.OpenFile(arg(0))
+		if err != nil {
+			return ""
+		}
+		defer f.Close()
+		info, _, err := readRoundTripSheet(f)
+		if err != nil {
+			return ""
+		}
+		return info.table
+	}
+	return ""
+}
+
+// dryRunProblemSamples 每张表的隔离行最多列出的原因条数
+const dryRunProblemSamples = 5
+
+// DryRunImport 试运行导入：binding 为导入方法名（如 OpenExcel、ImportSheetInto），args 为该方法的参数（按顺序）
+// 在只含表结构、设置和数据字典的临时数据库中完整执行一次导入（解析、类型推断、列映射和类型校验），data.db 不做任何修改；
+// 追加、刷新和回填时临时库中还有目标表的全部行，与已有数据的冲突同样会被发现；
+// 返回 {dryRun, binding, message, tables: [{table, mode, columns: [{name, declType, inferredType}], rows, existingRows, rowsAfter, rejectedRows}], problems}
+// mode 为 create（新建）、replace（替换已有表）、append（追加）、refresh（刷新数据）或 update（回填修改）
+// wails:export DryRunImport
+func (a *App) DryRunImport(binding string, args []interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	mode, ok := dryRunImportBindings[binding]
+	if !ok {
+		names := make([]string, 0, len(dryRunImportBindings))
+		for name := range dryRunImportBindings {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		result["error"] = fmt.Sprintf("导入方法 %s 不支持试运行（支持：%s）", binding, strings.Join(names, "、"))
+		return result
+	}
+
+	dir, err := os.MkdirTemp("", "excel-db-dryrun-")
+	if err != nil {
+		result["error"] = fmt.Sprintf("创建临时目录失败: %v", err)
+		return result
+	}
+	defer os.RemoveAll(dir)
+	target := dryRunTarget(binding, args)
+	scratch, existing, copied, err := a.openDryRunDatabase(filepath.Join(dir, "dryrun.db"), target)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	defer scratch.Close()
+
+	dry := &App{
+		ctx:     a.ctx,
+		session: newQuerySession(a.session.currentPageSize()),
+		cache:   newResultCache(),
+		exports: newExportJobs(),
+	}
+	dry.stats = newStatsCache(dry.statsVersion)
+	dry.handles.Store(&dbHandles{db: scratch})
+	dry.includeDeleted.Store(a.includeDeleted.Load())
+	method := reflect.ValueOf(dry).MethodByName(binding)
+	in, err := bindingArgs(method.Type(), args)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	out := method.Call(in)
+	message, failed := "", false
+	if len(out) > 0 {
+		switch v := out[0].Interface().(type) {
+		case string:
+			message = v
+		case map[string]interface{}:
+			message, _ = v["message"].(string)
+			if e, ok := v["error"].(string); ok && e != "" {
+				message, failed = e, true
+			}
+		}
+	}
+
+	// 导入失败时事务已回滚，不会写入任何表
+	tables, problems := []map[string]interface{}{}, []string{"导入将失败：" + message}
+	if !failed {
+		tables, problems, err = a.dryRunReport(scratch, existing, copied, mode)
+		if err != nil {
+			result["error"] = err.Error()
+			return result
+		}
+	}
+	rows := 0
+	for _, t := range tables {
+		rows += t["rows"].(int)
+	}
+	if !failed && rows == 0 && mode != "update" {
+		problems = append(problems, "导入未写入任何数据："+message)
+	}
+	result["dryRun"] = true
+	result["binding"] = binding
+	result["tables"] = tables
+	result["problems"] = problems
+	result["importMessage"] = message
+	result["message"] = fmt.Sprintf("试运行：将写入 %d 张表共 %d 行，发现 %d 个问题，未修改数据", len(tables), rows, len(problems))
+	return result
+}
+
+// bindingArgs 把前端传入的参数（JSON 解码后的值）按方法的参数类型转换
+func bindingArgs(t reflect.Type, args []interface{}) ([]reflect.Value, error) {
+	if len(args) != t.NumIn() {
+		return nil, fmt.Errorf("参数个数不符：需要 %d 个，传入 %d 个", t.NumIn(), len(args))
+	}
+	in := make([]reflect.Value, len(args))
+	for i, arg := range args {
+		data, err := json.Marshal(arg)
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 个参数无效: %v", i+1, err)
+		}
+		v := reflect.New(t.In(i))
+		if err := json.Unmarshal(data, v.Interface()); err != nil {
+			return nil, fmt.Errorf("第 %d 个参数类型不符（需要 %s）: %v", i+1, t.In(i), err)
+		}
+		in[i] = v.Elem()
+	}
+	return in, nil
+}
+
+// openDryRunDatabase 创建试运行用的临时数据库：元数据表、全部用户表和索引的结构（不含数据，虚拟表除外）、导入设置和数据字典，
+// 以及目标表 target（不为空时）的全部行；返回临时库、当前各表的建表语句和复制了数据的表的行数
+func (a *App) openDryRunDatabase(path string, target string) (*sql.DB, map[string]string, map[string]int, error) {
+	scratch, err := sql.Open(scratchDriverName, path)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("创建临时数据库失败: %v", err)
+	}
+	fail := func(err error) (*sql.DB, map[string]string, map[string]int, error) {
+		scratch.Close()
+		return nil, nil, nil, err
+	}
+	if err := initMetaTables(scratch); err != nil {
+		return fail(err)
+	}
+
+	rows, err := a.readDB().Query(`SELECT type, name, sql FROM sqlite_master
+		WHERE type IN ('table', 'index') AND sql IS NOT NULL AND sql NOT LIKE 'CREATE VIRTUAL%'
+		AND name NOT LIKE 'sqlite\_%' ESCAPE '\' AND tbl_name NOT LIKE '\_app\_%' ESCAPE '\'
+		ORDER BY type = 'index'`)
+	if err != nil {
+		return fail(fmt.Errorf("读取表结构失败: %v", err))
+	}
+	existing := make(map[string]string)
+	var ddl []string
+	for rows.Next() {
+		var kind, name, s string
+		if err := rows.Scan(&kind, &name, &s); err != nil {
+			rows.Close()
+			return fail(fmt.Errorf("读取表结构失败: %v", err))
+		}
+		if kind == "table" {
+			existing[name] = s
+		}
+		ddl = append(ddl, s)
+	}
+	rows.Close()
+	for _, s := range ddl {
+		if _, err := scratch.Exec(s); err != nil {
+			return fail(fmt.Errorf("复制表结构失败: %v", err))
+		}
+	}
+	for _, table := range dryRunCopiedMeta {
+		if _, err := copyTableRows(a.readDB(), scratch, table, false); err != nil {
+			return fail(err)
+		}
+	}
+	copied := make(map[string]int)
+	if _, ok := existing[target]; ok {
+		// 回填按 rowid 定位行，保留 rowid 才能找到导出时的行
+		n, err := copyTableRows(a.readDB(), scratch, target, a.tableHasRowid(target))
+		if err != nil {
+			return fail(err)
+		}
+		copied[target] = n
+	}
+	return scratch, existing, copied, nil
+}
+
+// copyTableRows 在一个事务中把 src 中一张表的全部行复制到 dst 的同名表（两边的列相同），withRowid 时保留 rowid；返回复制的行数
+func copyTableRows(src *sql.DB, dst *sql.DB, table string, withRowid bool) (int, error) {
+	columns, err := tableColumnInfos(src, table)
+	if err != nil {
+		return 0, err
+	}
+	names := make([]string, 0, len(columns)+1)
+	if withRowid {
+		names = append(names, "rowid")
+	}
+	for _, c := range columns {
+		names = append(names, quoteIdent(c.name))
+	}
+	list := strings.Join(names, ", ")
+	rows, err := src.Query(fmt.Sprintf("SELECT %s FROM %s", list, quoteIdent(table)))
+	if err != nil {
+		return 0, fmt.Errorf("读取 %s 失败: %v", table, err)
+	}
+	defer rows.Close()
+	tx, err := dst.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("复制 %s 失败: %v", table, err)
+	}
+	defer tx.Rollback()
+	insert, err := tx.Prepare(fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", quoteIdent(table), list,
+		strings.TrimSuffix(strings.Repeat("?,", len(names)), ",")))
+	if err != nil {
+		return 0, fmt.Errorf("复制 %s 失败: %v", table, err)
+	}
+	defer insert.Close()
+	values := make([]interface{}, len(names))
+	ptrs := make([]interface{}, len(names))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	n := 0
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return 0, fmt.Errorf("读取 %s 失败: %v", table, err)
+		}
+		if _, err := insert.Exec(values...); err != nil {
+			return 0, fmt.Errorf("复制 %s 失败: %v", table, err)
+		}
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("读取 %s 失败: %v", table, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("复制 %s 失败: %v", table, err)
+	}
+	return n, nil
+}
+
+// dryRunReport 比较临时库与当前数据库，列出导入会写入的表（结构、行数、推断的列类型）和发现的问题；
+// copied 为试运行前复制了数据的表及其行数，这些表导入的行数为试运行后的行数减去复制的行数
+func (a *App) dryRunReport(scratch *sql.DB, existing map[string]string, copied map[string]int, mode string) ([]map[string]interface{}, []string, error) {
+	tables := []map[string]interface{}{}
+	problems := []string{}
+	rows, err := scratch.Query(`SELECT name, sql FROM sqlite_master
+		WHERE type = 'table' AND name NOT LIKE 'sqlite\_%' ESCAPE '\' AND name NOT LIKE '\_app\_%' ESCAPE '\'
+		ORDER BY name`)
+	if err != nil {
+		return nil, nil, fmt.Errorf("读取试运行结果失败: %v", err)
+	}
+	after := make(map[string]string)
+	var names []string
+	for rows.Next() {
+		var name, s string
+		if err := rows.Scan(&name, &s); err != nil {
+			rows.Close()
+			return nil, nil, fmt.Errorf("读取试运行结果失败: %v", err)
+		}
+		after[name] = s
+		names = append(names, name)
+	}
+	rows.Close()
+
+	counts := make(map[string]int, len(names))
+	for _, name := range names {
+		var n int
+		if err := scratch.QueryRow("SELECT COUNT(*) FROM " + quoteIdent(name)).Scan(&n); err != nil {
+			return nil, nil, fmt.Errorf("读取试运行结果失败: %v", err)
+		}
+		counts[name] = n
+	}
+
+	for _, name := range names {
+		before, existed := existing[name]
+		// 隔离表作为所属表的问题报告
+		if base := strings.TrimSuffix(name, rejectedTableSuffix); base != name {
+			if _, ok := after[base]; ok {
+				continue
+			}
+		}
+		copiedRows, isCopied := copied[name]
+		if existed && !isCopied && counts[name] == 0 && before == after[name] {
+			continue
+		}
+		rowsAfter := counts[name]
+		imported := rowsAfter
+		if isCopied && mode != "refresh" {
+			imported = rowsAfter - copiedRows
+		}
+		infos, err := tableColumnInfos(scratch, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		sample, err := dryRunSample(scratch, name, infos)
+		if err != nil {
+			return nil, nil, err
+		}
+		columns := make([]map[string]interface{}, len(infos))
+		for i, c := range infos {
+			inferred := inferColumnType(sample, c.name)
+			columns[i] = map[string]interface{}{"name": c.name, "declType": c.declType, "inferredType": inferred}
+			if inferred == "empty" && rowsAfter > 0 && !isCopied {
+				problems = append(problems, fmt.Sprintf("导入表 %s 的数据中列 %s 全部为空", name, c.name))
+			}
+		}
+		t := map[string]interface{}{"table": name, "columns": columns, "rows": imported}
+		tables = append(tables, t)
+
+		switch {
+		case !existed:
+			t["mode"] = "create"
+			t["existingRows"] = 0
+			t["rowsAfter"] = rowsAfter
+		case isCopied:
+			t["mode"] = mode
+			t["existingRows"] = copiedRows
+			t["rowsAfter"] = rowsAfter
+			if mode == "refresh" {
+				problems = append(problems, fmt.Sprintf("将替换表 %s 的现有 %d 行数据（保留表结构）", name, copiedRows))
+			}
+		default:
+			existingRows, err := a.cachedRowCount(name)
+			if err != nil {
+				return nil, nil, err
+			}
+			t["existingRows"] = existingRows
+			if mode == "append" {
+				t["mode"] = "append"
+				t["rowsAfter"] = existingRows + int64(rowsAfter)
+			} else {
+				t["mode"] = "replace"
+				t["rowsAfter"] = rowsAfter
+				problems = append(problems, fmt.Sprintf("将替换已有的表 %s（现有 %d 行会被删除）", name, existingRows))
+				if missing := missingColumns(a.writeDB(), scratch, name); len(missing) > 0 {
+					problems = append(problems, fmt.Sprintf("替换后表 %s 将缺少现有的列 %s", name, strings.Join(missing, "、")))
+				}
+			}
+		}
+		if imported == 0 && mode != "update" {
+			problems = append(problems, fmt.Sprintf("表 %s 没有导入任何数据行", name))
+		}
+
+		rejected := rejectedTableName(name)
+		if n := counts[rejected]; n > 0 {
+			t["rejectedRows"] = n
+			problems = append(problems, fmt.Sprintf("表 %s 有 %d 行无法按列类型写入，将写入隔离表 %s", name, n, rejected))
+			reasons, err := scratch.Query(fmt.Sprintf("SELECT %s, %s FROM %s ORDER BY rowid LIMIT %d",
+				quoteIdent(rejectedRowColumn), quoteIdent(rejectedReasonColumn), quoteIdent(rejected), dryRunProblemSamples))
+			if err == nil {
+				for reasons.Next() {
+					var rowNum sql.NullInt64
+					var reason sql.NullString
+					if reasons.Scan(&rowNum, &reason) == nil {
+						problems = append(problems, fmt.Sprintf("第 %d 行：%s", rowNum.Int64, reason.String))
+					}
+				}
+				reasons.Close()
+			}
+		}
+	}
+	return tables, problems, nil
+}
+
+// dryRunSample 读取试运行写入的前若干行用于推断列类型
+func dryRunSample(db *sql.DB, table string, infos []columnInfo) ([]map[string]interface{}, error) {
+	names := make([]string, len(infos))
+	quoted := make([]string, len(infos))
+	for i, c := range infos {
+		names[i] = c.name
+		quoted[i] = quoteIdent(c.name)
+	}
+	rows, err := db.Query(fmt.Sprintf("SELECT %s FROM %s LIMIT %d", strings.Join(quoted, ", "), quoteIdent(table), columnTypeSampleRows))
+	if err != nil {
+		return nil, fmt.Errorf("读取试运行结果失败: %v", err)
+	}
+	defer rows.Close()
+	return scanRowMaps(rows, names, nil)
+}
+
+// missingColumns 当前表中有、试运行后的同名表中没有的列
+func missingColumns(current *sql.DB, scratch *sql.DB, table string) []string {
+	before, err := tableColumnInfos(current, table)
+	if err != nil {
+		return nil
+	}
+	afterInfos, err := tableColumnInfos(scratch, table)
+	if err != nil {
+		return nil
+	}
+	kept := make(map[string]bool, len(afterInfos))
+	for _, c := range afterInfos {
+		kept[c.name] = true
+	}
+	var missing []string
+	for _, c := range before {
+		if !kept[c.name] {
+			missing = append(missing, c.name)
+		}
+	}
+	return missing
+}
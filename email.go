@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// validatePort 校验端口号设置
+func validatePort(v string) error {
+	port, err := strconv.Atoi(v)
+	if err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("端口必须为 1-65535 之间的整数")
+	}
+	return nil
+}
+
+// smtpConfig 发送邮件所需的 SMTP 设置
+type smtpConfig struct {
+	host, port, username, password, from string
+}
+
+// smtpSettings 读取 SMTP 设置，未配置服务器时返回错误
+func (a *App) smtpSettings() (smtpConfig, error) {
+	cfg := smtpConfig{
+		host:     strings.TrimSpace(a.setting("smtp_host")),
+		port:     a.setting("smtp_port"),
+		username: a.setting("smtp_username"),
+		password: a.setting("smtp_password"),
+		from:     strings.TrimSpace(a.setting("smtp_from")),
+	}
+	if cfg.host == "" {
+		return cfg, fmt.Errorf("尚未配置 SMTP 服务器，请先在设置中填写 smtp_host")
+	}
+	if cfg.from == "" {
+		cfg.from = cfg.username
+	}
+	if _, err := mail.ParseAddress(cfg.from); err != nil {
+		return cfg, fmt.Errorf("发件人地址无效: %s", cfg.from)
+	}
+	return cfg, nil
+}
+
+// sendMail 通过 SMTP 发送邮件：465 端口使用 SSL 直连，其他端口由 net/smtp 在服务器支持时自动 STARTTLS
+func sendMail(cfg smtpConfig, to []string, msg []byte) error {
+	addr := net.JoinHostPort(cfg.host, cfg.port)
+	var auth smtp.Auth
+	if cfg.username != "" {
+		auth = smtp.PlainAuth("", cfg.username, cfg.password, cfg.host)
+	}
+	if cfg.port != "465" {
+		return smtp.SendMail(addr, auth, cfg.from, to, msg)
+	}
+
+	conn, err := tls.Dial("tcp", addr, &tls.Config{ServerName: cfg.host})
+	if err != nil {
+		return err
+	}
+	client, err := smtp.NewClient(conn, cfg.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+	if auth != nil {
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(cfg.from); err != nil {
+		return err
+	}
+	for _, addr := range to {
+		if err := client.Rcpt(addr); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// buildMailMessage 生成带一个附件的 MIME 邮件
+func buildMailMessage(from string, to []string, subject, body, fileName, contentType string, attachment []byte) []byte {
+	boundary := fmt.Sprintf("excel-db-analysis-%d", time.Now().UnixNano())
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", boundary)
+
+	fmt.Fprintf(&buf, "--%s\r\n", boundary)
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+	writeBase64Lines(&buf, []byte(body))
+
+	fmt.Fprintf(&buf, "--%s\r\n", boundary)
+	encodedName := mime.BEncoding.Encode("UTF-8", fileName)
+	fmt.Fprintf(&buf, "Content-Type: %s; name=%q\r\n", contentType, encodedName)
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n")
+	fmt.Fprintf(&buf, "Content-Disposition: attachment; filename=%q\r\n\r\n", encodedName)
+	writeBase64Lines(&buf, attachment)
+
+	fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+	return buf.Bytes()
+}
+
+// writeBase64Lines 按每行 76 个字符写入 base64 编码内容
+func writeBase64Lines(buf *bytes.Buffer, data []byte) {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded + "\r\n")
+}
+
+// SendExportByEmail 执行 SQL 并将结果作为附件发送到指定邮箱
+// format 为 xlsx（默认）或 csv，SMTP 服务器在设置中配置；邮件发出后无法撤回，
+// 结果中检测到敏感列时不发送并返回提示，确认后以 allowSensitive 为 true 再次调用才发送
+// wails:export SendExportByEmail
+func (a *App) SendExportByEmail(sqlStr string, recipients []string, format string, allowSensitive bool) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+
+	sqlStr = strings.TrimSpace(sqlStr)
+	if sqlStr == "" {
+		return "错误：SQL 语句不能为空！"
+	}
+
+	var to []string
+	for _, r := range recipients {
+		r = strings.TrimSpace(r)
+		if r == "" {
+			continue
+		}
+		addr, err := mail.ParseAddress(r)
+		if err != nil {
+			return fmt.Sprintf("收件人地址无效: %s", r)
+		}
+		to = append(to, addr.Address)
+	}
+	if len(to) == 0 {
+		return "请填写收件人"
+	}
+
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		format = "xlsx"
+	}
+	if format != "xlsx" && format != "csv" {
+		return fmt.Sprintf("不支持的导出格式: %s（可选 xlsx、csv）", format)
+	}
+
+	cfg, err := a.smtpSettings()
+	if err != nil {
+		return err.Error()
+	}
+
+	columns, fullData, err := a.queryExportData(context.Background(), a.readDB(), sqlStr)
+	if err != nil {
+		return err.Error()
+	}
+	if len(fullData) == 0 {
+		return "导出失败：SQL 查询结果为空！"
+	}
+	warning := exportSensitiveWarning(columns, fullData)
+	if warning != "" && !allowSensitive {
+		return "邮件未发送。" + warning + "\n确认可以发送后请选择“仍要发送”"
+	}
+
+	var attachment []byte
+	var contentType string
+	if format == "csv" {
+		attachment, err = a.buildExportCSV(columns, fullData)
+		if err != nil {
+			return fmt.Sprintf("生成 CSV 失败: %v", err)
+		}
+		contentType = "text/csv"
+	} else {
+		f, err := a.buildExportWorkbook(columns, fullData)
+		if err != nil {
+			return fmt.Sprintf("导出 Excel 失败: %v", err)
+		}
+		if a.setting("export_provenance") == "true" {
+			if err := a.addProvenanceSheet(f, sqlStr, len(fullData)); err != nil {
+				f.Close()
+				return fmt.Sprintf("生成来源信息失败: %v", err)
+			}
+		}
+		buf, err := f.WriteToBuffer()
+		f.Close()
+		if err != nil {
+			return fmt.Sprintf("导出 Excel 失败: %v", err)
+		}
+		attachment = buf.Bytes()
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+
+	now := time.Now()
+	subject := fmt.Sprintf("查询结果 %s", now.Format("2006-01-02 15:04"))
+	fileName := fmt.Sprintf("查询结果_%s.%s", now.Format("20060102_150405"), format)
+	body := fmt.Sprintf("附件为查询结果（共 %d 条数据）。\r\n\r\n查询语句：\r\n%s\r\n", len(fullData), sqlStr)
+	msg := buildMailMessage(cfg.from, to, subject, body, fileName, contentType, attachment)
+
+	if err := sendMail(cfg, to, msg); err != nil {
+		return fmt.Sprintf("邮件发送失败: %v", err)
+	}
+
+	message := fmt.Sprintf("邮件已发送至 %s（共 %d 条数据）", strings.Join(to, ", "), len(fullData))
+	if warning != "" {
+		message += "\n" + warning
+	}
+	return message
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// EnrichTable 按键把查找表的列追加到目标表上并保存（相当于在 Excel 中用 VLOOKUP 补列）
+// keyMapping 为 目标表列 -> 查找表列；columnsToAdd 为查找表中要追加的列，
+// 与目标表已有列重名（不区分大小写，同 SQLite）时命名为 <查找表名>_<列名>；查找表中同一个键有多行时与 VLOOKUP 一样取第一行（按 rowid）
+// wails:export EnrichTable
+func (a *App) EnrichTable(target string, lookupTable string, keyMapping map[string]string, columnsToAdd []string) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	if len(keyMapping) == 0 {
+		return "请指定匹配键"
+	}
+	if len(columnsToAdd) == 0 {
+		return "请选择要追加的列"
+	}
+
+	targetInfos, err := tableColumnInfos(a.writeDB(), target)
+	if err != nil {
+		return err.Error()
+	}
+	lookupInfos, err := tableColumnInfos(a.writeDB(), lookupTable)
+	if err != nil {
+		return err.Error()
+	}
+	targetCols := make([]string, len(targetInfos))
+	for i, info := range targetInfos {
+		targetCols[i] = info.name
+	}
+	lookupTypes := make(map[string]string, len(lookupInfos))
+	for _, info := range lookupInfos {
+		lookupTypes[info.name] = info.declType
+	}
+
+	keys := make([]string, 0, len(keyMapping))
+	for k := range keyMapping {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var conditions, lookupKeys []string
+	for _, k := range keys {
+		lk := keyMapping[k]
+		if !containsString(targetCols, k) {
+			return fmt.Sprintf("表 %s 中不存在列 %s", target, k)
+		}
+		if _, ok := lookupTypes[lk]; !ok {
+			return fmt.Sprintf("表 %s 中不存在列 %s", lookupTable, lk)
+		}
+		conditions = append(conditions, fmt.Sprintf("t.%s = l.%s", quoteIdent(k), quoteIdent(lk)))
+		lookupKeys = append(lookupKeys, quoteIdent(lk))
+	}
+	on := strings.Join(conditions, " AND ")
+
+	// 新列名：与目标表已有列重名时加查找表名前缀；SQLite 的列名不区分大小写，按小写比较
+	used := make(map[string]bool, len(targetCols))
+	for _, col := range targetCols {
+		used[strings.ToLower(col)] = true
+	}
+	newCols := make([]string, len(columnsToAdd))
+	for i, col := range columnsToAdd {
+		if _, ok := lookupTypes[col]; !ok {
+			return fmt.Sprintf("表 %s 中不存在列 %s", lookupTable, col)
+		}
+		name := col
+		if used[strings.ToLower(name)] {
+			name = lookupTable + "_" + col
+		}
+		for n := 2; used[strings.ToLower(name)]; n++ {
+			name = fmt.Sprintf("%s_%s_%d", lookupTable, col, n)
+		}
+		used[strings.ToLower(name)] = true
+		newCols[i] = name
+	}
+
+	// 查找表中已软删除的行不参与匹配，目标表只统计未删除的行
+	lookupCols := make([]string, len(lookupInfos))
+	for i, info := range lookupInfos {
+		lookupCols[i] = info.name
+	}
+	lookupFrom, _ := a.liveSource(lookupTable, lookupCols)
+	targetFrom, _ := a.liveSource(target, targetCols)
+	// “第一行”按 rowid 确定；视图和 WITHOUT ROWID 表没有 rowid，按追加的列排序，结果同样确定
+	lookupRowid := a.tableHasRowid(lookupTable)
+	if lookupRowid && lookupFrom != quoteIdent(lookupTable) {
+		lookupFrom, _ = liveSubquery(lookupTable, lookupCols, true)
+	}
+
+	tx, err := a.writeDB().Begin()
+	if err != nil {
+		return fmt.Sprintf("开启事务失败: %v", err)
+	}
+	defer tx.Rollback()
+
+	var matched, duplicateKeys int
+	if err := tx.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s AS t WHERE EXISTS (SELECT 1 FROM %s AS l WHERE %s)",
+		targetFrom, lookupFrom, on)).Scan(&matched); err != nil {
+		return fmt.Sprintf("统计匹配行失败: %v", err)
+	}
+	if err := tx.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM (SELECT 1 FROM %s GROUP BY %s HAVING COUNT(*) > 1)",
+		lookupFrom, strings.Join(lookupKeys, ", "))).Scan(&duplicateKeys); err != nil {
+		return fmt.Sprintf("检查重复键失败: %v", err)
+	}
+
+	var sets, picks []string
+	for i, col := range columnsToAdd {
+		def := strings.TrimSpace(quoteIdent(newCols[i]) + " " + lookupTypes[col])
+		if _, err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", quoteIdent(target), def)); err != nil {
+			return fmt.Sprintf("添加列 %s 失败: %v", newCols[i], err)
+		}
+		sets = append(sets, fmt.Sprintf("%s = l.%s", quoteIdent(newCols[i]), quoteIdent(col)))
+		picks = append(picks, quoteIdent(col))
+	}
+
+	// 每个键只取查找表中的第一行
+	order := "rowid"
+	if !lookupRowid {
+		order = strings.Join(picks, ", ")
+	}
+	firstRows := fmt.Sprintf("(SELECT * FROM (SELECT %s, %s, ROW_NUMBER() OVER (PARTITION BY %s ORDER BY %s) AS _rn FROM %s) WHERE _rn = 1)",
+		strings.Join(lookupKeys, ", "), strings.Join(picks, ", "), strings.Join(lookupKeys, ", "), order, lookupFrom)
+	update := fmt.Sprintf("UPDATE %s AS t SET %s FROM %s AS l WHERE %s",
+		quoteIdent(target), strings.Join(sets, ", "), firstRows, on)
+	if _, err := tx.Exec(update); err != nil {
+		return fmt.Sprintf("补充数据失败: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Sprintf("提交事务失败: %v", err)
+	}
+
+	var total int
+	a.readDB().QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s", targetFrom)).Scan(&total)
+	message := fmt.Sprintf("已为表 %s 追加 %d 列（%s），%d 行匹配，%d 行未匹配",
+		target, len(newCols), strings.Join(newCols, "、"), matched, total-matched)
+	if duplicateKeys > 0 {
+		message += fmt.Sprintf("；查找表 %s 中有 %d 个键对应多行，已取第一行", lookupTable, duplicateKeys)
+	}
+	return message
+}
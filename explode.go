@@ -0,0 +1,132 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// explodeDelimiters 未指定分隔符时按顺序尝试的常见分隔符（取在数据中出现最多的一个）
+var explodeDelimiters = []string{";", "；", ",", "，", "、", "|", "/", "\n"}
+
+// detectDelimiter 在样本中出现次数最多的分隔符，均未出现时返回空字符串
+func detectDelimiter(samples []string) string {
+	best, bestCount := "", 0
+	for _, d := range explodeDelimiters {
+		count := 0
+		for _, s := range samples {
+			count += strings.Count(s, d)
+		}
+		if count > bestCount {
+			best, bestCount = d, count
+		}
+	}
+	return best
+}
+
+// ExplodeColumn 将一个单元格中用分隔符分隔的多个取值（如 "A;B;C"）拆分为子表 <表名>_<列名>_items：
+// 每个取值一行，包含 _source_rowid（源表 rowid）、position（序号，从 1 开始）和 value；delimiter 为空时自动识别
+// wails:export ExplodeColumn
+func (a *App) ExplodeColumn(tableName string, column string, delimiter string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+
+	columns, err := a.tableColumns(tableName)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	if !containsString(columns, column) {
+		result["error"] = fmt.Sprintf("表 %s 中不存在列 %s", tableName, column)
+		return result
+	}
+
+	rows, err := a.readDB().Query(fmt.Sprintf("SELECT rowid, CAST(%s AS TEXT) FROM %s WHERE TRIM(COALESCE(%s, '')) <> '' ORDER BY rowid",
+		quoteIdent(column), quoteIdent(tableName), quoteIdent(column)))
+	if err != nil {
+		result["error"] = fmt.Sprintf("读取数据失败: %v", err)
+		return result
+	}
+	type cell struct {
+		rowid int64
+		text  string
+	}
+	var cells []cell
+	var texts []string
+	for rows.Next() {
+		var c cell
+		var text sql.NullString
+		if err := rows.Scan(&c.rowid, &text); err != nil {
+			rows.Close()
+			result["error"] = fmt.Sprintf("读取数据失败: %v", err)
+			return result
+		}
+		c.text = text.String
+		cells = append(cells, c)
+		texts = append(texts, c.text)
+	}
+	rows.Close()
+
+	if delimiter == "" {
+		delimiter = detectDelimiter(texts)
+		if delimiter == "" {
+			result["error"] = fmt.Sprintf("列 %s 中未发现常见分隔符，请手动指定", column)
+			return result
+		}
+	}
+
+	var values [][]interface{}
+	for _, c := range cells {
+		position := 0
+		for _, part := range strings.Split(c.text, delimiter) {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			position++
+			values = append(values, []interface{}{c.rowid, position, part})
+		}
+	}
+
+	target := tableName + "_" + column + "_items"
+	tx, err := a.writeDB().Begin()
+	if err != nil {
+		result["error"] = fmt.Sprintf("开启事务失败: %v", err)
+		return result
+	}
+	defer tx.Rollback()
+	if err := dropTableOrView(tx, target); err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	if _, err := tx.Exec(fmt.Sprintf("CREATE TABLE %s (%s INTEGER, position INTEGER, value TEXT)",
+		quoteIdent(target), quoteIdent(flattenRowIDColumn))); err != nil {
+		result["error"] = fmt.Sprintf("创建表 %s 失败: %v", target, err)
+		return result
+	}
+	if err := insertRows(tx, target, []string{flattenRowIDColumn, "position", "value"}, values, nil); err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	if _, err := tx.Exec(fmt.Sprintf("CREATE INDEX %s ON %s (%s)",
+		quoteIdent("idx_"+target+flattenRowIDColumn), quoteIdent(target), quoteIdent(flattenRowIDColumn))); err != nil {
+		result["error"] = fmt.Sprintf("创建索引失败: %v", err)
+		return result
+	}
+	if err := tx.Commit(); err != nil {
+		result["error"] = fmt.Sprintf("提交事务失败: %v", err)
+		return result
+	}
+
+	result["table"] = target
+	result["delimiter"] = delimiter
+	result["rows"] = len(values)
+	result["sql"] = fmt.Sprintf("SELECT t.*, i.position, i.value\nFROM %s AS t\nJOIN %s AS i ON i.%s = t.rowid",
+		quoteIdent(tableName), quoteIdent(target), quoteIdent(flattenRowIDColumn))
+	result["message"] = fmt.Sprintf("已将列 %s 的 %d 个单元格拆分为表 %s（共 %d 个取值，分隔符 %q）",
+		column, len(cells), target, len(values), delimiter)
+	return result
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// exportSheetName 导出工作簿中数据所在的 Sheet
+const exportSheetName = "Sheet1"
+
+// contextQueryer *sql.DB、*sql.Conn 和 *sql.Tx 共有的查询方法
+type contextQueryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// exportOptions 导出 Excel 的附加选项（导出已保存的查询时使用其高亮规则和列设置），
+// pinned 为移到最左侧并冻结的关键列，spill 为 true 时经临时文件流式导出（见 exportExcelStreaming），csv 为 true 时导出为 CSV（见 exportCSV），
+// outline 为分组列（按该列插入组标题行并设置大纲级别，见 outline.go），validations 为各列的下拉列表（见 validation.go），
+// protect 不为 nil 时保护工作表并锁定其中的列（见 protection.go），hidden 为隐藏的列，roundTrip 不为 nil 时写入往返导出的来源信息（见 roundtrip.go）
+type exportOptions struct {
+	rules       []highlightRule
+	layout      []columnLayout
+	pinned      []string
+	spill       bool
+	csv         bool
+	outline     string
+	validations []columnValidation
+	protect     *sheetProtection
+	hidden      []string
+	roundTrip   *roundTripInfo
+	formats     []columnFormat
+	formulas    []columnFormula
+	totals      []columnTotal
+}
+
+// queryExportData 执行 SQL 并读取全量结果（无分页）
+func (a *App) queryExportData(ctx context.Context, q contextQueryer, sqlStr string) ([]string, []map[string]interface{}, error) {
+	fullRows, err := q.QueryContext(ctx, a.liveSQL(sqlStr))
+	if err != nil {
+		return nil, nil, fmt.Errorf("SQL 执行失败: %v", err)
+	}
+	defer fullRows.Close()
+
+	columns, err := fullRows.Columns()
+	if err != nil {
+		return nil, nil, fmt.Errorf("获取列名失败: %v", err)
+	}
+
+	fullData, err := scanRowMaps(fullRows, columns, a.nullValue())
+	if err != nil {
+		return nil, nil, err
+	}
+	return columns, fullData, nil
+}
+
+// exportHeaders 导出表头：设置了 export_header_language 时优先使用该语言的表头翻译，
+// 开启 export_header_labels 时使用数据字典中的显示名，都没有时为列名
+func (a *App) exportHeaders(columns []string) []string {
+	var labels, translated map[string]string
+	if a.setting("export_header_labels") == "true" {
+		labels = a.columnLabels()
+	}
+	if language := a.setting("export_header_language"); language != "" {
+		translated = a.headerTranslations(language)
+	}
+	headers := make([]string, len(columns))
+	for i, colName := range columns {
+		if header, ok := translated[colName]; ok {
+			headers[i] = header
+		} else if label, ok := labels[colName]; ok {
+			headers[i] = label
+		} else {
+			headers[i] = colName
+		}
+	}
+	return headers
+}
+
+// buildExportWorkbook 将查询结果写入新的 Excel 工作簿
+func (a *App) buildExportWorkbook(columns []string, fullData []map[string]interface{}) (*excelize.File, error) {
+	f := excelize.NewFile()
+	if err := a.writeExportSheet(f, exportSheetName, columns, fullData); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// writeExportSheet 把表头和全量数据写入工作簿中已存在的工作表；列数超过 Excel 上限时返回错误
+func (a *App) writeExportSheet(f *excelize.File, sheetName string, columns []string, fullData []map[string]interface{}) error {
+	// 写入表头
+	for colIdx, header := range a.exportHeaders(columns) {
+		cell, err := excelize.CoordinatesToCellName(colIdx+1, 1)
+		if err != nil {
+			return fmt.Errorf("写入表头失败（共 %d 列）: %v", len(columns), err)
+		}
+		f.SetCellValue(sheetName, cell, header)
+	}
+
+	// 日期列按 locale 设置的格式写为 Excel 日期
+	_, locale := currentDateConfig()
+	dateStyle, _ := f.NewStyle(&excelize.Style{CustomNumFmt: stringPtr(localeExcelDateFormat(locale, false))})
+	dateTimeStyle, _ := f.NewStyle(&excelize.Style{CustomNumFmt: stringPtr(localeExcelDateFormat(locale, true))})
+
+	// 网址写为可点击的超链接
+	links := newHyperlinkWriter(f)
+
+	// 写入全量数据
+	for rowIdx, rowData := range fullData {
+		for colIdx, colName := range columns {
+			cell, err := excelize.CoordinatesToCellName(colIdx+1, rowIdx+2)
+			if err != nil {
+				return fmt.Errorf("写入第 %d 行失败: %v", rowIdx+1, err)
+			}
+			if links.write(sheetName, cell, rowData[colName]) {
+				continue
+			}
+			if t, hasTime, ok := isoDateValue(rowData[colName]); ok {
+				f.SetCellValue(sheetName, cell, t)
+				if hasTime {
+					f.SetCellStyle(sheetName, cell, cell, dateTimeStyle)
+				} else {
+					f.SetCellStyle(sheetName, cell, cell, dateStyle)
+				}
+				continue
+			}
+			f.SetCellValue(sheetName, cell, rowData[colName])
+		}
+	}
+	return nil
+}
+
+// buildExportCSV 将查询结果写为 CSV（带 UTF-8 BOM，便于 Excel 直接打开中文）
+func (a *App) buildExportCSV(columns []string, fullData []map[string]interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("\xEF\xBB\xBF")
+	w := csv.NewWriter(&buf)
+	if err := w.Write(a.exportHeaders(columns)); err != nil {
+		return nil, err
+	}
+	record := make([]string, len(columns))
+	for _, rowData := range fullData {
+		for i, colName := range columns {
+			if rowData[colName] == nil {
+				record[i] = ""
+			} else {
+				record[i] = fmt.Sprint(rowData[colName])
+			}
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// exportSensitiveWarning 抽样检测导出结果中的敏感列，返回提示文字（无敏感列时为空）
+func exportSensitiveWarning(columns []string, fullData []map[string]interface{}) string {
+	samples := make(map[string][]string)
+	for _, rowData := range fullData {
+		for _, colName := range columns {
+			if isNullDisplay(rowData[colName]) {
+				continue
+			}
+			v := strings.TrimSpace(fmt.Sprint(rowData[colName]))
+			if v != "" && len(samples[colName]) < sensitiveSampleSize {
+				samples[colName] = append(samples[colName], v)
+			}
+		}
+	}
+	return sensitiveWarning(detectSensitive(columns, samples))
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"github.com/xuri/excelize/v2"
+)
+
+// queryValueRows 执行查询并按列顺序返回每行的值（TEXT 转为 string）
+func (a *App) queryValueRows(query string) ([][]interface{}, error) {
+	rows, err := a.readDB().Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("SQL 执行失败: %v", err)
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("获取列名失败: %v", err)
+	}
+
+	var result [][]interface{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		ptrs := make([]interface{}, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, fmt.Errorf("读取数据失败: %v", err)
+		}
+		for i, v := range values {
+			if b, ok := v.([]byte); ok {
+				values[i] = string(b)
+			}
+		}
+		result = append(result, values)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("遍历数据失败: %v", err)
+	}
+	return result, nil
+}
+
+// groupKey 分组取值拼成的键
+func groupKey(values []interface{}) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		if v == nil {
+			parts[i] = "\x01"
+		} else {
+			parts[i] = sqlValueText(v)
+		}
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// groupLabel 分组取值的显示文字
+func groupLabel(v interface{}) string {
+	if v == nil || sqlValueText(v) == "" {
+		return "(空)"
+	}
+	return sqlValueText(v)
+}
+
+// ExportGroupedExcel 按分组列汇总并导出带格式的 Excel：外层分组有分组标题行和小计行，末尾为总计行
+// source 为表名或查询语句；measures 为 [{column, agg, label}]，agg 可选 sum、count、avg、min、max、countDistinct
+// 小计和总计按对应层级重新聚合，平均值、去重计数等指标同样准确；结果记录到导出历史，可用 ReRunExport 重新导出
+// wails:export ExportGroupedExcel
+func (a *App) ExportGroupedExcel(source string, groupColumns []string, measures []map[string]interface{}) string {
+	message, err := a.exportGroupedExcel(source, groupColumns, measures, "")
+	if err != nil {
+		return err.Error()
+	}
+	return message
+}
+
+// exportGroupedExcel 执行分组汇总导出，savePath 为空时弹出保存对话框；选择了保存路径后的结果记录到导出历史
+func (a *App) exportGroupedExcel(source string, groupColumns []string, measures []map[string]interface{}, savePath string) (string, error) {
+	if a.writeDB() == nil {
+		return "", fmt.Errorf("错误：数据库连接未初始化，请重启应用！")
+	}
+	if len(groupColumns) == 0 {
+		return "", fmt.Errorf("请至少选择一个分组列")
+	}
+
+	startedAt := time.Now()
+	from, columns, err := a.resolveSource(strings.TrimSpace(source))
+	if err != nil {
+		return "", err
+	}
+	for _, col := range groupColumns {
+		if !containsString(columns, col) {
+			return "", fmt.Errorf("不存在列 %s", col)
+		}
+	}
+	ms, err := parseMeasures(measures, columns)
+	if err != nil {
+		return "", err
+	}
+	exprs := make([]string, len(ms))
+	for i, m := range ms {
+		exprs[i] = m.expr()
+	}
+
+	// 各层级的汇总：level 为分组列数，0 为总计
+	n := len(groupColumns)
+	levelQuery := func(level int) string {
+		if level == 0 {
+			return fmt.Sprintf("SELECT %s FROM %s", strings.Join(exprs, ", "), from)
+		}
+		quoted := make([]string, level)
+		for i, col := range groupColumns[:level] {
+			quoted[i] = quoteIdent(col)
+		}
+		keys := strings.Join(quoted, ", ")
+		return fmt.Sprintf("SELECT %s, %s FROM %s GROUP BY %s ORDER BY %s", keys, strings.Join(exprs, ", "), from, keys, keys)
+	}
+	detail, err := a.queryValueRows(levelQuery(n))
+	if err != nil {
+		return "", err
+	}
+	if len(detail) == 0 {
+		return "", fmt.Errorf("导出失败：SQL 查询结果为空！")
+	}
+	subtotals := make([]map[string][]interface{}, n)
+	for level := 0; level < n; level++ {
+		rows, err := a.queryValueRows(levelQuery(level))
+		if err != nil {
+			return "", err
+		}
+		subtotals[level] = make(map[string][]interface{}, len(rows))
+		for _, row := range rows {
+			subtotals[level][groupKey(row[:level])] = row[level:]
+		}
+	}
+
+	if savePath == "" {
+		savePath, err = runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+			Title:           "导出分组汇总",
+			DefaultFilename: "分组汇总.xlsx",
+			Filters:         []runtime.FileFilter{{Pattern: "*.xlsx", DisplayName: "Excel 文件"}},
+		})
+		if err != nil {
+			return "", fmt.Errorf("文件保存失败: %v", err)
+		}
+		if savePath == "" {
+			return "取消导出", nil
+		}
+	}
+
+	f, rowCount := a.buildGroupedWorkbook(groupColumns, ms, detail, subtotals)
+	defer f.Close()
+	options := map[string]interface{}{"grouped": map[string]interface{}{"groupColumns": groupColumns, "measures": measures}}
+	if err := f.SaveAs(savePath); err != nil {
+		err = fmt.Errorf("导出 Excel 失败: %v", err)
+		a.insertExportHistory(source, savePath, options, "failed", 0, startedAt, time.Now(), err.Error())
+		return "", err
+	}
+	a.insertExportHistory(source, savePath, options, "done", rowCount, startedAt, time.Now(), "")
+	return fmt.Sprintf("分组汇总导出成功: %s（%d 个分组，%d 行）", savePath, len(detail), rowCount), nil
+}
+
+// buildGroupedWorkbook 按明细行和各层级汇总生成分组汇总工作簿，返回工作簿和写入的行数
+func (a *App) buildGroupedWorkbook(groupColumns []string, ms []measure, detail [][]interface{}, subtotals []map[string][]interface{}) (*excelize.File, int) {
+	f := excelize.NewFile()
+	sheet := exportSheetName
+	headerStyle, _ := f.NewStyle(&excelize.Style{
+		Font:   &excelize.Font{Bold: true},
+		Border: []excelize.Border{{Type: "bottom", Color: "000000", Style: 1}},
+	})
+	groupStyle, _ := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"DDEBF7"}},
+	})
+	subtotalStyle, _ := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	totalStyle, _ := f.NewStyle(&excelize.Style{
+		Font:   &excelize.Font{Bold: true},
+		Border: []excelize.Border{{Type: "top", Color: "000000", Style: 2}},
+	})
+
+	n := len(groupColumns)
+	width := n + len(ms)
+	rowNum := 0
+	writeRow := func(values []interface{}, style int) {
+		rowNum++
+		cell, _ := excelize.CoordinatesToCellName(1, rowNum)
+		f.SetSheetRow(sheet, cell, &values)
+		if style != 0 {
+			last, _ := excelize.CoordinatesToCellName(width, rowNum)
+			f.SetCellStyle(sheet, cell, last, style)
+		}
+	}
+	measureRow := func(label string, labelCol int, values []interface{}) []interface{} {
+		row := make([]interface{}, width)
+		row[labelCol] = label
+		copy(row[n:], values)
+		return row
+	}
+
+	header := make([]interface{}, 0, width)
+	for _, h := range a.exportHeaders(groupColumns) {
+		header = append(header, h)
+	}
+	for _, m := range ms {
+		header = append(header, m.label)
+	}
+	writeRow(header, headerStyle)
+
+	// 外层分组取值变化时先关闭内层小计，再打开新的分组标题
+	var current []interface{}
+	closeGroups := func(from int) {
+		for level := n - 1; level >= from && level >= 1; level-- {
+			label := groupLabel(current[level-1]) + " 小计"
+			writeRow(measureRow(label, level-1, subtotals[level][groupKey(current[:level])]), subtotalStyle)
+		}
+	}
+	for _, row := range detail {
+		changed := n
+		if current == nil {
+			changed = 0
+		} else {
+			for level := 0; level < n-1; level++ {
+				if groupKey(row[level:level+1]) != groupKey(current[level:level+1]) {
+					changed = level
+					break
+				}
+			}
+		}
+		if current != nil && changed < n {
+			closeGroups(changed + 1)
+		}
+		current = row[:n]
+		for level := changed; level < n-1; level++ {
+			title := make([]interface{}, width)
+			title[level] = fmt.Sprintf("%s: %s", header[level], groupLabel(row[level]))
+			writeRow(title, groupStyle)
+		}
+		writeRow(measureRow(groupLabel(row[n-1]), n-1, row[n:]), 0)
+	}
+	closeGroups(1)
+	writeRow(measureRow("总计", 0, subtotals[0][""]), totalStyle)
+
+	return f, rowNum
+}
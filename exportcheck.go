@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Excel 工作表的行数和列数上限（行数含表头）
+const (
+	excelMaxRows    = 1048576
+	excelMaxColumns = 16384
+)
+
+// 导出预估使用的经验值：抽样行数、每秒写入的单元格数、xlsx 压缩后的大小比例和每个单元格的 XML 开销
+const (
+	exportSampleRows        = 200
+	xlsxCellsPerSecond      = 400000
+	csvCellsPerSecond       = 3000000
+	xlsxCompressionRatio    = 0.2
+	xlsxCellOverheadBytes   = 30
+	exportHugeCells         = 5000000
+	exportSlowSeconds       = 60
+	defaultExportSampleSize = 10000
+)
+
+// errExcelRowLimit 结果行数超过 Excel 工作表上限
+func errExcelRowLimit(rows int) error {
+	return fmt.Errorf("结果共 %d 行，超过 Excel 单个工作表的上限 %d 行，请改为导出 CSV（ExportCSVBySQL）或抽样导出（ExportSample）", rows, excelMaxRows-1)
+}
+
+// EstimateExport 导出前的预估：统计行数、列数，抽样估算文件大小和耗时
+// 返回 {rows, columns, cells, xlsxBytes, csvBytes, xlsxSeconds, csvSeconds, exceedsExcelLimit, huge, warnings, options, message}；
+// options 为建议的导出方式：xlsx（普通导出）、large（流式导出）、csv（ExportCSVBySQL）、sample（ExportSample）
+// wails:export EstimateExport
+func (a *App) EstimateExport(sqlStr string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	stmt := parseStatement(strings.TrimSpace(sqlStr))
+	if stmt.text == "" {
+		result["error"] = "错误：SQL 语句不能为空！"
+		return result
+	}
+	stmt.text = a.liveSQL(stmt.text)
+	countSQL, err := stmt.countSQL()
+	if err != nil {
+		result["error"] = fmt.Sprintf("无法预估导出大小: %v", err)
+		return result
+	}
+	var total int
+	if err := a.readDB().QueryRow(countSQL).Scan(&total); err != nil {
+		result["error"] = fmt.Sprintf("SQL 执行失败: %v", err)
+		return result
+	}
+
+	sampleSQL, _ := stmt.pagedSQL(exportSampleRows, 0)
+	rows, err := a.readDB().Query(sampleSQL)
+	if err != nil {
+		result["error"] = fmt.Sprintf("SQL 执行失败: %v", err)
+		return result
+	}
+	columns, _ := rows.Columns()
+	sample, err := scanRowMaps(rows, columns, nil)
+	rows.Close()
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+
+	// 按样本的平均文本长度推算全量数据的大小
+	rowBytes := 0.0
+	if len(sample) > 0 {
+		rowBytes = float64(estimateDataSize(columns, sample)) / float64(len(sample))
+	}
+	cells := total * len(columns)
+	rawBytes := rowBytes * float64(total)
+	xlsxBytes := int64((rawBytes + float64(cells*xlsxCellOverheadBytes)) * xlsxCompressionRatio)
+	csvBytes := int64(rawBytes) + int64(cells) + int64(total)
+	xlsxSeconds := float64(cells) / xlsxCellsPerSecond
+	csvSeconds := float64(cells) / csvCellsPerSecond
+
+	exceeds := total+1 > excelMaxRows || len(columns) > excelMaxColumns
+	huge := cells > exportHugeCells || xlsxSeconds > exportSlowSeconds
+	warnings := []string{}
+	options := []string{}
+	switch {
+	case exceeds:
+		warnings = append(warnings, fmt.Sprintf("结果共 %d 行 %d 列，超过 Excel 单个工作表的上限（%d 行、%d 列），无法导出为 Excel", total, len(columns), excelMaxRows-1, excelMaxColumns))
+		options = append(options, "csv", "sample")
+	case huge:
+		warnings = append(warnings, fmt.Sprintf("结果共 %d 个单元格，导出 Excel 预计需要约 %.0f 秒、文件约 %s", cells, xlsxSeconds, formatBytes(uint64(xlsxBytes))))
+		options = append(options, "large", "csv", "sample")
+	default:
+		options = append(options, "xlsx")
+	}
+	if limit := memoryLimit.Load(); limit > 0 && rawBytes*3 > float64(limit)*memoryPressureRatio && !exceeds {
+		warnings = append(warnings, "全量结果可能超出内存上限，普通导出会自动改为流式写入")
+	}
+
+	result["rows"] = total
+	result["columns"] = len(columns)
+	result["cells"] = cells
+	result["xlsxBytes"] = xlsxBytes
+	result["csvBytes"] = csvBytes
+	result["xlsxSeconds"] = xlsxSeconds
+	result["csvSeconds"] = csvSeconds
+	result["exceedsExcelLimit"] = exceeds
+	result["huge"] = huge
+	result["warnings"] = warnings
+	result["options"] = options
+	result["message"] = fmt.Sprintf("共 %d 行 %d 列，Excel 约 %s（约 %.1f 秒），CSV 约 %s（约 %.1f 秒），以上为按 %d 行样本估算的结果",
+		total, len(columns), formatBytes(uint64(xlsxBytes)), xlsxSeconds, formatBytes(uint64(csvBytes)), csvSeconds, len(sample))
+	return result
+}
+
+// exportCSV 逐行把查询结果写入 CSV 文件（带 UTF-8 BOM），不在内存中保留全量结果，也不受 Excel 行数上限限制；
+// 结果为空时不创建文件，写入中途失败时删除写了一半的文件
+func (a *App) exportCSV(ctx context.Context, q contextQueryer, sqlStr string, savePath string) (message string, count int, err error) {
+	if err := checkDiskSpace(filepath.Dir(savePath), 0); err != nil {
+		return "", 0, err
+	}
+	op := operationFrom(ctx)
+	op.progress("query", 0, 0, "正在执行查询")
+	rows, err := q.QueryContext(ctx, sqlStr)
+	if err != nil {
+		return "", 0, fmt.Errorf("SQL 执行失败: %v", err)
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", 0, fmt.Errorf("获取列名失败: %v", err)
+	}
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return "", 0, fmt.Errorf("遍历数据失败: %v", err)
+		}
+		return "", 0, fmt.Errorf("导出失败：SQL 查询结果为空！")
+	}
+
+	file, err := os.Create(savePath)
+	if err != nil {
+		return "", 0, fmt.Errorf("创建文件失败: %v", err)
+	}
+	defer func() {
+		file.Close()
+		if err != nil {
+			os.Remove(savePath)
+		}
+	}()
+	buf := bufio.NewWriter(file)
+	buf.WriteString("\xEF\xBB\xBF")
+	w := csv.NewWriter(buf)
+	if err := w.Write(a.exportHeaders(columns)); err != nil {
+		return "", 0, fmt.Errorf("写入表头失败: %v", err)
+	}
+
+	values := make([]interface{}, len(columns))
+	ptrs := make([]interface{}, len(columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	record := make([]string, len(columns))
+	for more := true; more; more = rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return "", 0, err
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return "", 0, fmt.Errorf("读取数据失败: %v", err)
+		}
+		for i, v := range values {
+			switch x := v.(type) {
+			case nil:
+				record[i] = ""
+			case []byte:
+				record[i] = string(x)
+			default:
+				record[i] = fmt.Sprint(x)
+			}
+		}
+		if err := w.Write(record); err != nil {
+			return "", 0, fmt.Errorf("写入第 %d 行失败: %v", count+1, err)
+		}
+		count++
+		op.progress("write", count, 0, fmt.Sprintf("已写入 %d 行", count))
+	}
+	if err := rows.Err(); err != nil {
+		return "", 0, fmt.Errorf("遍历数据失败: %v", err)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", 0, fmt.Errorf("写入 CSV 失败: %v", err)
+	}
+	if err := buf.Flush(); err != nil {
+		return "", 0, fmt.Errorf("写入 CSV 失败: %v", err)
+	}
+	return fmt.Sprintf("CSV 导出成功: %s（共 %d 条数据）", savePath, count), count, nil
+}
+
+// ExportCSVBySQL 在后台把查询结果导出为 CSV，适合超过 Excel 行数上限或很大的结果；返回值同 ExportExcelBySQL
+// wails:export ExportCSVBySQL
+func (a *App) ExportCSVBySQL(sqlStr string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	sqlStr = strings.TrimSpace(sqlStr)
+	if sqlStr == "" {
+		result["error"] = "错误：SQL 语句不能为空！"
+		return result
+	}
+	return a.startExportJob(sqlStr, "查询结果", exportOptions{csv: true})
+}
+
+// ExportSample 抽样导出：从结果中按原顺序随机保留约 n 行（n <= 0 时为 1 万行）导出为 Excel，用于先把结构和样例发给对方确认
+// wails:export ExportSample
+func (a *App) ExportSample(sqlStr string, n int) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	stmt := parseStatement(strings.TrimSpace(sqlStr))
+	if stmt.text == "" {
+		result["error"] = "错误：SQL 语句不能为空！"
+		return result
+	}
+	if n <= 0 {
+		n = defaultExportSampleSize
+	}
+	stmt.text = a.liveSQL(stmt.text)
+	countSQL, err := stmt.countSQL()
+	if err != nil {
+		result["error"] = fmt.Sprintf("无法抽样导出: %v", err)
+		return result
+	}
+	var total int
+	if err := a.readDB().QueryRow(countSQL).Scan(&total); err != nil {
+		result["error"] = fmt.Sprintf("SQL 执行失败: %v", err)
+		return result
+	}
+
+	sampleSQL := stmt.text
+	if total > n {
+		// 伯努利抽样：每行以 n/total 的概率入选，保持原有顺序
+		sampleSQL = fmt.Sprintf("SELECT * FROM (\n%s\n) WHERE (RANDOM() & 9223372036854775807) %% %d < %d", stmt.text, total, n)
+	}
+	result = a.startExportJob(sampleSQL, "抽样结果", exportOptions{})
+	if exportStarted(result) && total > n {
+		result["message"] = fmt.Sprintf("%s（从 %d 行中抽样约 %d 行）", result["message"], total, n)
+	}
+	return result
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// exportHistoryLimit 导出历史最多保留的条数，超出时删除最早的记录
+const exportHistoryLimit = 500
+
+// toMap 导出选项的 JSON 表示，记录在导出历史中，由 parseExportOptions 还原；
+// 工作表保护的密码不记录，只记录是否设置了密码，重新导出时需要再次输入
+func (o exportOptions) toMap() map[string]interface{} {
+	m := map[string]interface{}{}
+	if len(o.rules) > 0 {
+		rules := make([]map[string]interface{}, len(o.rules))
+		for i, r := range o.rules {
+			rules[i] = r.toMap()
+		}
+		m["rules"] = rules
+	}
+	if len(o.layout) > 0 {
+		layout := make([]map[string]interface{}, len(o.layout))
+		for i, c := range o.layout {
+			layout[i] = c.toMap()
+		}
+		m["layout"] = layout
+	}
+	if len(o.validations) > 0 {
+		vs := make([]map[string]interface{}, len(o.validations))
+		for i, v := range o.validations {
+			vs[i] = map[string]interface{}{"column": v.column, "values": v.values, "allowBlank": v.allowBlank}
+		}
+		m["validations"] = vs
+	}
+	if len(o.pinned) > 0 {
+		m["pinned"] = o.pinned
+	}
+	if len(o.hidden) > 0 {
+		m["hidden"] = o.hidden
+	}
+	if o.spill {
+		m["spill"] = true
+	}
+	if o.csv {
+		m["csv"] = true
+	}
+	if o.outline != "" {
+		m["outline"] = o.outline
+	}
+	if o.protect != nil {
+		m["protect"] = map[string]interface{}{"locked": o.protect.locked, "hasPassword": o.protect.password != ""}
+	}
+	if len(o.formats) > 0 {
+		m["formats"] = columnFormatMaps(o.formats)
+	}
+	if len(o.formulas) > 0 {
+		formulas := make([]map[string]interface{}, len(o.formulas))
+		for i, c := range o.formulas {
+			formulas[i] = c.toMap()
+		}
+		m["formulas"] = formulas
+	}
+	if len(o.totals) > 0 {
+		totals := make([]map[string]interface{}, len(o.totals))
+		for i, c := range o.totals {
+			totals[i] = c.toMap()
+		}
+		m["totals"] = totals
+	}
+	if o.roundTrip != nil {
+		m["roundTrip"] = map[string]interface{}{"table": o.roundTrip.table, "columns": o.roundTrip.columns}
+	}
+	return m
+}
+
+// stringList 把 JSON 解码得到的数组转为字符串切片
+func stringList(v interface{}) []string {
+	items, _ := v.([]interface{})
+	list := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+// mapList 把 JSON 解码得到的对象数组转为 []map[string]interface{}
+func mapList(v interface{}) []map[string]interface{} {
+	items, _ := v.([]interface{})
+	list := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		if m, ok := item.(map[string]interface{}); ok {
+			list = append(list, m)
+		}
+	}
+	return list
+}
+
+// protectedWithPassword 记录的工作表保护是否设置了密码（早期的记录中为密码本身）
+func protectedWithPassword(protect map[string]interface{}) bool {
+	if has, ok := protect["hasPassword"].(bool); ok {
+		return has
+	}
+	password, _ := protect["password"].(string)
+	return password != ""
+}
+
+// parseExportOptions 由导出历史中记录的 JSON 还原导出选项；needsPassword 表示原导出的工作表保护设置了密码，
+// 还原的选项中不含密码
+func parseExportOptions(optionsJSON string) (opts exportOptions, needsPassword bool, err error) {
+	m := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(optionsJSON), &m); err != nil {
+		return opts, false, fmt.Errorf("导出选项记录已损坏: %v", err)
+	}
+	if opts.rules, err = parseHighlightRules(mapList(m["rules"])); err != nil {
+		return opts, false, err
+	}
+	if opts.layout, err = parseColumnLayout(mapList(m["layout"])); err != nil {
+		return opts, false, err
+	}
+	if opts.validations, err = parseValidations(mapList(m["validations"])); err != nil {
+		return opts, false, err
+	}
+	if opts.formats, err = parseColumnFormats(mapList(m["formats"])); err != nil {
+		return opts, false, err
+	}
+	if opts.formulas, err = parseColumnFormulas(mapList(m["formulas"])); err != nil {
+		return opts, false, err
+	}
+	if opts.totals, err = parseColumnTotals(mapList(m["totals"])); err != nil {
+		return opts, false, err
+	}
+	opts.pinned = stringList(m["pinned"])
+	opts.hidden = stringList(m["hidden"])
+	opts.spill, _ = m["spill"].(bool)
+	opts.csv, _ = m["csv"].(bool)
+	opts.outline, _ = m["outline"].(string)
+	if p, ok := m["protect"].(map[string]interface{}); ok {
+		opts.protect = &sheetProtection{locked: stringList(p["locked"])}
+		needsPassword = protectedWithPassword(p)
+	}
+	if rt, ok := m["roundTrip"].(map[string]interface{}); ok {
+		opts.roundTrip = &roundTripInfo{columns: stringList(rt["columns"])}
+		opts.roundTrip.table, _ = rt["table"].(string)
+	}
+	return opts, needsPassword, nil
+}
+
+// recordExportHistory 记录一次导出任务的 SQL、保存路径、选项、耗时和行数
+func (a *App) recordExportHistory(job *exportJob) {
+	errorText := ""
+	if job.status == "failed" {
+		errorText = job.message
+	}
+	a.insertExportHistory(job.sql, job.savePath, job.options.toMap(), job.status, job.rows, job.startedAt, job.finishedAt, errorText)
+}
+
+// insertExportHistory 写入一条导出记录（只读模式下不记录，记录失败不影响导出）；
+// 不经过后台导出任务的导出（追加工作表、分组汇总）在 options 中记录各自的参数，见 ReRunExport
+func (a *App) insertExportHistory(sqlStr string, savePath string, options map[string]interface{}, status string, rows int,
+	startedAt time.Time, finishedAt time.Time, errorText string) {
+	if a.writeDB() == nil || a.readOnly() {
+		return
+	}
+	optionsJSON, _ := json.Marshal(options)
+	if _, err := a.writeDB().Exec(`INSERT INTO _app_export_history (sql, save_path, options, status, rows, duration_ms, started_at, error)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`, sqlStr, savePath, string(optionsJSON), status, rows,
+		finishedAt.Sub(startedAt).Milliseconds(), startedAt.Format("2006-01-02 15:04:05"), errorText); err != nil {
+		fmt.Printf("记录导出历史失败: %v\n", err)
+		return
+	}
+	if _, err := a.writeDB().Exec("DELETE FROM _app_export_history WHERE id <= (SELECT MAX(id) FROM _app_export_history) - ?", exportHistoryLimit); err != nil {
+		fmt.Printf("清理导出历史失败: %v\n", err)
+	}
+}
+
+// GetExportHistory 最近的导出记录（最近的在前）：id、sql、savePath、options（保护密码只标明是否设置）、status、rows、durationMs、startedAt、error
+// wails:export GetExportHistory
+func (a *App) GetExportHistory(limit int) []map[string]interface{} {
+	history := []map[string]interface{}{}
+	if a.writeDB() == nil {
+		return history
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+	rows, err := a.readDB().Query(`SELECT id, sql, save_path, options, status, rows, duration_ms, started_at, error
+		FROM _app_export_history ORDER BY id DESC LIMIT ?`, limit)
+	if err != nil {
+		fmt.Printf("读取导出历史失败: %v\n", err)
+		return history
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, rowCount, durationMs int64
+		var sqlStr, savePath, optionsJSON, status, startedAt, errorText string
+		if err := rows.Scan(&id, &sqlStr, &savePath, &optionsJSON, &status, &rowCount, &durationMs, &startedAt, &errorText); err != nil {
+			fmt.Printf("读取导出历史失败: %v\n", err)
+			return history
+		}
+		options := map[string]interface{}{}
+		json.Unmarshal([]byte(optionsJSON), &options)
+		if p, ok := options["protect"].(map[string]interface{}); ok {
+			p["password"] = protectedWithPassword(p)
+			delete(p, "hasPassword")
+		}
+		history = append(history, map[string]interface{}{
+			"id":         id,
+			"sql":        sqlStr,
+			"savePath":   savePath,
+			"options":    options,
+			"status":     status,
+			"rows":       rowCount,
+			"durationMs": durationMs,
+			"startedAt":  startedAt,
+			"error":      errorText,
+		})
+	}
+	return history
+}
+
+// ReRunExport 按导出历史中的记录重新导出：使用相同的 SQL 和选项，覆盖写入原保存路径（查询结果为当前数据），
+// 适合定期交付的报表；原导出的工作表保护设置了密码时需要通过 password 再次提供（导出历史不保存密码）。
+// 返回新的后台导出任务，进度和结果同 ExportExcelBySQL；追加工作表和分组汇总的记录直接重新执行，返回其结果
+// wails:export ReRunExport
+func (a *App) ReRunExport(id int, password string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	var sqlStr, savePath, optionsJSON string
+	err := a.readDB().QueryRow("SELECT sql, save_path, options FROM _app_export_history WHERE id = ?", id).Scan(&sqlStr, &savePath, &optionsJSON)
+	if err == sql.ErrNoRows {
+		result["error"] = fmt.Sprintf("导出记录 %d 不存在", id)
+		return result
+	}
+	if err != nil {
+		result["error"] = fmt.Sprintf("读取导出记录失败: %v", err)
+		return result
+	}
+	m := map[string]interface{}{}
+	json.Unmarshal([]byte(optionsJSON), &m)
+	if appendSheet, ok := m["appendSheet"].(map[string]interface{}); ok {
+		sheetName, _ := appendSheet["sheetName"].(string)
+		return a.ExportAppendSheet(savePath, sheetName, sqlStr)
+	}
+	if grouped, ok := m["grouped"].(map[string]interface{}); ok {
+		message, err := a.exportGroupedExcel(sqlStr, stringList(grouped["groupColumns"]), mapList(grouped["measures"]), savePath)
+		if err != nil {
+			result["error"] = err.Error()
+			return result
+		}
+		result["message"] = message
+		return result
+	}
+	opts, needsPassword, err := parseExportOptions(optionsJSON)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	if needsPassword {
+		if password == "" {
+			result["error"] = "原导出的工作表保护设置了密码，导出历史不保存密码，请输入密码后重新导出"
+			result["needsPassword"] = true
+			return result
+		}
+		opts.protect.password = password
+	}
+	return a.launchExportJob(sqlStr, savePath, opts)
+}
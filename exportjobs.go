@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// exportFinishedEvent 后台导出完成（成功或失败）时发送给前端的事件名，负载同 GetExportJob
+const exportFinishedEvent = "export-finished"
+
+// exportJob 一个后台导出任务
+type exportJob struct {
+	seq        int
+	id         string
+	sql        string
+	savePath   string
+	options    exportOptions
+	status     string // running、done、failed
+	message    string
+	rows       int
+	startedAt  time.Time
+	finishedAt time.Time
+}
+
+func (j *exportJob) toMap() map[string]interface{} {
+	m := map[string]interface{}{
+		"jobId":     j.id,
+		"sql":       j.sql,
+		"savePath":  j.savePath,
+		"status":    j.status,
+		"message":   j.message,
+		"rows":      j.rows,
+		"startedAt": j.startedAt.Format("2006-01-02 15:04:05"),
+	}
+	if !j.finishedAt.IsZero() {
+		m["finishedAt"] = j.finishedAt.Format("2006-01-02 15:04:05")
+	}
+	if j.status == "failed" {
+		m["code"] = codeExportFailed
+	}
+	return m
+}
+
+// exportJobsKept 最多保留的已结束导出任务数，超出时丢弃最早的（进行中的任务不受影响）；导出记录另见导出历史
+const exportJobsKept = 100
+
+// exportJobs 本次运行中的导出任务：进行中的全部，已结束的最近 exportJobsKept 个
+type exportJobs struct {
+	mu     sync.Mutex
+	nextID int
+	jobs   map[string]*exportJob
+}
+
+func newExportJobs() *exportJobs {
+	return &exportJobs{jobs: make(map[string]*exportJob)}
+}
+
+func (e *exportJobs) start(sqlStr string, savePath string) *exportJob {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.nextID++
+	job := &exportJob{
+		seq:       e.nextID,
+		id:        fmt.Sprintf("export-%d", e.nextID),
+		sql:       sqlStr,
+		savePath:  savePath,
+		status:    "running",
+		message:   "导出中",
+		startedAt: time.Now(),
+	}
+	e.jobs[job.id] = job
+	return job
+}
+
+// finish 记录任务结果并返回任务快照
+func (e *exportJobs) finish(job *exportJob, message string, rows int, err error) map[string]interface{} {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	job.finishedAt = time.Now()
+	if err != nil {
+		job.status = "failed"
+		job.message = err.Error()
+	} else {
+		job.status = "done"
+		job.message = message
+		job.rows = rows
+	}
+	e.prune()
+	return job.toMap()
+}
+
+// prune 丢弃超出 exportJobsKept 的最早结束的任务，调用方须持有 mu
+func (e *exportJobs) prune() {
+	var finished []*exportJob
+	for _, job := range e.jobs {
+		if job.status != "running" {
+			finished = append(finished, job)
+		}
+	}
+	if len(finished) <= exportJobsKept {
+		return
+	}
+	sort.Slice(finished, func(i, j int) bool { return finished[i].seq < finished[j].seq })
+	for _, job := range finished[:len(finished)-exportJobsKept] {
+		delete(e.jobs, job.id)
+	}
+}
+
+func (e *exportJobs) get(id string) (map[string]interface{}, bool) {
+	if e == nil {
+		return nil, false
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	job, ok := e.jobs[id]
+	if !ok {
+		return nil, false
+	}
+	return job.toMap(), true
+}
+
+func (e *exportJobs) list() []map[string]interface{} {
+	if e == nil {
+		return nil
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	jobs := make([]*exportJob, 0, len(e.jobs))
+	for _, job := range e.jobs {
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].seq > jobs[j].seq })
+	list := make([]map[string]interface{}, len(jobs))
+	for i, job := range jobs {
+		list[i] = job.toMap()
+	}
+	return list
+}
+
+// startExportJob 选择保存路径并启动后台导出任务，defaultName 为默认文件名（不含扩展名）；
+// 返回的 status 为 success（已开始导出）、cancelled（未选择保存路径）或 error（见 status.go）
+func (a *App) startExportJob(sqlStr string, defaultName string, opts exportOptions) map[string]interface{} {
+	title, ext, filter := "导出 Excel 文件", ".xlsx", runtime.FileFilter{Pattern: "*.xlsx", DisplayName: "Excel 文件"}
+	if opts.csv {
+		title, ext, filter = "导出 CSV 文件", ".csv", runtime.FileFilter{Pattern: "*.csv", DisplayName: "CSV 文件"}
+	}
+	savePath, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           title,
+		DefaultFilename: strings.NewReplacer("/", "_", `\`, "_").Replace(defaultName) + ext,
+		Filters:         []runtime.FileFilter{filter},
+	})
+	if err != nil {
+		return errorResult(codeDialogFailed, fmt.Sprintf("文件保存失败: %v", err))
+	}
+	if savePath == "" {
+		return cancelledResult("取消导出")
+	}
+	return a.launchExportJob(sqlStr, savePath, opts)
+}
+
+// launchExportJob 启动写入 savePath 的后台导出任务
+func (a *App) launchExportJob(sqlStr string, savePath string, opts exportOptions) map[string]interface{} {
+	result := successResult("")
+	job := a.exports.start(sqlStr, savePath)
+	job.options = opts
+	go a.runExportJob(job)
+
+	result["jobId"] = job.id
+	result["savePath"] = savePath
+	result["message"] = fmt.Sprintf("导出任务 %s 已开始：%s", job.id, savePath)
+	return result
+}
+
+// runExportJob 在后台执行导出任务（进度事件的 operationId 即任务 ID），完成后通知前端
+func (a *App) runExportJob(job *exportJob) {
+	op := a.beginOperation(job.id, "export", fmt.Sprintf("导出 %s", job.savePath))
+	message, rows, err := a.exportExcel(withOperation(op.context(), op), job.sql, job.savePath, job.options)
+	snapshot := a.exports.finish(job, message, rows, err)
+	a.recordExportHistory(job)
+	op.finish(message, err)
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, exportFinishedEvent, snapshot)
+	}
+}
+
+// GetExportJob 查询导出任务的状态（running、done、failed）和结果说明
+// wails:export GetExportJob
+func (a *App) GetExportJob(jobID string) map[string]interface{} {
+	if job, ok := a.exports.get(jobID); ok {
+		return job
+	}
+	return map[string]interface{}{"error": fmt.Sprintf("导出任务 %s 不存在", jobID)}
+}
+
+// ListExportJobs 列出本次运行中的导出任务（最近的在前）
+// wails:export ListExportJobs
+func (a *App) ListExportJobs() []map[string]interface{} {
+	return a.exports.list()
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// exportExcelStreaming 大结果或内存紧张时的导出路径：查询结果先写入磁盘临时文件（见 spool.go），读完即释放数据库读锁，
+// 再逐行读回并用 excelize 流式写入（超过缓冲区的数据同样暂存到临时文件），内存中不保留全量结果；
+// 列设置、冻结列、高亮规则和日期格式与普通导出一致，但网址不写为超链接
+func (a *App) exportExcelStreaming(ctx context.Context, q contextQueryer, sqlStr string, savePath string, opts exportOptions) (string, int, error) {
+	if err := checkDiskSpace(filepath.Dir(savePath), 0); err != nil {
+		return "", 0, err
+	}
+	spool, err := spoolQuery(ctx, q, sqlStr)
+	if err != nil {
+		return "", 0, err
+	}
+	defer spool.close()
+	if spool.count == 0 {
+		return "", 0, fmt.Errorf("导出失败：SQL 查询结果为空！")
+	}
+	if spool.count+1 > excelMaxRows {
+		return "", 0, errExcelRowLimit(spool.count)
+	}
+
+	columns := layoutColumns(spool.columns, opts.layout)
+	columns, frozen := pinColumns(columns, opts.pinned)
+	formulas, err := newFormulaPlan(columns, opts.formulas, opts.totals)
+	if err != nil {
+		return "", 0, err
+	}
+
+	f := excelize.NewFile()
+	defer f.Close()
+	if opts.outline != "" {
+		if !containsString(spool.columns, opts.outline) {
+			return "", 0, fmt.Errorf("分组列 %s 不在结果中", opts.outline)
+		}
+		if err := setOutlineSummaryAbove(f, exportSheetName); err != nil {
+			return "", 0, fmt.Errorf("设置分组大纲失败: %v", err)
+		}
+	}
+	sw, err := f.NewStreamWriter(exportSheetName)
+	if err != nil {
+		return "", 0, fmt.Errorf("创建工作表失败: %v", err)
+	}
+
+	// 列宽、隐藏列和冻结窗格必须在写入数据行之前设置
+	widths := make(map[string]float64, len(opts.layout))
+	for _, c := range opts.layout {
+		widths[c.column] = c.width
+	}
+	for i, col := range columns {
+		if w := widths[col]; w > 0 {
+			sw.SetColWidth(i+1, i+1, w/excelPixelsPerChar)
+		}
+	}
+	// 保护工作表时可编辑列取消锁定（列样式覆盖下方用于填写的空行，已写入的单元格在写入时逐个设置）
+	var editable map[int]bool
+	unlocked := newUnlockedStyles(f)
+	if opts.protect != nil {
+		editable = opts.protect.editable(columns)
+		for idx := range editable {
+			if err := sw.SetColStyle(idx+1, idx+1, unlocked.get(0)); err != nil {
+				return "", 0, fmt.Errorf("设置可编辑列失败: %v", err)
+			}
+		}
+	}
+	// 流式写入不输出列的 hidden 属性，改用列宽 0 隐藏
+	for i, col := range columns {
+		if containsString(opts.hidden, col) {
+			sw.SetColWidth(i+1, i+1, 0)
+		}
+	}
+	if frozen > 0 {
+		if err := sw.SetPanes(frozenPanes(frozen)); err != nil {
+			return "", 0, fmt.Errorf("冻结关键列失败: %v", err)
+		}
+	}
+
+	op := operationFrom(ctx)
+	headers := a.exportHeaders(columns)
+	headerRow := make([]interface{}, len(headers))
+	for i, h := range headers {
+		headerRow[i] = h
+	}
+	if err := sw.SetRow("A1", headerRow); err != nil {
+		return "", 0, fmt.Errorf("写入表头失败: %v", err)
+	}
+
+	_, locale := currentDateConfig()
+	dateStyle, _ := f.NewStyle(&excelize.Style{CustomNumFmt: stringPtr(localeExcelDateFormat(locale, false))})
+	dateTimeStyle, _ := f.NewStyle(&excelize.Style{CustomNumFmt: stringPtr(localeExcelDateFormat(locale, true))})
+	ruleStyles := highlightStyles(f, opts.rules)
+	formatter := newColumnFormatter(f, opts.formats)
+	outIndex := make(map[string]int, len(columns))
+	for i, col := range columns {
+		outIndex[col] = i
+	}
+
+	samples := make(map[string][]string)
+	count := 0
+	// line 为已写入的工作表行数（含表头和组标题行）
+	line := 1
+	var groupStyle int
+	var detailOpts []excelize.RowOpts
+	var lastGroup string
+	if opts.outline != "" {
+		groupStyle = outlineHeaderStyle(f)
+		detailOpts = []excelize.RowOpts{{OutlineLevel: 1}}
+	}
+	err = spool.each(a.nullValue(), func(row map[string]interface{}) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		cells := make([]interface{}, len(columns))
+		styles := make([]int, len(columns))
+		for i, col := range columns {
+			v := row[col]
+			if c, ok := formatter.lookup(col); ok && !isNullDisplay(v) {
+				v = c.convert(v)
+			}
+			if t, hasTime, ok := isoDateValue(v); ok {
+				v = t
+				styles[i] = dateStyle
+				if hasTime {
+					styles[i] = dateTimeStyle
+				}
+			}
+			cells[i] = v
+			if !isNullDisplay(row[col]) && len(samples[col]) < sensitiveSampleSize {
+				if text := strings.TrimSpace(fmt.Sprint(row[col])); text != "" {
+					samples[col] = append(samples[col], text)
+				}
+			}
+		}
+		for i, r := range opts.rules {
+			idx, ok := outIndex[r.column]
+			if !ok || !r.matches(row[r.column]) {
+				continue
+			}
+			if r.wholeRow {
+				for j := range styles {
+					styles[j] = ruleStyles[i]
+				}
+			} else {
+				styles[idx] = ruleStyles[i]
+			}
+		}
+		for i, col := range columns {
+			styles[i] = formatter.style(col, styles[i])
+		}
+		for i := range editable {
+			styles[i] = unlocked.get(styles[i])
+		}
+		for i := range cells {
+			if styles[i] != 0 {
+				cells[i] = excelize.Cell{StyleID: styles[i], Value: cells[i]}
+			}
+		}
+
+		if opts.outline != "" && (count == 0 || outlineKey(row[opts.outline]) != lastGroup) {
+			lastGroup = outlineKey(row[opts.outline])
+			header := make([]interface{}, len(columns))
+			for i, col := range columns {
+				header[i] = excelize.Cell{StyleID: groupStyle}
+				if col == opts.outline {
+					header[i] = excelize.Cell{StyleID: groupStyle, Value: groupLabel(row[col])}
+				}
+			}
+			line++
+			cell, _ := excelize.CoordinatesToCellName(1, line)
+			if err := sw.SetRow(cell, header); err != nil {
+				return fmt.Errorf("写入组标题失败: %v", err)
+			}
+		}
+
+		count++
+		line++
+		if formulas != nil {
+			for i := range formulas.formulas {
+				cells[i] = excelize.Cell{StyleID: styles[i], Formula: formulas.rowFormula(i, line)}
+			}
+		}
+		cell, _ := excelize.CoordinatesToCellName(1, line)
+		if err := sw.SetRow(cell, cells, detailOpts...); err != nil {
+			return fmt.Errorf("写入第 %d 行失败: %v", count, err)
+		}
+		op.progress("write", count, spool.count, fmt.Sprintf("已写入 %d/%d 行", count, spool.count))
+		return nil
+	})
+	if err != nil {
+		return "", 0, err
+	}
+	if formulas != nil {
+		if len(formulas.totals) > 0 {
+			line++
+			cell, _ := excelize.CoordinatesToCellName(1, line)
+			if err := sw.SetRow(cell, formulas.totalCells(2, line-1, totalStyle(f))); err != nil {
+				return "", 0, fmt.Errorf("写入合计行失败: %v", err)
+			}
+		}
+		if err := enableRecalc(f); err != nil {
+			return "", 0, err
+		}
+	}
+	// 下拉列表和工作表保护保存在工作表结构中，须在 Flush 之前设置
+	if err := applyValidations(f, exportSheetName, columns, opts.validations); err != nil {
+		return "", 0, err
+	}
+	if opts.protect != nil {
+		if err := opts.protect.protectSheet(f, exportSheetName); err != nil {
+			return "", 0, err
+		}
+	}
+	if err := sw.Flush(); err != nil {
+		return "", 0, fmt.Errorf("写入工作表失败: %v", err)
+	}
+	if opts.roundTrip != nil {
+		if err := a.writeRoundTripSheet(f, opts.roundTrip); err != nil {
+			return "", 0, fmt.Errorf("生成往返信息失败: %v", err)
+		}
+	}
+
+	if a.setting("export_provenance") == "true" {
+		if err := a.addProvenanceSheet(f, sqlStr, count); err != nil {
+			return "", 0, fmt.Errorf("生成来源信息失败: %v", err)
+		}
+	}
+	op.progress("save", count, count, "正在保存文件")
+	if err := f.SaveAs(savePath); err != nil {
+		return "", 0, fmt.Errorf("导出 Excel 失败: %v", err)
+	}
+
+	message := fmt.Sprintf("Excel 导出成功: %s（共 %d 条数据，已使用流式写入，网址未写为超链接）", savePath, count)
+	if warning := sensitiveWarning(detectSensitive(columns, samples)); warning != "" {
+		message += "\n" + warning
+	}
+	return message, count, nil
+}
+
+// ExportExcelLarge 导出大结果：查询结果先写入临时文件再流式生成 Excel，内存中不保留全量结果，
+// 也不会在生成 Excel 的整个过程中占用数据库读锁；返回值同 ExportExcelBySQL
+// wails:export ExportExcelLarge
+func (a *App) ExportExcelLarge(sqlStr string) map[string]interface{} {
+	if a.writeDB() == nil {
+		return errorResult(codeDBNotReady, "错误：数据库连接未初始化，请重启应用！")
+	}
+	sqlStr = strings.TrimSpace(sqlStr)
+	if sqlStr == "" {
+		return errorResult(codeInvalidInput, "错误：SQL 语句不能为空！")
+	}
+	return a.startExportJob(sqlStr, "查询结果", exportOptions{spill: true})
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"unicode/utf8"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// chooseFile 未指定路径时弹出文件选择框，返回空字符串表示用户取消
+func (a *App) chooseFile(filePath string, title string, pattern string, displayName string) (string, error) {
+	if filePath != "" {
+		return filePath, nil
+	}
+	return runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title:   title,
+		Filters: []runtime.FileFilter{{Pattern: pattern, DisplayName: displayName}},
+	})
+}
+
+// decodeText 将文本转为 UTF-8：合法 UTF-8 原样返回，否则按 GB18030（兼容 GBK）解码
+func decodeText(b []byte) string {
+	if utf8.Valid(b) {
+		return string(b)
+	}
+	decoded, err := simplifiedchinese.GB18030.NewDecoder().Bytes(b)
+	if err != nil {
+		return string(b)
+	}
+	return string(decoded)
+}
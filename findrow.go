@@ -0,0 +1,133 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// cellMatches 单元格的值是否等于要查找的值：按文本比较，两边都是数字时按数值比较（如 1001 与 1001.0）
+func cellMatches(cell string, value string, number float64, numeric bool) bool {
+	cell = strings.TrimSpace(cell)
+	if cell == value {
+		return true
+	}
+	if !numeric {
+		return false
+	}
+	n, ok := parseNumberText(cell)
+	return ok && n == number
+}
+
+// FindRowInResult 在查询结果中按查询本身的排序查找 column 列等于 value 的第一行，返回它所在的页和页内位置，
+// 便于在很大的分页结果中直接跳到某个订单号；分页大小取当前分页设置
+// 返回 {found, rowNumber（从 1 开始）, page, indexInPage（从 0 开始）, pageSize, message}
+// wails:export FindRowInResult
+func (a *App) FindRowInResult(sqlStr string, column string, value string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	stmt := parseStatement(strings.TrimSpace(sqlStr))
+	if err := stmt.wrappable(); err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	stmt.text = a.liveSQL(stmt.text)
+	value = strings.TrimSpace(value)
+	if value == "" {
+		result["error"] = "请输入要查找的值"
+		return result
+	}
+	pageSize := a.session.currentPageSize()
+	if pageSize <= 0 {
+		pageSize = a.settingInt("page_size")
+	}
+	number, numeric := parseNumberText(value)
+
+	index := -1
+	if entry, ok := a.lookupCachedResult(stmt.text); ok {
+		if !containsString(entry.columns, column) {
+			result["error"] = fmt.Sprintf("查询结果中没有列 %s", column)
+			return result
+		}
+		for i, row := range entry.data {
+			if v := row[column]; !isNullDisplay(v) && cellMatches(fmt.Sprint(v), value, number, numeric) {
+				index = i
+				break
+			}
+		}
+	} else {
+		var err error
+		if index, err = a.scanForRow(stmt.text, column, value, number, numeric); err != nil {
+			result["error"] = err.Error()
+			return result
+		}
+	}
+
+	result["pageSize"] = pageSize
+	if index < 0 {
+		result["found"] = false
+		result["message"] = fmt.Sprintf("查询结果中没有 %s 等于 %s 的行", column, value)
+		return result
+	}
+	page := index/pageSize + 1
+	result["found"] = true
+	result["rowNumber"] = index + 1
+	result["page"] = page
+	result["indexInPage"] = index % pageSize
+	result["message"] = fmt.Sprintf("%s = %s 在第 %d 行（第 %d 页）", column, value, index+1, page)
+	return result
+}
+
+// lookupCachedResult 开启 query_cache 时取出与当前数据版本一致的缓存结果
+func (a *App) lookupCachedResult(sqlStr string) (*cachedResult, bool) {
+	if a.cache == nil || a.setting("query_cache") != "true" {
+		return nil, false
+	}
+	return a.cache.get(sqlStr)
+}
+
+// scanForRow 按原查询的顺序逐行读取，找到第一处匹配即停止，不在内存中保留结果
+func (a *App) scanForRow(sqlStr string, column string, value string, number float64, numeric bool) (int, error) {
+	query := a.beginQuery(sqlStr)
+	defer query.end()
+	rows, err := a.readDB().QueryContext(query.context(), sqlStr)
+	if err != nil {
+		return -1, fmt.Errorf("SQL 执行失败: %v", err)
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return -1, fmt.Errorf("获取列名失败: %v", err)
+	}
+	target := -1
+	for i, c := range columns {
+		if c == column {
+			target = i
+			break
+		}
+	}
+	if target < 0 {
+		return -1, fmt.Errorf("查询结果中没有列 %s", column)
+	}
+
+	values := make([]sql.RawBytes, len(columns))
+	ptrs := make([]interface{}, len(columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	for index := 0; rows.Next(); index++ {
+		if err := rows.Scan(ptrs...); err != nil {
+			return -1, fmt.Errorf("读取数据失败: %v", err)
+		}
+		if values[target] != nil && cellMatches(string(values[target]), value, number, numeric) {
+			return index, nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return -1, fmt.Errorf("遍历数据失败: %v", err)
+	}
+	return -1, nil
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// fixScriptLimit 一次修复脚本最多包含的语句数
+const fixScriptLimit = 200
+
+// fixStatement 修复脚本中的一条语句
+type fixStatement struct {
+	stmt sqlStatement
+	// prefix（WITH 子句）、target（目标表及别名）、where 为估算影响行数时改写成 SELECT 使用的部分；estimateErr 非空时无法改写
+	prefix      string
+	target      string
+	where       string
+	estimateErr string
+}
+
+// parseFixStatement 解析修复脚本中的一条语句：只允许单条 UPDATE / DELETE，并拆出目标表和 WHERE 条件
+func parseFixStatement(sqlStr string) (fixStatement, error) {
+	fs := fixStatement{stmt: parseStatement(strings.TrimSpace(sqlStr))}
+	if fs.stmt.text == "" {
+		return fs, fmt.Errorf("语句为空")
+	}
+	if fs.stmt.multiple {
+		return fs, fmt.Errorf("每项只能包含一条语句")
+	}
+	if fs.stmt.kind != stmtUpdate && fs.stmt.kind != stmtDelete {
+		return fs, fmt.Errorf("修复脚本只能包含 UPDATE / DELETE 语句")
+	}
+	fs.prefix, fs.target, fs.where, fs.estimateErr = splitWriteTarget(fs.stmt)
+	return fs, nil
+}
+
+// splitWriteTarget 按顶层关键字的位置拆出 UPDATE / DELETE 语句的 WITH 前缀、目标表（含别名）和 WHERE 条件，
+// 用于改写为 SELECT COUNT(*) 估算影响行数；UPDATE ... FROM 和带 LIMIT 的语句无法等价改写
+func splitWriteTarget(stmt sqlStatement) (prefix string, target string, where string, reason string) {
+	text := stmt.text
+	tokens, _, _ := scanSQL(text)
+	var top []sqlToken
+	for _, t := range tokens {
+		if t.depth == 0 {
+			top = append(top, t)
+		}
+	}
+	verb := -1
+	for i, t := range top {
+		if t.word == "UPDATE" || t.word == "DELETE" {
+			verb = i
+			break
+		}
+	}
+	if verb < 0 {
+		return "", "", "", "无法识别语句结构"
+	}
+	prefix = text[:top[verb].pos]
+
+	// 目标表从 UPDATE [OR 冲突处理] / DELETE FROM 之后开始，到 SET / WHERE 等顶层关键字为止
+	start := verb + 1
+	if top[verb].word == "UPDATE" && start+1 < len(top) && top[start].word == "OR" {
+		start += 2
+	} else if top[verb].word == "DELETE" && start < len(top) && top[start].word == "FROM" {
+		start++
+	}
+	if start > len(top) {
+		return "", "", "", "无法识别目标表"
+	}
+	targetStart := top[start-1].pos + len(top[start-1].word)
+	targetEnd, whereStart, whereEnd := len(text), -1, len(text)
+	afterSet := false
+	for _, t := range top[start:] {
+		switch t.word {
+		case "SET":
+			if !afterSet && top[verb].word == "UPDATE" {
+				targetEnd = min(targetEnd, t.pos)
+				afterSet = true
+			}
+		case "FROM":
+			if afterSet && whereStart < 0 {
+				return "", "", "", "UPDATE ... FROM 语句无法估算影响行数"
+			}
+		case "WHERE":
+			if whereStart < 0 {
+				targetEnd = min(targetEnd, t.pos)
+				whereStart = t.pos + len("WHERE")
+			}
+		case "RETURNING", "ORDER":
+			targetEnd = min(targetEnd, t.pos)
+			if whereStart >= 0 {
+				whereEnd = min(whereEnd, t.pos)
+			}
+		case "LIMIT":
+			return "", "", "", "带 LIMIT 的语句无法估算影响行数"
+		}
+	}
+	target = strings.TrimSpace(text[targetStart:targetEnd])
+	if target == "" || top[verb].word == "UPDATE" && !afterSet {
+		return "", "", "", "无法识别目标表"
+	}
+	if whereStart >= 0 {
+		where = strings.TrimSpace(text[whereStart:whereEnd])
+	}
+	return prefix, target, where, ""
+}
+
+// selectSQL 按目标表和 WHERE 条件生成查询，columns 为选择的列（如 COUNT(*)、*）
+func (fs fixStatement) selectSQL(columns string) string {
+	if fs.estimateErr != "" {
+		return ""
+	}
+	query := fs.prefix + "SELECT " + columns + " FROM " + fs.target
+	if fs.where != "" {
+		query += " WHERE " + fs.where
+	}
+	return query
+}
+
+// RunFixScript 在一个事务中依次执行多条 UPDATE / DELETE 修复语句，返回每条语句的影响行数，任何一条失败则全部回滚；
+// dryRun 为 true 时不修改数据，把每条语句改写为 SELECT COUNT(*) 估算影响行数（按当前数据估算，不计前面语句的影响），
+// 并返回可查看受影响行的 previewSQL
+// 返回 {dryRun, statements: [{index, sql, kind, rowsAffected | estimatedRows, previewSQL, error}], totalAffected, message}
+// wails:export RunFixScript
+func (a *App) RunFixScript(statements []string, dryRun bool) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	if !dryRun && a.readOnly() {
+		result["error"] = readOnlyMessage
+		return result
+	}
+	var fixes []fixStatement
+	for i, s := range statements {
+		if strings.TrimSpace(s) == "" {
+			continue
+		}
+		fs, err := parseFixStatement(s)
+		if err != nil {
+			result["error"] = fmt.Sprintf("第 %d 条语句无效: %v", i+1, err)
+			return result
+		}
+		fixes = append(fixes, fs)
+	}
+	if len(fixes) == 0 {
+		result["error"] = "请输入要执行的修复语句"
+		return result
+	}
+	if len(fixes) > fixScriptLimit {
+		result["error"] = fmt.Sprintf("一次最多执行 %d 条语句", fixScriptLimit)
+		return result
+	}
+
+	result["dryRun"] = dryRun
+	if dryRun {
+		return a.estimateFixScript(fixes, result)
+	}
+
+	var reports []map[string]interface{}
+	var total int64
+	failed := -1
+	err := withBusyRetry(func() error {
+		reports, total, failed = nil, 0, -1
+		tx, err := a.writeDB().Begin()
+		if err != nil {
+			return fmt.Errorf("开启事务失败: %v", err)
+		}
+		for i, fs := range fixes {
+			res, err := tx.Exec(fs.stmt.text)
+			if err != nil {
+				tx.Rollback()
+				failed = i
+				return err
+			}
+			affected, _ := res.RowsAffected()
+			total += affected
+			reports = append(reports, map[string]interface{}{
+				"index": i + 1, "sql": fs.stmt.text, "kind": fs.stmt.kind, "rowsAffected": affected,
+			})
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("提交事务失败: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		if failed >= 0 {
+			result["failedIndex"] = failed + 1
+			result["error"] = fmt.Sprintf("第 %d 条语句执行失败，全部修改已回滚: %v", failed+1, err)
+		} else {
+			result["error"] = err.Error()
+		}
+		return result
+	}
+	result["statements"] = reports
+	result["totalAffected"] = total
+	result["message"] = fmt.Sprintf("已在一个事务中执行 %d 条语句，共影响 %d 行", len(fixes), total)
+	return result
+}
+
+// estimateFixScript 试运行修复脚本：校验每条语句能否编译，并用改写的 SELECT COUNT(*) 估算影响行数
+func (a *App) estimateFixScript(fixes []fixStatement, result map[string]interface{}) map[string]interface{} {
+	db := a.readDB()
+	reports := make([]map[string]interface{}, len(fixes))
+	var total int64
+	estimated := 0
+	for i, fs := range fixes {
+		report := map[string]interface{}{"index": i + 1, "sql": fs.stmt.text, "kind": fs.stmt.kind}
+		reports[i] = report
+		// 写连接上编译语句（不执行）以检查表名、列名和语法
+		if err := prepareOnly(a.writeDB(), fs.stmt.text); err != nil {
+			report["error"] = fmt.Sprintf("语句无法执行: %v", err)
+			continue
+		}
+		if fs.estimateErr != "" {
+			report["error"] = fs.estimateErr
+			continue
+		}
+		var n int64
+		if err := db.QueryRow(fs.selectSQL("COUNT(*)")).Scan(&n); err != nil {
+			report["error"] = fmt.Sprintf("估算影响行数失败: %v", err)
+			continue
+		}
+		report["estimatedRows"] = n
+		report["previewSQL"] = fs.selectSQL("*")
+		total += n
+		estimated++
+	}
+	result["statements"] = reports
+	result["totalAffected"] = total
+	result["message"] = fmt.Sprintf("试运行：%d 条语句中 %d 条可估算，预计共影响 %d 行（按当前数据分别估算），未修改数据", len(fixes), estimated, total)
+	return result
+}
+
+// prepareOnly 编译语句但不执行，用于检查语句是否有效
+func prepareOnly(db *sql.DB, sqlStr string) error {
+	stmt, err := db.Prepare(sqlStr)
+	if err != nil {
+		return err
+	}
+	return stmt.Close()
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// formulaRefPattern 列公式中引用同一行其他列的写法：[列名]
+var formulaRefPattern = regexp.MustCompile(`\[([^\[\]]+)\]`)
+
+// totalFunctions 合计行可用的 Excel 汇总函数
+var totalFunctions = map[string]string{
+	"sum":     "SUM",
+	"average": "AVERAGE",
+	"count":   "COUNT",
+	"min":     "MIN",
+	"max":     "MAX",
+}
+
+// totalRowLabel 合计行第一列（该列没有合计时）的标签
+const totalRowLabel = "合计"
+
+// columnFormula 导出时用 Excel 公式代替静态值的列：expr 中以 [列名] 引用同一行的其他列，如 [单价]*[数量]
+type columnFormula struct {
+	column string
+	expr   string
+}
+
+// columnTotal 合计行中一列的汇总函数（sum、average、count、min、max）
+type columnTotal struct {
+	column   string
+	function string
+}
+
+// parseColumnFormulas 解析前端传来的 [{column, formula}]，formula 开头的 = 可省略
+func parseColumnFormulas(specs []map[string]interface{}) ([]columnFormula, error) {
+	formulas := make([]columnFormula, 0, len(specs))
+	seen := make(map[string]bool)
+	for i, spec := range specs {
+		var c columnFormula
+		c.column, _ = spec["column"].(string)
+		c.expr, _ = spec["formula"].(string)
+		c.expr = strings.TrimPrefix(strings.TrimSpace(c.expr), "=")
+		if c.column == "" {
+			return nil, fmt.Errorf("第 %d 项公式缺少列名", i+1)
+		}
+		if c.expr == "" {
+			return nil, fmt.Errorf("列 %s 的公式为空", c.column)
+		}
+		if seen[c.column] {
+			return nil, fmt.Errorf("列 %s 重复设置公式", c.column)
+		}
+		seen[c.column] = true
+		formulas = append(formulas, c)
+	}
+	return formulas, nil
+}
+
+// parseColumnTotals 解析前端传来的 [{column, function}]
+func parseColumnTotals(specs []map[string]interface{}) ([]columnTotal, error) {
+	totals := make([]columnTotal, 0, len(specs))
+	seen := make(map[string]bool)
+	for i, spec := range specs {
+		var c columnTotal
+		c.column, _ = spec["column"].(string)
+		c.function, _ = spec["function"].(string)
+		c.function = strings.ToLower(strings.TrimSpace(c.function))
+		if c.column == "" {
+			return nil, fmt.Errorf("第 %d 项合计缺少列名", i+1)
+		}
+		if c.function == "" {
+			c.function = "sum"
+		}
+		if _, ok := totalFunctions[c.function]; !ok {
+			return nil, fmt.Errorf("列 %s 的汇总函数 %q 无效（可选 sum、average、count、min、max）", c.column, c.function)
+		}
+		if seen[c.column] {
+			return nil, fmt.Errorf("列 %s 重复设置合计", c.column)
+		}
+		seen[c.column] = true
+		totals = append(totals, c)
+	}
+	return totals, nil
+}
+
+func (c columnFormula) toMap() map[string]interface{} {
+	return map[string]interface{}{"column": c.column, "formula": c.expr}
+}
+
+func (c columnTotal) toMap() map[string]interface{} {
+	return map[string]interface{}{"column": c.column, "function": c.function}
+}
+
+// formulaPlan 按导出列的位置展开公式和合计
+type formulaPlan struct {
+	columns  []string
+	letters  map[string]string
+	formulas map[int]columnFormula
+	totals   map[int]columnTotal
+}
+
+// newFormulaPlan 检查公式列、合计列和公式中引用的列都在导出结果中；没有设置公式和合计时返回 nil
+func newFormulaPlan(columns []string, formulas []columnFormula, totals []columnTotal) (*formulaPlan, error) {
+	if len(formulas) == 0 && len(totals) == 0 {
+		return nil, nil
+	}
+	p := &formulaPlan{
+		columns:  columns,
+		letters:  make(map[string]string, len(columns)),
+		formulas: make(map[int]columnFormula),
+		totals:   make(map[int]columnTotal),
+	}
+	index := make(map[string]int, len(columns))
+	for i, col := range columns {
+		index[col] = i
+		p.letters[col], _ = excelize.ColumnNumberToName(i + 1)
+	}
+	for _, c := range formulas {
+		i, ok := index[c.column]
+		if !ok {
+			return nil, fmt.Errorf("公式列 %s 不在导出结果中", c.column)
+		}
+		for _, m := range formulaRefPattern.FindAllStringSubmatch(c.expr, -1) {
+			if _, ok := index[m[1]]; !ok {
+				return nil, fmt.Errorf("列 %s 的公式引用了不在导出结果中的列 %s", c.column, m[1])
+			}
+		}
+		p.formulas[i] = c
+	}
+	for _, c := range totals {
+		i, ok := index[c.column]
+		if !ok {
+			return nil, fmt.Errorf("合计列 %s 不在导出结果中", c.column)
+		}
+		p.totals[i] = c
+	}
+	return p, nil
+}
+
+// rowFormula 第 row 行（工作表行号）中第 i 列的公式，该列没有公式时返回空字符串
+func (p *formulaPlan) rowFormula(i int, row int) string {
+	c, ok := p.formulas[i]
+	if !ok {
+		return ""
+	}
+	return formulaRefPattern.ReplaceAllStringFunc(c.expr, func(ref string) string {
+		return fmt.Sprintf("%s%d", p.letters[ref[1:len(ref)-1]], row)
+	})
+}
+
+// totalCells 合计行的内容：有合计的列为汇总 firstRow..lastRow 的公式，第一列没有合计时写入标签
+func (p *formulaPlan) totalCells(firstRow int, lastRow int, style int) []interface{} {
+	cells := make([]interface{}, len(p.columns))
+	for i, col := range p.columns {
+		cell := excelize.Cell{StyleID: style}
+		if c, ok := p.totals[i]; ok {
+			letter := p.letters[col]
+			cell.Formula = fmt.Sprintf("%s(%s%d:%s%d)", totalFunctions[c.function], letter, firstRow, letter, lastRow)
+		} else if i == 0 {
+			cell.Value = totalRowLabel
+		}
+		cells[i] = cell
+	}
+	return cells
+}
+
+// totalStyle 合计行使用的加粗样式
+func totalStyle(f *excelize.File) int {
+	id, _ := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}, Border: []excelize.Border{{Type: "top", Color: "000000", Style: 1}}})
+	return id
+}
+
+// enableRecalc 打开文件时重新计算全部公式：公式单元格写入时没有缓存的计算结果
+func enableRecalc(f *excelize.File) error {
+	on := true
+	if err := f.SetCalcProps(&excelize.CalcPropsOptions{FullCalcOnLoad: &on}); err != nil {
+		return fmt.Errorf("设置重新计算失败: %v", err)
+	}
+	return nil
+}
+
+// applyFormulas 把已导出工作表中的公式列改写为公式，并在数据之后追加合计行；
+// rows 为数据行数（不含表头，含分组标题行），groupHeaders 标记分组标题行（不写公式）
+func (p *formulaPlan) applyFormulas(f *excelize.File, sheet string, rows int, groupHeaders []bool) error {
+	if p == nil {
+		return nil
+	}
+	for i := range p.formulas {
+		for r := 0; r < rows; r++ {
+			if groupHeaders != nil && groupHeaders[r] {
+				continue
+			}
+			cell, _ := excelize.CoordinatesToCellName(i+1, r+2)
+			if err := f.SetCellFormula(sheet, cell, p.rowFormula(i, r+2)); err != nil {
+				return fmt.Errorf("写入列 %s 的公式失败: %v", p.columns[i], err)
+			}
+		}
+	}
+	if len(p.totals) > 0 {
+		style := totalStyle(f)
+		for i, v := range p.totalCells(2, rows+1, style) {
+			c := v.(excelize.Cell)
+			cell, _ := excelize.CoordinatesToCellName(i+1, rows+2)
+			if c.Formula != "" {
+				if err := f.SetCellFormula(sheet, cell, c.Formula); err != nil {
+					return fmt.Errorf("写入合计行失败: %v", err)
+				}
+			} else if c.Value != nil {
+				f.SetCellValue(sheet, cell, c.Value)
+			}
+			f.SetCellStyle(sheet, cell, cell, style)
+		}
+	}
+	return enableRecalc(f)
+}
+
+// ExportExcelWithFormulas 与 ExportExcelBySQL 相同，但按 formulas [{column, formula}] 把计算列写为 Excel 公式
+// （以 [列名] 引用同一行的其他列，如 [单价]*[数量]），并按 totals [{column, function}] 在末尾追加合计行
+// （sum、average、count、min、max）；收件人修改输入值后公式自动重新计算；返回值同 ExportExcelBySQL
+// wails:export ExportExcelWithFormulas
+func (a *App) ExportExcelWithFormulas(sqlStr string, formulas []map[string]interface{}, totals []map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	sqlStr = strings.TrimSpace(sqlStr)
+	if sqlStr == "" {
+		result["error"] = "错误：SQL 语句不能为空！"
+		return result
+	}
+	opts := exportOptions{}
+	var err error
+	if opts.formulas, err = parseColumnFormulas(formulas); err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	if opts.totals, err = parseColumnTotals(totals); err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	if len(opts.formulas) == 0 && len(opts.totals) == 0 {
+		result["error"] = "请设置公式列或合计列"
+		return result
+	}
+	return a.startExportJob(sqlStr, "查询结果", opts)
+}
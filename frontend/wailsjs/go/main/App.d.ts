@@ -1,10 +1,280 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AddRowHash(arg1:string):Promise<string>;
+
+export function ApplyStandardization(arg1:string,arg2:string,arg3:Record<string, string>):Promise<string>;
+
+export function BenfordAudit(arg1:string,arg2:string):Promise<Record<string, any>>;
+
+export function BuildGridSQL(arg1:string,arg2:Array<string>,arg3:Array<Record<string, any>>,arg4:Array<Record<string, any>>):Promise<Record<string, any>>;
+
+export function BuildJoin(arg1:string,arg2:string,arg3:Record<string, string>,arg4:string,arg5:Array<string>):Promise<Record<string, any>>;
+
+export function BuildTypedLayer(arg1:string):Promise<Record<string, any>>;
+
+export function CancelOperation(arg1:string):Promise<string>;
+
+export function CloseViewerPackage():Promise<string>;
+
+export function ClusterSimilarValues(arg1:string,arg2:string):Promise<Record<string, any>>;
+
+export function Commands(arg1:string):Promise<Array<Record<string, any>>>;
+
+export function ConvertColumnType(arg1:string,arg2:string,arg3:string):Promise<string>;
+
+export function CreateBackup(arg1:string):Promise<string>;
+
+export function CreateCleanView(arg1:string):Promise<Record<string, any>>;
+
+export function CreateHeaderView(arg1:string):Promise<Record<string, any>>;
+
+export function Crosstab(arg1:string,arg2:Array<string>,arg3:string,arg4:Array<Record<string, any>>):Promise<Record<string, any>>;
+
+export function CumulativeSum(arg1:string,arg2:string,arg3:string,arg4:string):Promise<Record<string, any>>;
+
+export function DeleteSavedQuery(arg1:string):Promise<string>;
+
+export function DeleteSnapshot(arg1:string):Promise<string>;
+
+export function DetectBooleanColumns(arg1:string):Promise<Record<string, any>>;
+
+export function DetectSensitiveColumns(arg1:string):Promise<Record<string, any>>;
+
+export function DiffSchemas(arg1:number,arg2:number):Promise<Record<string, any>>;
+
+export function DiscardRejectedRows(arg1:string,arg2:Array<number>):Promise<string>;
+
+export function DropStaleTables(arg1:Array<string>,arg2:number):Promise<string>;
+
+export function DryRunImport(arg1:string,arg2:Array<any>):Promise<Record<string, any>>;
+
+export function EnrichTable(arg1:string,arg2:string,arg3:Record<string, string>,arg4:Array<string>):Promise<string>;
+
+export function EstimateExport(arg1:string):Promise<Record<string, any>>;
+
+export function ExecuteSQLColumnar(arg1:string,arg2:number,arg3:number):Promise<Record<string, any>>;
+
 export function ExecuteSQLWithPage(arg1:string,arg2:number,arg3:number):Promise<Record<string, any>>;
 
-export function ExportExcelBySQL(arg1:string):Promise<string>;
+export function ExplodeColumn(arg1:string,arg2:string,arg3:string):Promise<Record<string, any>>;
+
+export function ExportAppendSheet(arg1:string,arg2:string,arg3:string):Promise<Record<string, any>>;
+
+export function ExportCSVBySQL(arg1:string):Promise<Record<string, any>>;
+
+export function ExportDatabaseCopy(arg1:string,arg2:Array<string>):Promise<string>;
+
+export function ExportDelta(arg1:string,arg2:string):Promise<Record<string, any>>;
+
+export function ExportExcelBySQL(arg1:string):Promise<Record<string, any>>;
+
+export function ExportExcelFormatted(arg1:string,arg2:Array<Record<string, any>>):Promise<Record<string, any>>;
+
+export function ExportExcelLarge(arg1:string):Promise<Record<string, any>>;
+
+export function ExportExcelOutlined(arg1:string,arg2:string):Promise<Record<string, any>>;
+
+export function ExportExcelPinned(arg1:string,arg2:Array<string>):Promise<Record<string, any>>;
+
+export function ExportExcelWithFormulas(arg1:string,arg2:Array<Record<string, any>>,arg3:Array<Record<string, any>>):Promise<Record<string, any>>;
+
+export function ExportFillTemplate(arg1:string,arg2:Record<string, any>):Promise<Record<string, any>>;
+
+export function ExportForRoundTrip(arg1:string,arg2:Record<string, any>):Promise<Record<string, any>>;
+
+export function ExportGroupedExcel(arg1:string,arg2:Array<string>,arg3:Array<Record<string, any>>):Promise<string>;
+
+export function ExportSample(arg1:string,arg2:number):Promise<Record<string, any>>;
+
+export function ExportSavedQuery(arg1:string):Promise<Record<string, any>>;
+
+export function ExportShareBundle(arg1:string):Promise<string>;
+
+export function ExportViewerPackage(arg1:string,arg2:string,arg3:Array<string>,arg4:Array<string>):Promise<string>;
+
+export function ExportWorkspaceConfig():Promise<string>;
+
+export function FindRowInResult(arg1:string,arg2:string,arg3:string):Promise<Record<string, any>>;
+
+export function GenerateCalendarTable(arg1:string,arg2:string):Promise<string>;
+
+export function GetCellComments(arg1:string):Promise<Record<string, any>>;
+
+export function GetCellValue(arg1:string,arg2:number,arg3:string):Promise<Record<string, any>>;
+
+export function GetColumnValues(arg1:string,arg2:string,arg3:number):Promise<Record<string, any>>;
 
 export function GetCurrentSQL():Promise<string>;
 
-export function OpenExcel():Promise<string>;
+export function GetDataVersions():Promise<Record<string, any>>;
+
+export function GetExportHistory(arg1:number):Promise<Array<Record<string, any>>>;
+
+export function GetExportJob(arg1:string):Promise<Record<string, any>>;
+
+export function GetGridFilterOperators():Promise<Array<Record<string, any>>>;
+
+export function GetHeaderMapping(arg1:string):Promise<Record<string, any>>;
+
+export function GetHeaderTranslations(arg1:string):Promise<Record<string, any>>;
+
+export function GetInstanceStatus():Promise<Record<string, any>>;
+
+export function GetMemoryUsage():Promise<Record<string, any>>;
+
+export function GetQueryHistory(arg1:number):Promise<Array<Record<string, any>>>;
+
+export function GetSchemaHistory(arg1:number):Promise<Array<Record<string, any>>>;
+
+export function GetSettings():Promise<Array<Record<string, any>>>;
+
+export function GetSlowestQueries(arg1:number):Promise<Array<Record<string, any>>>;
+
+export function GetStaleTables(arg1:number):Promise<Record<string, any>>;
+
+export function GetTableSchema(arg1:string):Promise<Record<string, any>>;
+
+export function GetTableTree():Promise<Record<string, any>>;
+
+export function GetTypeConversionErrors(arg1:string,arg2:number):Promise<Record<string, any>>;
+
+export function GetViewerPackage():Promise<Record<string, any>>;
+
+export function ImportFixedWidth(arg1:string,arg2:string,arg3:string,arg4:boolean):Promise<string>;
+
+export function ImportFromDatabase(arg1:string,arg2:string,arg3:string,arg4:string):Promise<string>;
+
+export function ImportHTMLTables(arg1:string):Promise<string>;
+
+export function ImportNamedRanges(arg1:string):Promise<string>;
+
+export function ImportPDFTable(arg1:string,arg2:string,arg3:string,arg4:string,arg5:boolean):Promise<string>;
+
+export function ImportSampled(arg1:string,arg2:Record<string, any>):Promise<string>;
+
+export function ImportSelectedColumns(arg1:string,arg2:Array<string>):Promise<string>;
+
+export function ImportSheetInto(arg1:string,arg2:string,arg3:string,arg4:Record<string, string>):Promise<string>;
+
+export function ImportUnion(arg1:Array<string>,arg2:string):Promise<string>;
+
+export function ImportWorkspaceConfig(arg1:string):Promise<string>;
+
+export function InstallReferenceTables(arg1:Array<string>):Promise<string>;
+
+export function JSONFlatten(arg1:string,arg2:string):Promise<Record<string, any>>;
+
+export function LinkCSVFile(arg1:string,arg2:string,arg3:Record<string, any>):Promise<Record<string, any>>;
+
+export function LintSQL(arg1:string):Promise<Record<string, any>>;
+
+export function ListActiveOperations():Promise<Array<Record<string, any>>>;
+
+export function ListBackups():Promise<Array<Record<string, any>>>;
+
+export function ListDatabaseDrivers():Promise<Array<string>>;
+
+export function ListExchangeRates():Promise<Record<string, any>>;
+
+export function ListExportJobs():Promise<Array<Record<string, any>>>;
+
+export function ListReferenceTables():Promise<Array<Record<string, any>>>;
+
+export function ListRejectedRows(arg1:string):Promise<Record<string, any>>;
+
+export function ListSavedQueries():Promise<Array<Record<string, any>>>;
+
+export function ListSnapshots():Promise<Array<Record<string, any>>>;
+
+export function ListTables():Promise<Record<string, any>>;
+
+export function ListTablesByTag(arg1:string):Promise<Record<string, any>>;
+
+export function ListTags():Promise<Record<string, any>>;
+
+export function ListUnfinishedImports():Promise<Array<Record<string, any>>>;
+
+export function MarkRowsDeleted(arg1:string,arg2:Array<number>):Promise<string>;
+
+export function MatchSchemas(arg1:string,arg2:string):Promise<Record<string, any>>;
+
+export function NormalizeBooleanColumns(arg1:string,arg2:Array<string>):Promise<string>;
+
+export function OpenExcel():Promise<Record<string, any>>;
+
+export function OpenViewerPackage(arg1:string):Promise<Record<string, any>>;
+
+export function PartitionTable(arg1:string,arg2:string,arg3:boolean):Promise<Record<string, any>>;
+
+export function Ping():Promise<Record<string, any>>;
+
+export function PreviewPDFTable(arg1:string,arg2:string,arg3:string):Promise<Record<string, any>>;
+
+export function PreviewTable(arg1:string,arg2:number):Promise<Record<string, any>>;
+
+export function ProfileTable(arg1:string,arg2:boolean):Promise<Record<string, any>>;
+
+export function ReRunExport(arg1:number,arg2:string):Promise<Record<string, any>>;
+
+export function ReapplyRejectedRows(arg1:string):Promise<Record<string, any>>;
+
+export function Reconnect():Promise<string>;
+
+export function RefreshTable(arg1:string,arg2:string):Promise<Record<string, any>>;
+
+export function ReimportFilled(arg1:string):Promise<Record<string, any>>;
+
+export function ResolveUnfinishedImport(arg1:number,arg2:string):Promise<string>;
+
+export function RestoreRows(arg1:string,arg2:Array<number>):Promise<string>;
+
+export function RestoreTableFromBackup(arg1:number,arg2:string):Promise<string>;
+
+export function RunBenchmark(arg1:number):Promise<Record<string, any>>;
+
+export function RunFixScript(arg1:Array<string>,arg2:boolean):Promise<Record<string, any>>;
+
+export function RunSavedQuery(arg1:string,arg2:number,arg3:number):Promise<Record<string, any>>;
+
+export function SaveQuery(arg1:string,arg2:string,arg3:Array<Record<string, any>>):Promise<string>;
+
+export function SaveSnapshot(arg1:string,arg2:string,arg3:Array<string>):Promise<string>;
+
+export function SendExportByEmail(arg1:string,arg2:Array<string>,arg3:string,arg4:boolean):Promise<string>;
+
+export function SetColumnDescription(arg1:string,arg2:string,arg3:string,arg4:string):Promise<string>;
+
+export function SetExchangeRates(arg1:Array<Record<string, any>>):Promise<string>;
+
+export function SetHeaderTranslations(arg1:string,arg2:string,arg3:Record<string, string>):Promise<string>;
+
+export function SetHoliday(arg1:string,arg2:string,arg3:boolean):Promise<string>;
+
+export function SetIncludeDeleted(arg1:boolean):Promise<string>;
+
+export function SetSavedQueryFormats(arg1:string,arg2:Array<Record<string, any>>):Promise<string>;
+
+export function SetSavedQueryLayout(arg1:string,arg2:Array<Record<string, any>>):Promise<string>;
+
+export function SetSetting(arg1:string,arg2:string):Promise<string>;
+
+export function SetTableDescription(arg1:string,arg2:string,arg3:string):Promise<string>;
+
+export function SetTableFolder(arg1:string,arg2:string):Promise<string>;
+
+export function SetTableTags(arg1:string,arg2:Array<string>):Promise<string>;
+
+export function SetWindowBackground(arg1:boolean):Promise<void>;
+
+export function SplitAddressColumn(arg1:string,arg2:string):Promise<string>;
+
+export function TestNotification():Promise<string>;
+
+export function TraceSourceCell(arg1:string,arg2:number,arg3:string):Promise<Record<string, any>>;
+
+export function UnlinkCSVFile(arg1:string):Promise<string>;
+
+export function UpdateRejectedRow(arg1:string,arg2:number,arg3:Record<string, string>):Promise<string>;
+
+export function UpdateStatistics(arg1:string):Promise<string>;
@@ -2,18 +2,558 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AddRowHash(arg1) {
+  return window['go']['main']['App']['AddRowHash'](arg1);
+}
+
+export function ApplyStandardization(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ApplyStandardization'](arg1, arg2, arg3);
+}
+
+export function BenfordAudit(arg1, arg2) {
+  return window['go']['main']['App']['BenfordAudit'](arg1, arg2);
+}
+
+export function BuildGridSQL(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['BuildGridSQL'](arg1, arg2, arg3, arg4);
+}
+
+export function BuildJoin(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['BuildJoin'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function BuildTypedLayer(arg1) {
+  return window['go']['main']['App']['BuildTypedLayer'](arg1);
+}
+
+export function CancelOperation(arg1) {
+  return window['go']['main']['App']['CancelOperation'](arg1);
+}
+
+export function CloseViewerPackage() {
+  return window['go']['main']['App']['CloseViewerPackage']();
+}
+
+export function ClusterSimilarValues(arg1, arg2) {
+  return window['go']['main']['App']['ClusterSimilarValues'](arg1, arg2);
+}
+
+export function Commands(arg1) {
+  return window['go']['main']['App']['Commands'](arg1);
+}
+
+export function ConvertColumnType(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ConvertColumnType'](arg1, arg2, arg3);
+}
+
+export function CreateBackup(arg1) {
+  return window['go']['main']['App']['CreateBackup'](arg1);
+}
+
+export function CreateCleanView(arg1) {
+  return window['go']['main']['App']['CreateCleanView'](arg1);
+}
+
+export function CreateHeaderView(arg1) {
+  return window['go']['main']['App']['CreateHeaderView'](arg1);
+}
+
+export function Crosstab(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['Crosstab'](arg1, arg2, arg3, arg4);
+}
+
+export function CumulativeSum(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['CumulativeSum'](arg1, arg2, arg3, arg4);
+}
+
+export function DeleteSavedQuery(arg1) {
+  return window['go']['main']['App']['DeleteSavedQuery'](arg1);
+}
+
+export function DeleteSnapshot(arg1) {
+  return window['go']['main']['App']['DeleteSnapshot'](arg1);
+}
+
+export function DetectBooleanColumns(arg1) {
+  return window['go']['main']['App']['DetectBooleanColumns'](arg1);
+}
+
+export function DetectSensitiveColumns(arg1) {
+  return window['go']['main']['App']['DetectSensitiveColumns'](arg1);
+}
+
+export function DiffSchemas(arg1, arg2) {
+  return window['go']['main']['App']['DiffSchemas'](arg1, arg2);
+}
+
+export function DiscardRejectedRows(arg1, arg2) {
+  return window['go']['main']['App']['DiscardRejectedRows'](arg1, arg2);
+}
+
+export function DropStaleTables(arg1, arg2) {
+  return window['go']['main']['App']['DropStaleTables'](arg1, arg2);
+}
+
+export function DryRunImport(arg1, arg2) {
+  return window['go']['main']['App']['DryRunImport'](arg1, arg2);
+}
+
+export function EnrichTable(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['EnrichTable'](arg1, arg2, arg3, arg4);
+}
+
+export function EstimateExport(arg1) {
+  return window['go']['main']['App']['EstimateExport'](arg1);
+}
+
+export function ExecuteSQLColumnar(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ExecuteSQLColumnar'](arg1, arg2, arg3);
+}
+
 export function ExecuteSQLWithPage(arg1, arg2, arg3) {
   return window['go']['main']['App']['ExecuteSQLWithPage'](arg1, arg2, arg3);
 }
 
+export function ExplodeColumn(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ExplodeColumn'](arg1, arg2, arg3);
+}
+
+export function ExportAppendSheet(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ExportAppendSheet'](arg1, arg2, arg3);
+}
+
+export function ExportCSVBySQL(arg1) {
+  return window['go']['main']['App']['ExportCSVBySQL'](arg1);
+}
+
+export function ExportDatabaseCopy(arg1, arg2) {
+  return window['go']['main']['App']['ExportDatabaseCopy'](arg1, arg2);
+}
+
+export function ExportDelta(arg1, arg2) {
+  return window['go']['main']['App']['ExportDelta'](arg1, arg2);
+}
+
 export function ExportExcelBySQL(arg1) {
   return window['go']['main']['App']['ExportExcelBySQL'](arg1);
 }
 
+export function ExportExcelFormatted(arg1, arg2) {
+  return window['go']['main']['App']['ExportExcelFormatted'](arg1, arg2);
+}
+
+export function ExportExcelLarge(arg1) {
+  return window['go']['main']['App']['ExportExcelLarge'](arg1);
+}
+
+export function ExportExcelOutlined(arg1, arg2) {
+  return window['go']['main']['App']['ExportExcelOutlined'](arg1, arg2);
+}
+
+export function ExportExcelPinned(arg1, arg2) {
+  return window['go']['main']['App']['ExportExcelPinned'](arg1, arg2);
+}
+
+export function ExportExcelWithFormulas(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ExportExcelWithFormulas'](arg1, arg2, arg3);
+}
+
+export function ExportFillTemplate(arg1, arg2) {
+  return window['go']['main']['App']['ExportFillTemplate'](arg1, arg2);
+}
+
+export function ExportForRoundTrip(arg1, arg2) {
+  return window['go']['main']['App']['ExportForRoundTrip'](arg1, arg2);
+}
+
+export function ExportGroupedExcel(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ExportGroupedExcel'](arg1, arg2, arg3);
+}
+
+export function ExportSample(arg1, arg2) {
+  return window['go']['main']['App']['ExportSample'](arg1, arg2);
+}
+
+export function ExportSavedQuery(arg1) {
+  return window['go']['main']['App']['ExportSavedQuery'](arg1);
+}
+
+export function ExportShareBundle(arg1) {
+  return window['go']['main']['App']['ExportShareBundle'](arg1);
+}
+
+export function ExportViewerPackage(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ExportViewerPackage'](arg1, arg2, arg3, arg4);
+}
+
+export function ExportWorkspaceConfig() {
+  return window['go']['main']['App']['ExportWorkspaceConfig']();
+}
+
+export function FindRowInResult(arg1, arg2, arg3) {
+  return window['go']['main']['App']['FindRowInResult'](arg1, arg2, arg3);
+}
+
+export function GenerateCalendarTable(arg1, arg2) {
+  return window['go']['main']['App']['GenerateCalendarTable'](arg1, arg2);
+}
+
+export function GetCellComments(arg1) {
+  return window['go']['main']['App']['GetCellComments'](arg1);
+}
+
+export function GetCellValue(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetCellValue'](arg1, arg2, arg3);
+}
+
+export function GetColumnValues(arg1, arg2, arg3) {
+  return window['go']['main']['App']['GetColumnValues'](arg1, arg2, arg3);
+}
+
 export function GetCurrentSQL() {
   return window['go']['main']['App']['GetCurrentSQL']();
 }
 
+export function GetDataVersions() {
+  return window['go']['main']['App']['GetDataVersions']();
+}
+
+export function GetExportHistory(arg1) {
+  return window['go']['main']['App']['GetExportHistory'](arg1);
+}
+
+export function GetExportJob(arg1) {
+  return window['go']['main']['App']['GetExportJob'](arg1);
+}
+
+export function GetGridFilterOperators() {
+  return window['go']['main']['App']['GetGridFilterOperators']();
+}
+
+export function GetHeaderMapping(arg1) {
+  return window['go']['main']['App']['GetHeaderMapping'](arg1);
+}
+
+export function GetHeaderTranslations(arg1) {
+  return window['go']['main']['App']['GetHeaderTranslations'](arg1);
+}
+
+export function GetInstanceStatus() {
+  return window['go']['main']['App']['GetInstanceStatus']();
+}
+
+export function GetMemoryUsage() {
+  return window['go']['main']['App']['GetMemoryUsage']();
+}
+
+export function GetQueryHistory(arg1) {
+  return window['go']['main']['App']['GetQueryHistory'](arg1);
+}
+
+export function GetSchemaHistory(arg1) {
+  return window['go']['main']['App']['GetSchemaHistory'](arg1);
+}
+
+export function GetSettings() {
+  return window['go']['main']['App']['GetSettings']();
+}
+
+export function GetSlowestQueries(arg1) {
+  return window['go']['main']['App']['GetSlowestQueries'](arg1);
+}
+
+export function GetStaleTables(arg1) {
+  return window['go']['main']['App']['GetStaleTables'](arg1);
+}
+
+export function GetTableSchema(arg1) {
+  return window['go']['main']['App']['GetTableSchema'](arg1);
+}
+
+export function GetTableTree() {
+  return window['go']['main']['App']['GetTableTree']();
+}
+
+export function GetTypeConversionErrors(arg1, arg2) {
+  return window['go']['main']['App']['GetTypeConversionErrors'](arg1, arg2);
+}
+
+export function GetViewerPackage() {
+  return window['go']['main']['App']['GetViewerPackage']();
+}
+
+export function ImportFixedWidth(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ImportFixedWidth'](arg1, arg2, arg3, arg4);
+}
+
+export function ImportFromDatabase(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ImportFromDatabase'](arg1, arg2, arg3, arg4);
+}
+
+export function ImportHTMLTables(arg1) {
+  return window['go']['main']['App']['ImportHTMLTables'](arg1);
+}
+
+export function ImportNamedRanges(arg1) {
+  return window['go']['main']['App']['ImportNamedRanges'](arg1);
+}
+
+export function ImportPDFTable(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['ImportPDFTable'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function ImportSampled(arg1, arg2) {
+  return window['go']['main']['App']['ImportSampled'](arg1, arg2);
+}
+
+export function ImportSelectedColumns(arg1, arg2) {
+  return window['go']['main']['App']['ImportSelectedColumns'](arg1, arg2);
+}
+
+export function ImportSheetInto(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ImportSheetInto'](arg1, arg2, arg3, arg4);
+}
+
+export function ImportUnion(arg1, arg2) {
+  return window['go']['main']['App']['ImportUnion'](arg1, arg2);
+}
+
+export function ImportWorkspaceConfig(arg1) {
+  return window['go']['main']['App']['ImportWorkspaceConfig'](arg1);
+}
+
+export function InstallReferenceTables(arg1) {
+  return window['go']['main']['App']['InstallReferenceTables'](arg1);
+}
+
+export function JSONFlatten(arg1, arg2) {
+  return window['go']['main']['App']['JSONFlatten'](arg1, arg2);
+}
+
+export function LinkCSVFile(arg1, arg2, arg3) {
+  return window['go']['main']['App']['LinkCSVFile'](arg1, arg2, arg3);
+}
+
+export function LintSQL(arg1) {
+  return window['go']['main']['App']['LintSQL'](arg1);
+}
+
+export function ListActiveOperations() {
+  return window['go']['main']['App']['ListActiveOperations']();
+}
+
+export function ListBackups() {
+  return window['go']['main']['App']['ListBackups']();
+}
+
+export function ListDatabaseDrivers() {
+  return window['go']['main']['App']['ListDatabaseDrivers']();
+}
+
+export function ListExchangeRates() {
+  return window['go']['main']['App']['ListExchangeRates']();
+}
+
+export function ListExportJobs() {
+  return window['go']['main']['App']['ListExportJobs']();
+}
+
+export function ListReferenceTables() {
+  return window['go']['main']['App']['ListReferenceTables']();
+}
+
+export function ListRejectedRows(arg1) {
+  return window['go']['main']['App']['ListRejectedRows'](arg1);
+}
+
+export function ListSavedQueries() {
+  return window['go']['main']['App']['ListSavedQueries']();
+}
+
+export function ListSnapshots() {
+  return window['go']['main']['App']['ListSnapshots']();
+}
+
+export function ListTables() {
+  return window['go']['main']['App']['ListTables']();
+}
+
+export function ListTablesByTag(arg1) {
+  return window['go']['main']['App']['ListTablesByTag'](arg1);
+}
+
+export function ListTags() {
+  return window['go']['main']['App']['ListTags']();
+}
+
+export function ListUnfinishedImports() {
+  return window['go']['main']['App']['ListUnfinishedImports']();
+}
+
+export function MarkRowsDeleted(arg1, arg2) {
+  return window['go']['main']['App']['MarkRowsDeleted'](arg1, arg2);
+}
+
+export function MatchSchemas(arg1, arg2) {
+  return window['go']['main']['App']['MatchSchemas'](arg1, arg2);
+}
+
+export function NormalizeBooleanColumns(arg1, arg2) {
+  return window['go']['main']['App']['NormalizeBooleanColumns'](arg1, arg2);
+}
+
 export function OpenExcel() {
   return window['go']['main']['App']['OpenExcel']();
 }
+
+export function OpenViewerPackage(arg1) {
+  return window['go']['main']['App']['OpenViewerPackage'](arg1);
+}
+
+export function PartitionTable(arg1, arg2, arg3) {
+  return window['go']['main']['App']['PartitionTable'](arg1, arg2, arg3);
+}
+
+export function Ping() {
+  return window['go']['main']['App']['Ping']();
+}
+
+export function PreviewPDFTable(arg1, arg2, arg3) {
+  return window['go']['main']['App']['PreviewPDFTable'](arg1, arg2, arg3);
+}
+
+export function PreviewTable(arg1, arg2) {
+  return window['go']['main']['App']['PreviewTable'](arg1, arg2);
+}
+
+export function ProfileTable(arg1, arg2) {
+  return window['go']['main']['App']['ProfileTable'](arg1, arg2);
+}
+
+export function ReRunExport(arg1, arg2) {
+  return window['go']['main']['App']['ReRunExport'](arg1, arg2);
+}
+
+export function ReapplyRejectedRows(arg1) {
+  return window['go']['main']['App']['ReapplyRejectedRows'](arg1);
+}
+
+export function Reconnect() {
+  return window['go']['main']['App']['Reconnect']();
+}
+
+export function RefreshTable(arg1, arg2) {
+  return window['go']['main']['App']['RefreshTable'](arg1, arg2);
+}
+
+export function ReimportFilled(arg1) {
+  return window['go']['main']['App']['ReimportFilled'](arg1);
+}
+
+export function ResolveUnfinishedImport(arg1, arg2) {
+  return window['go']['main']['App']['ResolveUnfinishedImport'](arg1, arg2);
+}
+
+export function RestoreRows(arg1, arg2) {
+  return window['go']['main']['App']['RestoreRows'](arg1, arg2);
+}
+
+export function RestoreTableFromBackup(arg1, arg2) {
+  return window['go']['main']['App']['RestoreTableFromBackup'](arg1, arg2);
+}
+
+export function RunBenchmark(arg1) {
+  return window['go']['main']['App']['RunBenchmark'](arg1);
+}
+
+export function RunFixScript(arg1, arg2) {
+  return window['go']['main']['App']['RunFixScript'](arg1, arg2);
+}
+
+export function RunSavedQuery(arg1, arg2, arg3) {
+  return window['go']['main']['App']['RunSavedQuery'](arg1, arg2, arg3);
+}
+
+export function SaveQuery(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SaveQuery'](arg1, arg2, arg3);
+}
+
+export function SaveSnapshot(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SaveSnapshot'](arg1, arg2, arg3);
+}
+
+export function SendExportByEmail(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['SendExportByEmail'](arg1, arg2, arg3, arg4);
+}
+
+export function SetColumnDescription(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['SetColumnDescription'](arg1, arg2, arg3, arg4);
+}
+
+export function SetExchangeRates(arg1) {
+  return window['go']['main']['App']['SetExchangeRates'](arg1);
+}
+
+export function SetHeaderTranslations(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetHeaderTranslations'](arg1, arg2, arg3);
+}
+
+export function SetHoliday(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetHoliday'](arg1, arg2, arg3);
+}
+
+export function SetIncludeDeleted(arg1) {
+  return window['go']['main']['App']['SetIncludeDeleted'](arg1);
+}
+
+export function SetSavedQueryFormats(arg1, arg2) {
+  return window['go']['main']['App']['SetSavedQueryFormats'](arg1, arg2);
+}
+
+export function SetSavedQueryLayout(arg1, arg2) {
+  return window['go']['main']['App']['SetSavedQueryLayout'](arg1, arg2);
+}
+
+export function SetSetting(arg1, arg2) {
+  return window['go']['main']['App']['SetSetting'](arg1, arg2);
+}
+
+export function SetTableDescription(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetTableDescription'](arg1, arg2, arg3);
+}
+
+export function SetTableFolder(arg1, arg2) {
+  return window['go']['main']['App']['SetTableFolder'](arg1, arg2);
+}
+
+export function SetTableTags(arg1, arg2) {
+  return window['go']['main']['App']['SetTableTags'](arg1, arg2);
+}
+
+export function SetWindowBackground(arg1) {
+  return window['go']['main']['App']['SetWindowBackground'](arg1);
+}
+
+export function SplitAddressColumn(arg1, arg2) {
+  return window['go']['main']['App']['SplitAddressColumn'](arg1, arg2);
+}
+
+export function TestNotification() {
+  return window['go']['main']['App']['TestNotification']();
+}
+
+export function TraceSourceCell(arg1, arg2, arg3) {
+  return window['go']['main']['App']['TraceSourceCell'](arg1, arg2, arg3);
+}
+
+export function UnlinkCSVFile(arg1) {
+  return window['go']['main']['App']['UnlinkCSVFile'](arg1);
+}
+
+export function UpdateRejectedRow(arg1, arg2, arg3) {
+  return window['go']['main']['App']['UpdateRejectedRow'](arg1, arg2, arg3);
+}
+
+export function UpdateStatistics(arg1) {
+  return window['go']['main']['App']['UpdateStatistics'](arg1);
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// 生成表的生成方式（_app_generated_tables.generator）
+const (
+	generatorPartition = "partition"
+	generatorReference = "reference" // 内置参考表（见 reference.go）
+	generatorCalendar  = "calendar"  // GenerateCalendarTable 生成的日历表
+)
+
+// checkGeneratedTarget 确认可以写入生成的表或视图 name：不存在，或是 generator 此前由 source 生成且未被替换；
+// 同名对象是用户的表或视图时返回错误，避免重新生成时删除用户数据
+func checkGeneratedTarget(db sqlExecutor, name string, generator string, source string) error {
+	var kind, definition string
+	err := db.QueryRow("SELECT type, COALESCE(sql, '') FROM sqlite_master WHERE name = ? COLLATE NOCASE AND type IN ('table', 'view')", name).
+		Scan(&kind, &definition)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("检查 %s 失败: %v", name, err)
+	}
+	var recordedGenerator, recordedSource, recorded string
+	err = db.QueryRow("SELECT generator, source, definition FROM _app_generated_tables WHERE name = ?", name).
+		Scan(&recordedGenerator, &recordedSource, &recorded)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("检查 %s 失败: %v", name, err)
+	}
+	if err == nil && recordedGenerator == generator && recordedSource == source && recorded == definition {
+		return nil
+	}
+	label := "表"
+	if kind == "view" {
+		label = "视图"
+	}
+	return fmt.Errorf("已存在同名的%s %s，且不是此前生成的，为避免覆盖已停止；请先重命名或删除它", label, name)
+}
+
+// recordGeneratedTable 记录生成的表或视图及其当前的建表语句，须在创建之后、同一事务中调用
+func recordGeneratedTable(db sqlExecutor, name string, generator string, source string) error {
+	var definition string
+	if err := db.QueryRow("SELECT COALESCE(sql, '') FROM sqlite_master WHERE name = ? COLLATE NOCASE AND type IN ('table', 'view')", name).Scan(&definition); err != nil {
+		return fmt.Errorf("记录生成的表 %s 失败: %v", name, err)
+	}
+	_, err := db.Exec(`INSERT INTO _app_generated_tables (name, generator, source, definition, created_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET generator = excluded.generator, source = excluded.source,
+			definition = excluded.definition, created_at = excluded.created_at`,
+		name, generator, source, definition, time.Now().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return fmt.Errorf("记录生成的表 %s 失败: %v", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"sync"
+)
+
+// earthRadiusKm 地球平均半径（千米）
+const earthRadiusKm = 6371.0088
+
+// maxCachedPolygons 缓存的已解析多边形数量（同一查询中多边形参数通常是同一个常量）
+const maxCachedPolygons = 64
+
+// udfFloat 将自定义 SQL 函数的参数转为数值（支持带千分位的文本），参数为 NULL 或无法解析时返回 false
+func udfFloat(v interface{}) (float64, bool) {
+	switch x := v.(type) {
+	case int64:
+		return float64(x), true
+	case float64:
+		return x, true
+	}
+	text, ok := udfText(v)
+	if !ok {
+		return 0, false
+	}
+	return parseNumberText(text)
+}
+
+// haversineFunc SQL 函数 HAVERSINE(lat1, lon1, lat2, lon2)：两点间的球面距离（千米），参数无法解析时返回 NULL
+func haversineFunc(lat1, lon1, lat2, lon2 interface{}) interface{} {
+	coords := make([]float64, 4)
+	for i, v := range []interface{}{lat1, lon1, lat2, lon2} {
+		f, ok := udfFloat(v)
+		if !ok {
+			return nil
+		}
+		coords[i] = f * math.Pi / 180
+	}
+	dLat := coords[2] - coords[0]
+	dLon := coords[3] - coords[1]
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(coords[0])*math.Cos(coords[2])*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// polygonCache 已解析的多边形：原始文本 -> 顶点（经度, 纬度）
+var polygonCache = struct {
+	sync.Mutex
+	items map[string][][2]float64
+}{items: make(map[string][][2]float64)}
+
+// parsePolygon 解析多边形：支持 [[经度,纬度], ...] 坐标数组，或 GeoJSON Polygon/Feature（取外环）
+func parsePolygon(text string) ([][2]float64, bool) {
+	polygonCache.Lock()
+	if ring, ok := polygonCache.items[text]; ok {
+		polygonCache.Unlock()
+		return ring, true
+	}
+	polygonCache.Unlock()
+
+	var raw interface{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(text)), &raw); err != nil {
+		return nil, false
+	}
+	if obj, ok := raw.(map[string]interface{}); ok {
+		if geometry, ok := obj["geometry"].(map[string]interface{}); ok {
+			obj = geometry
+		}
+		rings, ok := obj["coordinates"].([]interface{})
+		if !ok || len(rings) == 0 {
+			return nil, false
+		}
+		raw = rings[0]
+	}
+	points, ok := raw.([]interface{})
+	if !ok || len(points) < 3 {
+		return nil, false
+	}
+	ring := make([][2]float64, 0, len(points))
+	for _, p := range points {
+		pair, ok := p.([]interface{})
+		if !ok || len(pair) < 2 {
+			return nil, false
+		}
+		lon, ok1 := pair[0].(float64)
+		lat, ok2 := pair[1].(float64)
+		if !ok1 || !ok2 {
+			return nil, false
+		}
+		ring = append(ring, [2]float64{lon, lat})
+	}
+
+	polygonCache.Lock()
+	if len(polygonCache.items) >= maxCachedPolygons {
+		polygonCache.items = make(map[string][][2]float64)
+	}
+	polygonCache.items[text] = ring
+	polygonCache.Unlock()
+	return ring, true
+}
+
+// pointInPolygonFunc SQL 函数 POINT_IN_POLYGON(lat, lon, polygon)：点是否在多边形内（射线法，1/0），
+// polygon 为 [[经度,纬度], ...] 或 GeoJSON（注意 GeoJSON 坐标为经度在前）；参数无法解析时返回 NULL
+func pointInPolygonFunc(lat, lon, polygon interface{}) interface{} {
+	y, ok1 := udfFloat(lat)
+	x, ok2 := udfFloat(lon)
+	text, ok3 := udfText(polygon)
+	if !ok1 || !ok2 || !ok3 {
+		return nil
+	}
+	ring, ok := parsePolygon(text)
+	if !ok {
+		return nil
+	}
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi := ring[i][0], ring[i][1]
+		xj, yj := ring[j][0], ring[j][1]
+		if (yi > y) != (yj > y) && x < (xj-xi)*(y-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	if inside {
+		return int64(1)
+	}
+	return int64(0)
+}
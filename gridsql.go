@@ -0,0 +1,247 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// gridFilterOperators 表格筛选支持的运算符及说明
+var gridFilterOperators = map[string]string{
+	"=":          "等于",
+	"!=":         "不等于",
+	">":          "大于",
+	">=":         "大于等于",
+	"<":          "小于",
+	"<=":         "小于等于",
+	"contains":   "包含",
+	"startsWith": "开头是",
+	"endsWith":   "结尾是",
+	"in":         "属于（逗号分隔或数组）",
+	"between":    "介于（两个值）",
+	"isEmpty":    "为空",
+	"isNotEmpty": "不为空",
+}
+
+// sqlValueLiteral 把前端传来的值写成 SQL 字面量：数字原样输出，其余按字符串转义
+func sqlValueLiteral(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return "NULL"
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(x)
+	case int64:
+		return strconv.FormatInt(x, 10)
+	case bool:
+		if x {
+			return "1"
+		}
+		return "0"
+	}
+	return quoteLiteral(fmt.Sprint(v))
+}
+
+// likeLiteral 生成 LIKE 模式字面量，转义值中的 % 和 _
+func likeLiteral(prefix string, v interface{}, suffix string) string {
+	s := fmt.Sprint(v)
+	s = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+	return quoteLiteral(prefix+s+suffix) + ` ESCAPE '\'`
+}
+
+// filterValues 把 in / between 的值拆成列表：数组原样使用，字符串按逗号分隔
+func filterValues(v interface{}) []interface{} {
+	switch x := v.(type) {
+	case []interface{}:
+		return x
+	case string:
+		var values []interface{}
+		for _, part := range strings.Split(x, ",") {
+			values = append(values, strings.TrimSpace(part))
+		}
+		return values
+	}
+	return []interface{}{v}
+}
+
+// gridFilterCondition 生成单个筛选条件
+func gridFilterCondition(filter map[string]interface{}) (string, error) {
+	column, _ := filter["column"].(string)
+	op, _ := filter["op"].(string)
+	if column == "" {
+		return "", fmt.Errorf("筛选条件缺少列名")
+	}
+	if _, ok := gridFilterOperators[op]; !ok {
+		return "", fmt.Errorf("不支持的筛选运算符: %s", op)
+	}
+	col := quoteIdent(column)
+	value := filter["value"]
+
+	switch op {
+	case "contains":
+		return fmt.Sprintf("%s LIKE %s", col, likeLiteral("%", value, "%")), nil
+	case "startsWith":
+		return fmt.Sprintf("%s LIKE %s", col, likeLiteral("", value, "%")), nil
+	case "endsWith":
+		return fmt.Sprintf("%s LIKE %s", col, likeLiteral("%", value, "")), nil
+	case "isEmpty":
+		return fmt.Sprintf("(%s IS NULL OR %s = '')", col, col), nil
+	case "isNotEmpty":
+		return fmt.Sprintf("(%s IS NOT NULL AND %s <> '')", col, col), nil
+	case "in":
+		values := filterValues(value)
+		if len(values) == 0 {
+			return "", fmt.Errorf("列 %s 的 in 条件没有取值", column)
+		}
+		literals := make([]string, len(values))
+		for i, v := range values {
+			literals[i] = sqlValueLiteral(v)
+		}
+		return fmt.Sprintf("%s IN (%s)", col, strings.Join(literals, ", ")), nil
+	case "between":
+		values := filterValues(value)
+		if len(values) != 2 {
+			return "", fmt.Errorf("列 %s 的 between 条件需要两个值", column)
+		}
+		return fmt.Sprintf("%s BETWEEN %s AND %s", col, sqlValueLiteral(values[0]), sqlValueLiteral(values[1])), nil
+	}
+	if value == nil {
+		if op == "=" {
+			return fmt.Sprintf("%s IS NULL", col), nil
+		}
+		if op == "!=" {
+			return fmt.Sprintf("%s IS NOT NULL", col), nil
+		}
+	}
+	if op == "!=" {
+		op = "<>"
+	}
+	return fmt.Sprintf("%s %s %s", col, op, sqlValueLiteral(value)), nil
+}
+
+// resolveSource 解析表名或查询语句：表名直接引用，查询语句作为子查询；同时返回其列名
+func (a *App) resolveSource(source string) (string, []string, error) {
+	if columns, err := a.tableColumns(source); err == nil {
+		from, columns := a.liveSource(source, columns)
+		return from, columns, nil
+	}
+	stmt := parseStatement(source)
+	if err := stmt.wrappable(); err != nil {
+		return "", nil, fmt.Errorf("%s 不是表名，也不是可用的查询语句", source)
+	}
+	from := "(\n" + a.liveSQL(stmt.text) + "\n)"
+	rows, err := a.readDB().Query("SELECT * FROM " + from + " LIMIT 0")
+	if err != nil {
+		return "", nil, fmt.Errorf("SQL 执行失败: %v", err)
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", nil, fmt.Errorf("获取列名失败: %v", err)
+	}
+	return from, columns, nil
+}
+
+// BuildGridSQL 根据表格上的操作（选中的列、筛选、排序）生成 SQL 文本，便于用户在此基础上手动修改
+// source 为表名或一条查询语句；columns 为空表示全部列；
+// filters 为 [{column, op, value}]（多个条件以 AND 连接，运算符见 GetGridFilterOperators）；sorts 为 [{column, desc}]
+// wails:export BuildGridSQL
+func (a *App) BuildGridSQL(source string, columns []string, filters []map[string]interface{}, sorts []map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+
+	source = strings.TrimSpace(source)
+	if source == "" {
+		result["error"] = "请指定表名或查询语句"
+		return result
+	}
+
+	from, known, err := a.resolveSource(source)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	checkColumn := func(col string) error {
+		if !containsString(known, col) {
+			return fmt.Errorf("%s 中不存在列 %s", source, col)
+		}
+		return nil
+	}
+
+	selectList := "*"
+	if len(columns) > 0 {
+		quoted := make([]string, len(columns))
+		for i, col := range columns {
+			if err := checkColumn(col); err != nil {
+				result["error"] = err.Error()
+				return result
+			}
+			quoted[i] = quoteIdent(col)
+		}
+		selectList = strings.Join(quoted, ", ")
+	}
+	sqlText := fmt.Sprintf("SELECT %s\nFROM %s", selectList, from)
+
+	var conditions []string
+	for _, filter := range filters {
+		if col, _ := filter["column"].(string); col != "" {
+			if err := checkColumn(col); err != nil {
+				result["error"] = err.Error()
+				return result
+			}
+		}
+		cond, err := gridFilterCondition(filter)
+		if err != nil {
+			result["error"] = err.Error()
+			return result
+		}
+		conditions = append(conditions, cond)
+	}
+	if len(conditions) > 0 {
+		sqlText += "\nWHERE " + strings.Join(conditions, "\n  AND ")
+	}
+
+	var orders []string
+	for _, s := range sorts {
+		col, _ := s["column"].(string)
+		if col == "" {
+			continue
+		}
+		if err := checkColumn(col); err != nil {
+			result["error"] = err.Error()
+			return result
+		}
+		order := quoteIdent(col)
+		if desc, _ := s["desc"].(bool); desc {
+			order += " DESC"
+		}
+		orders = append(orders, order)
+	}
+	if len(orders) > 0 {
+		sqlText += "\nORDER BY " + strings.Join(orders, ", ")
+	}
+
+	result["sql"] = sqlText
+	result["message"] = "已生成 SQL"
+	return result
+}
+
+// GetGridFilterOperators 获取 BuildGridSQL 支持的筛选运算符及说明
+// wails:export GetGridFilterOperators
+func (a *App) GetGridFilterOperators() []map[string]interface{} {
+	ops := make([]string, 0, len(gridFilterOperators))
+	for op := range gridFilterOperators {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+	list := make([]map[string]interface{}, len(ops))
+	for i, op := range ops {
+		list[i] = map[string]interface{}{"op": op, "label": gridFilterOperators[op]}
+	}
+	return list
+}
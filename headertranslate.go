@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// headerLanguagePattern 表头语言代码，如 en、en-US、zh-CN
+var headerLanguagePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})?$`)
+
+// validateHeaderLanguage 校验 export_header_language 设置：空表示不翻译表头
+func validateHeaderLanguage(v string) error {
+	if v != "" && !headerLanguagePattern.MatchString(v) {
+		return fmt.Errorf("语言代码 %q 无效（如 en-US、zh-CN，留空表示不翻译）", v)
+	}
+	return nil
+}
+
+// headerTranslations 生成 列名 -> 指定语言表头 的映射；与 columnLabels 相同，
+// 同一列名在不同表中译名不同时视为歧义，不做替换
+func (a *App) headerTranslations(language string) map[string]string {
+	rows, err := a.readDB().Query("SELECT column_name, header FROM _app_header_translations WHERE language = ? AND header <> ''", language)
+	if err != nil {
+		fmt.Printf("读取表头翻译失败: %v\n", err)
+		return nil
+	}
+	defer rows.Close()
+
+	headers := make(map[string]string)
+	ambiguous := make(map[string]bool)
+	for rows.Next() {
+		var col, header string
+		if err := rows.Scan(&col, &header); err != nil {
+			return nil
+		}
+		if existing, ok := headers[col]; ok && existing != header {
+			ambiguous[col] = true
+		}
+		headers[col] = header
+	}
+	for col := range ambiguous {
+		delete(headers, col)
+	}
+	return headers
+}
+
+// SetHeaderTranslations 设置表中各列在 language 语言下的导出表头 {列名: 表头}，表头为空时删除该列的译名；
+// 将 export_header_language 设为该语言后，导出的 Excel/CSV 表头使用译名，无需在 SQL 中逐列起别名
+// wails:export SetHeaderTranslations
+func (a *App) SetHeaderTranslations(tableName string, language string, headers map[string]string) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	if a.readOnly() {
+		return readOnlyMessage
+	}
+	language = strings.TrimSpace(language)
+	if language == "" {
+		return "请指定表头语言"
+	}
+	if err := validateHeaderLanguage(language); err != nil {
+		return err.Error()
+	}
+	columns, err := a.tableColumns(tableName)
+	if err != nil {
+		return err.Error()
+	}
+	for col := range headers {
+		if !containsString(columns, col) {
+			return fmt.Sprintf("表 %s 中不存在列 %s", tableName, col)
+		}
+	}
+
+	tx, err := a.writeDB().Begin()
+	if err != nil {
+		return fmt.Sprintf("开启事务失败: %v", err)
+	}
+	saved, removed := 0, 0
+	for col, header := range headers {
+		header = strings.TrimSpace(header)
+		if header == "" {
+			_, err = tx.Exec("DELETE FROM _app_header_translations WHERE table_name = ? AND column_name = ? AND language = ?",
+				tableName, col, language)
+			removed++
+		} else {
+			_, err = tx.Exec(`INSERT INTO _app_header_translations (table_name, column_name, language, header) VALUES (?, ?, ?, ?)
+				ON CONFLICT(table_name, column_name, language) DO UPDATE SET header = excluded.header`,
+				tableName, col, language, header)
+			saved++
+		}
+		if err != nil {
+			tx.Rollback()
+			return fmt.Sprintf("保存列 %s 的表头翻译失败: %v", col, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Sprintf("提交事务失败: %v", err)
+	}
+	message := fmt.Sprintf("已更新表 %s 的 %s 表头：%d 列", tableName, language, saved)
+	if removed > 0 {
+		message += fmt.Sprintf("，清除 %d 列", removed)
+	}
+	return message
+}
+
+// GetHeaderTranslations 列出表中各列的显示名和各语言表头：columns 为 [{column, label, translations: {语言: 表头}}]，
+// languages 为已设置过的语言
+// wails:export GetHeaderTranslations
+func (a *App) GetHeaderTranslations(tableName string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	columns, err := a.tableColumns(tableName)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	entries, err := a.dictionaryEntries(tableName)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	rows, err := a.readDB().Query("SELECT column_name, language, header FROM _app_header_translations WHERE table_name = ? ORDER BY language", tableName)
+	if err != nil {
+		result["error"] = fmt.Sprintf("读取表头翻译失败: %v", err)
+		return result
+	}
+	defer rows.Close()
+	translations := make(map[string]map[string]string)
+	languages := []string{}
+	for rows.Next() {
+		var col, language, header string
+		if err := rows.Scan(&col, &language, &header); err != nil {
+			result["error"] = fmt.Sprintf("读取表头翻译失败: %v", err)
+			return result
+		}
+		if translations[col] == nil {
+			translations[col] = make(map[string]string)
+		}
+		translations[col][language] = header
+		if !containsString(languages, language) {
+			languages = append(languages, language)
+		}
+	}
+
+	list := make([]map[string]interface{}, len(columns))
+	for i, col := range columns {
+		t := translations[col]
+		if t == nil {
+			t = map[string]string{}
+		}
+		list[i] = map[string]interface{}{"column": col, "label": entries[col].label, "translations": t}
+	}
+	result["table"] = tableName
+	result["columns"] = list
+	result["languages"] = languages
+	result["language"] = a.setting("export_header_language")
+	return result
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// headerViewSuffix 以原表头为列名的视图名后缀：sheet1 的视图为 sheet1_headers
+const headerViewSuffix = "_headers"
+
+// headerAlias 表列与其在视图中使用的原表头
+type headerAlias struct {
+	column string
+	header string
+}
+
+// loadSourceHeader 读取表导入时记录的源表头（见 recordTableHeader），没有记录时返回 nil
+func (a *App) loadSourceHeader(tableName string) (*sourceHeader, error) {
+	var headerJSON string
+	err := a.readDB().QueryRow("SELECT header FROM _app_table_sources WHERE table_name = ?", tableName).Scan(&headerJSON)
+	if err == sql.ErrNoRows || err == nil && headerJSON == "" {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取表 %s 的源表头失败: %v", tableName, err)
+	}
+	h := &sourceHeader{}
+	if err := json.Unmarshal([]byte(headerJSON), h); err != nil {
+		return nil, fmt.Errorf("表 %s 的源表头记录已损坏: %v", tableName, err)
+	}
+	return h, nil
+}
+
+// headerAliases 按表列顺序生成 表列 -> 原表头 的对应：空白表头保留列名，重名的（忽略大小写和全半角）加 _2、_3 后缀；
+// 没有对应表头的列（如 _url 伴随列）保留原列名，软删除和整行哈希等内部列不出现在视图中
+func headerAliases(columns []string, h *sourceHeader) []headerAlias {
+	aliases := make([]headerAlias, 0, len(columns))
+	seen := make(map[string]int, len(columns))
+	for _, col := range columns {
+		if col == deletedFlagColumn || col == rowHashColumn {
+			continue
+		}
+		name := strings.TrimSpace(h.Columns[col])
+		if name == "" {
+			name = col
+		}
+		key := foldKey(name)
+		seen[key]++
+		if seen[key] > 1 {
+			name = fmt.Sprintf("%s_%d", name, seen[key])
+		}
+		aliases = append(aliases, headerAlias{column: col, header: name})
+	}
+	return aliases
+}
+
+// createHeaderView 创建（或重建）以原表头为列名的视图，查询时可直接写 SELECT "客户名称" FROM sheet1_headers；
+// 软删除的行不出现在视图中
+func (a *App) createHeaderView(tableName string, h *sourceHeader) (string, []headerAlias, error) {
+	viewName := tableName + headerViewSuffix
+	var kind string
+	if a.readDB().QueryRow("SELECT type FROM sqlite_master WHERE name = ?", viewName).Scan(&kind) == nil && kind != "view" {
+		return "", nil, fmt.Errorf("已存在名为 %s 的表，无法创建表头视图", viewName)
+	}
+	columns, err := a.tableColumns(tableName)
+	if err != nil {
+		return "", nil, err
+	}
+	aliases := headerAliases(columns, h)
+	items := make([]string, len(aliases))
+	for i, al := range aliases {
+		items[i] = fmt.Sprintf("%s AS %s", quoteIdent(al.column), quoteIdent(al.header))
+	}
+	where := ""
+	if containsString(columns, deletedFlagColumn) {
+		where = fmt.Sprintf(" WHERE %s = 0", quoteIdent(deletedFlagColumn))
+	}
+	if _, err := a.writeDB().Exec(fmt.Sprintf("DROP VIEW IF EXISTS %s", quoteIdent(viewName))); err != nil {
+		return "", nil, fmt.Errorf("删除旧视图 %s 失败: %v", viewName, err)
+	}
+	ddl := fmt.Sprintf("CREATE VIEW %s AS SELECT %s FROM %s%s", quoteIdent(viewName), strings.Join(items, ", "), quoteIdent(tableName), where)
+	if _, err := a.writeDB().Exec(ddl); err != nil {
+		return "", nil, fmt.Errorf("创建视图 %s 失败: %v", viewName, err)
+	}
+	return viewName, aliases, nil
+}
+
+// aliasList 供前端使用的对应关系列表
+func aliasList(aliases []headerAlias) []map[string]string {
+	list := make([]map[string]string, len(aliases))
+	for i, al := range aliases {
+		list[i] = map[string]string{"column": al.column, "header": al.header}
+	}
+	return list
+}
+
+// GetHeaderMapping 获取表导入时记录的 原表头 -> 列名 对应关系：[{column, header}]（与表头视图中的列名一致）
+// wails:export GetHeaderMapping
+func (a *App) GetHeaderMapping(tableName string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	columns, err := a.tableColumns(tableName)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	h, err := a.loadSourceHeader(tableName)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	if h == nil {
+		result["error"] = fmt.Sprintf("表 %s 没有记录导入时的表头（重新导入后即可记录）", tableName)
+		return result
+	}
+	result["mapping"] = aliasList(headerAliases(columns, h))
+	result["view"] = tableName + headerViewSuffix
+	return result
+}
+
+// CreateHeaderView 为导入的表创建以原表头为列名的视图 <表名>_headers，列名中的空格、关键字等无需再转换
+// 开启 header_views 设置时导入和刷新后自动创建；返回 view 和 mapping（[{column, header}]）
+// wails:export CreateHeaderView
+func (a *App) CreateHeaderView(tableName string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	if a.readOnly() {
+		result["error"] = readOnlyMessage
+		return result
+	}
+	h, err := a.loadSourceHeader(tableName)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	if h == nil {
+		result["error"] = fmt.Sprintf("表 %s 没有记录导入时的表头（重新导入后即可记录）", tableName)
+		return result
+	}
+	viewName, aliases, err := a.createHeaderView(tableName, h)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	result["view"] = viewName
+	result["mapping"] = aliasList(aliases)
+	result["message"] = fmt.Sprintf("已创建视图 %s，可用原表头查询，如 SELECT %s FROM %s", viewName, quoteIdent(aliases[0].header), quoteIdent(viewName))
+	return result
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// healthCheckInterval 后台检查数据库连接的间隔
+const healthCheckInterval = 30 * time.Second
+
+// healthCheckTimeout 单次检查的超时时间（数据库被长时间锁住时也视为不可用）
+const healthCheckTimeout = 5 * time.Second
+
+// dbStatusEvent 数据库连接状态变化时发送给前端的事件，数据为 {ok, error?, message}
+const dbStatusEvent = "db-status"
+
+// reconnectBackoff 重连的等待间隔（逐次加倍）
+var reconnectBackoff = []time.Duration{0, 500 * time.Millisecond, time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second}
+
+// errDatabaseBusy 检查时数据库正被长时间的写入占用：连接本身正常，不需要重连
+var errDatabaseBusy = errors.New("数据库正忙（有较长的写入正在进行）")
+
+// checkHealth 检查数据库文件仍在原位且能正常查询；通过只读连接检查（WAL 模式下不会被写入阻塞），
+// 仍然超时或返回 SQLITE_BUSY 时返回 errDatabaseBusy
+func (a *App) checkHealth() error {
+	if a.writeDB() == nil {
+		return errors.New("数据库连接未初始化")
+	}
+	path := "./data.db"
+	if v := a.viewer(); v != nil {
+		path = v.path
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("数据库文件不可访问（可能已被移动或删除）: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+	var n int
+	if err := a.readDB().QueryRowContext(ctx, "SELECT COUNT(*) FROM sqlite_master").Scan(&n); err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.Is(err, context.DeadlineExceeded) ||
+			errors.As(err, &sqliteErr) && (sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked) {
+			return errDatabaseBusy
+		}
+		return fmt.Errorf("数据库查询失败: %v", err)
+	}
+	return nil
+}
+
+// reconnect 按 reconnectBackoff 的间隔重新打开数据库，成功后替换旧连接；
+// 旧连接等仍在使用它的查询、导出和导入结束后再关闭（见 dbconn.go）
+// 曾经连接成功过而数据库文件已不存在时不会重连，避免在原处新建一个空数据库
+func (a *App) reconnect(ctx context.Context) error {
+	a.switchMu.Lock()
+	defer a.switchMu.Unlock()
+	if a.viewer() != nil {
+		return errors.New("只读分享包无法访问，请关闭分享包后重新打开")
+	}
+	var lastErr error
+	for _, wait := range reconnectBackoff {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		if a.writeDB() != nil {
+			if _, err := os.Stat("./data.db"); err != nil {
+				lastErr = fmt.Errorf("数据库文件不可访问（可能已被移动或删除），请放回原处后重试: %v", err)
+				continue
+			}
+		}
+		db, err := openDatabase(a.readOnly())
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		old, err := a.attachDB(db)
+		if err != nil {
+			db.Close()
+			lastErr = err
+			continue
+		}
+		a.cache.clear()
+		a.stats.clear()
+		retireHandles(old)
+		return nil
+	}
+	return lastErr
+}
+
+// watchHealth 定时检查数据库连接，不可用时自动重连并通知前端
+func (a *App) watchHealth(ctx context.Context) {
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		err := a.checkHealth()
+		if err == nil || errors.Is(err, errDatabaseBusy) {
+			continue
+		}
+		fmt.Printf("数据库连接异常，尝试重连: %v\n", err)
+		runtime.EventsEmit(ctx, dbStatusEvent, map[string]interface{}{"ok": false, "error": err.Error(), "message": "数据库连接异常，正在重连…"})
+		if err := a.reconnect(ctx); err != nil {
+			runtime.EventsEmit(ctx, dbStatusEvent, map[string]interface{}{"ok": false, "error": err.Error(), "message": "数据库重连失败"})
+			continue
+		}
+		runtime.EventsEmit(ctx, dbStatusEvent, map[string]interface{}{"ok": true, "message": "数据库已重新连接"})
+	}
+}
+
+// Ping 检查数据库连接，返回 {ok, busy, latencyMs, error?}；数据库正被长时间写入占用时 ok 为 true、busy 为 true
+// wails:export Ping
+func (a *App) Ping() map[string]interface{} {
+	start := time.Now()
+	err := a.checkHealth()
+	busy := errors.Is(err, errDatabaseBusy)
+	result := map[string]interface{}{
+		"ok":        err == nil || busy,
+		"busy":      busy,
+		"latencyMs": time.Since(start).Milliseconds(),
+	}
+	if err != nil && !busy {
+		result["error"] = err.Error()
+	}
+	return result
+}
+
+// Reconnect 立即重新打开数据库（失败时按退避间隔重试），用于磁盘空间恢复、文件放回原处后无需重启应用
+// wails:export Reconnect
+func (a *App) Reconnect() string {
+	ctx := a.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := a.reconnect(ctx); err != nil {
+		return fmt.Sprintf("数据库重连失败: %v", err)
+	}
+	return "数据库已重新连接"
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// colorPattern 高亮颜色：6 位十六进制 RGB，可带 #
+var colorPattern = regexp.MustCompile(`^#?[0-9A-Fa-f]{6}$`)
+
+// highlightRule 结果表格的条件高亮规则：列值满足 op/value 条件时按颜色显示，wholeRow 为 true 时高亮整行
+// op 与表格筛选相同（见 GetGridFilterOperators）
+type highlightRule struct {
+	column     string
+	op         string
+	value      interface{}
+	color      string
+	background string
+	bold       bool
+	wholeRow   bool
+	label      string
+}
+
+// parseHighlightRules 解析前端传来的 [{column, op, value, color, background, bold, wholeRow, label}]
+func parseHighlightRules(specs []map[string]interface{}) ([]highlightRule, error) {
+	rules := make([]highlightRule, 0, len(specs))
+	for i, spec := range specs {
+		var r highlightRule
+		r.column, _ = spec["column"].(string)
+		r.op, _ = spec["op"].(string)
+		r.value = spec["value"]
+		r.color, _ = spec["color"].(string)
+		r.background, _ = spec["background"].(string)
+		r.bold, _ = spec["bold"].(bool)
+		r.wholeRow, _ = spec["wholeRow"].(bool)
+		r.label, _ = spec["label"].(string)
+		if r.column == "" {
+			return nil, fmt.Errorf("第 %d 条高亮规则缺少列名", i+1)
+		}
+		if _, ok := gridFilterOperators[r.op]; !ok {
+			return nil, fmt.Errorf("第 %d 条高亮规则的运算符 %s 不支持", i+1, r.op)
+		}
+		for _, c := range []string{r.color, r.background} {
+			if c != "" && !colorPattern.MatchString(c) {
+				return nil, fmt.Errorf("第 %d 条高亮规则的颜色 %s 格式错误（应为 #RRGGBB）", i+1, c)
+			}
+		}
+		if r.color == "" && r.background == "" && !r.bold {
+			r.background = "#FFEB9C"
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// toMap 规则的前端表示
+func (r highlightRule) toMap() map[string]interface{} {
+	return map[string]interface{}{
+		"column":     r.column,
+		"op":         r.op,
+		"value":      r.value,
+		"color":      r.color,
+		"background": r.background,
+		"bold":       r.bold,
+		"wholeRow":   r.wholeRow,
+		"label":      r.label,
+	}
+}
+
+// compareRuleValues 比较单元格值和规则值：两者都能解析为数值时按数值比较，否则按文本比较
+func compareRuleValues(cell interface{}, value interface{}) int {
+	a, b := strings.TrimSpace(fmt.Sprint(cell)), strings.TrimSpace(fmt.Sprint(value))
+	if x, ok := parseNumberText(a); ok {
+		if y, ok := parseNumberText(b); ok {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(a, b)
+}
+
+// matches 单元格值是否满足规则条件
+func (r highlightRule) matches(cell interface{}) bool {
+	empty := isNullDisplay(cell) || fmt.Sprint(cell) == ""
+	switch r.op {
+	case "isEmpty":
+		return empty
+	case "isNotEmpty":
+		return !empty
+	}
+	if empty {
+		return false
+	}
+	text := fmt.Sprint(cell)
+	switch r.op {
+	case "=":
+		return compareRuleValues(cell, r.value) == 0
+	case "!=":
+		return compareRuleValues(cell, r.value) != 0
+	case ">":
+		return compareRuleValues(cell, r.value) > 0
+	case ">=":
+		return compareRuleValues(cell, r.value) >= 0
+	case "<":
+		return compareRuleValues(cell, r.value) < 0
+	case "<=":
+		return compareRuleValues(cell, r.value) <= 0
+	case "contains":
+		return strings.Contains(text, fmt.Sprint(r.value))
+	case "startsWith":
+		return strings.HasPrefix(text, fmt.Sprint(r.value))
+	case "endsWith":
+		return strings.HasSuffix(text, fmt.Sprint(r.value))
+	case "in":
+		for _, v := range filterValues(r.value) {
+			if compareRuleValues(cell, v) == 0 {
+				return true
+			}
+		}
+	case "between":
+		if values := filterValues(r.value); len(values) == 2 {
+			return compareRuleValues(cell, values[0]) >= 0 && compareRuleValues(cell, values[1]) <= 0
+		}
+	}
+	return false
+}
+
+// evaluateHighlights 计算一页数据中命中的高亮：[{row, column, rule}]，row 为页内行序号，整行高亮时 column 为空
+func evaluateHighlights(rules []highlightRule, data []map[string]interface{}) []map[string]interface{} {
+	var highlights []map[string]interface{}
+	for rowIdx, row := range data {
+		for ruleIdx, r := range rules {
+			if !r.matches(row[r.column]) {
+				continue
+			}
+			column := r.column
+			if r.wholeRow {
+				column = ""
+			}
+			highlights = append(highlights, map[string]interface{}{"row": rowIdx, "column": column, "rule": ruleIdx})
+		}
+	}
+	return highlights
+}
+
+// highlightStyles 为每条规则创建单元格样式，返回与 rules 一一对应的样式 ID
+func highlightStyles(f *excelize.File, rules []highlightRule) []int {
+	styles := make([]int, len(rules))
+	for i, r := range rules {
+		style := &excelize.Style{Font: &excelize.Font{Bold: r.bold, Color: strings.TrimPrefix(r.color, "#")}}
+		if r.background != "" {
+			style.Fill = excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{strings.TrimPrefix(r.background, "#")}}
+		}
+		styles[i], _ = f.NewStyle(style)
+	}
+	return styles
+}
+
+// applyHighlightRules 在导出的工作表上按规则设置单元格样式（数据从第 2 行开始）
+func applyHighlightRules(f *excelize.File, sheet string, columns []string, data []map[string]interface{}, rules []highlightRule) {
+	if len(rules) == 0 {
+		return
+	}
+	styles := highlightStyles(f, rules)
+	colIndex := make(map[string]int, len(columns))
+	for i, col := range columns {
+		colIndex[col] = i + 1
+	}
+	for rowIdx, row := range data {
+		for i, r := range rules {
+			col, ok := colIndex[r.column]
+			if !ok || !r.matches(row[r.column]) {
+				continue
+			}
+			first, last := col, col
+			if r.wholeRow {
+				first, last = 1, len(columns)
+			}
+			from, _ := excelize.CoordinatesToCellName(first, rowIdx+2)
+			to, _ := excelize.CoordinatesToCellName(last, rowIdx+2)
+			f.SetCellStyle(sheet, from, to, styles[i])
+		}
+	}
+}
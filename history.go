@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// queryHistoryLimit 查询历史最多保留的条数，超出时删除最早的记录
+const queryHistoryLimit = 1000
+
+// planSummary 查询计划摘要：EXPLAIN QUERY PLAN 各步骤用 "; " 连接，无法分析（如多条语句）时返回空字符串
+func (a *App) planSummary(sqlStr string) string {
+	rows, err := a.readDB().Query("EXPLAIN QUERY PLAN " + sqlStr)
+	if err != nil {
+		return ""
+	}
+	defer rows.Close()
+	var steps []string
+	for rows.Next() {
+		var id, parent, notUsed int
+		var detail string
+		if err := rows.Scan(&id, &parent, &notUsed, &detail); err != nil {
+			break
+		}
+		steps = append(steps, detail)
+	}
+	return strings.Join(steps, "; ")
+}
+
+// recordQueryHistory 记录一次执行的 SQL、耗时、行数和查询计划（只读模式下不记录，记录失败不影响查询）
+func (a *App) recordQueryHistory(sqlStr string, duration time.Duration, result map[string]interface{}) {
+	if a.writeDB() == nil || a.readOnly() {
+		return
+	}
+	var rowCount int64
+	switch n := result["total"].(type) {
+	case int:
+		rowCount = int64(n)
+	}
+	switch n := result["rowsAffected"].(type) {
+	case int:
+		rowCount = int64(n)
+	case int64:
+		rowCount = n
+	}
+	message, _ := result["error"].(string)
+	plan := ""
+	if stmt := parseStatement(strings.TrimSpace(sqlStr)); message == "" && !stmt.multiple {
+		plan = a.planSummary(stmt.text)
+	}
+
+	if _, err := a.writeDB().Exec("INSERT INTO _app_query_history (sql, executed_at, duration_ms, row_count, plan, error) VALUES (?, ?, ?, ?, ?, ?)",
+		strings.TrimSpace(sqlStr), time.Now().Format("2006-01-02 15:04:05"), duration.Milliseconds(), rowCount, plan, message); err != nil {
+		fmt.Printf("记录查询历史失败: %v\n", err)
+		return
+	}
+	if _, err := a.writeDB().Exec("DELETE FROM _app_query_history WHERE id <= (SELECT MAX(id) FROM _app_query_history) - ?", queryHistoryLimit); err != nil {
+		fmt.Printf("清理查询历史失败: %v\n", err)
+	}
+	if message == "" {
+		a.touchQueryTables(sqlStr)
+	}
+}
+
+// isFullScanPlan 查询计划中是否有不使用索引的全表扫描
+func isFullScanPlan(plan string) bool {
+	for _, step := range strings.Split(plan, "; ") {
+		if strings.HasPrefix(step, "SCAN ") && !strings.Contains(step, " USING ") &&
+			!strings.HasPrefix(step, "SCAN CONSTANT") && !strings.HasSuffix(step, "VALUES CLAUSE") {
+			return true
+		}
+	}
+	return false
+}
+
+// GetQueryHistory 最近执行的查询（最新的在前）：{id, sql, executedAt, durationMs, rowCount, plan, error}
+// limit <= 0 时返回全部保留的记录（最多 1000 条）
+// wails:export GetQueryHistory
+func (a *App) GetQueryHistory(limit int) []map[string]interface{} {
+	list := []map[string]interface{}{}
+	if a.writeDB() == nil {
+		return list
+	}
+	if limit <= 0 {
+		limit = queryHistoryLimit
+	}
+	rows, err := a.readDB().Query("SELECT id, sql, executed_at, duration_ms, row_count, plan, error FROM _app_query_history ORDER BY id DESC LIMIT ?", limit)
+	if err != nil {
+		fmt.Printf("读取查询历史失败: %v\n", err)
+		return list
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, durationMs, rowCount int64
+		var sqlText, executedAt, plan, message string
+		if err := rows.Scan(&id, &sqlText, &executedAt, &durationMs, &rowCount, &plan, &message); err != nil {
+			fmt.Printf("读取查询历史失败: %v\n", err)
+			return list
+		}
+		list = append(list, map[string]interface{}{
+			"id":         id,
+			"sql":        sqlText,
+			"executedAt": executedAt,
+			"durationMs": durationMs,
+			"rowCount":   rowCount,
+			"plan":       plan,
+			"error":      message,
+		})
+	}
+	return list
+}
+
+// GetSlowestQueries 慢查询报告：按最长耗时排序的前 n 条执行成功的 SQL（相同 SQL 合并）
+// 返回 {sql, runs, maxMs, avgMs, lastRun, rowCount, plan, fullScan}，rowCount 和 plan 取自耗时最长的一次；
+// fullScan 为 true 表示计划中有不使用索引的全表扫描，通常可通过建立索引改善
+// wails:export GetSlowestQueries
+func (a *App) GetSlowestQueries(n int) []map[string]interface{} {
+	list := []map[string]interface{}{}
+	if a.writeDB() == nil {
+		return list
+	}
+	if n <= 0 {
+		n = 10
+	}
+	// SQLite 中与唯一的 MAX() 一起查询的普通列取自达到最大值的那一行，因此最近执行时间用子查询获取
+	rows, err := a.readDB().Query(`SELECT sql, COUNT(*), MAX(duration_ms), AVG(duration_ms),
+		(SELECT MAX(l.executed_at) FROM _app_query_history l WHERE l.sql = h.sql AND l.error = ''), row_count, plan
+		FROM _app_query_history h WHERE error = '' GROUP BY sql ORDER BY MAX(duration_ms) DESC LIMIT ?`, n)
+	if err != nil {
+		fmt.Printf("读取查询历史失败: %v\n", err)
+		return list
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var runs, maxMs, rowCount int64
+		var avgMs float64
+		var sqlText, lastRun, plan string
+		if err := rows.Scan(&sqlText, &runs, &maxMs, &avgMs, &lastRun, &rowCount, &plan); err != nil {
+			fmt.Printf("读取查询历史失败: %v\n", err)
+			return list
+		}
+		list = append(list, map[string]interface{}{
+			"sql":      sqlText,
+			"runs":     runs,
+			"maxMs":    maxMs,
+			"avgMs":    avgMs,
+			"lastRun":  lastRun,
+			"rowCount": rowCount,
+			"plan":     plan,
+			"fullScan": isFullScanPlan(plan),
+		})
+	}
+	return list
+}
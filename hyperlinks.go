@@ -0,0 +1,283 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// maxExportHyperlinks Excel 单个工作表允许的超链接数上限
+const maxExportHyperlinks = 65530
+
+// hyperlinkColumnSuffix 导入时保存单元格超链接地址的伴随列后缀
+const hyperlinkColumnSuffix = "_url"
+
+// appendHyperlinkColumns 读取 Sheet 数据行中的超链接，为含链接的列追加 <列名>_url 伴随列
+// rows 包含表头行；返回追加后的列名和数据行（不含表头）
+func appendHyperlinkColumns(f *excelize.File, filePath string, sheetName string, columns []string, rows [][]string) ([]string, [][]string) {
+	dataRows := rows[1:]
+	targets, err := readSheetHyperlinks(filePath, sheetName)
+	if err != nil {
+		fmt.Printf("读取 Sheet %s 的超链接失败，改为逐个单元格读取: %v\n", sheetName, err)
+	}
+	links := make(map[int][]string)
+	addLink := func(colIdx, rowIdx int, target string) {
+		if target == "" || colIdx >= len(columns) || rowIdx >= len(dataRows) {
+			return
+		}
+		if links[colIdx] == nil {
+			links[colIdx] = make([]string, len(dataRows))
+		}
+		if links[colIdx][rowIdx] == "" {
+			links[colIdx][rowIdx] = target
+		}
+	}
+	if err == nil {
+		// 一个超链接可以覆盖一个区域；与 Excel 一致，同一单元格取先出现的链接
+		for _, link := range targets {
+			fromCol, fromRow, toCol, toRow, ok := hyperlinkRange(link.ref)
+			if !ok {
+				continue
+			}
+			for row := max(fromRow, 2); row <= toRow && row-2 < len(dataRows); row++ {
+				for col := fromCol; col <= toCol && col <= len(columns); col++ {
+					addLink(col-1, row-2, link.target)
+				}
+			}
+		}
+	} else {
+		for rowIdx := range dataRows {
+			for colIdx := range columns {
+				cell, err := excelize.CoordinatesToCellName(colIdx+1, rowIdx+2)
+				if err != nil {
+					continue
+				}
+				if ok, target, err := f.GetCellHyperLink(sheetName, cell); err == nil && ok {
+					addLink(colIdx, rowIdx, target)
+				}
+			}
+		}
+	}
+	if len(links) == 0 {
+		return columns, dataRows
+	}
+
+	var linkCols []int
+	for colIdx := range columns {
+		if links[colIdx] != nil {
+			linkCols = append(linkCols, colIdx)
+		}
+	}
+	newColumns := append([]string{}, columns...)
+	for _, colIdx := range linkCols {
+		newColumns = append(newColumns, columns[colIdx]+hyperlinkColumnSuffix)
+	}
+	newRows := make([][]string, len(dataRows))
+	for rowIdx, row := range dataRows {
+		padded := make([]string, len(columns), len(newColumns))
+		copy(padded, row)
+		for _, colIdx := range linkCols {
+			padded = append(padded, links[colIdx][rowIdx])
+		}
+		newRows[rowIdx] = padded
+	}
+	return newColumns, newRows
+}
+
+// sheetHyperlink 工作表 <hyperlinks> 中的一个超链接：ref 为单元格或区域，target 为外部地址或工作簿内的位置
+type sheetHyperlink struct {
+	ref    string
+	target string
+}
+
+// readSheetHyperlinks 从 xlsx 包中一次读出工作表的全部超链接，避免逐个单元格调用 GetCellHyperLink
+// （每次调用都要遍历整张表的超链接列表）
+func readSheetHyperlinks(filePath string, sheetName string) ([]sheetHyperlink, error) {
+	zr, err := zip.OpenReader(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	files := make(map[string]*zip.File)
+	for _, zf := range zr.File {
+		files[strings.ToLower(zf.Name)] = zf
+	}
+	decode := func(name string, v interface{}) error {
+		zf, ok := files[strings.ToLower(name)]
+		if !ok {
+			return fmt.Errorf("缺少 %s", name)
+		}
+		rc, err := zf.Open()
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+		return xml.NewDecoder(rc).Decode(v)
+	}
+	type relationships struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+
+	// 工作表名 -> rId -> 工作表路径
+	var workbook struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+			ID   string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := decode("xl/workbook.xml", &workbook); err != nil {
+		return nil, err
+	}
+	var workbookRels relationships
+	if err := decode("xl/_rels/workbook.xml.rels", &workbookRels); err != nil {
+		return nil, err
+	}
+	var sheetPath string
+	for _, sheet := range workbook.Sheets {
+		if !strings.EqualFold(sheet.Name, sheetName) {
+			continue
+		}
+		for _, rel := range workbookRels.Relationships {
+			if rel.ID == sheet.ID {
+				if strings.HasPrefix(rel.Target, "/") {
+					sheetPath = strings.TrimPrefix(rel.Target, "/")
+				} else {
+					sheetPath = path.Join("xl", rel.Target)
+				}
+			}
+		}
+	}
+	if sheetPath == "" {
+		return nil, fmt.Errorf("找不到 Sheet %s", sheetName)
+	}
+
+	// <hyperlinks> 位于 <sheetData> 之后，逐个读取元素直到遇到它
+	zf, ok := files[strings.ToLower(sheetPath)]
+	if !ok {
+		return nil, fmt.Errorf("缺少 %s", sheetPath)
+	}
+	rc, err := zf.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	var hyperlinks struct {
+		Hyperlink []struct {
+			Ref      string `xml:"ref,attr"`
+			Location string `xml:"location,attr"`
+			RID      string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"hyperlink"`
+	}
+	decoder := xml.NewDecoder(rc)
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := tok.(xml.StartElement); ok && start.Name.Local == "hyperlinks" {
+			if err := decoder.DecodeElement(&hyperlinks, &start); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	if len(hyperlinks.Hyperlink) == 0 {
+		return nil, nil
+	}
+
+	// 外部链接的地址在工作表的关系文件中
+	sheetRels := make(map[string]string)
+	var rels relationships
+	relsPath := path.Join(path.Dir(sheetPath), "_rels", path.Base(sheetPath)+".rels")
+	if err := decode(relsPath, &rels); err == nil {
+		for _, rel := range rels.Relationships {
+			sheetRels[rel.ID] = rel.Target
+		}
+	}
+	links := make([]sheetHyperlink, 0, len(hyperlinks.Hyperlink))
+	for _, link := range hyperlinks.Hyperlink {
+		target := link.Location
+		if link.RID != "" {
+			target = sheetRels[link.RID]
+		}
+		links = append(links, sheetHyperlink{ref: link.Ref, target: target})
+	}
+	return links, nil
+}
+
+// hyperlinkRange 解析超链接的单元格或区域引用（如 B2、B2:D5），返回起止列号和行号
+func hyperlinkRange(ref string) (fromCol, fromRow, toCol, toRow int, ok bool) {
+	from, to, isRange := strings.Cut(ref, ":")
+	fromCol, fromRow, err := excelize.CellNameToCoordinates(from)
+	if err != nil {
+		return 0, 0, 0, 0, false
+	}
+	toCol, toRow = fromCol, fromRow
+	if isRange {
+		if toCol, toRow, err = excelize.CellNameToCoordinates(to); err != nil {
+			return 0, 0, 0, 0, false
+		}
+	}
+	if toCol < fromCol {
+		fromCol, toCol = toCol, fromCol
+	}
+	if toRow < fromRow {
+		fromRow, toRow = toRow, fromRow
+	}
+	return fromCol, fromRow, toCol, toRow, true
+}
+
+// isURLValue 判断导出值是否为可点击的网址
+func isURLValue(v interface{}) (string, bool) {
+	s, ok := v.(string)
+	if !ok {
+		return "", false
+	}
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://") || strings.HasPrefix(s, "mailto:") {
+		return s, !strings.ContainsAny(s, " \t\n")
+	}
+	return "", false
+}
+
+// hyperlinkWriter 导出时将网址写为超链接单元格，超过 Excel 上限后按普通文本写入
+type hyperlinkWriter struct {
+	f     *excelize.File
+	style int
+	count int
+}
+
+func newHyperlinkWriter(f *excelize.File) *hyperlinkWriter {
+	style, _ := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Color: "0563C1", Underline: "single"},
+	})
+	return &hyperlinkWriter{f: f, style: style}
+}
+
+// write 若值为网址则写入超链接并返回 true
+func (w *hyperlinkWriter) write(sheetName string, cell string, v interface{}) bool {
+	url, ok := isURLValue(v)
+	if !ok || w.count >= maxExportHyperlinks {
+		return false
+	}
+	w.f.SetCellValue(sheetName, cell, url)
+	if err := w.f.SetCellHyperLink(sheetName, cell, url, "External"); err != nil {
+		fmt.Printf("写入超链接 %s 失败: %v\n", cell, err)
+		return true
+	}
+	w.f.SetCellStyle(sheetName, cell, cell, w.style)
+	w.count++
+	return true
+}
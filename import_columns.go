@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// columnSelector 导入时选择列的一条规则，匹配表头或列序号（从 1 开始）
+type columnSelector struct {
+	spec     string
+	from, to int            // 序号范围，from > 0 时按序号选择
+	pattern  *regexp.Regexp // re: 前缀的正则
+	glob     string         // 含 * 或 ? 的通配符
+	name     string         // 表头名称（忽略大小写和全半角）
+}
+
+// parseColumnSelectors 解析列选择规则：3 或 3-10 为列序号（范围），re:^q\d+$ 为表头正则，
+// 含 * ? 的为表头通配符，其余为表头名称
+func parseColumnSelectors(specs []string) ([]columnSelector, error) {
+	var selectors []columnSelector
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		s := columnSelector{spec: spec}
+		switch {
+		case strings.HasPrefix(spec, "re:"):
+			re, err := regexp.Compile(strings.TrimPrefix(spec, "re:"))
+			if err != nil {
+				return nil, fmt.Errorf("列选择规则 %s 不是有效的正则表达式: %v", spec, err)
+			}
+			s.pattern = re
+		case strings.ContainsAny(spec, "*?"):
+			if _, err := path.Match(spec, ""); err != nil {
+				return nil, fmt.Errorf("列选择规则 %s 不是有效的通配符: %v", spec, err)
+			}
+			s.glob = foldKey(spec)
+		default:
+			from, to, isRange := strings.Cut(spec, "-")
+			a, errA := strconv.Atoi(strings.TrimSpace(from))
+			b, errB := a, error(nil)
+			if isRange {
+				b, errB = strconv.Atoi(strings.TrimSpace(to))
+			}
+			if errA == nil && errB == nil {
+				if a < 1 || b < a {
+					return nil, fmt.Errorf("列序号 %s 无效（从 1 开始）", spec)
+				}
+				s.from, s.to = a, b
+			} else {
+				s.name = foldKey(spec)
+			}
+		}
+		selectors = append(selectors, s)
+	}
+	if len(selectors) == 0 {
+		return nil, fmt.Errorf("请指定要导入的列")
+	}
+	return selectors, nil
+}
+
+// match 判断第 idx 列（从 0 开始）是否被选中
+func (s columnSelector) match(idx int, header string) bool {
+	header = strings.TrimSpace(header)
+	switch {
+	case s.from > 0:
+		return idx+1 >= s.from && idx+1 <= s.to
+	case s.pattern != nil:
+		return s.pattern.MatchString(header)
+	case s.glob != "":
+		ok, _ := path.Match(s.glob, foldKey(header))
+		return ok
+	default:
+		return s.name == foldKey(header)
+	}
+}
+
+// selectColumns 按规则从表头中选出要导入的列下标（保持原有顺序）
+func selectColumns(header []string, selectors []columnSelector) []int {
+	var picked []int
+	for i, h := range header {
+		for _, s := range selectors {
+			if s.match(i, h) {
+				picked = append(picked, i)
+				break
+			}
+		}
+	}
+	return picked
+}
+
+// ImportSelectedColumns 只导入宽表中选中的列（规则见 parseColumnSelectors），减小数据库体积、加快查询；
+// filePath 为空时弹出文件选择框；每个 Sheet 导入为 sheet1..N 表，逐行读取并丢弃未选中的列，
+// 保留的列按其在原表中的位置命名（如 column3、column17），便于与原文件对照；没有选中任何列的 Sheet 跳过
+// wails:export ImportSelectedColumns
+func (a *App) ImportSelectedColumns(filePath string, columns []string) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	selectors, err := parseColumnSelectors(columns)
+	if err != nil {
+		return err.Error()
+	}
+	filePath, err = a.chooseFile(filePath, "选择 Excel 文件", "*.xlsx", "Excel 文件")
+	if err != nil {
+		return fmt.Sprintf("文件选择失败: %v", err)
+	}
+	if filePath == "" {
+		return "未选择文件"
+	}
+
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		return fmt.Sprintf("Excel 解析失败: %v", err)
+	}
+	defer f.Close()
+
+	var summary, skipped []string
+	sheets := f.GetSheetList()
+	for sheetIdx, sheetName := range sheets {
+		rows, err := f.Rows(sheetName)
+		if err != nil {
+			return fmt.Sprintf("读取 Sheet %s 失败: %v", sheetName, err)
+		}
+		var picked []int
+		var header []string
+		var data [][]string
+		for rows.Next() {
+			cols, err := rows.Columns()
+			if err != nil {
+				rows.Close()
+				return fmt.Sprintf("读取 Sheet %s 失败: %v", sheetName, err)
+			}
+			if header == nil {
+				header = cols
+				picked = selectColumns(header, selectors)
+				if len(picked) == 0 {
+					break
+				}
+				continue
+			}
+			row := make([]string, len(picked))
+			for i, idx := range picked {
+				if idx < len(cols) {
+					row[i] = cols[idx]
+				}
+			}
+			data = append(data, row)
+		}
+		rows.Close()
+		if header == nil {
+			continue
+		}
+		if len(picked) == 0 {
+			skipped = append(skipped, sheetName)
+			continue
+		}
+
+		tableName := fmt.Sprintf("sheet%d", sheetIdx+1)
+		names := make([]string, len(picked))
+		for i, idx := range picked {
+			names[i] = fmt.Sprintf("column%d", idx+1)
+		}
+		if a.importSourceColumns() {
+			names, data = appendSourceColumns(names, data, filePath, sheetName, 2)
+		}
+		if err := a.writeTable(tableName, names, data); err != nil {
+			return err.Error()
+		}
+		a.recordTableSource(tableName, filePath+"/"+sheetName)
+		recorded := sourceHeader{Header: header, Columns: make(map[string]string, len(picked))}
+		for i, idx := range picked {
+			recorded.Columns[names[i]] = strings.TrimSpace(header[idx])
+		}
+		a.recordTableHeader(tableName, recorded)
+		summary = append(summary, fmt.Sprintf("%s（%d/%d 列，%d 行）", tableName, len(picked), len(header), len(data)))
+	}
+
+	if len(summary) == 0 {
+		return "导入失败：没有 Sheet 包含选中的列，请检查列选择规则"
+	}
+	message := fmt.Sprintf("成功导入 %d 个 Sheet：%s", len(summary), strings.Join(summary, "、"))
+	if len(skipped) > 0 {
+		message += fmt.Sprintf("；%s 没有选中的列，已跳过", strings.Join(skipped, "、"))
+	}
+	return message
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// fixedField 定长字段定义（start 从 0 开始，按字节计算）
+type fixedField struct {
+	start  int
+	length int
+}
+
+// ImportFixedWidth 导入定长文本文件（主机/银行导出的 .txt）
+// layout 格式为 "起始位置:长度"，逗号分隔，起始位置从 1 开始按字节计算；为空时根据空白列自动推断
+// wails:export ImportFixedWidth
+func (a *App) ImportFixedWidth(filePath string, tableName string, layout string, hasHeader bool) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+
+	filePath, err := a.chooseFile(filePath, "选择定长文本文件", "*.txt;*.dat", "定长文本文件")
+	if err != nil {
+		return fmt.Sprintf("文件选择失败: %v", err)
+	}
+	if filePath == "" {
+		return "未选择文件"
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Sprintf("读取文件失败: %v", err)
+	}
+
+	content = bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+	var lines [][]byte
+	for _, line := range bytes.Split(content, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) > 0 {
+			lines = append(lines, line)
+		}
+	}
+
+	// 自动推断时表头行也参与，表头通常能更清晰地划分列边界
+	var fields []fixedField
+	if strings.TrimSpace(layout) == "" {
+		fields = guessFixedLayout(lines)
+	} else {
+		fields, err = parseFixedLayout(layout)
+		if err != nil {
+			return err.Error()
+		}
+	}
+
+	if hasHeader && len(lines) > 0 {
+		lines = lines[1:]
+	}
+	if len(lines) == 0 {
+		return "导入失败：文件中没有数据行！"
+	}
+	if len(fields) == 0 {
+		return "导入失败：无法识别列布局，请手动指定！"
+	}
+
+	rows := make([][]string, len(lines))
+	for i, line := range lines {
+		row := make([]string, len(fields))
+		for j, fd := range fields {
+			row[j] = sliceFixedField(line, fd)
+		}
+		rows[i] = row
+	}
+
+	if tableName == "" {
+		tableName = tableNameFromFile(filePath)
+	}
+	if err := a.writeTable(tableName, defaultColumnNames(len(fields)), rows); err != nil {
+		return err.Error()
+	}
+	a.recordTableSource(tableName, filePath)
+
+	return fmt.Sprintf("成功导入定长文件到表 %s（共 %d 列，%d 行）", tableName, len(fields), len(rows))
+}
+
+// parseFixedLayout 解析 "起始位置:长度" 形式的列定义
+func parseFixedLayout(layout string) ([]fixedField, error) {
+	var fields []fixedField
+	for _, part := range strings.Split(layout, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		pair := strings.SplitN(part, ":", 2)
+		if len(pair) != 2 {
+			return nil, fmt.Errorf("列定义格式错误: %s（应为 起始位置:长度）", part)
+		}
+		start, err1 := strconv.Atoi(strings.TrimSpace(pair[0]))
+		length, err2 := strconv.Atoi(strings.TrimSpace(pair[1]))
+		if err1 != nil || err2 != nil || start < 1 || length < 1 {
+			return nil, fmt.Errorf("列定义格式错误: %s（起始位置和长度须为正整数）", part)
+		}
+		fields = append(fields, fixedField{start: start - 1, length: length})
+	}
+	return fields, nil
+}
+
+// guessFixedLayout 根据样本行中所有行都为空白的字节位置推断列边界
+func guessFixedLayout(lines [][]byte) []fixedField {
+	sample := lines
+	if len(sample) > 200 {
+		sample = sample[:200]
+	}
+
+	width := 0
+	for _, line := range sample {
+		if len(line) > width {
+			width = len(line)
+		}
+	}
+
+	// blank[i] 为 true 表示所有样本行在位置 i 都是空白（或行已结束）
+	blank := make([]bool, width)
+	for i := range blank {
+		blank[i] = true
+	}
+	for _, line := range sample {
+		for i, c := range line {
+			if c != ' ' && c != '\t' {
+				blank[i] = false
+			}
+		}
+	}
+
+	// 每段非空白区域的起点即为一列的起点，列一直延伸到下一列起点
+	var starts []int
+	for i := 0; i < width; i++ {
+		if !blank[i] && (i == 0 || blank[i-1]) {
+			starts = append(starts, i)
+		}
+	}
+
+	fields := make([]fixedField, len(starts))
+	for i, start := range starts {
+		end := width
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+		fields[i] = fixedField{start: start, length: end - start}
+	}
+	return fields
+}
+
+// sliceFixedField 截取字段并去除首尾空白，超出行长度的部分视为空
+func sliceFixedField(line []byte, fd fixedField) string {
+	if fd.start >= len(line) {
+		return ""
+	}
+	end := fd.start + fd.length
+	if end > len(line) {
+		end = len(line)
+	}
+	return strings.TrimSpace(decodeText(line[fd.start:end]))
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+// ImportHTMLTables 从网页 URL 或本地 HTML 文件中提取所有 <table> 导入数据库
+// 每个表格生成一张表 html1..N，首行视为表头不入库
+// wails:export ImportHTMLTables
+func (a *App) ImportHTMLTables(source string) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+
+	source = strings.TrimSpace(source)
+	source, err := a.chooseFile(source, "选择 HTML 文件", "*.html;*.htm", "HTML 文件")
+	if err != nil {
+		return fmt.Sprintf("文件选择失败: %v", err)
+	}
+	if source == "" {
+		return "未选择文件"
+	}
+
+	doc, err := loadHTML(source)
+	if err != nil {
+		return err.Error()
+	}
+
+	tables := extractHTMLTables(doc)
+	successCount := 0
+	for tableIdx, rows := range tables {
+		if len(rows) == 0 {
+			continue
+		}
+
+		colCount := 0
+		for _, row := range rows {
+			if len(row) > colCount {
+				colCount = len(row)
+			}
+		}
+
+		tableName := fmt.Sprintf("html%d", tableIdx+1)
+		if err := a.writeTable(tableName, defaultColumnNames(colCount), rows[1:]); err != nil {
+			return err.Error()
+		}
+		a.recordTableSource(tableName, source)
+		successCount++
+	}
+
+	if successCount == 0 {
+		return "导入失败：页面中未找到表格！"
+	}
+	return fmt.Sprintf("成功导入 %d 个表格到数据库（共 %d 个表格）", successCount, len(tables))
+}
+
+// loadHTML 读取并解析 HTML，http/https 开头的按 URL 下载，否则按本地文件读取
+func loadHTML(source string) (*html.Node, error) {
+	var reader io.Reader
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		client := &http.Client{Timeout: 30 * time.Second}
+		resp, err := client.Get(source)
+		if err != nil {
+			return nil, fmt.Errorf("下载网页失败: %v", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("下载网页失败: HTTP %d", resp.StatusCode)
+		}
+		// 按响应头/页面 meta 声明的编码转为 UTF-8
+		reader, err = charset.NewReader(resp.Body, resp.Header.Get("Content-Type"))
+		if err != nil {
+			return nil, fmt.Errorf("识别网页编码失败: %v", err)
+		}
+	} else {
+		f, err := os.Open(source)
+		if err != nil {
+			return nil, fmt.Errorf("读取文件失败: %v", err)
+		}
+		defer f.Close()
+		reader, err = charset.NewReader(f, "")
+		if err != nil {
+			return nil, fmt.Errorf("识别文件编码失败: %v", err)
+		}
+	}
+
+	doc, err := html.Parse(reader)
+	if err != nil {
+		return nil, fmt.Errorf("HTML 解析失败: %v", err)
+	}
+	return doc, nil
+}
+
+// extractHTMLTables 按文档顺序提取所有表格，嵌套表格单独作为一个表格
+func extractHTMLTables(doc *html.Node) [][][]string {
+	var tables [][][]string
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "table" {
+			tables = append(tables, extractHTMLRows(n))
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+	return tables
+}
+
+// extractHTMLRows 提取表格自身的行（不进入嵌套表格），colspan 展开为多个单元格
+func extractHTMLRows(table *html.Node) [][]string {
+	var rows [][]string
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			switch c.Data {
+			case "table":
+				// 嵌套表格由 extractHTMLTables 单独处理
+			case "tr":
+				var row []string
+				for cell := c.FirstChild; cell != nil; cell = cell.NextSibling {
+					if cell.Type != html.ElementNode || (cell.Data != "td" && cell.Data != "th") {
+						continue
+					}
+					row = append(row, htmlText(cell))
+					if span, err := strconv.Atoi(htmlAttr(cell, "colspan")); err == nil {
+						for i := 1; i < span; i++ {
+							row = append(row, "")
+						}
+					}
+				}
+				if len(row) > 0 {
+					rows = append(rows, row)
+				}
+			default:
+				walk(c)
+			}
+		}
+	}
+	walk(table)
+	return rows
+}
+
+// htmlText 获取节点下的文本内容（不含嵌套表格）并压缩连续空白
+func htmlText(n *html.Node) string {
+	var sb strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+		}
+		if n.Type == html.ElementNode && (n.Data == "br" || n.Data == "p" || n.Data == "div") {
+			sb.WriteString(" ")
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && c.Data == "table" {
+				continue
+			}
+			walk(c)
+		}
+	}
+	walk(n)
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+// htmlAttr 获取节点属性值，不存在时返回空字符串
+func htmlAttr(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,335 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// maxReportedMismatches 校验报告中最多列出的类型不匹配明细数
+const maxReportedMismatches = 20
+
+// coercionError 一处无法转换为目标列类型的值，row 为源数据中的行号（表头为第 1 行）
+type coercionError struct {
+	row    int
+	column string
+	value  string
+	err    error
+}
+
+func (e coercionError) String() string {
+	return fmt.Sprintf("第 %d 行 %s 列：值 %q %v", e.row, e.column, e.value, e.err)
+}
+
+// resolveColumnMapping 将源表头映射到目标列，返回每个目标列对应的源列下标
+// mapping 为 源表头 -> 目标列，目标列为空表示跳过该源列；mapping 为空时按列名或数据字典显示名自动匹配
+func resolveColumnMapping(header []string, targets []columnInfo, mapping map[string]string, labels map[string]dictionaryEntry) ([]int, []string) {
+	srcIdx := make([]int, len(targets))
+	for i := range srcIdx {
+		srcIdx[i] = -1
+	}
+	targetIdx := make(map[string]int, len(targets))
+	for i, t := range targets {
+		targetIdx[foldKey(t.name)] = i
+		if entry, ok := labels[t.name]; ok && entry.label != "" {
+			if _, exists := targetIdx[foldKey(entry.label)]; !exists {
+				targetIdx[foldKey(entry.label)] = i
+			}
+		}
+	}
+	headerIdx := make(map[string]int, len(header))
+	for i, h := range header {
+		if _, exists := headerIdx[strings.TrimSpace(h)]; !exists {
+			headerIdx[strings.TrimSpace(h)] = i
+		}
+	}
+
+	var problems []string
+	assign := func(src int, target int, srcName string) {
+		if prev := srcIdx[target]; prev >= 0 && prev != src {
+			problems = append(problems, fmt.Sprintf("目标列 %s 被多个源列映射（%s、%s）", targets[target].name, strings.TrimSpace(header[prev]), srcName))
+			return
+		}
+		srcIdx[target] = src
+	}
+
+	if len(mapping) == 0 {
+		for i, h := range header {
+			if t, ok := targetIdx[foldKey(strings.TrimSpace(h))]; ok && strings.TrimSpace(h) != "" {
+				assign(i, t, h)
+			}
+		}
+		return srcIdx, problems
+	}
+
+	for src, target := range mapping {
+		src, target = strings.TrimSpace(src), strings.TrimSpace(target)
+		if target == "" {
+			continue
+		}
+		i, ok := headerIdx[src]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("源数据中没有列 %s", src))
+			continue
+		}
+		t, ok := targetIdx[foldKey(target)]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("目标表中没有列 %s", target))
+			continue
+		}
+		assign(i, t, src)
+	}
+	return srcIdx, problems
+}
+
+// coerceRows 按映射把源数据行转换为目标列类型，返回可写入的行和无法转换的行
+// 文本列按导入设置做日期/中文数字规范化，与 writeTable 保持一致；整行为空的行被跳过
+func (a *App) coerceRows(rows [][]string, firstRowNum int, srcIdx []int, targets []columnInfo) ([][]interface{}, []rejectedRow) {
+	normalizeCN := a.setting("normalize_cn_numbers") == "true"
+	normalizeDates := a.setting("normalize_dates") == "true"
+	emptyAsNull := a.importEmptyAsNull()
+
+	var values [][]interface{}
+	var rejects []rejectedRow
+	for r, row := range rows {
+		if strings.TrimSpace(strings.Join(row, "")) == "" {
+			continue
+		}
+		record := make([]interface{}, 0, len(targets))
+		raw := make([]string, 0, len(targets))
+		var errs []coercionError
+		for t, src := range srcIdx {
+			if src < 0 {
+				continue
+			}
+			text := ""
+			if src < len(row) {
+				text = row[src]
+			}
+			raw = append(raw, text)
+			if columnAffinity(targets[t].declType) == "TEXT" && text != "" {
+				if normalizeDates {
+					text = normalizeDate(text)
+				}
+				if normalizeCN {
+					text = normalizeChineseNumber(text)
+				}
+			}
+			v, err := coerceValue(text, targets[t].declType, emptyAsNull)
+			if err != nil {
+				errs = append(errs, coercionError{row: firstRowNum + r, column: targets[t].name, value: text, err: err})
+				continue
+			}
+			record = append(record, v)
+		}
+		if len(errs) > 0 {
+			rejects = append(rejects, rejectedRow{row: firstRowNum + r, raw: raw, errors: errs})
+		} else {
+			values = append(values, record)
+		}
+	}
+	return values, rejects
+}
+
+// insertRows 在给定事务内批量插入已转换的行，op 不为 nil 时报告写入进度并响应取消
+func insertRows(tx *sql.Tx, tableName string, columns []string, values [][]interface{}, op *operation) error {
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = quoteIdent(col)
+	}
+	stmt, err := tx.Prepare(fmt.Sprintf(
+		"INSERT INTO %s (%s) VALUES (%s)",
+		quoteIdent(tableName),
+		strings.Join(quoted, ", "),
+		strings.TrimSuffix(strings.Repeat("?,", len(columns)), ","),
+	))
+	if err != nil {
+		return fmt.Errorf("预编译插入语句失败: %v", err)
+	}
+	defer stmt.Close()
+
+	for rowIdx, record := range values {
+		if err := op.canceled(); err != nil {
+			return err
+		}
+		if _, err := stmt.Exec(record...); err != nil {
+			return fmt.Errorf("插入第 %d 行数据失败: %v", rowIdx+1, err)
+		}
+		op.progress("write", rowIdx+1, len(values), fmt.Sprintf("已写入 %d/%d 行", rowIdx+1, len(values)))
+	}
+	return nil
+}
+
+// appendWithQuarantine 把可转换的行追加到目标表、无法转换的行写入隔离表；与 writeTable 一样拒绝只读模式、
+// 预先检查磁盘空间，追加过程记录在导入日志中并报告进度，数据库忙时重试
+func (a *App) appendWithQuarantine(tableName string, source string, columns []string, values [][]interface{}, rejects []rejectedRow) error {
+	if a.readOnly() {
+		return errors.New(readOnlyMessage)
+	}
+	if err := checkDiskSpace(filepath.Dir("./data.db"), estimateValuesSize(values)*importSpaceFactor); err != nil {
+		return err
+	}
+	journalID := a.beginImportJournal(tableName, len(values))
+	op := a.beginOperation("", "import", fmt.Sprintf("追加到表 %s", tableName))
+	err := withBusyRetry(func() error { return a.appendRows(tableName, source, columns, values, rejects, op) })
+	a.finishImportJournal(journalID, err)
+	if err == nil {
+		a.analyzeAfterImport(tableName, len(values))
+	}
+	op.finish(fmt.Sprintf("已追加 %d 行到表 %s", len(values), tableName), err)
+	return err
+}
+
+// appendRows appendWithQuarantine 的实际写入过程：在一个事务内追加到目标表并写入隔离表；
+// 后台优先级时先分批写入暂存表（见 throttle.go），最后的事务中整体追加，失败时目标表不变
+func (a *App) appendRows(tableName string, source string, columns []string, values [][]interface{}, rejects []rejectedRow, op *operation) error {
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = quoteIdent(col)
+	}
+	list := strings.Join(quoted, ", ")
+	background := a.backgroundImport()
+	staging := stagingTableName(tableName)
+	if background {
+		// 暂存表的列取目标表列的类型亲和性，写入的值与直接写入目标表时一致
+		createSQL := fmt.Sprintf("CREATE TABLE %s AS SELECT %s FROM %s WHERE 0", quoteIdent(staging), list, quoteIdent(tableName))
+		err := a.stageInBatches(staging, createSQL, len(values), func(tx *sql.Tx, start, end int) error {
+			return insertRows(tx, staging, columns, values[start:end], nil)
+		}, op)
+		if err != nil {
+			return err
+		}
+	}
+
+	tx, err := a.writeDB().Begin()
+	if err != nil {
+		if background {
+			a.dropStagingTable(staging)
+		}
+		return fmt.Errorf("开启事务失败: %v", err)
+	}
+	fail := func(err error) error {
+		tx.Rollback()
+		if background {
+			a.dropStagingTable(staging)
+		}
+		return err
+	}
+	if background {
+		if _, err := tx.Exec(fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s ORDER BY rowid",
+			quoteIdent(tableName), list, list, quoteIdent(staging))); err != nil {
+			return fail(fmt.Errorf("追加到表 %s 失败: %v", tableName, err))
+		}
+		if _, err := tx.Exec(fmt.Sprintf("DROP TABLE %s", quoteIdent(staging))); err != nil {
+			return fail(fmt.Errorf("删除暂存表 %s 失败: %v", staging, err))
+		}
+	} else if err := insertRows(tx, tableName, columns, values, op); err != nil {
+		return fail(err)
+	}
+	if err := quarantineRows(tx, tableName, source, columns, rejects); err != nil {
+		return fail(err)
+	}
+	if err := tx.Commit(); err != nil {
+		if background {
+			a.dropStagingTable(staging)
+		}
+		return fmt.Errorf("提交事务失败: %v", err)
+	}
+	return nil
+}
+
+// rejectReport 隔离结果摘要，列出前若干处不匹配明细
+func rejectReport(tableName string, rejects []rejectedRow) string {
+	if len(rejects) == 0 {
+		return ""
+	}
+	var lines []string
+	total := 0
+	for _, r := range rejects {
+		for _, e := range r.errors {
+			if total < maxReportedMismatches {
+				lines = append(lines, e.String())
+			}
+			total++
+		}
+	}
+	if total > maxReportedMismatches {
+		lines = append(lines, fmt.Sprintf("……（其余 %d 处省略）", total-maxReportedMismatches))
+	}
+	return fmt.Sprintf("%d 行存在 %d 处值与目标列类型不匹配，已写入隔离表 %s：\n%s",
+		len(rejects), total, rejectedTableName(tableName), strings.Join(lines, "\n"))
+}
+
+// ImportSheetInto 将工作簿中的一个 Sheet 追加到已有的表，首行视为表头
+// sheet 为空时取第一个 Sheet；columnMapping 为 表头 -> 目标列，为空时按列名或数据字典显示名自动匹配
+// 写入前先按目标列类型校验全部数据：可转换的行追加到目标表，无法转换的行写入 <表名>_rejected 隔离表并返回明细
+// wails:export ImportSheetInto
+func (a *App) ImportSheetInto(filePath string, sheet string, targetTable string, columnMapping map[string]string) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+
+	targets, err := tableColumnInfos(a.writeDB(), targetTable)
+	if err != nil {
+		return err.Error()
+	}
+
+	filePath, err = a.chooseFile(filePath, "选择 Excel 文件", "*.xlsx;*.xls", "Excel 文件")
+	if err != nil {
+		return fmt.Sprintf("文件选择失败: %v", err)
+	}
+	if filePath == "" {
+		return "未选择文件"
+	}
+
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		return fmt.Sprintf("Excel 解析失败: %v", err)
+	}
+	defer f.Close()
+
+	if sheet == "" {
+		sheet = f.GetSheetName(0)
+	}
+	rows, err := f.GetRows(sheet)
+	if err != nil {
+		return fmt.Sprintf("读取 Sheet %s 失败: %v", sheet, err)
+	}
+	if len(rows) < 2 {
+		return fmt.Sprintf("Sheet %s 没有可导入的数据", sheet)
+	}
+
+	labels, err := a.dictionaryEntries(targetTable)
+	if err != nil {
+		return err.Error()
+	}
+	srcIdx, problems := resolveColumnMapping(rows[0], targets, columnMapping, labels)
+	if len(problems) > 0 {
+		return "列映射有误：" + strings.Join(problems, "；")
+	}
+
+	var columns []string
+	for t, src := range srcIdx {
+		if src >= 0 {
+			columns = append(columns, targets[t].name)
+		}
+	}
+	if len(columns) == 0 {
+		return fmt.Sprintf("Sheet %s 的表头与表 %s 没有可匹配的列，请指定列映射", sheet, targetTable)
+	}
+
+	values, rejects := a.coerceRows(rows[1:], 2, srcIdx, targets)
+	if err := a.appendWithQuarantine(targetTable, filePath+"/"+sheet, columns, values, rejects); err != nil {
+		return err.Error()
+	}
+
+	message := fmt.Sprintf("成功将 Sheet %s 的 %d 行追加到表 %s（映射 %d 列）", sheet, len(values), targetTable, len(columns))
+	if report := rejectReport(targetTable, rejects); report != "" {
+		message += "\n" + report
+	}
+	return message
+}
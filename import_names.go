@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ImportNamedRanges 将工作簿中定义的名称（命名区域）分别导入为表，名称即表名，区域首行视为表头
+// 公式型名称、多区域名称和内置名称（打印区域等）会被跳过
+// wails:export ImportNamedRanges
+func (a *App) ImportNamedRanges(filePath string) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+
+	filePath, err := a.chooseFile(filePath, "选择 Excel 文件", "*.xlsx;*.xls", "Excel 文件")
+	if err != nil {
+		return fmt.Sprintf("文件选择失败: %v", err)
+	}
+	if filePath == "" {
+		return "未选择文件"
+	}
+
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		return fmt.Sprintf("Excel 解析失败: %v", err)
+	}
+	defer f.Close()
+
+	names := f.GetDefinedName()
+	sheetRows := make(map[string][][]string)
+	var imported, skipped []string
+	for _, dn := range names {
+		if strings.HasPrefix(dn.Name, "_xlnm.") {
+			continue
+		}
+		sheetName, x1, y1, x2, y2, ok := parseRangeRef(dn.RefersTo)
+		if !ok {
+			skipped = append(skipped, dn.Name)
+			continue
+		}
+
+		rows, cached := sheetRows[sheetName]
+		if !cached {
+			rows, err = f.GetRows(sheetName)
+			if err != nil {
+				skipped = append(skipped, dn.Name)
+				continue
+			}
+			sheetRows[sheetName] = rows
+		}
+
+		// 截取区域内的单元格（坐标从 1 开始）
+		var block [][]string
+		for r := y1; r <= y2; r++ {
+			line := make([]string, x2-x1+1)
+			if r-1 < len(rows) {
+				for c := x1; c <= x2; c++ {
+					if c-1 < len(rows[r-1]) {
+						line[c-x1] = rows[r-1][c-1]
+					}
+				}
+			}
+			block = append(block, line)
+		}
+
+		tableName := sanitizeName(dn.Name)
+		if dn.Scope != "" && dn.Scope != "Workbook" {
+			tableName = sanitizeName(dn.Scope + "_" + dn.Name)
+		}
+		if err := a.writeTable(tableName, defaultColumnNames(x2-x1+1), block[1:]); err != nil {
+			return err.Error()
+		}
+		a.recordTableSource(tableName, filePath+"/"+dn.Name)
+		imported = append(imported, tableName)
+	}
+
+	if len(imported) == 0 {
+		if len(skipped) > 0 {
+			return fmt.Sprintf("导入失败：名称 %s 不是单一的单元格区域", strings.Join(skipped, "、"))
+		}
+		return "导入失败：工作簿中没有定义名称！"
+	}
+	message := fmt.Sprintf("成功导入 %d 个命名区域：%s", len(imported), strings.Join(imported, "、"))
+	if len(skipped) > 0 {
+		message += fmt.Sprintf("（跳过 %d 个非区域名称：%s）", len(skipped), strings.Join(skipped, "、"))
+	}
+	return message
+}
+
+// parseRangeRef 解析 Sheet1!$A$1:$C$10 或 'My Sheet'!A1 形式的区域引用，返回 Sheet 名及起止坐标
+func parseRangeRef(ref string) (sheet string, x1, y1, x2, y2 int, ok bool) {
+	ref = strings.TrimPrefix(strings.TrimSpace(ref), "=")
+	idx := strings.LastIndex(ref, "!")
+	if idx <= 0 || strings.ContainsAny(ref[idx+1:], ",()#") {
+		return "", 0, 0, 0, 0, false
+	}
+	sheet = ref[:idx]
+	if strings.HasPrefix(sheet, "'") && strings.HasSuffix(sheet, "'") && len(sheet) >= 2 {
+		sheet = strings.ReplaceAll(sheet[1:len(sheet)-1], "''", "'")
+	}
+
+	cells := strings.Split(strings.ReplaceAll(ref[idx+1:], "$", ""), ":")
+	if len(cells) > 2 {
+		return "", 0, 0, 0, 0, false
+	}
+	var err error
+	x1, y1, err = excelize.CellNameToCoordinates(cells[0])
+	if err != nil {
+		return "", 0, 0, 0, 0, false
+	}
+	x2, y2 = x1, y1
+	if len(cells) == 2 {
+		x2, y2, err = excelize.CellNameToCoordinates(cells[1])
+		if err != nil {
+			return "", 0, 0, 0, 0, false
+		}
+	}
+	if x1 > x2 {
+		x1, x2 = x2, x1
+	}
+	if y1 > y2 {
+		y1, y2 = y2, y1
+	}
+	return sheet, x1, y1, x2, y2, true
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PreviewPDFTable 预览 PDF 中识别出的表格（不写入数据库）
+// pages 为页码范围，如 "1-3,5"，为空表示全部页面
+// region 为页面区域 "x0,y0,x1,y1"（单位为点，原点在页面左下角），为空表示整页
+// wails:export PreviewPDFTable
+func (a *App) PreviewPDFTable(filePath string, pages string, region string) map[string]interface{} {
+	result := make(map[string]interface{})
+
+	filePath, err := a.chooseFile(filePath, "选择 PDF 文件", "*.pdf", "PDF 文件")
+	if err != nil {
+		result["error"] = fmt.Sprintf("文件选择失败: %v", err)
+		return result
+	}
+	if filePath == "" {
+		result["error"] = "未选择文件"
+		return result
+	}
+
+	rows, pageCount, err := extractPDFTable(filePath, pages, region)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+
+	colCount := 0
+	for _, row := range rows {
+		if len(row) > colCount {
+			colCount = len(row)
+		}
+	}
+	columns := defaultColumnNames(colCount)
+
+	preview := rows
+	if limit := a.settingInt("pdf_preview_rows"); len(preview) > limit {
+		preview = preview[:limit]
+	}
+	var data []map[string]interface{}
+	for _, row := range preview {
+		item := make(map[string]interface{})
+		for i, col := range columns {
+			if i < len(row) {
+				item[col] = row[i]
+			} else {
+				item[col] = ""
+			}
+		}
+		data = append(data, item)
+	}
+
+	result["filePath"] = filePath
+	result["columns"] = columns
+	result["data"] = data
+	result["total"] = len(rows)
+	result["pageCount"] = pageCount
+	result["message"] = fmt.Sprintf("识别到 %d 行 %d 列（PDF 共 %d 页），预览前 %d 行", len(rows), colCount, pageCount, len(preview))
+	return result
+}
+
+// ImportPDFTable 将 PDF 中识别出的表格导入数据库，参数含义同 PreviewPDFTable
+// wails:export ImportPDFTable
+func (a *App) ImportPDFTable(filePath string, pages string, region string, tableName string, hasHeader bool) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+
+	filePath, err := a.chooseFile(filePath, "选择 PDF 文件", "*.pdf", "PDF 文件")
+	if err != nil {
+		return fmt.Sprintf("文件选择失败: %v", err)
+	}
+	if filePath == "" {
+		return "未选择文件"
+	}
+
+	rows, _, err := extractPDFTable(filePath, pages, region)
+	if err != nil {
+		return err.Error()
+	}
+	if hasHeader && len(rows) > 0 {
+		rows = rows[1:]
+	}
+	if len(rows) == 0 {
+		return "导入失败：所选范围内未识别到表格数据！"
+	}
+
+	colCount := 0
+	for _, row := range rows {
+		if len(row) > colCount {
+			colCount = len(row)
+		}
+	}
+
+	if tableName == "" {
+		tableName = tableNameFromFile(filePath)
+	}
+	if err := a.writeTable(tableName, defaultColumnNames(colCount), rows); err != nil {
+		return err.Error()
+	}
+	a.recordTableSource(tableName, filePath)
+	return fmt.Sprintf("成功导入 PDF 表格到表 %s（共 %d 列，%d 行）", tableName, colCount, len(rows))
+}
+
+// extractPDFTable 提取所选页面/区域内的文本并按坐标还原为行列
+func extractPDFTable(filePath string, pages string, region string) ([][]string, int, error) {
+	doc, err := openPDF(filePath)
+	if err != nil {
+		return nil, 0, err
+	}
+	allPages := doc.pages()
+	if len(allPages) == 0 {
+		return nil, 0, fmt.Errorf("PDF 解析失败：未找到页面")
+	}
+
+	selected, err := parsePageRange(pages, len(allPages))
+	if err != nil {
+		return nil, len(allPages), err
+	}
+	bounds, err := parsePDFRegion(region)
+	if err != nil {
+		return nil, len(allPages), err
+	}
+
+	var rows [][]string
+	for _, pageNum := range selected {
+		var runs []pdfTextRun
+		for _, run := range doc.textRuns(allPages[pageNum-1]) {
+			if strings.TrimSpace(run.text) == "" {
+				continue
+			}
+			if bounds != nil && (run.x < bounds[0] || run.y < bounds[1] || run.x > bounds[2] || run.y > bounds[3]) {
+				continue
+			}
+			runs = append(runs, run)
+		}
+		rows = append(rows, layoutPDFTable(runs)...)
+	}
+	return rows, len(allPages), nil
+}
+
+// parsePageRange 解析 "1-3,5" 形式的页码范围，为空时返回全部页码
+func parsePageRange(spec string, pageCount int) ([]int, error) {
+	var pages []int
+	if strings.TrimSpace(spec) == "" {
+		for i := 1; i <= pageCount; i++ {
+			pages = append(pages, i)
+		}
+		return pages, nil
+	}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		lo, hi := part, part
+		if idx := strings.Index(part, "-"); idx >= 0 {
+			lo, hi = part[:idx], part[idx+1:]
+		}
+		from, err1 := strconv.Atoi(strings.TrimSpace(lo))
+		to, err2 := strconv.Atoi(strings.TrimSpace(hi))
+		if err1 != nil || err2 != nil || from < 1 || to < from {
+			return nil, fmt.Errorf("页码范围格式错误: %s", part)
+		}
+		if to > pageCount {
+			return nil, fmt.Errorf("页码 %d 超出范围（PDF 共 %d 页）", to, pageCount)
+		}
+		for i := from; i <= to; i++ {
+			pages = append(pages, i)
+		}
+	}
+	return pages, nil
+}
+
+// parsePDFRegion 解析 "x0,y0,x1,y1" 形式的区域，为空时返回 nil
+func parsePDFRegion(spec string) ([]float64, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+	parts := strings.Split(spec, ",")
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("区域格式错误: %s（应为 x0,y0,x1,y1）", spec)
+	}
+	bounds := make([]float64, 4)
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, fmt.Errorf("区域格式错误: %s（应为 x0,y0,x1,y1）", spec)
+		}
+		bounds[i] = v
+	}
+	if bounds[0] > bounds[2] {
+		bounds[0], bounds[2] = bounds[2], bounds[0]
+	}
+	if bounds[1] > bounds[3] {
+		bounds[1], bounds[3] = bounds[3], bounds[1]
+	}
+	return bounds, nil
+}
+
+// pdfCell 同一行内合并后的单元格
+type pdfCell struct {
+	x, endX float64
+	text    string
+}
+
+// layoutPDFTable 将文本片段按纵坐标聚成行、按间距切分单元格，再以单元格最多的行为模板对齐列
+func layoutPDFTable(runs []pdfTextRun) [][]string {
+	if len(runs) == 0 {
+		return nil
+	}
+
+	// 从上到下、从左到右排序
+	sort.SliceStable(runs, func(i, j int) bool {
+		if math.Abs(runs[i].y-runs[j].y) > 0.5 {
+			return runs[i].y > runs[j].y
+		}
+		return runs[i].x < runs[j].x
+	})
+
+	var lines [][]pdfTextRun
+	for _, run := range runs {
+		n := len(lines)
+		if n > 0 {
+			last := lines[n-1][0]
+			tolerance := math.Max(last.size, run.size) * 0.5
+			if math.Abs(last.y-run.y) <= tolerance {
+				lines[n-1] = append(lines[n-1], run)
+				continue
+			}
+		}
+		lines = append(lines, []pdfTextRun{run})
+	}
+
+	var cellRows [][]pdfCell
+	for _, line := range lines {
+		sort.SliceStable(line, func(i, j int) bool { return line[i].x < line[j].x })
+		var cells []pdfCell
+		for _, run := range line {
+			size := math.Max(run.size, 1)
+			if n := len(cells); n > 0 {
+				gap := run.x - cells[n-1].endX
+				if gap < size*0.8 {
+					if gap > size*0.15 {
+						cells[n-1].text += " "
+					}
+					cells[n-1].text += run.text
+					cells[n-1].endX = math.Max(cells[n-1].endX, run.endX)
+					continue
+				}
+			}
+			cells = append(cells, pdfCell{x: run.x, endX: run.endX, text: run.text})
+		}
+		cellRows = append(cellRows, cells)
+	}
+
+	// 以单元格最多的行作为列模板，列边界取相邻单元格之间的中点
+	template := cellRows[0]
+	for _, cells := range cellRows {
+		if len(cells) > len(template) {
+			template = cells
+		}
+	}
+	boundaries := make([]float64, 0, len(template))
+	for i := 0; i+1 < len(template); i++ {
+		boundaries = append(boundaries, (template[i].endX+template[i+1].x)/2)
+	}
+
+	rows := make([][]string, 0, len(cellRows))
+	for _, cells := range cellRows {
+		row := make([]string, len(template))
+		for _, cell := range cells {
+			center := (cell.x + cell.endX) / 2
+			col := sort.SearchFloat64s(boundaries, center)
+			if row[col] != "" {
+				row[col] += " "
+			}
+			row[col] += strings.TrimSpace(cell.text)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
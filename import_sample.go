@@ -0,0 +1,191 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// samplingPlan 抽样导入的方式：every > 0 时每 every 行取 1 行，否则每行以 fraction 的概率入选
+type samplingPlan struct {
+	every    int
+	fraction float64
+	seed     int64
+	rng      *rand.Rand
+}
+
+// parseSamplingPlan 解析 options.every（每 N 行取 1 行）或 options.fraction（0~1 之间的随机比例，可配合 options.seed 复现同一份样本）
+func parseSamplingPlan(options map[string]interface{}) (*samplingPlan, error) {
+	p := &samplingPlan{}
+	if every, ok := options["every"].(float64); ok && every > 0 {
+		if every < 2 || every != float64(int(every)) {
+			return nil, fmt.Errorf("every 必须是大于等于 2 的整数")
+		}
+		p.every = int(every)
+		return p, nil
+	}
+	fraction, _ := options["fraction"].(float64)
+	if fraction <= 0 || fraction >= 1 {
+		return nil, fmt.Errorf("请指定 every（每 N 行取 1 行）或 0~1 之间的 fraction（随机比例）")
+	}
+	p.fraction = fraction
+	p.seed = time.Now().UnixNano()
+	if seed, ok := options["seed"].(float64); ok {
+		p.seed = int64(seed)
+	}
+	p.rng = rand.New(rand.NewSource(p.seed))
+	return p, nil
+}
+
+// keep 判断第 i 个数据行（从 0 开始）是否入选
+func (p *samplingPlan) keep(i int) bool {
+	if p.every > 0 {
+		return i%p.every == 0
+	}
+	return p.rng.Float64() < p.fraction
+}
+
+// String 抽样方式的说明，记录在导入来源中
+func (p *samplingPlan) String() string {
+	if p.every > 0 {
+		return fmt.Sprintf("每 %d 行取 1 行", p.every)
+	}
+	return fmt.Sprintf("随机抽取 %g%%（种子 %d）", p.fraction*100, p.seed)
+}
+
+// sampledSheet 一个 Sheet（或 CSV 文件）的抽样结果
+type sampledSheet struct {
+	header []string
+	rows   [][]string
+	total  int
+}
+
+// sampleExcelSheet 逐行读取 Sheet 并抽样，不把整个 Sheet 读入内存
+func sampleExcelSheet(f *excelize.File, sheetName string, plan *samplingPlan) (*sampledSheet, error) {
+	rows, err := f.Rows(sheetName)
+	if err != nil {
+		return nil, fmt.Errorf("读取 Sheet %s 失败: %v", sheetName, err)
+	}
+	defer rows.Close()
+	s := &sampledSheet{}
+	for i := -1; rows.Next(); i++ {
+		cols, err := rows.Columns()
+		if err != nil {
+			return nil, fmt.Errorf("读取 Sheet %s 失败: %v", sheetName, err)
+		}
+		if i < 0 {
+			s.header = cols
+			continue
+		}
+		s.total++
+		if plan.keep(i) {
+			s.rows = append(s.rows, cols)
+		}
+	}
+	return s, rows.Error()
+}
+
+// sampleCSVFile 逐行读取 CSV 文件并抽样（编码和分隔符自动判断，见 sniffCSV）
+func sampleCSVFile(path string, plan *samplingPlan) (*sampledSheet, error) {
+	src := csvSource{path: path}
+	var err error
+	if src.encoding, src.delimiter, err = sniffCSV(path); err != nil {
+		return nil, err
+	}
+	file, reader, err := src.open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	reader.ReuseRecord = false
+	s := &sampledSheet{}
+	for i := -1; ; i++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("读取 %s 第 %d 行失败: %v", path, i+2, err)
+		}
+		if i < 0 {
+			s.header = record
+			continue
+		}
+		s.total++
+		if plan.keep(i) {
+			s.rows = append(s.rows, record)
+		}
+	}
+	return s, nil
+}
+
+// ImportSampled 抽样导入超大文件，用于先快速了解数据：options.every 为每 N 行取 1 行，
+// options.fraction 为随机抽取的比例（0~1，options.seed 可复现同一份样本）；
+// xlsx 的每个 Sheet 导入为 sheet1..N 表，CSV 按文件名建表，列名与普通导入相同；抽样方式记录在表的导入来源中
+// wails:export ImportSampled
+func (a *App) ImportSampled(filePath string, options map[string]interface{}) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	plan, err := parseSamplingPlan(options)
+	if err != nil {
+		return err.Error()
+	}
+	filePath, err = a.chooseFile(filePath, "选择要抽样导入的文件", "*.xlsx;*.csv;*.tsv;*.txt", "Excel 或 CSV 文件")
+	if err != nil {
+		return fmt.Sprintf("文件选择失败: %v", err)
+	}
+	if filePath == "" {
+		return "未选择文件"
+	}
+
+	var summary []string
+	write := func(tableName string, source string, s *sampledSheet) error {
+		if err := a.writeTable(tableName, defaultColumnNames(len(s.header)), s.rows); err != nil {
+			return err
+		}
+		a.recordTableSource(tableName, source)
+		a.recordTableHeader(tableName, positionalHeader(s.header))
+		a.recordTableSampling(tableName, plan.String())
+		summary = append(summary, fmt.Sprintf("%s（%d 行中取 %d 行）", tableName, s.total, len(s.rows)))
+		return nil
+	}
+
+	if !strings.EqualFold(filepath.Ext(filePath), ".xlsx") {
+		s, err := sampleCSVFile(filePath, plan)
+		if err != nil {
+			return err.Error()
+		}
+		if len(s.header) == 0 {
+			return "导入失败：文件中没有数据行！"
+		}
+		if err := write(tableNameFromFile(filePath), filePath, s); err != nil {
+			return err.Error()
+		}
+		return fmt.Sprintf("抽样导入完成（%s）：%s", plan, strings.Join(summary, "、"))
+	}
+
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		return fmt.Sprintf("Excel 解析失败: %v", err)
+	}
+	defer f.Close()
+	for sheetIdx, sheetName := range f.GetSheetList() {
+		s, err := sampleExcelSheet(f, sheetName, plan)
+		if err != nil {
+			return err.Error()
+		}
+		if len(s.header) == 0 {
+			continue
+		}
+		if err := write(fmt.Sprintf("sheet%d", sheetIdx+1), filePath+"/"+sheetName, s); err != nil {
+			return err.Error()
+		}
+	}
+	return fmt.Sprintf("抽样导入完成（%s）：%s", plan, strings.Join(summary, "、"))
+}
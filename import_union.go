@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"github.com/xuri/excelize/v2"
+)
+
+// unionSourceColumn 合并导入时记录来源（文件名/Sheet 名）的列名
+const unionSourceColumn = "_source"
+
+// unionGroup 表头相同的一组 Sheet
+type unionGroup struct {
+	header  []string
+	sources []string
+	rows    [][]string
+}
+
+// ImportUnion 合并导入：表头相同的 Sheet（可跨文件）合并为一张表，并增加 _source 列记录来源
+// filePaths 为空时弹出多选文件框；表头不同的 Sheet 分别成表，表名为 tableName、tableName_2……
+// wails:export ImportUnion
+func (a *App) ImportUnion(filePaths []string, tableName string) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+
+	if len(filePaths) == 0 {
+		var err error
+		filePaths, err = runtime.OpenMultipleFilesDialog(a.ctx, runtime.OpenDialogOptions{
+			Title:   "选择要合并的 Excel 文件",
+			Filters: []runtime.FileFilter{{Pattern: "*.xlsx;*.xls", DisplayName: "Excel 文件"}},
+		})
+		if err != nil {
+			return fmt.Sprintf("文件选择失败: %v", err)
+		}
+		if len(filePaths) == 0 {
+			return "未选择文件"
+		}
+	}
+
+	var groups []*unionGroup
+	sheetCount := 0
+	for _, filePath := range filePaths {
+		f, err := excelize.OpenFile(filePath)
+		if err != nil {
+			return fmt.Sprintf("Excel 解析失败（%s）: %v", filepath.Base(filePath), err)
+		}
+		for _, sheetName := range f.GetSheetList() {
+			rows, err := f.GetRows(sheetName)
+			if err != nil {
+				f.Close()
+				return fmt.Sprintf("读取 Sheet %s 失败: %v", sheetName, err)
+			}
+			if len(rows) == 0 {
+				continue
+			}
+			sheetCount++
+
+			header := normalizeHeader(rows[0])
+			source := filepath.Base(filePath) + "/" + sheetName
+			group := findUnionGroup(groups, header)
+			if group == nil {
+				group = &unionGroup{header: header}
+				groups = append(groups, group)
+			}
+			group.sources = append(group.sources, source)
+			for _, row := range rows[1:] {
+				padded := make([]string, len(header)+1)
+				copy(padded, row)
+				padded[len(header)] = source
+				group.rows = append(group.rows, padded)
+			}
+		}
+		f.Close()
+	}
+	if len(groups) == 0 {
+		return "导入失败：所选文件中没有数据！"
+	}
+
+	if tableName == "" {
+		tableName = "union"
+	}
+	var summary []string
+	for i, group := range groups {
+		name := tableName
+		if i > 0 {
+			name = fmt.Sprintf("%s_%d", tableName, i+1)
+		}
+		columns := append(defaultColumnNames(len(group.header)), unionSourceColumn)
+		if err := a.writeTable(name, columns, group.rows); err != nil {
+			return err.Error()
+		}
+		a.recordTableSource(name, strings.Join(group.sources, "; "))
+		summary = append(summary, fmt.Sprintf("%s（%d 个 Sheet，%d 行）", name, len(group.sources), len(group.rows)))
+	}
+
+	return fmt.Sprintf("合并导入完成：%d 个 Sheet 合并为 %d 张表：%s", sheetCount, len(groups), strings.Join(summary, "、"))
+}
+
+// normalizeHeader 去除表头单元格首尾空白及末尾的空列，用于比较表头是否相同
+func normalizeHeader(header []string) []string {
+	normalized := make([]string, len(header))
+	for i, h := range header {
+		normalized[i] = strings.TrimSpace(h)
+	}
+	for len(normalized) > 0 && normalized[len(normalized)-1] == "" {
+		normalized = normalized[:len(normalized)-1]
+	}
+	return normalized
+}
+
+// findUnionGroup 查找表头完全相同的分组
+func findUnionGroup(groups []*unionGroup, header []string) *unionGroup {
+	for _, group := range groups {
+		if strings.Join(group.header, "\x00") == strings.Join(header, "\x00") {
+			return group
+		}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// 导入日志状态
+const (
+	importRunning     = "running"
+	importDone        = "done"
+	importFailed      = "failed"
+	importInterrupted = "interrupted" // 应用在导入过程中退出（崩溃、强制关闭）
+	importDiscarded   = "discarded"   // 已删除未完成的表
+	importKept        = "kept"        // 用户确认保留现有数据
+)
+
+// beginImportJournal 在写表前记录一条 running 日志，返回日志 ID（记录失败时返回 0，不影响导入）
+func (a *App) beginImportJournal(tableName string, expectedRows int) int64 {
+	res, err := a.writeDB().Exec("INSERT INTO _app_import_journal (table_name, state, expected_rows, started_at) VALUES (?, ?, ?, ?)",
+		tableName, importRunning, expectedRows, time.Now().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		fmt.Printf("记录导入日志失败: %v\n", err)
+		return 0
+	}
+	id, _ := res.LastInsertId()
+	return id
+}
+
+// finishImportJournal 写表结束后更新日志状态
+func (a *App) finishImportJournal(id int64, importErr error) {
+	if id == 0 {
+		return
+	}
+	state, message := importDone, ""
+	if importErr != nil {
+		state, message = importFailed, importErr.Error()
+	}
+	if _, err := a.writeDB().Exec("UPDATE _app_import_journal SET state = ?, finished_at = ?, error = ? WHERE id = ?",
+		state, time.Now().Format("2006-01-02 15:04:05"), message, id); err != nil {
+		fmt.Printf("更新导入日志失败: %v\n", err)
+	}
+}
+
+// markInterruptedImports 启动时将上次运行遗留的 running 日志标记为 interrupted（单写者锁保证此时没有其他实例在导入）
+func (a *App) markInterruptedImports() {
+	res, err := a.writeDB().Exec("UPDATE _app_import_journal SET state = ?, error = ? WHERE state = ?",
+		importInterrupted, "导入过程中应用退出", importRunning)
+	if err != nil {
+		fmt.Printf("检查导入日志失败: %v\n", err)
+		return
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		fmt.Printf("发现 %d 个未完成的导入，可在导入日志中清理或保留\n", n)
+	}
+}
+
+// ListUnfinishedImports 列出中断或失败、尚未处理的导入：{id, table, state, expectedRows, currentRows, tableExists, source, startedAt, error}
+// 表可能只有部分数据甚至为空；currentRows 为表当前的行数，source 为该表最近一次成功导入的来源，可据此重新导入
+// wails:export ListUnfinishedImports
+func (a *App) ListUnfinishedImports() []map[string]interface{} {
+	list := []map[string]interface{}{}
+	if a.writeDB() == nil {
+		return list
+	}
+	rows, err := a.readDB().Query(`SELECT j.id, j.table_name, j.state, j.expected_rows, j.started_at, j.error, COALESCE(s.source, '')
+		FROM _app_import_journal j LEFT JOIN _app_table_sources s ON s.table_name = j.table_name
+		WHERE j.state IN (?, ?) ORDER BY j.id DESC`, importInterrupted, importFailed)
+	if err != nil {
+		fmt.Printf("读取导入日志失败: %v\n", err)
+		return list
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, expected int64
+		var table, state, startedAt, message, source string
+		if err := rows.Scan(&id, &table, &state, &expected, &startedAt, &message, &source); err != nil {
+			fmt.Printf("读取导入日志失败: %v\n", err)
+			return list
+		}
+		list = append(list, map[string]interface{}{
+			"id":           id,
+			"table":        table,
+			"state":        state,
+			"expectedRows": expected,
+			"startedAt":    startedAt,
+			"error":        message,
+			"source":       source,
+		})
+	}
+	rows.Close()
+
+	for _, item := range list {
+		var count int64
+		err := a.readDB().QueryRow("SELECT COUNT(*) FROM " + quoteIdent(item["table"].(string))).Scan(&count)
+		item["tableExists"] = err == nil
+		item["currentRows"] = count
+	}
+	return list
+}
+
+// ResolveUnfinishedImport 处理一条未完成的导入：action 为 drop 时删除不完整的表，为 keep 时保留现有数据
+// 需要继续导入时，先 drop 再从原文件重新导入即可
+// wails:export ResolveUnfinishedImport
+func (a *App) ResolveUnfinishedImport(id int, action string) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	var table, state string
+	if err := a.readDB().QueryRow("SELECT table_name, state FROM _app_import_journal WHERE id = ?", id).Scan(&table, &state); err != nil {
+		return fmt.Sprintf("导入日志 %d 不存在", id)
+	}
+	if state != importInterrupted && state != importFailed {
+		return fmt.Sprintf("导入日志 %d 的状态为 %s，无需处理", id, state)
+	}
+
+	var newState, message string
+	switch action {
+	case "drop":
+		if err := dropTableOrView(a.writeDB(), table); err != nil {
+			return fmt.Sprintf("删除表 %s 失败: %v", table, err)
+		}
+		a.cache.clear()
+		newState, message = importDiscarded, fmt.Sprintf("已删除未完成导入的表 %s", table)
+	case "keep":
+		newState, message = importKept, fmt.Sprintf("已保留表 %s 的现有数据", table)
+	default:
+		return fmt.Sprintf("不支持的操作: %s（可选 drop、keep）", action)
+	}
+	if _, err := a.writeDB().Exec("UPDATE _app_import_journal SET state = ? WHERE id = ?", newState, id); err != nil {
+		return fmt.Sprintf("更新导入日志失败: %v", err)
+	}
+	return message
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// instanceLockPath 单写者锁文件：借助 SQLite 的排他锁模式加锁，进程退出（包括崩溃）后由操作系统自动释放，
+// 不会留下需要手动清理的残留锁
+const instanceLockPath = "./data.db.lock"
+
+// readOnlyMessage 只读模式下拒绝修改时的提示
+const readOnlyMessage = "当前为只读模式，无法修改数据（另一个实例正在使用 data.db 时请关闭其他实例后重启应用；查看只读分享包时请先关闭分享包）"
+
+// errInstanceLocked 锁已被另一个实例持有
+var errInstanceLocked = errors.New("另一个实例正在使用 data.db")
+
+// acquireInstanceLock 获取单写者锁，返回的连接在应用运行期间保持打开以持有锁
+func acquireInstanceLock() (*sql.Conn, error) {
+	lockDB, err := sql.Open(scratchDriverName, "file:"+instanceLockPath+"?_locking_mode=EXCLUSIVE&_busy_timeout=0")
+	if err != nil {
+		return nil, err
+	}
+	// 排他锁模式下第一次写入后锁一直保持到连接关闭（打开连接时的初始化语句也可能因锁被占用而失败）
+	conn, err := lockDB.Conn(context.Background())
+	if err == nil {
+		_, err = conn.ExecContext(context.Background(), `CREATE TABLE IF NOT EXISTS instance (pid INTEGER, started_at TEXT);
+			DELETE FROM instance;`)
+		if err == nil {
+			_, err = conn.ExecContext(context.Background(), "INSERT INTO instance (pid, started_at) VALUES (?, ?)",
+				os.Getpid(), time.Now().Format("2006-01-02 15:04:05"))
+		}
+		if err != nil {
+			conn.Close()
+		}
+	}
+	if err != nil {
+		lockDB.Close()
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && (sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked) {
+			return nil, errInstanceLocked
+		}
+		return nil, fmt.Errorf("获取实例锁失败: %v", err)
+	}
+	return conn, nil
+}
+
+// GetInstanceStatus 当前实例状态：readOnly 为 true 表示启动时另一个实例已持有写锁，本实例以只读方式打开数据库
+// wails:export GetInstanceStatus
+func (a *App) GetInstanceStatus() map[string]interface{} {
+	status := map[string]interface{}{
+		"readOnly": a.readOnly(),
+		"pid":      os.Getpid(),
+		"message":  "本实例持有数据库写锁",
+	}
+	if v := a.viewer(); v != nil {
+		status["viewer"] = v.path
+		status["message"] = fmt.Sprintf("正在查看只读分享包「%s」，不能修改数据", v.manifest.Title)
+	} else if a.readOnly() {
+		status["message"] = readOnlyMessage
+	} else if a.instanceLock == nil {
+		status["message"] = "未能获取实例锁，多开应用时可能相互覆盖数据"
+	}
+	return status
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// joinTypes BuildJoin 支持的连接方式
+var joinTypes = map[string]string{
+	"inner": "INNER JOIN",
+	"left":  "LEFT JOIN",
+	"right": "RIGHT JOIN",
+	"full":  "FULL OUTER JOIN",
+}
+
+// joinSelectList 生成连接结果的列：selected 中的列可写为 a.列、b.列 或不带前缀（先在 A 表中查找）；
+// selected 为空时取 A 表全部列和 B 表全部列，B 表中与 A 表重名的列命名为 <B表名>_<列名>
+func joinSelectList(tableA string, colsA []string, tableB string, colsB []string, selected []string) ([]string, error) {
+	var items []string
+	used := make(map[string]bool)
+	add := func(alias string, col string, preferred string) {
+		name := col
+		if used[name] {
+			name = preferred + "_" + col
+		}
+		for i := 2; used[name]; i++ {
+			name = fmt.Sprintf("%s_%s_%d", preferred, col, i)
+		}
+		used[name] = true
+		items = append(items, fmt.Sprintf("%s.%s AS %s", alias, quoteIdent(col), quoteIdent(name)))
+	}
+
+	if len(selected) == 0 {
+		for _, col := range colsA {
+			add("a", col, tableA)
+		}
+		for _, col := range colsB {
+			add("b", col, tableB)
+		}
+		return items, nil
+	}
+
+	for _, item := range selected {
+		item = strings.TrimSpace(item)
+		switch {
+		case strings.HasPrefix(item, "a.") && containsString(colsA, item[2:]):
+			add("a", item[2:], tableA)
+		case strings.HasPrefix(item, "b.") && containsString(colsB, item[2:]):
+			add("b", item[2:], tableB)
+		case containsString(colsA, item):
+			add("a", item, tableA)
+		case containsString(colsB, item):
+			add("b", item, tableB)
+		default:
+			return nil, fmt.Errorf("两张表中都没有列 %s", item)
+		}
+	}
+	return items, nil
+}
+
+// BuildJoin 生成并执行两张表的连接查询（替代跨表 VLOOKUP），同时统计两边未匹配的行数
+// joinKeys 为 A 表列 -> B 表列；joinType 为 inner、left（默认）、right、full；
+// selectedColumns 为输出列（a.列 / b.列 / 列名），为空时输出两表全部列
+// 返回结果第一页（格式同 ExecuteSQLWithPage），另含 sql、unmatchedA、unmatchedB
+// wails:export BuildJoin
+func (a *App) BuildJoin(tableA string, tableB string, joinKeys map[string]string, joinType string, selectedColumns []string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	if len(joinKeys) == 0 {
+		result["error"] = "请指定连接键"
+		return result
+	}
+
+	joinType = strings.ToLower(strings.TrimSpace(joinType))
+	if joinType == "" {
+		joinType = "left"
+	}
+	joinSQL, ok := joinTypes[joinType]
+	if !ok {
+		result["error"] = fmt.Sprintf("不支持的连接方式: %s（可选 inner、left、right、full）", joinType)
+		return result
+	}
+
+	colsA, err := a.tableColumns(tableA)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	colsB, err := a.tableColumns(tableB)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+
+	keysA := make([]string, 0, len(joinKeys))
+	for k := range joinKeys {
+		keysA = append(keysA, k)
+	}
+	sort.Strings(keysA)
+	var conditions []string
+	for _, keyA := range keysA {
+		keyB := joinKeys[keyA]
+		if !containsString(colsA, keyA) {
+			result["error"] = fmt.Sprintf("表 %s 中不存在列 %s", tableA, keyA)
+			return result
+		}
+		if !containsString(colsB, keyB) {
+			result["error"] = fmt.Sprintf("表 %s 中不存在列 %s", tableB, keyB)
+			return result
+		}
+		conditions = append(conditions, fmt.Sprintf("a.%s = b.%s", quoteIdent(keyA), quoteIdent(keyB)))
+	}
+	on := strings.Join(conditions, " AND ")
+
+	// 已软删除的行不参与连接
+	fromA, colsA := a.liveSource(tableA, colsA)
+	fromB, colsB := a.liveSource(tableB, colsB)
+	items, err := joinSelectList(tableA, colsA, tableB, colsB, selectedColumns)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	sqlText := fmt.Sprintf("SELECT %s\nFROM %s AS a\n%s %s AS b ON %s",
+		strings.Join(items, ",\n       "), fromA, joinSQL, fromB, on)
+
+	// 未匹配行数：A 表中在 B 表找不到的行，以及 B 表中在 A 表找不到的行
+	var unmatchedA, unmatchedB int
+	countSQL := "SELECT COUNT(*) FROM %s AS a WHERE NOT EXISTS (SELECT 1 FROM %s AS b WHERE %s)"
+	if err := a.readDB().QueryRow(fmt.Sprintf(countSQL, fromA, fromB, on)).Scan(&unmatchedA); err != nil {
+		result["error"] = fmt.Sprintf("统计未匹配行失败: %v", err)
+		return result
+	}
+	countSQL = "SELECT COUNT(*) FROM %s AS b WHERE NOT EXISTS (SELECT 1 FROM %s AS a WHERE %s)"
+	if err := a.readDB().QueryRow(fmt.Sprintf(countSQL, fromB, fromA, on)).Scan(&unmatchedB); err != nil {
+		result["error"] = fmt.Sprintf("统计未匹配行失败: %v", err)
+		return result
+	}
+
+	pageSize := a.session.currentPageSize()
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	result = a.ExecuteSQLWithPage(sqlText, 1, pageSize)
+	if _, failed := result["error"]; failed {
+		return result
+	}
+	result["sql"] = sqlText
+	result["unmatchedA"] = unmatchedA
+	result["unmatchedB"] = unmatchedB
+	result["message"] = fmt.Sprintf("%s；%s 中 %d 行在 %s 中无匹配，%s 中 %d 行在 %s 中无匹配",
+		result["message"], tableA, unmatchedA, tableB, tableB, unmatchedB, tableA)
+	return result
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxFlattenColumns JSON 展开后允许的最大列数（路径过多时通常是数组过长，应改用 ExplodeColumn 等方式）
+const maxFlattenColumns = 500
+
+// flattenRowIDColumn 展开表中指向源表行的 rowid 列
+const flattenRowIDColumn = "_source_rowid"
+
+// checkJSONSupport 确认 SQLite 内置了 JSON 函数（json_extract、json_each 等，3.38 起默认启用）
+func checkJSONSupport(db *sql.DB) error {
+	var v string
+	if err := db.QueryRow(`SELECT json_extract('{"ok":"yes"}', '$.ok')`).Scan(&v); err != nil {
+		return fmt.Errorf("当前 SQLite 未启用 JSON 扩展，json_extract 等函数不可用: %v", err)
+	}
+	return nil
+}
+
+// decodeJSONCell 解析单元格中的 JSON 文本，数字保留原始写法
+func decodeJSONCell(s string) (interface{}, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" || (s[0] != '{' && s[0] != '[') {
+		return nil, false
+	}
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, false
+	}
+	return v, true
+}
+
+// jsonScalar 将 JSON 值转为 SQL 取值：字符串原样、数字按数值、布尔为 1/0、null 为 NULL、对象和数组为 JSON 文本
+func jsonScalar(v interface{}) interface{} {
+	switch x := v.(type) {
+	case nil:
+		return nil
+	case string:
+		return x
+	case json.Number:
+		if i, err := x.Int64(); err == nil {
+			return i
+		}
+		if f, err := x.Float64(); err == nil {
+			return f
+		}
+		return x.String()
+	case bool:
+		if x {
+			return int64(1)
+		}
+		return int64(0)
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(v)
+	return strings.TrimSpace(buf.String())
+}
+
+// flattenJSON 将 JSON 展开为 路径 -> 叶子值，路径形如 a.b、items[0].name；paths 按首次出现的顺序记录
+func flattenJSON(prefix string, v interface{}, out map[string]interface{}, paths *[]string) {
+	add := func(path string, value interface{}) {
+		if _, ok := out[path]; !ok {
+			*paths = append(*paths, path)
+		}
+		out[path] = value
+	}
+	switch x := v.(type) {
+	case map[string]interface{}:
+		if len(x) == 0 && prefix != "" {
+			add(prefix, "{}")
+		}
+		for _, key := range sortedKeys(x) {
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			flattenJSON(path, x[key], out, paths)
+		}
+	case []interface{}:
+		if len(x) == 0 && prefix != "" {
+			add(prefix, "[]")
+		}
+		for i, item := range x {
+			flattenJSON(fmt.Sprintf("%s[%d]", prefix, i), item, out, paths)
+		}
+	default:
+		if prefix == "" {
+			prefix = "value"
+		}
+		add(prefix, jsonScalar(x))
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// jsonPathPattern 路径中的一段：键名或 [下标]
+var jsonPathPattern = regexp.MustCompile(`[^.\[\]]+|\[\d+\]`)
+
+// jsonGetFunc SQL 函数 JSON_GET(json, path)：按 a.b[0].c 形式的路径（可带 $ 前缀）取值；
+// 与 json_extract 不同，单元格不是合法 JSON 或路径不存在时返回 NULL 而不是报错，适合清洗不规整的数据
+func jsonGetFunc(doc, path interface{}) interface{} {
+	text, ok := udfText(doc)
+	if !ok {
+		return nil
+	}
+	v, ok := decodeJSONCell(text)
+	if !ok {
+		return nil
+	}
+	p, ok := udfText(path)
+	if !ok {
+		return nil
+	}
+	p = strings.TrimPrefix(strings.TrimSpace(p), "$")
+	for _, part := range jsonPathPattern.FindAllString(p, -1) {
+		if strings.HasPrefix(part, "[") {
+			idx, _ := strconv.Atoi(part[1 : len(part)-1])
+			arr, ok := v.([]interface{})
+			if !ok || idx >= len(arr) {
+				return nil
+			}
+			v = arr[idx]
+			continue
+		}
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		if v, ok = obj[part]; !ok {
+			return nil
+		}
+	}
+	return jsonScalar(v)
+}
+
+// JSONFlatten 将单元格中嵌入 JSON 的列展开为新表 <表名>_<列名>_json：每个叶子路径一列（如 address.city、items[0].sku），
+// 并以 _source_rowid 关联源表的 rowid；不是合法 JSON 的单元格跳过
+// wails:export JSONFlatten
+func (a *App) JSONFlatten(tableName string, column string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+
+	columns, err := a.tableColumns(tableName)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	if !containsString(columns, column) {
+		result["error"] = fmt.Sprintf("表 %s 中不存在列 %s", tableName, column)
+		return result
+	}
+
+	rows, err := a.readDB().Query(fmt.Sprintf("SELECT rowid, CAST(%s AS TEXT) FROM %s WHERE %s IS NOT NULL ORDER BY rowid",
+		quoteIdent(column), quoteIdent(tableName), quoteIdent(column)))
+	if err != nil {
+		result["error"] = fmt.Sprintf("读取数据失败: %v", err)
+		return result
+	}
+	type flatRow struct {
+		rowid  int64
+		values map[string]interface{}
+	}
+	var flat []flatRow
+	var paths []string
+	seen := make(map[string]bool)
+	skipped := 0
+	for rows.Next() {
+		var rowid int64
+		var text sql.NullString
+		if err := rows.Scan(&rowid, &text); err != nil {
+			rows.Close()
+			result["error"] = fmt.Sprintf("读取数据失败: %v", err)
+			return result
+		}
+		v, ok := decodeJSONCell(text.String)
+		if !ok {
+			skipped++
+			continue
+		}
+		values := make(map[string]interface{})
+		var rowPaths []string
+		flattenJSON("", v, values, &rowPaths)
+		for _, p := range rowPaths {
+			if !seen[p] {
+				seen[p] = true
+				paths = append(paths, p)
+			}
+		}
+		flat = append(flat, flatRow{rowid: rowid, values: values})
+	}
+	rows.Close()
+
+	if len(flat) == 0 {
+		result["error"] = fmt.Sprintf("列 %s 中没有可解析的 JSON（跳过 %d 个单元格）", column, skipped)
+		return result
+	}
+	if len(paths) > maxFlattenColumns {
+		result["error"] = fmt.Sprintf("JSON 展开后共 %d 个路径，超过上限 %d", len(paths), maxFlattenColumns)
+		return result
+	}
+
+	target := tableName + "_" + column + "_json"
+	tx, err := a.writeDB().Begin()
+	if err != nil {
+		result["error"] = fmt.Sprintf("开启事务失败: %v", err)
+		return result
+	}
+	defer tx.Rollback()
+	if err := dropTableOrView(tx, target); err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	defs := []string{quoteIdent(flattenRowIDColumn) + " INTEGER"}
+	for _, p := range paths {
+		defs = append(defs, quoteIdent(p))
+	}
+	if _, err := tx.Exec(fmt.Sprintf("CREATE TABLE %s (%s)", quoteIdent(target), strings.Join(defs, ", "))); err != nil {
+		result["error"] = fmt.Sprintf("创建表 %s 失败: %v", target, err)
+		return result
+	}
+	values := make([][]interface{}, len(flat))
+	for i, row := range flat {
+		record := make([]interface{}, 0, len(paths)+1)
+		record = append(record, row.rowid)
+		for _, p := range paths {
+			record = append(record, row.values[p])
+		}
+		values[i] = record
+	}
+	if err := insertRows(tx, target, append([]string{flattenRowIDColumn}, paths...), values, nil); err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	if err := tx.Commit(); err != nil {
+		result["error"] = fmt.Sprintf("提交事务失败: %v", err)
+		return result
+	}
+
+	result["table"] = target
+	result["columns"] = paths
+	result["rows"] = len(flat)
+	result["skipped"] = skipped
+	result["message"] = fmt.Sprintf("已将列 %s 展开为表 %s（%d 行，%d 列，跳过 %d 个非 JSON 单元格）", column, target, len(flat), len(paths), skipped)
+	return result
+}
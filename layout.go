@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// excelPixelsPerChar 列宽像素换算为 Excel 列宽（字符数）的系数
+const excelPixelsPerChar = 7.0
+
+// columnLayout 结果表格中一列的显示设置：顺序由列表顺序决定，width 为像素宽度（0 表示默认），
+// pinned 表示导出时固定在左侧
+type columnLayout struct {
+	column  string
+	visible bool
+	width   float64
+	pinned  bool
+}
+
+// parseColumnLayout 解析前端传来的 [{column, visible, width, pinned}]，visible 缺省为 true
+func parseColumnLayout(specs []map[string]interface{}) ([]columnLayout, error) {
+	layout := make([]columnLayout, 0, len(specs))
+	seen := make(map[string]bool)
+	for i, spec := range specs {
+		var c columnLayout
+		c.column, _ = spec["column"].(string)
+		if c.column == "" {
+			return nil, fmt.Errorf("第 %d 项列设置缺少列名", i+1)
+		}
+		if seen[c.column] {
+			return nil, fmt.Errorf("列 %s 重复设置", c.column)
+		}
+		seen[c.column] = true
+		c.visible = true
+		if v, ok := spec["visible"].(bool); ok {
+			c.visible = v
+		}
+		c.width, _ = spec["width"].(float64)
+		c.pinned, _ = spec["pinned"].(bool)
+		if c.width < 0 {
+			return nil, fmt.Errorf("列 %s 的宽度不能为负数", c.column)
+		}
+		layout = append(layout, c)
+	}
+	return layout, nil
+}
+
+func (c columnLayout) toMap() map[string]interface{} {
+	return map[string]interface{}{"column": c.column, "visible": c.visible, "width": c.width, "pinned": c.pinned}
+}
+
+// layoutColumns 按列设置排列结果列并去掉隐藏列；列设置中没有的列（如查询新增的列）保持原顺序排在最后
+func layoutColumns(columns []string, layout []columnLayout) []string {
+	if len(layout) == 0 {
+		return columns
+	}
+	present := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		present[col] = true
+	}
+	listed := make(map[string]bool, len(layout))
+	ordered := make([]string, 0, len(columns))
+	for _, c := range layout {
+		listed[c.column] = true
+		if c.visible && present[c.column] {
+			ordered = append(ordered, c.column)
+		}
+	}
+	for _, col := range columns {
+		if !listed[col] {
+			ordered = append(ordered, col)
+		}
+	}
+	return ordered
+}
+
+// applyColumnWidths 按列设置中的像素宽度设置导出工作表的列宽
+func applyColumnWidths(f *excelize.File, sheet string, columns []string, layout []columnLayout) {
+	widths := make(map[string]float64, len(layout))
+	for _, c := range layout {
+		widths[c.column] = c.width
+	}
+	for i, col := range columns {
+		if w := widths[col]; w > 0 {
+			name, _ := excelize.ColumnNumberToName(i + 1)
+			f.SetColWidth(sheet, name, name, w/excelPixelsPerChar)
+		}
+	}
+}
+
+// pinColumns 将关键列按给定顺序移到最左侧，返回新的列顺序和实际固定的列数（结果中不存在的列忽略）
+func pinColumns(columns []string, keys []string) ([]string, int) {
+	if len(keys) == 0 {
+		return columns, 0
+	}
+	present := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		present[col] = true
+	}
+	pinned := make(map[string]bool, len(keys))
+	ordered := make([]string, 0, len(columns))
+	for _, key := range keys {
+		if present[key] && !pinned[key] {
+			pinned[key] = true
+			ordered = append(ordered, key)
+		}
+	}
+	count := len(ordered)
+	for _, col := range columns {
+		if !pinned[col] {
+			ordered = append(ordered, col)
+		}
+	}
+	return ordered, count
+}
+
+// freezeColumns 冻结表头行和左侧 count 列，滚动时关键列和表头始终可见
+func freezeColumns(f *excelize.File, sheet string, count int) error {
+	if count <= 0 {
+		return nil
+	}
+	return f.SetPanes(sheet, frozenPanes(count))
+}
+
+// frozenPanes 冻结表头行和左侧 count 列的窗格设置
+func frozenPanes(count int) *excelize.Panes {
+	topLeft, _ := excelize.CoordinatesToCellName(count+1, 2)
+	return &excelize.Panes{
+		Freeze:      true,
+		XSplit:      count,
+		YSplit:      1,
+		TopLeftCell: topLeft,
+		ActivePane:  "bottomRight",
+	}
+}
+
+// ExportExcelPinned 与 ExportExcelBySQL 相同，但把 keyColumns 按顺序移到最左侧并冻结（连同表头行），
+// 便于查看宽报表时关键列始终可见；返回值同 ExportExcelBySQL
+// wails:export ExportExcelPinned
+func (a *App) ExportExcelPinned(sqlStr string, keyColumns []string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	sqlStr = strings.TrimSpace(sqlStr)
+	if sqlStr == "" {
+		result["error"] = "错误：SQL 语句不能为空！"
+		return result
+	}
+	if len(keyColumns) == 0 {
+		result["error"] = "请选择要固定的关键列"
+		return result
+	}
+	return a.startExportJob(sqlStr, "查询结果", exportOptions{pinned: keyColumns})
+}
@@ -0,0 +1,490 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// lintWideColumns SELECT * 的表超过该列数时提示只选择需要的列
+const lintWideColumns = 30
+
+// 检查规则
+const (
+	lintCartesianJoin = "cartesian_join"      // JOIN 缺少 ON 条件，或逗号连接的表没有 WHERE 条件
+	lintSelectStar    = "select_star_wide"    // 对很宽的表 SELECT *
+	lintNonSargable   = "non_sargable"        // 条件中对已建索引的列使用函数，无法使用索引
+	lintTextNumber    = "text_number_compare" // 文本列与数字比较，按文本比较
+)
+
+// nonSargableFuncs 用在条件列上会使索引失效的常用函数
+var nonSargableFuncs = map[string]bool{
+	"DATE": true, "DATETIME": true, "STRFTIME": true, "JULIANDAY": true, "UNIXEPOCH": true,
+	"SUBSTR": true, "SUBSTRING": true, "LOWER": true, "UPPER": true, "TRIM": true, "LTRIM": true, "RTRIM": true,
+	"CAST": true, "IFNULL": true, "COALESCE": true, "REPLACE": true,
+}
+
+// lintClauseWords 结束 FROM / JOIN 子句的关键字
+var lintClauseWords = map[string]bool{
+	"WHERE": true, "GROUP": true, "ORDER": true, "LIMIT": true, "HAVING": true, "WINDOW": true,
+	"UNION": true, "EXCEPT": true, "INTERSECT": true, "JOIN": true, "LEFT": true, "RIGHT": true,
+	"INNER": true, "FULL": true, "CROSS": true, "NATURAL": true, "ON": true, "USING": true, "RETURNING": true,
+}
+
+// lintKeywords 不能作为表名、列名或别名的关键字
+var lintKeywords = map[string]bool{
+	"SELECT": true, "FROM": true, "AS": true, "AND": true, "OR": true, "NOT": true, "NULL": true, "IS": true,
+	"IN": true, "LIKE": true, "GLOB": true, "BETWEEN": true, "CASE": true, "WHEN": true, "THEN": true,
+	"ELSE": true, "END": true, "DISTINCT": true, "ALL": true, "EXISTS": true, "WITH": true, "BY": true,
+	"ASC": true, "DESC": true, "OUTER": true, "VALUES": true, "SET": true, "UPDATE": true, "DELETE": true,
+	"INSERT": true, "INTO": true, "ESCAPE": true, "COLLATE": true, "OFFSET": true, "TRUE": true, "FALSE": true,
+}
+
+// lintToken SQL 词法单元：kind 为 w（单词）、i（引号标识符）、n（数字）、s（字符串）、p（符号）
+// depth 为所在的括号层数，括号本身计入外层
+type lintToken struct {
+	text  string
+	upper string
+	kind  byte
+	depth int
+}
+
+// isIdent 是否可能是表名、列名或别名
+func (t lintToken) isIdent() bool {
+	return t.kind == 'i' || t.kind == 'w' && !lintKeywords[t.upper] && !lintClauseWords[t.upper]
+}
+
+// isWord 是否为指定的关键字
+func (t lintToken) isWord(words ...string) bool {
+	if t.kind != 'w' {
+		return false
+	}
+	for _, w := range words {
+		if t.upper == w {
+			return true
+		}
+	}
+	return false
+}
+
+// lintTokenize 把 SQL 切分为词法单元（见 lexSQL），跳过注释；字符串和引号标识符取去掉引号后的内容
+func lintTokenize(s string) []lintToken {
+	var tokens []lintToken
+	depth := 0
+	lexSQL(s, func(kind byte, start int, end int) bool {
+		text := s[start:end]
+		switch kind {
+		case 'c':
+			return true
+		case 's', 'i':
+			text = sqlLiteralText(text)
+		case 'p':
+			if text == ")" {
+				depth--
+			}
+		}
+		tokens = append(tokens, lintToken{text: text, upper: strings.ToUpper(text), kind: kind, depth: depth})
+		if text == "(" && kind == 'p' {
+			depth++
+		}
+		return true
+	})
+	return tokens
+}
+
+// lintTableRef 查询中引用的表
+type lintTableRef struct {
+	table string
+	alias string
+	depth int
+	pos   int // 表名在词法单元中的位置
+}
+
+// lintContext 一次检查中用到的语句和表结构（表结构按需读取并缓存）
+type lintContext struct {
+	app     *App
+	tokens  []lintToken
+	refs    []lintTableRef
+	infos   map[string][]columnInfo
+	indexes map[string]map[string]bool
+}
+
+func newLintContext(a *App, sqlStr string) *lintContext {
+	c := &lintContext{
+		app:     a,
+		tokens:  lintTokenize(sqlStr),
+		infos:   make(map[string][]columnInfo),
+		indexes: make(map[string]map[string]bool),
+	}
+	c.refs = c.tableRefs()
+	return c
+}
+
+// columns 表的列信息，表不存在（如 CTE、子查询别名）时返回 nil
+func (c *lintContext) columns(table string) []columnInfo {
+	key := strings.ToLower(table)
+	if infos, ok := c.infos[key]; ok {
+		return infos
+	}
+	infos, _ := tableColumnInfos(c.app.writeDB(), table)
+	c.infos[key] = infos
+	return infos
+}
+
+// column 查找列的声明类型
+func (c *lintContext) column(table string, column string) (columnInfo, bool) {
+	for _, info := range c.columns(table) {
+		if strings.EqualFold(info.name, column) {
+			return info, true
+		}
+	}
+	return columnInfo{}, false
+}
+
+// indexed 列是否为某个索引的首列（只有首列能直接用于条件查找）
+func (c *lintContext) indexed(table string, column string) bool {
+	key := strings.ToLower(table)
+	leading, ok := c.indexes[key]
+	if !ok {
+		leading = make(map[string]bool)
+		if rows, err := c.app.readDB().Query(fmt.Sprintf("SELECT ii.name FROM pragma_index_list(%s) il, pragma_index_info(il.name) ii WHERE ii.seqno = 0",
+			quoteLiteral(table))); err == nil {
+			for rows.Next() {
+				var name string
+				if rows.Scan(&name) == nil {
+					leading[strings.ToLower(name)] = true
+				}
+			}
+			rows.Close()
+		}
+		c.indexes[key] = leading
+	}
+	return leading[strings.ToLower(column)]
+}
+
+// tableRefs 找出 FROM / JOIN 后引用的表（含逗号分隔的多张表）及别名
+func (c *lintContext) tableRefs() []lintTableRef {
+	var refs []lintTableRef
+	t := c.tokens
+	for i := 0; i < len(t); i++ {
+		if !t[i].isWord("FROM", "JOIN") {
+			continue
+		}
+		j := i + 1
+		for j < len(t) && t[j].isIdent() && t[j].depth == t[i].depth {
+			ref := lintTableRef{table: t[j].text, depth: t[i].depth, pos: j}
+			j++
+			// schema.table
+			if j+1 < len(t) && t[j].text == "." && t[j+1].isIdent() {
+				ref.table = t[j+1].text
+				j += 2
+			}
+			if j < len(t) && t[j].isWord("AS") {
+				j++
+			}
+			if j < len(t) && t[j].isIdent() && t[j].depth == t[i].depth {
+				ref.alias = t[j].text
+				j++
+			}
+			refs = append(refs, ref)
+			if !t[i].isWord("FROM") || j >= len(t) || t[j].text != "," {
+				break
+			}
+			j++
+		}
+	}
+	return refs
+}
+
+// resolve 将 [限定名.]列 解析为所属的表：限定名按别名或表名匹配，未限定时在同层及外层引用的表中唯一匹配
+func (c *lintContext) resolve(qualifier string, column string, depth int) (string, columnInfo, bool) {
+	var found []lintTableRef
+	for _, ref := range c.refs {
+		if qualifier != "" {
+			if strings.EqualFold(ref.alias, qualifier) || ref.alias == "" && strings.EqualFold(ref.table, qualifier) {
+				found = append(found, ref)
+			}
+			continue
+		}
+		if ref.depth <= depth {
+			if _, ok := c.column(ref.table, column); ok {
+				found = append(found, ref)
+			}
+		}
+	}
+	if len(found) != 1 {
+		return "", columnInfo{}, false
+	}
+	info, ok := c.column(found[0].table, column)
+	return found[0].table, info, ok
+}
+
+// columnAt 位置 i 处的列引用（[限定名.]列），返回限定名、列名及下一个位置
+func (c *lintContext) columnAt(i int) (qualifier string, column string, next int, ok bool) {
+	t := c.tokens
+	if i >= len(t) || !t[i].isIdent() || i+1 < len(t) && t[i+1].text == "(" {
+		return "", "", i, false
+	}
+	if i+2 < len(t) && t[i+1].text == "." && t[i+2].isIdent() {
+		return t[i].text, t[i+2].text, i + 3, true
+	}
+	return "", t[i].text, i + 1, true
+}
+
+// columnBefore 以位置 end（不含）结尾的列引用
+func (c *lintContext) columnBefore(end int) (qualifier string, column string, start int, ok bool) {
+	t := c.tokens
+	i := end - 1
+	if i < 0 || !t[i].isIdent() {
+		return "", "", end, false
+	}
+	if i >= 2 && t[i-1].text == "." && t[i-2].isIdent() {
+		return t[i-2].text, t[i].text, i - 2, true
+	}
+	if i >= 1 && t[i-1].text == "." {
+		return "", "", end, false
+	}
+	return "", t[i].text, i, true
+}
+
+// clauseOf 位置 i 所在的子句关键字（向前找同层的 SELECT / FROM / WHERE / ON 等）
+func (c *lintContext) clauseOf(i int) string {
+	depth := c.tokens[i].depth
+	for j := i - 1; j >= 0; j-- {
+		t := c.tokens[j]
+		if t.depth < depth {
+			// 括号内的表达式（如函数参数）归属外层子句
+			depth = t.depth
+		}
+		if t.depth == depth && t.isWord("SELECT", "FROM", "JOIN", "WHERE", "ON", "GROUP", "ORDER", "HAVING", "SET", "VALUES") {
+			return t.upper
+		}
+	}
+	return ""
+}
+
+// cartesianJoins JOIN 缺少 ON / USING 条件（CROSS JOIN、NATURAL JOIN 视为有意为之），或 FROM 中逗号连接多张表却没有 WHERE
+func (c *lintContext) cartesianJoins() []map[string]interface{} {
+	var warnings []map[string]interface{}
+	t := c.tokens
+	for i, tok := range t {
+		switch {
+		case tok.isWord("JOIN"):
+			if i > 0 && t[i-1].isWord("CROSS", "NATURAL") {
+				continue
+			}
+			missing := true
+			for j := i + 1; j < len(t) && t[j].depth >= tok.depth; j++ {
+				if t[j].depth != tok.depth {
+					continue
+				}
+				if t[j].isWord("ON", "USING") {
+					missing = false
+					break
+				}
+				if lintClauseWords[t[j].upper] && t[j].kind == 'w' || t[j].text == "," {
+					break
+				}
+			}
+			if !missing {
+				continue
+			}
+			name := "右侧的表"
+			if i+1 < len(t) {
+				name = t[i+1].text
+			}
+			warnings = append(warnings, map[string]interface{}{
+				"rule":       lintCartesianJoin,
+				"message":    fmt.Sprintf("JOIN %s 缺少 ON 条件，每一行都会与另一张表的每一行组合（笛卡尔积），结果行数可能极大", name),
+				"suggestion": "添加 ON 关联条件；确实需要全部组合时请写 CROSS JOIN",
+			})
+		case tok.isWord("FROM"):
+			var tables []string
+			for _, ref := range c.refs {
+				if ref.pos > i && ref.depth == tok.depth && c.fromOf(ref.pos) == i {
+					tables = append(tables, ref.table)
+				}
+			}
+			if len(tables) < 2 || c.hasWhere(i) {
+				continue
+			}
+			warnings = append(warnings, map[string]interface{}{
+				"rule":       lintCartesianJoin,
+				"message":    fmt.Sprintf("FROM 中用逗号连接了 %s 但没有 WHERE 条件，结果为笛卡尔积", strings.Join(tables, "、")),
+				"suggestion": "改用 JOIN ... ON 写明关联条件，或在 WHERE 中添加关联条件",
+			})
+		}
+	}
+	return warnings
+}
+
+// fromOf 表名位置 pos 所属的 FROM 的位置（逗号列表中的每张表都归属同一个 FROM），不属于 FROM 时返回 -1
+func (c *lintContext) fromOf(pos int) int {
+	depth := c.tokens[pos].depth
+	for j := pos - 1; j >= 0; j-- {
+		t := c.tokens[j]
+		if t.depth < depth {
+			return -1
+		}
+		if t.depth != depth {
+			continue
+		}
+		if t.isWord("FROM") {
+			return j
+		}
+		if t.kind == 'w' && (lintClauseWords[t.upper] || t.upper == "SELECT") {
+			return -1
+		}
+	}
+	return -1
+}
+
+// hasWhere FROM（位置 from）所在的查询是否有 WHERE 条件
+func (c *lintContext) hasWhere(from int) bool {
+	depth := c.tokens[from].depth
+	for j := from + 1; j < len(c.tokens) && c.tokens[j].depth >= depth; j++ {
+		t := c.tokens[j]
+		if t.depth != depth {
+			continue
+		}
+		if t.isWord("WHERE") {
+			return true
+		}
+		if t.isWord("UNION", "EXCEPT", "INTERSECT") {
+			return false
+		}
+	}
+	return false
+}
+
+// wideSelectStars 对列数超过 lintWideColumns 的表使用 SELECT * 或 别名.*
+func (c *lintContext) wideSelectStars() []map[string]interface{} {
+	var warnings []map[string]interface{}
+	t := c.tokens
+	seen := make(map[string]bool)
+	warn := func(table string) {
+		n := len(c.columns(table))
+		if n <= lintWideColumns || seen[strings.ToLower(table)] {
+			return
+		}
+		seen[strings.ToLower(table)] = true
+		warnings = append(warnings, map[string]interface{}{
+			"rule":       lintSelectStar,
+			"table":      table,
+			"message":    fmt.Sprintf("SELECT * 返回表 %s 的全部 %d 列，查询和导出都会变慢，结果也不便查看", table, n),
+			"suggestion": "只列出需要的列",
+		})
+	}
+	for i, tok := range t {
+		if tok.text != "*" || i == 0 {
+			continue
+		}
+		prev := t[i-1]
+		switch {
+		case prev.isWord("SELECT", "DISTINCT", "ALL") || prev.text == ",":
+			if c.clauseOf(i) != "SELECT" {
+				continue
+			}
+			for _, ref := range c.refs {
+				if ref.depth == tok.depth && ref.pos > i && c.selectOf(ref.pos) == c.selectOf(i) {
+					warn(ref.table)
+				}
+			}
+		case prev.text == "." && i >= 2:
+			for _, ref := range c.refs {
+				if strings.EqualFold(ref.alias, t[i-2].text) || ref.alias == "" && strings.EqualFold(ref.table, t[i-2].text) {
+					warn(ref.table)
+				}
+			}
+		}
+	}
+	return warnings
+}
+
+// selectOf 位置 i 所属的 SELECT 的位置
+func (c *lintContext) selectOf(i int) int {
+	depth := c.tokens[i].depth
+	for j := i; j >= 0; j-- {
+		t := c.tokens[j]
+		if t.depth < depth {
+			return -1
+		}
+		if t.depth == depth && t.isWord("SELECT") {
+			return j
+		}
+	}
+	return -1
+}
+
+// nonSargable 条件（WHERE / ON）中对已建索引的列使用函数，SQLite 无法直接用索引查找
+func (c *lintContext) nonSargable() []map[string]interface{} {
+	var warnings []map[string]interface{}
+	t := c.tokens
+	seen := make(map[string]bool)
+	for i := 0; i+1 < len(t); i++ {
+		if t[i].kind != 'w' || !nonSargableFuncs[t[i].upper] || t[i+1].text != "(" {
+			continue
+		}
+		if clause := c.clauseOf(i); clause != "WHERE" && clause != "ON" {
+			continue
+		}
+		argDepth := t[i].depth + 1
+		for j := i + 2; j < len(t) && t[j].depth >= argDepth; {
+			if t[j].depth != argDepth {
+				j++
+				continue
+			}
+			qualifier, column, next, ok := c.columnAt(j)
+			if !ok {
+				j++
+				continue
+			}
+			j = next
+			table, info, ok := c.resolve(qualifier, column, t[i].depth)
+			if !ok || !c.indexed(table, info.name) || seen[table+"."+info.name] {
+				continue
+			}
+			seen[table+"."+info.name] = true
+			warnings = append(warnings, map[string]interface{}{
+				"rule":    lintNonSargable,
+				"table":   table,
+				"column":  info.name,
+				"message": fmt.Sprintf("条件中对已建索引的列 %s 使用了 %s()，查询无法使用索引，需要扫描全表", info.name, t[i].upper),
+				"suggestion": fmt.Sprintf("改为对列 %s 本身写范围条件（如把 DATE(%s) = '2024-01-01' 改写为 %s >= '2024-01-01' AND %s < '2024-01-02'），或为该表达式建立索引",
+					info.name, info.name, info.name, info.name),
+			})
+		}
+	}
+	return warnings
+}
+
+// LintSQL 检查 SQL 中的常见问题，返回 {warnings: [{rule, message, suggestion, table?, column?}], message}
+// 规则：JOIN 缺少 ON（笛卡尔积）、对很宽的表 SELECT *、条件中对已建索引的列使用日期等函数、文本列与数字比较
+// 检查基于词法分析，不执行 SQL，可能遗漏或误报
+// wails:export LintSQL
+func (a *App) LintSQL(sqlStr string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	sqlStr = strings.TrimSpace(sqlStr)
+	if sqlStr == "" {
+		result["error"] = "错误：SQL 语句不能为空！"
+		return result
+	}
+
+	c := newLintContext(a, sqlStr)
+	warnings := []map[string]interface{}{}
+	warnings = append(warnings, c.cartesianJoins()...)
+	warnings = append(warnings, c.wideSelectStars()...)
+	warnings = append(warnings, c.nonSargable()...)
+	warnings = append(warnings, c.textNumberComparisons()...)
+
+	result["warnings"] = warnings
+	if len(warnings) == 0 {
+		result["message"] = "未发现常见问题"
+	} else {
+		result["message"] = fmt.Sprintf("发现 %d 个可能的问题", len(warnings))
+	}
+	return result
+}
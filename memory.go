@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"sync/atomic"
+)
+
+// memoryPressureRatio 内存占用达到上限的该比例时视为内存紧张
+const memoryPressureRatio = 0.8
+
+// memoryCheckRows 读取全量结果时每隔多少行检查一次内存（ReadMemStats 会短暂暂停程序，不宜逐行调用）
+const memoryCheckRows = 5000
+
+// memoryLimit 内存上限（字节），取自 memory_limit_mb 设置
+var memoryLimit atomic.Uint64
+
+// errMemoryLimit 读取结果时内存接近上限
+var errMemoryLimit = errors.New("内存占用接近上限（memory_limit_mb 设置），结果过大：请加 LIMIT 或分页查看，导出会自动改为流式写入")
+
+// validateMemoryLimit 校验 memory_limit_mb 设置
+func validateMemoryLimit(v string) error {
+	mb, err := strconv.Atoi(v)
+	if err != nil || mb < 256 {
+		return fmt.Errorf("内存上限必须为不小于 256 的整数（MB）")
+	}
+	return nil
+}
+
+// setMemoryLimit 应用 memory_limit_mb 设置，同时作为 Go 运行时的软内存上限，使 GC 在接近上限时更积极地回收
+func setMemoryLimit(v string) {
+	mb, err := strconv.Atoi(v)
+	if err != nil {
+		return
+	}
+	limit := uint64(mb) << 20
+	memoryLimit.Store(limit)
+	debug.SetMemoryLimit(int64(limit))
+}
+
+// memoryUsage 当前进程 Go 运行时占用的内存（向操作系统申请的内存减去已归还的部分，近似常驻内存）
+func memoryUsage() (used uint64, stats runtime.MemStats) {
+	runtime.ReadMemStats(&stats)
+	return stats.Sys - stats.HeapReleased, stats
+}
+
+// memoryUnderPressure 内存占用是否已接近上限
+func memoryUnderPressure() bool {
+	limit := memoryLimit.Load()
+	if limit == 0 {
+		return false
+	}
+	used, _ := memoryUsage()
+	return float64(used) >= float64(limit)*memoryPressureRatio
+}
+
+// GetMemoryUsage 内存诊断信息：{usedMB, heapMB, limitMB, ratio, pressure, goroutines, numGC, cachedQueries}
+// 接近上限（pressure 为 true）时大结果不再缓存，全量读取会提前终止，导出改为流式写入
+// wails:export GetMemoryUsage
+func (a *App) GetMemoryUsage() map[string]interface{} {
+	used, stats := memoryUsage()
+	limit := memoryLimit.Load()
+	ratio := 0.0
+	if limit > 0 {
+		ratio = float64(used) / float64(limit)
+	}
+	cached := 0
+	if a.cache != nil {
+		cached = a.cache.len()
+	}
+	return map[string]interface{}{
+		"usedMB":        float64(used) / (1 << 20),
+		"heapMB":        float64(stats.HeapAlloc) / (1 << 20),
+		"limitMB":       limit >> 20,
+		"ratio":         ratio,
+		"pressure":      limit > 0 && ratio >= memoryPressureRatio,
+		"goroutines":    runtime.NumGoroutine(),
+		"numGC":         stats.NumGC,
+		"cachedQueries": cached,
+	}
+}
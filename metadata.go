@@ -0,0 +1,204 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// metaTables 应用自身使用的元数据表（统一以 _app_ 前缀命名，与导入的业务表区分）
+var metaTables = []string{
+	`CREATE TABLE IF NOT EXISTS _app_settings (
+		key TEXT PRIMARY KEY,
+		value TEXT NOT NULL
+	)`,
+	// 数据字典：column_name 为空字符串的行描述表本身
+	`CREATE TABLE IF NOT EXISTS _app_dictionary (
+		table_name TEXT NOT NULL,
+		column_name TEXT NOT NULL DEFAULT '',
+		label TEXT NOT NULL DEFAULT '',
+		description TEXT NOT NULL DEFAULT '',
+		PRIMARY KEY (table_name, column_name)
+	)`,
+	// 导出表头翻译：列在各语言（如 en-US）下的表头，export_header_language 设置选择使用的语言
+	`CREATE TABLE IF NOT EXISTS _app_header_translations (
+		table_name TEXT NOT NULL,
+		column_name TEXT NOT NULL,
+		language TEXT NOT NULL,
+		header TEXT NOT NULL,
+		PRIMARY KEY (table_name, column_name, language)
+	)`,
+	`CREATE TABLE IF NOT EXISTS _app_table_tags (
+		table_name TEXT NOT NULL,
+		tag TEXT NOT NULL,
+		PRIMARY KEY (table_name, tag)
+	)`,
+	// 表所属文件夹，层级用 / 分隔，如 finance/2024
+	`CREATE TABLE IF NOT EXISTS _app_table_folders (
+		table_name TEXT PRIMARY KEY,
+		folder TEXT NOT NULL
+	)`,
+	// 单元格批注：row_num 对应数据表的 rowid，0 表示表头行
+	`CREATE TABLE IF NOT EXISTS _app_cell_comments (
+		table_name TEXT NOT NULL,
+		row_num INTEGER NOT NULL,
+		column_name TEXT NOT NULL,
+		author TEXT NOT NULL DEFAULT '',
+		text TEXT NOT NULL,
+		PRIMARY KEY (table_name, row_num, column_name)
+	)`,
+	// 表的导入来源（文件路径/网址/外部查询），用于导出时标注数据出处
+	`CREATE TABLE IF NOT EXISTS _app_table_sources (
+		table_name TEXT PRIMARY KEY,
+		source TEXT NOT NULL,
+		imported_at TEXT NOT NULL
+	)`,
+	// 节假日与调休：is_workday 为 1 表示周末调休上班，生成日历表时使用
+	`CREATE TABLE IF NOT EXISTS _app_holidays (
+		date TEXT PRIMARY KEY,
+		name TEXT NOT NULL DEFAULT '',
+		is_workday INTEGER NOT NULL DEFAULT 0
+	)`,
+	// 汇率：rate 为 1 单位 currency 自 date 起折合多少基准币种，供 CONVERT_CURRENCY 使用
+	`CREATE TABLE IF NOT EXISTS _app_exchange_rates (
+		currency TEXT NOT NULL,
+		date TEXT NOT NULL,
+		rate REAL NOT NULL,
+		PRIMARY KEY (currency, date)
+	)`,
+	// 已保存的查询：rules 为结果表格条件高亮规则的 JSON 数组
+	`CREATE TABLE IF NOT EXISTS _app_saved_queries (
+		name TEXT PRIMARY KEY,
+		sql TEXT NOT NULL,
+		rules TEXT NOT NULL DEFAULT '[]',
+		updated_at TEXT NOT NULL
+	)`,
+	// 导入日志：state 为 running/done/failed/interrupted/discarded/kept，启动时遗留的 running 记为 interrupted
+	`CREATE TABLE IF NOT EXISTS _app_import_journal (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		table_name TEXT NOT NULL,
+		state TEXT NOT NULL,
+		expected_rows INTEGER NOT NULL DEFAULT 0,
+		started_at TEXT NOT NULL,
+		finished_at TEXT NOT NULL DEFAULT '',
+		error TEXT NOT NULL DEFAULT ''
+	)`,
+	// 查询历史：duration_ms 为执行耗时，plan 为 EXPLAIN QUERY PLAN 的摘要，row_count 为结果行数或影响行数
+	`CREATE TABLE IF NOT EXISTS _app_query_history (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		sql TEXT NOT NULL,
+		executed_at TEXT NOT NULL,
+		duration_ms INTEGER NOT NULL,
+		row_count INTEGER NOT NULL DEFAULT 0,
+		plan TEXT NOT NULL DEFAULT '',
+		error TEXT NOT NULL DEFAULT ''
+	)`,
+	// 结果快照：数据保存在 _app_snapshot_<id> 表中，key_columns 为比对使用的主键列（JSON 数组，空数组表示按整行比对）
+	`CREATE TABLE IF NOT EXISTS _app_snapshots (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL UNIQUE,
+		source TEXT NOT NULL,
+		key_columns TEXT NOT NULL DEFAULT '[]',
+		row_count INTEGER NOT NULL DEFAULT 0,
+		created_at TEXT NOT NULL
+	)`,
+	// 结构版本：schema 为全部用户表的列名和声明类型（JSON），hash 用于跳过与上一版本相同的快照
+	`CREATE TABLE IF NOT EXISTS _app_schema_versions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		schema TEXT NOT NULL,
+		hash TEXT NOT NULL,
+		reason TEXT NOT NULL DEFAULT '',
+		created_at TEXT NOT NULL
+	)`,
+	// 数据库备份：path 为备份文件路径，可从中恢复单张表
+	`CREATE TABLE IF NOT EXISTS _app_backups (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		path TEXT NOT NULL,
+		size INTEGER NOT NULL DEFAULT 0,
+		note TEXT NOT NULL DEFAULT '',
+		created_at TEXT NOT NULL
+	)`,
+	// 导出历史：options 为导出选项（JSON，见 exportOptions.toMap），status 为 done/failed，可据此重新导出
+	`CREATE TABLE IF NOT EXISTS _app_export_history (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		sql TEXT NOT NULL,
+		save_path TEXT NOT NULL,
+		options TEXT NOT NULL DEFAULT '{}',
+		status TEXT NOT NULL,
+		rows INTEGER NOT NULL DEFAULT 0,
+		duration_ms INTEGER NOT NULL DEFAULT 0,
+		started_at TEXT NOT NULL,
+		error TEXT NOT NULL DEFAULT ''
+	)`,
+	// 表的最近使用时间（导入或被查询读取），用于列出长期未使用的表
+	`CREATE TABLE IF NOT EXISTS _app_table_usage (
+		table_name TEXT PRIMARY KEY,
+		last_used_at TEXT NOT NULL
+	)`,
+	// 应用生成的表和视图（分区、日历表等）：generator 为生成方式，source 为来源表，
+	// definition 为生成时的建表语句，重新生成前据此确认同名对象确实是此前生成的、未被用户替换
+	`CREATE TABLE IF NOT EXISTS _app_generated_tables (
+		name TEXT PRIMARY KEY COLLATE NOCASE,
+		generator TEXT NOT NULL,
+		source TEXT NOT NULL DEFAULT '',
+		definition TEXT NOT NULL,
+		created_at TEXT NOT NULL
+	)`,
+	// 类型化表：由原始 TEXT 表 raw_table 转换生成，column_types 为各列的声明类型（JSON 对象）
+	`CREATE TABLE IF NOT EXISTS _app_typed_layers (
+		typed_table TEXT PRIMARY KEY,
+		raw_table TEXT NOT NULL,
+		column_types TEXT NOT NULL DEFAULT '{}',
+		row_count INTEGER NOT NULL DEFAULT 0,
+		error_count INTEGER NOT NULL DEFAULT 0,
+		built_at TEXT NOT NULL
+	)`,
+	// 类型转换错误：raw_rowid 为原始表的 rowid，raw_value 为无法转换的原始单元格文本
+	`CREATE TABLE IF NOT EXISTS _app_type_errors (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		typed_table TEXT NOT NULL,
+		raw_rowid INTEGER NOT NULL,
+		column_name TEXT NOT NULL,
+		raw_value TEXT NOT NULL,
+		target_type TEXT NOT NULL,
+		error TEXT NOT NULL
+	)`,
+}
+
+// metaColumns 元数据表创建后新增的列，旧数据库启动时补齐
+var metaColumns = []struct {
+	table, column, definition string
+}{
+	// 已保存查询的列顺序、显示和宽度（JSON 数组）
+	{"_app_saved_queries", "layout", "TEXT NOT NULL DEFAULT '[]'"},
+	{"_app_saved_queries", "formats", "TEXT NOT NULL DEFAULT '[]'"},
+	// 抽样导入时的抽样方式（如 每 10 行取 1 行），完整导入时为空
+	{"_app_table_sources", "sampling", "TEXT NOT NULL DEFAULT ''"},
+	// 导入时的源表头及其与表列的对应关系（JSON，见 sourceHeader），刷新时据此检测结构变化
+	{"_app_table_sources", "header", "TEXT NOT NULL DEFAULT ''"},
+}
+
+// initMetaTables 创建缺失的元数据表并补齐新增的列
+func initMetaTables(db *sql.DB) error {
+	for _, ddl := range metaTables {
+		if _, err := db.Exec(ddl); err != nil {
+			return fmt.Errorf("初始化元数据表失败: %v", err)
+		}
+	}
+	for _, mc := range metaColumns {
+		columns, err := tableColumnInfos(db, mc.table)
+		if err != nil {
+			return fmt.Errorf("初始化元数据表失败: %v", err)
+		}
+		exists := false
+		for _, c := range columns {
+			exists = exists || c.name == mc.column
+		}
+		if exists {
+			continue
+		}
+		if _, err := db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", mc.table, quoteIdent(mc.column), mc.definition)); err != nil {
+			return fmt.Errorf("初始化元数据表失败: %v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// notifyMinDuration 操作耗时超过该时长才发送系统通知，短操作用户通常仍在等待结果
+const notifyMinDuration = 5 * time.Second
+
+// notifyKinds 需要发送系统通知的操作类型
+var notifyKinds = map[string]bool{"import": true, "export": true, "benchmark": true}
+
+// notifyMaxLength 通知正文的最大字符数（系统通知只显示前几行）
+const notifyMaxLength = 200
+
+// windowInBackground 窗口是否在后台：前端报告失去焦点或窗口已最小化
+func (a *App) windowInBackground() bool {
+	if a.background.Load() {
+		return true
+	}
+	return a.ctx != nil && runtime.WindowIsMinimised(a.ctx)
+}
+
+// notifyFinished 窗口在后台且耗时较长的导入、导出结束时发送系统通知，正文为结果说明（含行数）
+func (a *App) notifyFinished(op *operation, message string, err error) {
+	if !notifyKinds[op.kind] || time.Since(op.startedAt) < notifyMinDuration {
+		return
+	}
+	if a.setting("os_notifications") != "true" || !a.windowInBackground() {
+		return
+	}
+	title := op.title + " 已完成"
+	if err != nil {
+		title = op.title + " 失败"
+		message = err.Error()
+	}
+	// 只取第一行（后续行通常是敏感列等补充提示），过长时截断
+	message = truncateRunes(strings.SplitN(message, "\n", 2)[0], notifyMaxLength)
+	if err := sendNotification(title, message); err != nil {
+		fmt.Printf("发送系统通知失败: %v\n", err)
+	}
+}
+
+// SetWindowBackground 前端在窗口失去或获得焦点（visibilitychange、blur/focus）时调用，用于判断是否需要发送系统通知
+// wails:export SetWindowBackground
+func (a *App) SetWindowBackground(background bool) {
+	a.background.Store(background)
+}
+
+// TestNotification 立即发送一条测试通知，用于确认系统通知可用
+// wails:export TestNotification
+func (a *App) TestNotification() string {
+	if err := sendNotification("Excel Query工具", "这是一条测试通知"); err != nil {
+		return fmt.Sprintf("发送系统通知失败: %v", err)
+	}
+	return "已发送测试通知"
+}
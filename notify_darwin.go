@@ -0,0 +1,17 @@
+package main
+
+import "os/exec"
+
+// sendNotification 通过 osascript 发送 macOS 通知（标题和正文作为参数传入，无需转义）
+func sendNotification(title string, body string) error {
+	cmd := exec.Command("osascript",
+		"-e", "on run argv",
+		"-e", "display notification (item 2 of argv) with title (item 1 of argv)",
+		"-e", "end run",
+		title, body)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
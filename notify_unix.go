@@ -0,0 +1,15 @@
+//go:build !windows && !darwin
+
+package main
+
+import "os/exec"
+
+// sendNotification 通过 notify-send（libnotify）发送桌面通知
+func sendNotification(title string, body string) error {
+	cmd := exec.Command("notify-send", "--app-name=Excel Query工具", title, body)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// notifyScript 用托盘气泡显示通知，标题和正文从环境变量读取，避免拼接到脚本中
+const notifyScript = `Add-Type -AssemblyName System.Windows.Forms, System.Drawing
+$n = New-Object System.Windows.Forms.NotifyIcon
+$n.Icon = [System.Drawing.SystemIcons]::Information
+$n.Visible = $true
+$n.ShowBalloonTip(10000, $env:NOTIFY_TITLE, $env:NOTIFY_BODY, 'Info')
+Start-Sleep -Seconds 10
+$n.Dispose()`
+
+// sendNotification 通过 PowerShell 发送 Windows 通知（不显示控制台窗口）
+func sendNotification(title string, body string) error {
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", notifyScript)
+	cmd.Env = append(os.Environ(), "NOTIFY_TITLE="+title, "NOTIFY_BODY="+body)
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
@@ -0,0 +1,30 @@
+package main
+
+// nullMarker null_policy 为 marker 时 NULL 在结果和导出中的显示文本
+const nullMarker = "(NULL)"
+
+// nullValue 按 null_policy 设置返回结果集和导出中 NULL 的表示：
+// empty 转为空字符串（历史行为），null 保留为 null（导出为空单元格），marker 显示为 (NULL)
+func (a *App) nullValue() interface{} {
+	switch a.setting("null_policy") {
+	case "null":
+		return nil
+	case "marker":
+		return nullMarker
+	}
+	return ""
+}
+
+// importEmptyAsNull 导入时空单元格是否写入 NULL（null 和 marker 策略下为 true）
+func (a *App) importEmptyAsNull() bool {
+	return a.setting("null_policy") != "empty"
+}
+
+// isNullDisplay 判断结果值是否表示 NULL（nil 或 NULL 标记）
+func isNullDisplay(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	s, ok := v.(string)
+	return ok && s == nullMarker
+}
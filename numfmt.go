@@ -0,0 +1,238 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// columnFormatPatterns 预置的列格式对应的 Excel 数字格式；date 未指定 pattern 时使用 locale 设置的日期格式
+var columnFormatPatterns = map[string]string{
+	"percent":   "0.00%",
+	"decimal2":  "0.00",
+	"integer":   "0",
+	"thousands": "#,##0.00",
+	"text":      "@",
+}
+
+// columnFormat 导出时一列的 Excel 数字格式：format 为 percent、decimal2、integer、thousands、date、text 或 custom，
+// pattern 为 Excel 格式代码（custom 必填，date 可选，如 yyyy/mm/dd）
+type columnFormat struct {
+	column  string
+	format  string
+	pattern string
+}
+
+// parseColumnFormats 解析前端传来的 [{column, format, pattern}]
+func parseColumnFormats(specs []map[string]interface{}) ([]columnFormat, error) {
+	formats := make([]columnFormat, 0, len(specs))
+	seen := make(map[string]bool)
+	for i, spec := range specs {
+		var c columnFormat
+		c.column, _ = spec["column"].(string)
+		c.format, _ = spec["format"].(string)
+		c.pattern, _ = spec["pattern"].(string)
+		c.format = strings.ToLower(strings.TrimSpace(c.format))
+		c.pattern = strings.TrimSpace(c.pattern)
+		if c.column == "" {
+			return nil, fmt.Errorf("第 %d 项列格式缺少列名", i+1)
+		}
+		if seen[c.column] {
+			return nil, fmt.Errorf("列 %s 重复设置格式", c.column)
+		}
+		seen[c.column] = true
+		switch c.format {
+		case "date":
+		case "custom":
+			if c.pattern == "" {
+				return nil, fmt.Errorf("列 %s 的自定义格式缺少格式代码", c.column)
+			}
+		default:
+			if _, ok := columnFormatPatterns[c.format]; !ok {
+				return nil, fmt.Errorf("列 %s 的格式 %q 无效（可选 percent、decimal2、integer、thousands、date、text、custom）", c.column, c.format)
+			}
+			c.pattern = ""
+		}
+		formats = append(formats, c)
+	}
+	return formats, nil
+}
+
+func (c columnFormat) toMap() map[string]interface{} {
+	return map[string]interface{}{"column": c.column, "format": c.format, "pattern": c.pattern}
+}
+
+// columnFormatMaps 列格式的前端表示
+func columnFormatMaps(formats []columnFormat) []map[string]interface{} {
+	maps := make([]map[string]interface{}, len(formats))
+	for i, c := range formats {
+		maps[i] = c.toMap()
+	}
+	return maps
+}
+
+// numFmt 列格式对应的 Excel 格式代码
+func (c columnFormat) numFmt() string {
+	if c.pattern != "" {
+		return c.pattern
+	}
+	if c.format == "date" {
+		_, locale := currentDateConfig()
+		return localeExcelDateFormat(locale, false)
+	}
+	return columnFormatPatterns[c.format]
+}
+
+// convert 按列格式转换写入单元格的值：text 列写为文本（保留前导零、不按数字显示），
+// 其他列把文本形式的数字、日期转为数字、日期，使数字格式生效；无法转换的值原样写入
+func (c columnFormat) convert(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	switch c.format {
+	case "text":
+		if t, ok := v.(time.Time); ok {
+			return t.Format("2006-01-02 15:04:05")
+		}
+		return fmt.Sprint(v)
+	case "date":
+		if s, ok := v.(string); ok {
+			if t, _, ok := parseDateText(s); ok {
+				return t
+			}
+		}
+		return v
+	}
+	if s, ok := v.(string); ok {
+		if n, ok := parseNumberText(s); ok {
+			return n
+		}
+		if c.format == "custom" {
+			if t, _, ok := parseDateText(s); ok {
+				return t
+			}
+		}
+	}
+	return v
+}
+
+// columnFormatter 在单元格原有样式（日期、高亮等）上叠加列格式，同一原样式只生成一次新样式
+type columnFormatter struct {
+	f       *excelize.File
+	formats map[string]columnFormat
+	cache   map[string]int
+}
+
+func newColumnFormatter(f *excelize.File, formats []columnFormat) *columnFormatter {
+	m := &columnFormatter{f: f, formats: make(map[string]columnFormat, len(formats)), cache: make(map[string]int)}
+	for _, c := range formats {
+		m.formats[c.column] = c
+	}
+	return m
+}
+
+// lookup 列的格式设置
+func (m *columnFormatter) lookup(column string) (columnFormat, bool) {
+	c, ok := m.formats[column]
+	return c, ok
+}
+
+// style 在 styleID 上叠加列的数字格式，返回新样式 ID（列未设置格式时原样返回）
+func (m *columnFormatter) style(column string, styleID int) int {
+	c, ok := m.formats[column]
+	if !ok {
+		return styleID
+	}
+	key := fmt.Sprintf("%d\x00%s", styleID, c.numFmt())
+	if id, ok := m.cache[key]; ok {
+		return id
+	}
+	style := &excelize.Style{}
+	if styleID != 0 {
+		if s, err := m.f.GetStyle(styleID); err == nil {
+			style = s
+		}
+	}
+	style.NumFmt = 0
+	style.CustomNumFmt = stringPtr(c.numFmt())
+	id, err := m.f.NewStyle(style)
+	if err != nil {
+		id = styleID
+	}
+	m.cache[key] = id
+	return id
+}
+
+// applyColumnFormats 按列格式重写已导出工作表中对应列的值并设置数字格式（数据从第 2 行开始），
+// 在高亮规则之后、工作表保护之前调用，以保留单元格已有的样式
+func applyColumnFormats(f *excelize.File, sheet string, columns []string, data []map[string]interface{}, formats []columnFormat) {
+	if len(formats) == 0 {
+		return
+	}
+	formatter := newColumnFormatter(f, formats)
+	for i, col := range columns {
+		c, ok := formatter.lookup(col)
+		if !ok {
+			continue
+		}
+		for rowIdx, row := range data {
+			cell, _ := excelize.CoordinatesToCellName(i+1, rowIdx+2)
+			if v := row[col]; v != nil && !isNullDisplay(v) {
+				f.SetCellValue(sheet, cell, c.convert(v))
+			}
+			styleID, _ := f.GetCellStyle(sheet, cell)
+			f.SetCellStyle(sheet, cell, cell, formatter.style(col, styleID))
+		}
+	}
+}
+
+// ExportExcelFormatted 与 ExportExcelBySQL 相同，但按 formats [{column, format, pattern}] 设置列的数字格式
+// （percent 百分比、decimal2 两位小数、thousands 千分位、integer 整数、date 日期、text 文本、custom 自定义格式代码）；
+// 返回值同 ExportExcelBySQL
+// wails:export ExportExcelFormatted
+func (a *App) ExportExcelFormatted(sqlStr string, formats []map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	sqlStr = strings.TrimSpace(sqlStr)
+	if sqlStr == "" {
+		result["error"] = "错误：SQL 语句不能为空！"
+		return result
+	}
+	parsed, err := parseColumnFormats(formats)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	return a.startExportJob(sqlStr, "查询结果", exportOptions{formats: parsed})
+}
+
+// SetSavedQueryFormats 保存查询导出时的列格式 [{column, format, pattern}]，此后每次导出该查询都按相同格式输出
+// wails:export SetSavedQueryFormats
+func (a *App) SetSavedQueryFormats(name string, formats []map[string]interface{}) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	parsed, err := parseColumnFormats(formats)
+	if err != nil {
+		return err.Error()
+	}
+	formatsJSON, err := json.Marshal(columnFormatMaps(parsed))
+	if err != nil {
+		return fmt.Sprintf("保存列格式失败: %v", err)
+	}
+	res, err := a.writeDB().Exec("UPDATE _app_saved_queries SET formats = ?, updated_at = ? WHERE name = ?",
+		string(formatsJSON), time.Now().Format("2006-01-02 15:04:05"), name)
+	if err != nil {
+		return fmt.Sprintf("保存列格式失败: %v", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Sprintf("已保存的查询 %s 不存在", name)
+	}
+	return fmt.Sprintf("已保存查询 %s 的列格式（%d 列）", name, len(parsed))
+}
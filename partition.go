@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxPartitions 单次拆分允许生成的最大分区数，避免对高基数列误操作生成大量表
+const maxPartitions = 200
+
+// PartitionTable 按列值拆分表：每个取值生成一张 <表名>_<取值> 的表（asView 为 true 时生成视图）
+// 空值和 NULL 归入 <表名>_empty，是合并导入的逆操作，便于将子集分发给不同负责人；
+// 重新拆分时只替换此前由该表生成的分区，同名的其他表或视图不会被删除（见 generated.go）
+// wails:export PartitionTable
+func (a *App) PartitionTable(tableName string, column string, asView bool) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+
+	columns, err := a.tableColumns(tableName)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	// 软删除的行不进入分区，分区中也不带标记列
+	from, columns := a.liveSource(tableName, columns)
+	if !containsString(columns, column) {
+		result["error"] = fmt.Sprintf("表 %s 中不存在列 %s", tableName, column)
+		return result
+	}
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = quoteIdent(col)
+	}
+
+	colExpr := fmt.Sprintf("COALESCE(CAST(%s AS TEXT), '')", quoteIdent(column))
+	rows, err := a.readDB().Query(fmt.Sprintf(
+		"SELECT %s AS v, COUNT(*) FROM %s GROUP BY v ORDER BY v",
+		colExpr, from,
+	))
+	if err != nil {
+		result["error"] = fmt.Sprintf("读取分区取值失败: %v", err)
+		return result
+	}
+	type partitionValue struct {
+		value string
+		count int
+	}
+	var values []partitionValue
+	for rows.Next() {
+		var pv partitionValue
+		if err := rows.Scan(&pv.value, &pv.count); err != nil {
+			rows.Close()
+			result["error"] = fmt.Sprintf("读取分区取值失败: %v", err)
+			return result
+		}
+		values = append(values, pv)
+	}
+	rows.Close()
+
+	if len(values) > maxPartitions {
+		result["error"] = fmt.Sprintf("列 %s 共有 %d 个不同取值，超过上限 %d，请选择取值更少的列", column, len(values), maxPartitions)
+		return result
+	}
+
+	kind := "TABLE"
+	if asView {
+		kind = "VIEW"
+	}
+
+	tx, err := a.writeDB().Begin()
+	if err != nil {
+		result["error"] = fmt.Sprintf("开启事务失败: %v", err)
+		return result
+	}
+
+	used := make(map[string]bool)
+	var partitions []map[string]interface{}
+	for _, pv := range values {
+		suffix := "empty"
+		if strings.TrimSpace(pv.value) != "" {
+			suffix = strings.TrimPrefix(sanitizeName(pv.value), "t_")
+		}
+		name := tableName + "_" + suffix
+		for i := 2; used[strings.ToLower(name)]; i++ {
+			name = fmt.Sprintf("%s_%s_%d", tableName, suffix, i)
+		}
+		used[strings.ToLower(name)] = true
+
+		if err := checkGeneratedTarget(tx, name, generatorPartition, tableName); err != nil {
+			tx.Rollback()
+			result["error"] = err.Error()
+			return result
+		}
+		if err := dropTableOrView(tx, name); err != nil {
+			tx.Rollback()
+			result["error"] = fmt.Sprintf("删除旧分区 %s 失败: %v", name, err)
+			return result
+		}
+		_, err := tx.Exec(fmt.Sprintf("CREATE %s %s AS SELECT %s FROM %s WHERE %s = %s",
+			kind, quoteIdent(name), strings.Join(quoted, ", "), from, colExpr, quoteLiteral(pv.value)))
+		if err != nil {
+			tx.Rollback()
+			result["error"] = fmt.Sprintf("创建分区 %s 失败: %v", name, err)
+			return result
+		}
+		if err := recordGeneratedTable(tx, name, generatorPartition, tableName); err != nil {
+			tx.Rollback()
+			result["error"] = err.Error()
+			return result
+		}
+		partitions = append(partitions, map[string]interface{}{
+			"name":  name,
+			"value": pv.value,
+			"rows":  pv.count,
+		})
+	}
+
+	if err := tx.Commit(); err != nil {
+		result["error"] = fmt.Sprintf("提交事务失败: %v", err)
+		return result
+	}
+
+	result["partitions"] = partitions
+	result["message"] = fmt.Sprintf("已按列 %s 将表 %s 拆分为 %d 个分区", column, tableName, len(partitions))
+	return result
+}
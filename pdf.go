@@ -0,0 +1,843 @@
+package main
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"unicode/utf16"
+)
+
+// 本文件是一个尽力而为的 PDF 文本提取器，只覆盖表格提取所需的最小子集：
+// 间接对象、FlateDecode 流、对象流、页面树、ToUnicode 映射和文本定位操作符。
+// 不支持加密文档和扫描件（图片）。
+
+type pdfName string
+type pdfString string
+type pdfKeyword string
+type pdfDict map[string]interface{}
+
+type pdfRef struct {
+	num int
+	gen int
+}
+
+type pdfStream struct {
+	dict pdfDict
+	data []byte
+}
+
+// pdfLexer PDF 对象/内容流词法解析器
+type pdfLexer struct {
+	buf []byte
+	pos int
+}
+
+func isPDFWhite(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == 0
+}
+
+func isPDFDelim(c byte) bool {
+	return bytes.IndexByte([]byte("()<>[]{}/%"), c) >= 0
+}
+
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.buf) {
+		c := l.buf[l.pos]
+		if isPDFWhite(c) {
+			l.pos++
+		} else if c == '%' {
+			for l.pos < len(l.buf) && l.buf[l.pos] != '\n' && l.buf[l.pos] != '\r' {
+				l.pos++
+			}
+		} else {
+			return
+		}
+	}
+}
+
+// next 读取下一个对象，读到末尾时返回 io.EOF
+func (l *pdfLexer) next() (interface{}, error) {
+	l.skipSpace()
+	if l.pos >= len(l.buf) {
+		return nil, io.EOF
+	}
+	c := l.buf[l.pos]
+	switch {
+	case c == '/':
+		l.pos++
+		start := l.pos
+		for l.pos < len(l.buf) && !isPDFWhite(l.buf[l.pos]) && !isPDFDelim(l.buf[l.pos]) {
+			l.pos++
+		}
+		return pdfName(unescapePDFName(l.buf[start:l.pos])), nil
+	case c == '(':
+		return l.literalString(), nil
+	case c == '<' && l.pos+1 < len(l.buf) && l.buf[l.pos+1] == '<':
+		l.pos += 2
+		return l.dict()
+	case c == '<':
+		return l.hexString(), nil
+	case c == '[':
+		l.pos++
+		var arr []interface{}
+		for {
+			l.skipSpace()
+			if l.pos >= len(l.buf) {
+				return arr, nil
+			}
+			if l.buf[l.pos] == ']' {
+				l.pos++
+				return arr, nil
+			}
+			obj, err := l.next()
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, obj)
+		}
+	case c == ']' || c == '>' || c == ')' || c == '{' || c == '}':
+		l.pos++
+		return pdfKeyword(string(c)), nil
+	case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		return l.number(), nil
+	default:
+		start := l.pos
+		for l.pos < len(l.buf) && !isPDFWhite(l.buf[l.pos]) && !isPDFDelim(l.buf[l.pos]) {
+			l.pos++
+		}
+		word := string(l.buf[start:l.pos])
+		switch word {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		return pdfKeyword(word), nil
+	}
+}
+
+func (l *pdfLexer) number() interface{} {
+	start := l.pos
+	l.pos++
+	for l.pos < len(l.buf) && (l.buf[l.pos] == '.' || (l.buf[l.pos] >= '0' && l.buf[l.pos] <= '9')) {
+		l.pos++
+	}
+	f, _ := strconv.ParseFloat(string(l.buf[start:l.pos]), 64)
+
+	// 整数后跟 "gen R" 时为间接引用
+	if f == math.Trunc(f) && f >= 0 {
+		save := l.pos
+		l.skipSpace()
+		genStart := l.pos
+		for l.pos < len(l.buf) && l.buf[l.pos] >= '0' && l.buf[l.pos] <= '9' {
+			l.pos++
+		}
+		if l.pos > genStart {
+			gen, _ := strconv.Atoi(string(l.buf[genStart:l.pos]))
+			l.skipSpace()
+			if l.pos < len(l.buf) && l.buf[l.pos] == 'R' &&
+				(l.pos+1 == len(l.buf) || isPDFWhite(l.buf[l.pos+1]) || isPDFDelim(l.buf[l.pos+1])) {
+				l.pos++
+				return pdfRef{num: int(f), gen: gen}
+			}
+		}
+		l.pos = save
+	}
+	return f
+}
+
+func (l *pdfLexer) dict() (interface{}, error) {
+	d := pdfDict{}
+	for {
+		l.skipSpace()
+		if l.pos+1 < len(l.buf) && l.buf[l.pos] == '>' && l.buf[l.pos+1] == '>' {
+			l.pos += 2
+			return d, nil
+		}
+		key, err := l.next()
+		if err != nil {
+			return d, nil
+		}
+		name, ok := key.(pdfName)
+		if !ok {
+			continue
+		}
+		val, err := l.next()
+		if err != nil {
+			return d, nil
+		}
+		d[string(name)] = val
+	}
+}
+
+func (l *pdfLexer) literalString() pdfString {
+	l.pos++
+	var out []byte
+	depth := 1
+	for l.pos < len(l.buf) {
+		c := l.buf[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+			out = append(out, c)
+		case ')':
+			depth--
+			if depth == 0 {
+				return pdfString(out)
+			}
+			out = append(out, c)
+		case '\\':
+			if l.pos >= len(l.buf) {
+				return pdfString(out)
+			}
+			e := l.buf[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				out = append(out, '\n')
+			case 'r':
+				out = append(out, '\r')
+			case 't':
+				out = append(out, '\t')
+			case 'b':
+				out = append(out, '\b')
+			case 'f':
+				out = append(out, '\f')
+			case '\r':
+				if l.pos < len(l.buf) && l.buf[l.pos] == '\n' {
+					l.pos++
+				}
+			case '\n':
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.buf) && l.buf[l.pos] >= '0' && l.buf[l.pos] <= '7'; i++ {
+						v = v*8 + int(l.buf[l.pos]-'0')
+						l.pos++
+					}
+					out = append(out, byte(v))
+				} else {
+					out = append(out, e)
+				}
+			}
+		default:
+			out = append(out, c)
+		}
+	}
+	return pdfString(out)
+}
+
+func (l *pdfLexer) hexString() pdfString {
+	l.pos++
+	var digits []byte
+	for l.pos < len(l.buf) && l.buf[l.pos] != '>' {
+		c := l.buf[l.pos]
+		if (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F') {
+			digits = append(digits, c)
+		}
+		l.pos++
+	}
+	l.pos++
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	out := make([]byte, len(digits)/2)
+	for i := range out {
+		v, _ := strconv.ParseUint(string(digits[i*2:i*2+2]), 16, 8)
+		out[i] = byte(v)
+	}
+	return pdfString(out)
+}
+
+func unescapePDFName(b []byte) string {
+	if bytes.IndexByte(b, '#') < 0 {
+		return string(b)
+	}
+	var out []byte
+	for i := 0; i < len(b); i++ {
+		if b[i] == '#' && i+2 < len(b) {
+			if v, err := strconv.ParseUint(string(b[i+1:i+3]), 16, 8); err == nil {
+				out = append(out, byte(v))
+				i += 2
+				continue
+			}
+		}
+		out = append(out, b[i])
+	}
+	return string(out)
+}
+
+// pdfDocument 已加载的 PDF 文档（全部对象常驻内存）
+type pdfDocument struct {
+	objects map[int]interface{}
+}
+
+var pdfObjHeader = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+// openPDF 扫描文件中的全部间接对象（后出现的同号对象覆盖先出现的，兼容增量更新）
+func openPDF(filePath string) (*pdfDocument, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("读取文件失败: %v", err)
+	}
+	if !bytes.HasPrefix(bytes.TrimSpace(content[:min(len(content), 1024)]), []byte("%PDF")) {
+		return nil, fmt.Errorf("文件不是有效的 PDF")
+	}
+	if bytes.Contains(content, []byte("/Encrypt")) {
+		return nil, fmt.Errorf("暂不支持加密的 PDF 文件")
+	}
+
+	doc := &pdfDocument{objects: map[int]interface{}{}}
+	for _, loc := range pdfObjHeader.FindAllSubmatchIndex(content, -1) {
+		num, _ := strconv.Atoi(string(content[loc[2]:loc[3]]))
+		l := &pdfLexer{buf: content, pos: loc[1]}
+		obj, err := l.next()
+		if err != nil {
+			continue
+		}
+		// 字典后紧跟 stream 关键字时读取流数据（以 endstream 为界）
+		if d, ok := obj.(pdfDict); ok {
+			save := l.pos
+			if kw, err := l.next(); err == nil && kw == pdfKeyword("stream") {
+				start := l.pos
+				if start < len(content) && content[start] == '\r' {
+					start++
+				}
+				if start < len(content) && content[start] == '\n' {
+					start++
+				}
+				end := bytes.Index(content[start:], []byte("endstream"))
+				if end < 0 {
+					continue
+				}
+				data := content[start : start+end]
+				// 长度为负数或超出范围（损坏的文件）时以 endstream 为界
+				if length, ok := d["Length"].(float64); ok && length >= 0 && length <= float64(len(data)) {
+					data = data[:int(length)]
+				}
+				obj = &pdfStream{dict: d, data: data}
+			} else {
+				l.pos = save
+			}
+		}
+		doc.objects[num] = obj
+	}
+
+	// 展开对象流中的压缩对象
+	for _, obj := range doc.objects {
+		stream, ok := obj.(*pdfStream)
+		if !ok || doc.resolve(stream.dict["Type"]) != pdfName("ObjStm") {
+			continue
+		}
+		data, err := doc.decodeStream(stream)
+		if err != nil {
+			continue
+		}
+		n, _ := doc.resolve(stream.dict["N"]).(float64)
+		first, _ := doc.resolve(stream.dict["First"]).(float64)
+		header := &pdfLexer{buf: data}
+		for i := 0; i < int(n); i++ {
+			numObj, err1 := header.next()
+			offObj, err2 := header.next()
+			num, ok1 := numObj.(float64)
+			off, ok2 := offObj.(float64)
+			if err1 != nil || err2 != nil || !ok1 || !ok2 {
+				break
+			}
+			if _, exists := doc.objects[int(num)]; exists {
+				continue
+			}
+			// 偏移为负数或超出流数据（损坏的文件）时跳过该对象
+			pos := first + off
+			if pos < 0 || pos >= float64(len(data)) {
+				continue
+			}
+			l := &pdfLexer{buf: data, pos: int(pos)}
+			if v, err := l.next(); err == nil {
+				doc.objects[int(num)] = v
+			}
+		}
+	}
+	return doc, nil
+}
+
+// resolve 解引用间接对象
+func (doc *pdfDocument) resolve(obj interface{}) interface{} {
+	for i := 0; i < 32; i++ {
+		ref, ok := obj.(pdfRef)
+		if !ok {
+			return obj
+		}
+		obj = doc.objects[ref.num]
+	}
+	return nil
+}
+
+func (doc *pdfDocument) dictOf(obj interface{}) pdfDict {
+	switch v := doc.resolve(obj).(type) {
+	case pdfDict:
+		return v
+	case *pdfStream:
+		return v.dict
+	}
+	return nil
+}
+
+// decodeStream 解码流数据，仅支持 FlateDecode
+func (doc *pdfDocument) decodeStream(stream *pdfStream) ([]byte, error) {
+	var filters []interface{}
+	switch f := doc.resolve(stream.dict["Filter"]).(type) {
+	case pdfName:
+		filters = []interface{}{f}
+	case []interface{}:
+		filters = f
+	}
+	data := stream.data
+	for _, f := range filters {
+		switch doc.resolve(f) {
+		case pdfName("FlateDecode"):
+			r, err := zlib.NewReader(bytes.NewReader(data))
+			if err != nil {
+				return nil, err
+			}
+			// 部分生成器写出的流缺少校验和，读到的内容仍可用
+			decoded, err := io.ReadAll(r)
+			if len(decoded) == 0 && err != nil {
+				return nil, err
+			}
+			data = decoded
+		default:
+			return nil, fmt.Errorf("不支持的流压缩方式: %v", f)
+		}
+	}
+	return data, nil
+}
+
+// pdfPage 页面及其继承后的资源
+type pdfPage struct {
+	dict      pdfDict
+	resources pdfDict
+}
+
+// pages 按页面树顺序返回全部页面，页面树损坏时按对象编号兜底
+func (doc *pdfDocument) pages() []pdfPage {
+	var catalog pdfDict
+	for _, obj := range doc.objects {
+		if d := doc.dictOf(obj); d != nil && doc.resolve(d["Type"]) == pdfName("Catalog") {
+			catalog = d
+			break
+		}
+	}
+
+	var result []pdfPage
+	visited := map[int]bool{}
+	var walk func(node interface{}, resources pdfDict)
+	walk = func(node interface{}, resources pdfDict) {
+		if ref, ok := node.(pdfRef); ok {
+			if visited[ref.num] {
+				return
+			}
+			visited[ref.num] = true
+		}
+		d := doc.dictOf(node)
+		if d == nil {
+			return
+		}
+		if r := doc.dictOf(d["Resources"]); r != nil {
+			resources = r
+		}
+		if doc.resolve(d["Type"]) == pdfName("Page") {
+			result = append(result, pdfPage{dict: d, resources: resources})
+			return
+		}
+		kids, _ := doc.resolve(d["Kids"]).([]interface{})
+		for _, kid := range kids {
+			walk(kid, resources)
+		}
+	}
+	if catalog != nil {
+		walk(catalog["Pages"], nil)
+	}
+	if len(result) > 0 {
+		return result
+	}
+
+	var nums []int
+	for num, obj := range doc.objects {
+		if d := doc.dictOf(obj); d != nil && doc.resolve(d["Type"]) == pdfName("Page") {
+			nums = append(nums, num)
+		}
+	}
+	sort.Ints(nums)
+	for _, num := range nums {
+		d := doc.dictOf(doc.objects[num])
+		result = append(result, pdfPage{dict: d, resources: doc.dictOf(d["Resources"])})
+	}
+	return result
+}
+
+// pdfFont 字体解码信息：ToUnicode 映射与编码字节宽度
+type pdfFont struct {
+	codeLen   int
+	toUnicode map[string]string
+}
+
+func (doc *pdfDocument) loadFont(obj interface{}) *pdfFont {
+	font := &pdfFont{codeLen: 1}
+	d := doc.dictOf(obj)
+	if d == nil {
+		return font
+	}
+	if doc.resolve(d["Subtype"]) == pdfName("Type0") {
+		font.codeLen = 2
+	}
+	stream, ok := doc.resolve(d["ToUnicode"]).(*pdfStream)
+	if !ok {
+		return font
+	}
+	data, err := doc.decodeStream(stream)
+	if err != nil {
+		return font
+	}
+	font.toUnicode, font.codeLen = parseToUnicode(data, font.codeLen)
+	return font
+}
+
+// parseToUnicode 解析 ToUnicode CMap 中的 codespacerange、bfchar 和 bfrange
+func parseToUnicode(data []byte, codeLen int) (map[string]string, int) {
+	mapping := map[string]string{}
+	l := &pdfLexer{buf: data}
+	var operands []interface{}
+	mode := ""
+	for {
+		obj, err := l.next()
+		if err != nil {
+			break
+		}
+		kw, ok := obj.(pdfKeyword)
+		if !ok {
+			operands = append(operands, obj)
+			continue
+		}
+		switch kw {
+		case "begincodespacerange", "beginbfchar", "beginbfrange":
+			mode = string(kw)
+			operands = nil
+		case "endcodespacerange":
+			if len(operands) > 0 {
+				if s, ok := operands[0].(pdfString); ok && len(s) > 0 {
+					codeLen = len(s)
+				}
+			}
+			mode = ""
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				src, ok1 := operands[i].(pdfString)
+				dst, ok2 := operands[i+1].(pdfString)
+				if ok1 && ok2 {
+					mapping[string(src)] = decodeUTF16BE([]byte(dst))
+				}
+			}
+			mode = ""
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				lo, ok1 := operands[i].(pdfString)
+				hi, ok2 := operands[i+1].(pdfString)
+				if !ok1 || !ok2 || len(lo) != len(hi) {
+					continue
+				}
+				loN, hiN := bytesToInt([]byte(lo)), bytesToInt([]byte(hi))
+				if hiN < loN || hiN-loN > 0xFFFF {
+					continue
+				}
+				switch dst := operands[i+2].(type) {
+				case pdfString:
+					base := []byte(dst)
+					for code := loN; code <= hiN; code++ {
+						out := make([]byte, len(base))
+						copy(out, base)
+						addToBytes(out, code-loN)
+						mapping[string(intToBytes(code, len(lo)))] = decodeUTF16BE(out)
+					}
+				case []interface{}:
+					for j, item := range dst {
+						if s, ok := item.(pdfString); ok && loN+j <= hiN {
+							mapping[string(intToBytes(loN+j, len(lo)))] = decodeUTF16BE([]byte(s))
+						}
+					}
+				}
+			}
+			mode = ""
+		default:
+			if mode == "" {
+				operands = nil
+			}
+		}
+	}
+	return mapping, codeLen
+}
+
+func bytesToInt(b []byte) int {
+	v := 0
+	for _, c := range b {
+		v = v<<8 | int(c)
+	}
+	return v
+}
+
+func intToBytes(v int, n int) []byte {
+	out := make([]byte, n)
+	for i := n - 1; i >= 0; i-- {
+		out[i] = byte(v)
+		v >>= 8
+	}
+	return out
+}
+
+// addToBytes 将 delta 加到大端字节序列的末尾
+func addToBytes(b []byte, delta int) {
+	for i := len(b) - 1; i >= 0 && delta > 0; i-- {
+		sum := int(b[i]) + delta
+		b[i] = byte(sum)
+		delta = sum >> 8
+	}
+}
+
+func decodeUTF16BE(b []byte) string {
+	units := make([]uint16, len(b)/2)
+	for i := range units {
+		units[i] = uint16(b[i*2])<<8 | uint16(b[i*2+1])
+	}
+	return string(utf16.Decode(units))
+}
+
+// decode 按字体将字符串编码转为文本，返回文本和字符数
+func (f *pdfFont) decode(s pdfString) (string, int) {
+	b := []byte(s)
+	if f.toUnicode == nil {
+		if f.codeLen == 2 {
+			return "", len(b) / 2
+		}
+		// 无映射的单字节字体按 Latin-1 处理
+		runes := make([]rune, len(b))
+		for i, c := range b {
+			runes[i] = rune(c)
+		}
+		return string(runes), len(b)
+	}
+	var out []rune
+	count := 0
+	for i := 0; i < len(b); {
+		n := f.codeLen
+		if i+n > len(b) {
+			n = len(b) - i
+		}
+		if text, ok := f.toUnicode[string(b[i:i+n])]; ok {
+			out = append(out, []rune(text)...)
+		} else if n > 1 {
+			if text, ok := f.toUnicode[string(b[i:i+1])]; ok {
+				out = append(out, []rune(text)...)
+				n = 1
+			}
+		}
+		count++
+		i += n
+	}
+	return string(out), count
+}
+
+// pdfTextRun 页面上一段带坐标的文本（坐标为页面用户空间，原点在左下角）
+type pdfTextRun struct {
+	x, y, endX float64
+	size       float64
+	text       string
+}
+
+type pdfMatrix [6]float64
+
+var pdfIdentity = pdfMatrix{1, 0, 0, 1, 0, 0}
+
+func (m pdfMatrix) mul(n pdfMatrix) pdfMatrix {
+	return pdfMatrix{
+		m[0]*n[0] + m[1]*n[2],
+		m[0]*n[1] + m[1]*n[3],
+		m[2]*n[0] + m[3]*n[2],
+		m[2]*n[1] + m[3]*n[3],
+		m[4]*n[0] + m[5]*n[2] + n[4],
+		m[4]*n[1] + m[5]*n[3] + n[5],
+	}
+}
+
+// textRuns 执行页面内容流中的文本操作符，收集文本片段
+// 字形宽度没有读取字体度量，按半角 0.5em、全角 1em 估算
+func (doc *pdfDocument) textRuns(page pdfPage) []pdfTextRun {
+	var content []byte
+	switch c := doc.resolve(page.dict["Contents"]).(type) {
+	case *pdfStream:
+		content, _ = doc.decodeStream(c)
+	case []interface{}:
+		for _, item := range c {
+			if s, ok := doc.resolve(item).(*pdfStream); ok {
+				data, _ := doc.decodeStream(s)
+				content = append(content, data...)
+				content = append(content, '\n')
+			}
+		}
+	}
+
+	fonts := map[string]*pdfFont{}
+	fontDict := doc.dictOf(page.resources["Font"])
+	getFont := func(name string) *pdfFont {
+		if f, ok := fonts[name]; ok {
+			return f
+		}
+		f := doc.loadFont(fontDict[name])
+		fonts[name] = f
+		return f
+	}
+
+	var runs []pdfTextRun
+	ctm := pdfIdentity
+	var ctmStack []pdfMatrix
+	tm, lm := pdfIdentity, pdfIdentity
+	var font *pdfFont = &pdfFont{codeLen: 1}
+	fontSize, leading := 0.0, 0.0
+
+	num := func(v interface{}) float64 {
+		f, _ := v.(float64)
+		return f
+	}
+	show := func(s pdfString) {
+		text, count := font.decode(s)
+		m := tm.mul(ctm)
+		scale := math.Hypot(m[0], m[1])
+		width := 0.0
+		for _, r := range text {
+			if r > 0x2E80 {
+				width += 1.0
+			} else {
+				width += 0.5
+			}
+		}
+		if text == "" {
+			width = float64(count) * 0.5
+		}
+		width *= fontSize
+		if text != "" {
+			runs = append(runs, pdfTextRun{
+				x:    m[4],
+				y:    m[5],
+				endX: m[4] + width*scale,
+				size: fontSize * math.Hypot(m[2], m[3]),
+				text: text,
+			})
+		}
+		tm = pdfMatrix{1, 0, 0, 1, width, 0}.mul(tm)
+	}
+	nextLine := func() {
+		lm = pdfMatrix{1, 0, 0, 1, 0, -leading}.mul(lm)
+		tm = lm
+	}
+
+	l := &pdfLexer{buf: content}
+	var operands []interface{}
+	for {
+		obj, err := l.next()
+		if err != nil {
+			break
+		}
+		op, ok := obj.(pdfKeyword)
+		if !ok {
+			operands = append(operands, obj)
+			continue
+		}
+		switch op {
+		case "q":
+			ctmStack = append(ctmStack, ctm)
+		case "Q":
+			if len(ctmStack) > 0 {
+				ctm = ctmStack[len(ctmStack)-1]
+				ctmStack = ctmStack[:len(ctmStack)-1]
+			}
+		case "cm":
+			if len(operands) == 6 {
+				var m pdfMatrix
+				for i := range m {
+					m[i] = num(operands[i])
+				}
+				ctm = m.mul(ctm)
+			}
+		case "BT":
+			tm, lm = pdfIdentity, pdfIdentity
+		case "Tf":
+			if len(operands) == 2 {
+				if name, ok := operands[0].(pdfName); ok {
+					font = getFont(string(name))
+				}
+				fontSize = num(operands[1])
+			}
+		case "TL":
+			if len(operands) == 1 {
+				leading = num(operands[0])
+			}
+		case "Td", "TD":
+			if len(operands) == 2 {
+				if op == "TD" {
+					leading = -num(operands[1])
+				}
+				lm = pdfMatrix{1, 0, 0, 1, num(operands[0]), num(operands[1])}.mul(lm)
+				tm = lm
+			}
+		case "Tm":
+			if len(operands) == 6 {
+				for i := range lm {
+					lm[i] = num(operands[i])
+				}
+				tm = lm
+			}
+		case "T*":
+			nextLine()
+		case "Tj":
+			if len(operands) == 1 {
+				if s, ok := operands[0].(pdfString); ok {
+					show(s)
+				}
+			}
+		case "'", "\"":
+			nextLine()
+			if len(operands) > 0 {
+				if s, ok := operands[len(operands)-1].(pdfString); ok {
+					show(s)
+				}
+			}
+		case "TJ":
+			if len(operands) == 1 {
+				items, _ := operands[0].([]interface{})
+				for _, item := range items {
+					switch v := item.(type) {
+					case pdfString:
+						show(v)
+					case float64:
+						tm = pdfMatrix{1, 0, 0, 1, -v / 1000 * fontSize, 0}.mul(tm)
+					}
+				}
+			}
+		case "BI":
+			// 跳过内联图片数据
+			if idx := bytes.Index(content[l.pos:], []byte("EI")); idx >= 0 {
+				l.pos += idx + 2
+			}
+		}
+		operands = nil
+	}
+	return runs
+}
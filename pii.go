@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// sensitiveSampleSize 敏感列检测时每列抽样的非空值数量
+const sensitiveSampleSize = 1000
+
+// sensitiveMatchRatio 非空样本中匹配比例达到该值才判定为敏感列
+const sensitiveMatchRatio = 0.6
+
+var (
+	phonePattern    = regexp.MustCompile(`^(\+?86[- ]?)?1[3-9]\d{9}$`)
+	idCardPattern   = regexp.MustCompile(`^\d{17}[\dXx]$`)
+	emailPattern    = regexp.MustCompile(`^[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}$`)
+	bankCardPattern = regexp.MustCompile(`^\d{16,19}$`)
+)
+
+// sensitiveKinds 敏感数据类型及中文名称，按检测优先级排列
+var sensitiveKinds = []struct {
+	kind  string
+	label string
+	match func(string) bool
+}{
+	{"id_card", "身份证号", isChineseIDCard},
+	{"phone", "手机号", func(v string) bool { return phonePattern.MatchString(strings.ReplaceAll(v, " ", "")) }},
+	{"email", "邮箱", emailPattern.MatchString},
+	{"bank_card", "银行卡号", isBankCard},
+}
+
+// isChineseIDCard 校验 18 位居民身份证号（含校验位）
+func isChineseIDCard(v string) bool {
+	if !idCardPattern.MatchString(v) {
+		return false
+	}
+	weights := []int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+	sum := 0
+	for i, w := range weights {
+		sum += int(v[i]-'0') * w
+	}
+	check := "10X98765432"[sum%11]
+	return strings.ToUpper(v[17:]) == string(check)
+}
+
+// isBankCard 校验 16-19 位银行卡号（Luhn 校验，允许空格/短横分隔）
+func isBankCard(v string) bool {
+	v = strings.NewReplacer(" ", "", "-", "").Replace(v)
+	if !bankCardPattern.MatchString(v) {
+		return false
+	}
+	sum := 0
+	for i := 0; i < len(v); i++ {
+		d := int(v[len(v)-1-i] - '0')
+		if i%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// detectSensitive 根据样本值判断各列的敏感类型，返回按列顺序排列的检测结果
+func detectSensitive(columns []string, samples map[string][]string) []map[string]interface{} {
+	var found []map[string]interface{}
+	for _, col := range columns {
+		values := samples[col]
+		if len(values) == 0 {
+			continue
+		}
+		best, bestRate := -1, 0.0
+		for k, kind := range sensitiveKinds {
+			matched := 0
+			for _, v := range values {
+				if kind.match(strings.TrimSpace(v)) {
+					matched++
+				}
+			}
+			rate := float64(matched) / float64(len(values))
+			if rate >= sensitiveMatchRatio && rate > bestRate {
+				best, bestRate = k, rate
+			}
+		}
+		if best >= 0 {
+			found = append(found, map[string]interface{}{
+				"column":    col,
+				"kind":      sensitiveKinds[best].kind,
+				"label":     sensitiveKinds[best].label,
+				"matchRate": bestRate,
+				"samples":   len(values),
+			})
+		}
+	}
+	return found
+}
+
+// sensitiveWarning 生成导出时的敏感列提示，无敏感列时返回空字符串
+func sensitiveWarning(found []map[string]interface{}) string {
+	if len(found) == 0 {
+		return ""
+	}
+	parts := make([]string, len(found))
+	for i, item := range found {
+		parts[i] = fmt.Sprintf("%s(%s)", item["column"], item["label"])
+	}
+	sort.Strings(parts)
+	return fmt.Sprintf("注意：以下列可能包含敏感信息，请确认后再分发：%s", strings.Join(parts, "、"))
+}
+
+// DetectSensitiveColumns 检测表中可能包含手机号、身份证号、邮箱或银行卡号的列
+// wails:export DetectSensitiveColumns
+func (a *App) DetectSensitiveColumns(tableName string) map[string]interface{} {
+	result := make(map[string]interface{})
+
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+
+	columns, err := a.tableColumns(tableName)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+
+	samples := make(map[string][]string)
+	for _, col := range columns {
+		rows, err := a.readDB().Query(fmt.Sprintf(
+			"SELECT CAST(%s AS TEXT) FROM %s WHERE %s IS NOT NULL AND TRIM(%s) <> '' LIMIT %d",
+			quoteIdent(col), quoteIdent(tableName), quoteIdent(col), quoteIdent(col), sensitiveSampleSize,
+		))
+		if err != nil {
+			result["error"] = fmt.Sprintf("读取列 %s 失败: %v", col, err)
+			return result
+		}
+		for rows.Next() {
+			var v string
+			if err := rows.Scan(&v); err != nil {
+				rows.Close()
+				result["error"] = fmt.Sprintf("读取列 %s 失败: %v", col, err)
+				return result
+			}
+			samples[col] = append(samples[col], v)
+		}
+		rows.Close()
+	}
+
+	found := detectSensitive(columns, samples)
+	result["table"] = tableName
+	result["columns"] = found
+	result["message"] = fmt.Sprintf("表 %s 共 %d 列，检测到 %d 个疑似敏感列", tableName, len(columns), len(found))
+	return result
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PreviewTable 一次返回表的前 n 行和表结构（含数据字典），用于在表浏览器中双击快速查看
+// 普通表同时返回 rowIds（各行的 rowid，与 data 一一对应），视图和 WITHOUT ROWID 表没有
+// n <= 0 时取默认行数（preview_rows 设置），最多 max_preview_rows 行
+// wails:export PreviewTable
+func (a *App) PreviewTable(tableName string, n int) map[string]interface{} {
+	schema := a.GetTableSchema(tableName)
+	if _, failed := schema["error"]; failed {
+		return schema
+	}
+
+	result := make(map[string]interface{})
+	if n <= 0 {
+		n = a.settingInt("preview_rows")
+	}
+	if limit := a.settingInt("max_preview_rows"); n > limit {
+		n = limit
+	}
+
+	columns, err := a.tableColumns(tableName)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	from, visible := a.liveSource(tableName, columns)
+
+	var total int
+	if err := a.readDB().QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s", from)).Scan(&total); err != nil {
+		result["error"] = fmt.Sprintf("读取表 %s 失败: %v", tableName, err)
+		return result
+	}
+
+	// 同时读取 rowid（rowIds 与 data 一一对应），用于软删除、恢复等按行操作
+	sqlText := fmt.Sprintf("SELECT * FROM %s LIMIT %d", from, n)
+	query := sqlText
+	withRowid := a.tableHasRowid(tableName)
+	if withRowid {
+		rowFrom := from
+		if rowFrom != quoteIdent(tableName) {
+			rowFrom, _ = liveSubquery(tableName, columns, true)
+		}
+		quoted := make([]string, len(visible))
+		for i, col := range visible {
+			quoted[i] = quoteIdent(col)
+		}
+		query = fmt.Sprintf("SELECT rowid, %s FROM %s LIMIT %d", strings.Join(quoted, ", "), rowFrom, n)
+	}
+	rows, err := a.readDB().Query(query)
+	if err != nil {
+		result["error"] = fmt.Sprintf("读取表 %s 失败: %v", tableName, err)
+		return result
+	}
+	defer rows.Close()
+	columns, err = rows.Columns()
+	if err != nil {
+		result["error"] = fmt.Sprintf("获取列名失败: %v", err)
+		return result
+	}
+	data, err := scanRowMaps(rows, columns, a.nullValue())
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	if withRowid {
+		rowIDs := make([]interface{}, len(data))
+		for i, row := range data {
+			rowIDs[i] = row[columns[0]]
+			delete(row, columns[0])
+		}
+		columns = columns[1:]
+		result["rowIds"] = rowIDs
+	}
+
+	result["table"] = tableName
+	result["label"] = schema["label"]
+	result["description"] = schema["description"]
+	result["schema"] = schema["columns"]
+	result["columns"] = columns
+	result["data"] = data
+	result["total"] = total
+	result["sql"] = sqlText
+	result["message"] = fmt.Sprintf("表 %s 共 %d 行，预览前 %d 行", tableName, total, len(data))
+	return result
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+)
+
+// 超过 profileSampleThreshold 行的表默认抽样约 profileSampleSize 行计算概况，保持界面响应
+const (
+	profileSampleThreshold = 100000
+	profileSampleSize      = 20000
+)
+
+// profileSampleBlocks 按 rowid 抽样时把 rowid 范围等分的段数，每段从随机位置起连续读取一块，只读取样本所在的页
+const profileSampleBlocks = 50
+
+// profileColumnsPerQuery 每条统计语句计算的列数：每列 6 个聚合，SQLite 单条语句最多 2000 个结果列，宽表分多条语句计算
+const profileColumnsPerQuery = 300
+
+// ProfileTable 计算表中每列的概况：非空数、空值数、去重数、最小/最大值、数值列平均值
+// 行数超过阈值时默认按约 2 万行随机抽样计算并附带置信说明，exact 为 true 时强制全表计算
+// 计算过程通过 operation-progress 事件报告阶段（见 progress.go）；表数据未变化时直接使用上次的结果，cached 为 true
+// wails:export ProfileTable
+func (a *App) ProfileTable(tableName string, exact bool) map[string]interface{} {
+	key := fmt.Sprintf("profile\x00%t\x00%t", exact, a.includeDeleted.Load())
+	cacheable := a.writeDB() != nil && a.statsCacheable()
+	if cacheable {
+		if v, ok := a.stats.get(tableName, key); ok {
+			result := copyResult(v.(map[string]interface{}))
+			result["cached"] = true
+			return result
+		}
+	}
+	version, settled := a.stats.snapshot(tableName)
+	op := a.beginOperation("", "profile", fmt.Sprintf("计算表 %s 的概况", tableName))
+	result := a.profileTable(tableName, exact, op)
+	op.finishResult(result)
+	if _, failed := result["error"]; !failed && cacheable && settled {
+		a.stats.put(tableName, key, version, copyResult(result))
+	}
+	result["cached"] = false
+	return result
+}
+
+func (a *App) profileTable(tableName string, exact bool, op *operation) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+
+	allColumns, err := a.tableColumns(tableName)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+
+	from, columns := a.liveSource(tableName, allColumns)
+	op.progress("count", 0, 0, "正在统计行数")
+	var total int
+	if err := a.readDB().QueryRow("SELECT COUNT(*) FROM " + from).Scan(&total); err != nil {
+		result["error"] = fmt.Sprintf("统计行数失败: %v", err)
+		return result
+	}
+
+	sampled := !exact && total > profileSampleThreshold
+	byRowid := false
+	if sampled {
+		from, byRowid, err = a.profileSample(tableName, allColumns, from)
+		if err != nil {
+			result["error"] = err.Error()
+			return result
+		}
+	}
+
+	op.progress("scan", 0, 0, fmt.Sprintf("正在计算 %d 列的概况", len(columns)))
+	values := []interface{}{nil}
+	for start := 0; start == 0 || start < len(columns); start += profileColumnsPerQuery {
+		if err := op.canceled(); err != nil {
+			result["error"] = err.Error()
+			return result
+		}
+		chunk := columns[start:min(start+profileColumnsPerQuery, len(columns))]
+		items := []string{"COUNT(*)"}
+		for _, col := range chunk {
+			q := quoteIdent(col)
+			num := fmt.Sprintf("CASE WHEN typeof(%s) IN ('integer', 'real') THEN %s END", q, q)
+			items = append(items,
+				fmt.Sprintf("COUNT(%s)", q),
+				fmt.Sprintf("COUNT(DISTINCT %s)", q),
+				fmt.Sprintf("MIN(%s)", q),
+				fmt.Sprintf("MAX(%s)", q),
+				fmt.Sprintf("COUNT(%s)", num),
+				fmt.Sprintf("AVG(%s)", num),
+			)
+		}
+		chunkValues := make([]interface{}, len(items))
+		ptrs := make([]interface{}, len(items))
+		for i := range chunkValues {
+			ptrs[i] = &chunkValues[i]
+		}
+		if err := a.readDB().QueryRow(fmt.Sprintf("SELECT %s FROM %s", strings.Join(items, ", "), from)).Scan(ptrs...); err != nil {
+			result["error"] = fmt.Sprintf("计算列概况失败: %v", err)
+			return result
+		}
+		// 样本按 rowid 或 LIMIT 确定，各条语句读到的是同一批行
+		values[0] = chunkValues[0]
+		values = append(values, chunkValues[1:]...)
+		if len(columns) > profileColumnsPerQuery {
+			done := min(start+profileColumnsPerQuery, len(columns))
+			op.progress("scan", done, len(columns), fmt.Sprintf("已计算 %d/%d 列的概况", done, len(columns)))
+		}
+	}
+
+	sampleRows := int(values[0].(int64))
+	scale := 1.0
+	if sampled && sampleRows > 0 {
+		scale = float64(total) / float64(sampleRows)
+	}
+	var profiles []map[string]interface{}
+	for i, col := range columns {
+		v := values[1+i*6 : 7+i*6]
+		nonNull := int(v[0].(int64))
+		nulls := sampleRows - nonNull
+		profile := map[string]interface{}{
+			"column":       col,
+			"nonNull":      int(math.Round(float64(nonNull) * scale)),
+			"nulls":        int(math.Round(float64(nulls) * scale)),
+			"distinct":     v[1],
+			"min":          profileValue(v[2]),
+			"max":          profileValue(v[3]),
+			"numericCount": int(math.Round(float64(v[4].(int64)) * scale)),
+			"avg":          v[5],
+		}
+		if sampleRows > 0 {
+			ratio := float64(nulls) / float64(sampleRows)
+			profile["nullRatio"] = ratio
+			if sampled {
+				// 空值占比的 95% 置信区间半宽
+				profile["nullRatioMargin"] = 1.96 * math.Sqrt(ratio*(1-ratio)/float64(sampleRows))
+			}
+		}
+		profiles = append(profiles, profile)
+	}
+
+	result["table"] = tableName
+	result["columns"] = profiles
+	result["totalRows"] = total
+	result["sampled"] = sampled
+	result["sampleRows"] = sampleRows
+	if sampled {
+		method := fmt.Sprintf("按 %d 行样本计算（全表 %d 行，在 %d 个随机位置各连续读取一段）", sampleRows, total, profileSampleBlocks)
+		if !byRowid {
+			method = fmt.Sprintf("按前 %d 行计算（全表 %d 行；视图和 WITHOUT ROWID 表无法按 rowid 随机定位，数据按某种顺序排列时样本可能有偏）", sampleRows, total)
+		}
+		result["notes"] = []string{
+			method + "，非空数、空值数为按比例推算的估计值",
+			"空值占比给出 95% 置信区间半宽（nullRatioMargin）",
+			"去重数为样本内的去重数，是全表去重数的下限；最小/最大值可能未覆盖极端值",
+			"需要精确结果时请使用全表计算",
+		}
+		result["message"] = fmt.Sprintf("表 %s 共 %d 行，已按 %d 行样本计算 %d 列概况", tableName, total, sampleRows, len(columns))
+	} else {
+		result["message"] = fmt.Sprintf("表 %s 共 %d 行，已计算 %d 列概况", tableName, total, len(columns))
+	}
+	return result
+}
+
+// profileSample 抽样计算概况时的数据来源，只读取样本所在的页，不扫描全表：带 rowid 的表把 rowid 范围等分为
+// profileSampleBlocks 段，每段从随机位置起按 rowid 连续读取一块；视图和 WITHOUT ROWID 表取前 profileSampleSize 行。
+// from 为 liveSource 返回的来源，byRowid 表示是否按 rowid 抽样
+func (a *App) profileSample(tableName string, columns []string, from string) (sample string, byRowid bool, err error) {
+	limited := fmt.Sprintf("(SELECT * FROM %s LIMIT %d)", from, profileSampleSize)
+	if !a.tableHasRowid(tableName) {
+		return limited, false, nil
+	}
+	var lo, hi int64
+	if err := a.readDB().QueryRow("SELECT COALESCE(MIN(rowid), 0), COALESCE(MAX(rowid), 0) FROM "+quoteIdent(tableName)).Scan(&lo, &hi); err != nil {
+		return "", false, fmt.Errorf("读取 rowid 范围失败: %v", err)
+	}
+	// 有软删除标记时从同时选出 rowid 的子查询中抽样
+	source := quoteIdent(tableName)
+	if from != source {
+		source, _ = liveSubquery(tableName, columns, true)
+	}
+	stride := (hi - lo + 1) / profileSampleBlocks
+	blockRows := int64(profileSampleSize / profileSampleBlocks)
+	blocks := make([]string, profileSampleBlocks)
+	for i := range blocks {
+		start := lo + int64(i)*stride
+		end := start + stride
+		if i == profileSampleBlocks-1 {
+			end = hi + 1
+		}
+		if span := end - start - blockRows; span > 0 {
+			start += rand.Int63n(span + 1)
+		}
+		blocks[i] = fmt.Sprintf("SELECT * FROM (SELECT * FROM %s WHERE rowid >= %d AND rowid < %d LIMIT %d)", source, start, end, blockRows)
+	}
+	return "(" + strings.Join(blocks, " UNION ALL ") + ")", true, nil
+}
+
+// profileValue 概况中的取值：TEXT 转为字符串，NULL 保持为 nil
+func profileValue(v interface{}) interface{} {
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+	return v
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// progressEvent 耗时操作（导入、导出、表概况、维护操作）的进度事件名，负载见 operation.payload：
+// {operationId, kind, title, phase, percent, done, total, message, error?}
+// 同一操作依次发送 start、若干进度阶段，最后以 done 或 failed 结束；percent 为 -1 表示进度未知
+const progressEvent = "operation-progress"
+
+// 操作的开始和结束阶段
+const (
+	phaseStart  = "start"
+	phaseDone   = "done"
+	phaseFailed = "failed"
+)
+
+// progressInterval 同一阶段内两次进度事件的最小间隔，避免逐行发送事件拖慢操作
+const progressInterval = 200 * time.Millisecond
+
+// operationSeq 生成操作 ID 的序号
+var operationSeq atomic.Int64
+
+// errOperationCanceled 操作被取消（如关闭窗口时）
+var errOperationCanceled = errors.New("操作已取消")
+
+// operation 一个正在进行的耗时操作，方法对 nil 安全（不需要报告进度的调用方传 nil 即可）
+// ctx 在操作被取消时关闭，写入循环应通过 canceled 检查，查询应通过 QueryContext 使用 ctx
+type operation struct {
+	app    *App
+	id     string
+	kind   string // import、export、profile、maintenance、benchmark、query
+	title  string
+	ctx    context.Context
+	cancel context.CancelFunc
+	// 开始时使用的数据库连接：操作结束前切换连接时，旧连接保持打开（见 dbconn.go）
+	handles *dbHandles
+	ended   sync.Once
+
+	mu        sync.Mutex
+	phase     string
+	lastEmit  time.Time
+	lastDone  int
+	startedAt time.Time
+	// 最近一次报告的进度（不受事件限流影响），供 ListActiveOperations 查询
+	done    int
+	total   int
+	message string
+}
+
+// operationRegistry 正在进行的操作，关闭窗口时据此确认并取消
+type operationRegistry struct {
+	mu   sync.Mutex
+	ops  map[string]*operation
+	done chan struct{} // 有操作结束时关闭并替换，供 wait 等待
+}
+
+// activeOperations 全部正在进行的操作
+var activeOperations = &operationRegistry{ops: make(map[string]*operation), done: make(chan struct{})}
+
+func (r *operationRegistry) add(op *operation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ops[op.id] = op
+}
+
+func (r *operationRegistry) remove(op *operation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.ops[op.id] != op {
+		return
+	}
+	delete(r.ops, op.id)
+	close(r.done)
+	r.done = make(chan struct{})
+}
+
+// list 按开始时间排列的正在进行的操作
+func (r *operationRegistry) list() []*operation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ops := make([]*operation, 0, len(r.ops))
+	for _, op := range r.ops {
+		ops = append(ops, op)
+	}
+	sort.Slice(ops, func(i, j int) bool { return ops[i].startedAt.Before(ops[j].startedAt) })
+	return ops
+}
+
+// get 按 ID 查找正在进行的操作
+func (r *operationRegistry) get(id string) (*operation, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	op, ok := r.ops[id]
+	return op, ok
+}
+
+// cancelAll 取消全部正在进行的操作，返回取消的个数
+func (r *operationRegistry) cancelAll() int {
+	ops := r.list()
+	for _, op := range ops {
+		op.cancel()
+	}
+	return len(ops)
+}
+
+// wait 等待全部操作结束（已取消的操作回滚事务、关闭文件后才会结束），超时返回 false
+func (r *operationRegistry) wait(timeout time.Duration) bool {
+	deadline := time.After(timeout)
+	for {
+		r.mu.Lock()
+		n, done := len(r.ops), r.done
+		r.mu.Unlock()
+		if n == 0 {
+			return true
+		}
+		select {
+		case <-done:
+		case <-deadline:
+			return false
+		}
+	}
+}
+
+// newOperation 创建并登记一个可取消的操作，不发送事件
+func (a *App) newOperation(id string, kind string, title string) *operation {
+	if id == "" {
+		id = fmt.Sprintf("%s-%d", kind, operationSeq.Add(1))
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	op := &operation{app: a, id: id, kind: kind, title: title, ctx: ctx, cancel: cancel, startedAt: time.Now(), handles: a.conn()}
+	op.handles.acquire()
+	activeOperations.add(op)
+	return op
+}
+
+// beginOperation 开始一个操作并发送 start 事件，id 为空时自动生成（如 import-3）
+func (a *App) beginOperation(id string, kind string, title string) *operation {
+	op := a.newOperation(id, kind, title)
+	op.emit(phaseStart, 0, 0, title, nil)
+	return op
+}
+
+// beginQuery 登记一次查询，使其可在关闭窗口时被中断；查询不发送进度事件，结束时调用 end
+func (a *App) beginQuery(sqlStr string) *operation {
+	return a.newOperation("", "query", sqlStr)
+}
+
+// end 注销操作并释放其 context，finish 会自动调用
+func (op *operation) end() {
+	if op == nil {
+		return
+	}
+	op.ended.Do(func() {
+		activeOperations.remove(op)
+		op.cancel()
+		op.handles.release()
+	})
+}
+
+// context 操作的 context，被取消时关闭；op 为 nil 时返回 context.Background()
+func (op *operation) context() context.Context {
+	if op == nil || op.ctx == nil {
+		return context.Background()
+	}
+	return op.ctx
+}
+
+// canceled 操作已被取消时返回 errOperationCanceled
+func (op *operation) canceled() error {
+	if op != nil && op.ctx != nil && op.ctx.Err() != nil {
+		return errOperationCanceled
+	}
+	return nil
+}
+
+// progress 报告当前阶段的进度（total <= 0 表示总量未知）；阶段变化时立即发送，同一阶段内按 progressInterval 限流
+func (op *operation) progress(phase string, done int, total int, message string) {
+	if op == nil {
+		return
+	}
+	op.mu.Lock()
+	op.done, op.total, op.message = done, total, message
+	changed := phase != op.phase
+	due := time.Since(op.lastEmit) >= progressInterval && done != op.lastDone
+	if !changed && !due && !(total > 0 && done >= total) {
+		op.mu.Unlock()
+		return
+	}
+	op.phase, op.lastEmit, op.lastDone = phase, time.Now(), done
+	op.mu.Unlock()
+	op.emit(phase, done, total, message, nil)
+}
+
+// finish 发送 done 或 failed 事件结束操作
+func (op *operation) finish(message string, err error) {
+	if op == nil {
+		return
+	}
+	op.end()
+	if op.app != nil {
+		op.app.notifyFinished(op, message, err)
+	}
+	if err != nil {
+		op.emit(phaseFailed, 0, 0, message, err)
+		return
+	}
+	op.emit(phaseDone, 1, 1, message, nil)
+}
+
+// finishResult 按绑定方法的返回值结束操作：有 error 键时为 failed，否则以 message 为 done 的说明
+func (op *operation) finishResult(result map[string]interface{}) {
+	if msg, ok := result["error"].(string); ok && msg != "" {
+		op.finish(msg, errors.New(msg))
+		return
+	}
+	msg, _ := result["message"].(string)
+	op.finish(msg, nil)
+}
+
+// payload 进度事件的负载
+func (op *operation) payload(phase string, done int, total int, message string, err error) map[string]interface{} {
+	percent := -1
+	if total > 0 {
+		percent = done * 100 / total
+		if percent > 100 {
+			percent = 100
+		}
+	}
+	m := map[string]interface{}{
+		"operationId": op.id,
+		"kind":        op.kind,
+		"title":       op.title,
+		"phase":       phase,
+		"percent":     percent,
+		"done":        done,
+		"total":       total,
+		"message":     message,
+		"elapsedMs":   time.Since(op.startedAt).Milliseconds(),
+	}
+	if err != nil {
+		m["error"] = err.Error()
+	}
+	return m
+}
+
+func (op *operation) emit(phase string, done int, total int, message string, err error) {
+	if op.app == nil || op.app.ctx == nil {
+		return
+	}
+	runtime.EventsEmit(op.app.ctx, progressEvent, op.payload(phase, done, total, message, err))
+}
+
+// operationKey 在 context 中传递当前操作
+type operationKey struct{}
+
+// withOperation 把操作放入 context，供深层函数（如导出）报告进度
+func withOperation(ctx context.Context, op *operation) context.Context {
+	return context.WithValue(ctx, operationKey{}, op)
+}
+
+// operationFrom 取出 context 中的操作，没有时返回 nil
+func operationFrom(ctx context.Context) *operation {
+	op, _ := ctx.Value(operationKey{}).(*operation)
+	return op
+}
+
+// snapshot 操作的当前状态：与进度事件的负载相同，canceling 表示已请求取消、正在回滚或收尾
+func (op *operation) snapshot() map[string]interface{} {
+	op.mu.Lock()
+	phase, done, total, message := op.phase, op.done, op.total, op.message
+	op.mu.Unlock()
+	if phase == "" {
+		phase, message = phaseStart, op.title
+	}
+	m := op.payload(phase, done, total, message, nil)
+	m["startedAt"] = op.startedAt.Format("2006-01-02 15:04:05")
+	m["canceling"] = op.ctx.Err() != nil
+	return m
+}
+
+// ListActiveOperations 列出后台正在进行的全部操作（导入、查询、导出、维护等），按开始时间排列：
+// operationId、kind、title、phase、percent（-1 表示进度未知）、done、total、message、elapsedMs、startedAt、canceling；
+// 供前端轮询显示任务面板
+// wails:export ListActiveOperations
+func (a *App) ListActiveOperations() []map[string]interface{} {
+	ops := activeOperations.list()
+	list := make([]map[string]interface{}, len(ops))
+	for i, op := range ops {
+		list[i] = op.snapshot()
+	}
+	return list
+}
+
+// CancelOperation 取消正在进行的操作：导入回滚未提交的事务，查询和导出中断执行，
+// 操作结束后以 failed 事件报告
+// wails:export CancelOperation
+func (a *App) CancelOperation(operationID string) string {
+	op, ok := activeOperations.get(operationID)
+	if !ok {
+		return fmt.Sprintf("操作 %s 不存在或已结束", operationID)
+	}
+	if op.ctx.Err() != nil {
+		return fmt.Sprintf("操作 %s 正在取消", operationID)
+	}
+	op.cancel()
+	return fmt.Sprintf("已请求取消操作：%s", op.title)
+}
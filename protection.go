@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// sheetProtection 导出时的工作表保护：locked 为只读的列（如主键、公式），其余列可以编辑；password 为空时不设密码
+type sheetProtection struct {
+	locked   []string
+	password string
+}
+
+// editable 可编辑列在输出列中的序号
+func (p *sheetProtection) editable(columns []string) map[int]bool {
+	cols := make(map[int]bool, len(columns))
+	for i, col := range columns {
+		if !containsString(p.locked, col) {
+			cols[i] = true
+		}
+	}
+	return cols
+}
+
+// unlockedStyles 生成并缓存各样式取消锁定后的副本（保留数字格式、填充等原有设置）
+type unlockedStyles struct {
+	f     *excelize.File
+	cache map[int]int
+}
+
+func newUnlockedStyles(f *excelize.File) *unlockedStyles {
+	return &unlockedStyles{f: f, cache: make(map[int]int)}
+}
+
+func (u *unlockedStyles) get(styleID int) int {
+	if id, ok := u.cache[styleID]; ok {
+		return id
+	}
+	style := &excelize.Style{}
+	if styleID != 0 {
+		if s, err := u.f.GetStyle(styleID); err == nil {
+			style = s
+		}
+	}
+	style.Protection = &excelize.Protection{Locked: false}
+	id, err := u.f.NewStyle(style)
+	if err != nil {
+		id = styleID
+	}
+	u.cache[styleID] = id
+	return id
+}
+
+// protectSheet 保护工作表：允许选择单元格、调整行列宽度和插入行，锁定的单元格不能修改
+func (p *sheetProtection) protectSheet(f *excelize.File, sheet string) error {
+	err := f.ProtectSheet(sheet, &excelize.SheetProtectionOptions{
+		Password:            p.password,
+		SelectLockedCells:   true,
+		SelectUnlockedCells: true,
+		FormatColumns:       true,
+		FormatRows:          true,
+		InsertRows:          true,
+	})
+	if err != nil {
+		return fmt.Errorf("保护工作表失败: %v", err)
+	}
+	return nil
+}
+
+// applyProtection 为已写入 rowCount 行数据的工作表设置保护：可编辑列（含下方用于填写的空行）取消锁定，表头和锁定列保持只读
+func (p *sheetProtection) applyProtection(f *excelize.File, sheet string, columns []string, rowCount int) error {
+	if p == nil {
+		return nil
+	}
+	unlocked := newUnlockedStyles(f)
+	for idx := range p.editable(columns) {
+		name, _ := excelize.ColumnNumberToName(idx + 1)
+		// SetColStyle 会覆盖列中已有单元格的样式，先记下再按原样式改为取消锁定
+		styles := make([]int, rowCount+1)
+		for row := 1; row <= rowCount+1; row++ {
+			styles[row-1], _ = f.GetCellStyle(sheet, fmt.Sprintf("%s%d", name, row))
+		}
+		if err := f.SetColStyle(sheet, name, unlocked.get(0)); err != nil {
+			return fmt.Errorf("设置可编辑列失败: %v", err)
+		}
+		header := fmt.Sprintf("%s1", name)
+		f.SetCellStyle(sheet, header, header, styles[0])
+		for row := 2; row <= rowCount+1; row++ {
+			cell := fmt.Sprintf("%s%d", name, row)
+			f.SetCellStyle(sheet, cell, cell, unlocked.get(styles[row-1]))
+		}
+	}
+	return p.protectSheet(f, sheet)
+}
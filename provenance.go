@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/xuri/excelize/v2"
+)
+
+// provenanceSheetName 导出工作簿中记录数据出处的隐藏 Sheet
+const provenanceSheetName = "About"
+
+// recordTableSource 记录表的导入来源（记录失败不影响导入本身），同时清除上一次导入的抽样方式和源表头
+func (a *App) recordTableSource(tableName string, source string) {
+	_, err := a.writeDB().Exec(`INSERT INTO _app_table_sources (table_name, source, imported_at) VALUES (?, ?, ?)
+		ON CONFLICT(table_name) DO UPDATE SET source = excluded.source, imported_at = excluded.imported_at, sampling = '', header = ''`,
+		tableName, source, time.Now().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		fmt.Printf("记录表 %s 的导入来源失败: %v\n", tableName, err)
+	}
+	a.touchTables(tableName)
+	if a.setting("clean_views") == "true" {
+		if _, _, err := a.createCleanView(tableName); err != nil {
+			fmt.Printf("创建表 %s 的清洗视图失败: %v\n", tableName, err)
+		}
+	}
+	if a.setting("typed_layer") == "true" {
+		if _, _, _, _, err := a.buildTypedLayer(tableName); err != nil {
+			fmt.Printf("生成表 %s 的类型化表失败: %v\n", tableName, err)
+		}
+	}
+	a.recordSchemaVersion("导入 " + tableName)
+}
+
+// recordTableSampling 记录表是抽样导入的及其抽样方式（在 recordTableSource 之后调用）
+func (a *App) recordTableSampling(tableName string, sampling string) {
+	if _, err := a.writeDB().Exec("UPDATE _app_table_sources SET sampling = ? WHERE table_name = ?", sampling, tableName); err != nil {
+		fmt.Printf("记录表 %s 的抽样方式失败: %v\n", tableName, err)
+	}
+}
+
+// lineageObservers 正在做来源分析的连接及其回调：连接上安装的授权回调（见 driver.go）会转发给它，
+// 不替换授权回调本身，以免丢失数据版本需要的 DDL 记录
+var lineageObservers sync.Map // *sqlite3.SQLiteConn -> func(op int, table, column string)
+
+// observeLineage 授权回调中调用，连接正在做来源分析时转发
+func observeLineage(conn *sqlite3.SQLiteConn, op int, table, column string) {
+	if fn, ok := lineageObservers.Load(conn); ok {
+		fn.(func(int, string, string))(op, table, column)
+	}
+}
+
+// queryLineage 借助 SQLite 授权回调分析 SQL 实际读取的表和列（视图会展开为其底层表）
+// 返回 表名 -> 读取的列（按列名排序）
+func (a *App) queryLineage(sqlStr string) (map[string][]string, error) {
+	conn, err := a.readDB().Conn(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("获取数据库连接失败: %v", err)
+	}
+	defer conn.Close()
+
+	read := make(map[string]map[string]bool)
+	err = conn.Raw(func(driverConn interface{}) error {
+		c, ok := rawSQLiteConn(driverConn)
+		if !ok {
+			return fmt.Errorf("数据库驱动不支持来源分析")
+		}
+		lineageObservers.Store(c, func(op int, table, column string) {
+			if op == sqlite3.SQLITE_READ && table != "" && !strings.HasPrefix(table, "sqlite_") {
+				if read[table] == nil {
+					read[table] = make(map[string]bool)
+				}
+				if column != "" {
+					read[table][column] = true
+				}
+			}
+		})
+		defer lineageObservers.Delete(c)
+
+		stmt, err := c.Prepare(sqlStr)
+		if err != nil {
+			return fmt.Errorf("SQL 解析失败: %v", err)
+		}
+		return stmt.Close()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	lineage := make(map[string][]string, len(read))
+	for table, cols := range read {
+		names := make([]string, 0, len(cols))
+		for col := range cols {
+			names = append(names, col)
+		}
+		sort.Strings(names)
+		lineage[table] = names
+	}
+	return lineage, nil
+}
+
+// addProvenanceSheet 在导出工作簿中添加隐藏的 About 页，记录生成时间、SQL、来源表及其导入文件
+func (a *App) addProvenanceSheet(f *excelize.File, sqlStr string, rowCount int) error {
+	lineage, err := a.queryLineage(sqlStr)
+	if err != nil {
+		return err
+	}
+	if _, err := f.NewSheet(provenanceSheetName); err != nil {
+		return err
+	}
+
+	dbPath, _ := filepath.Abs("./data.db")
+	rows := [][]interface{}{
+		{"生成时间", time.Now().Format("2006-01-02 15:04:05")},
+		{"数据库", dbPath},
+		{"行数", rowCount},
+		{"SQL", sqlStr},
+		{},
+		{"来源表", "显示名", "读取的列", "导入来源", "导入时间", "抽样方式"},
+	}
+
+	tables := make([]string, 0, len(lineage))
+	for table := range lineage {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	for _, table := range tables {
+		var source, importedAt, sampling, label string
+		a.readDB().QueryRow("SELECT source, imported_at, sampling FROM _app_table_sources WHERE table_name = ?", table).Scan(&source, &importedAt, &sampling)
+		a.readDB().QueryRow("SELECT label FROM _app_dictionary WHERE table_name = ? AND column_name = ''", table).Scan(&label)
+		rows = append(rows, []interface{}{table, label, strings.Join(lineage[table], ", "), source, importedAt, sampling})
+	}
+
+	for i, row := range rows {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		if err := f.SetSheetRow(provenanceSheetName, cell, &row); err != nil {
+			return err
+		}
+	}
+	return f.SetSheetVisible(provenanceSheetName, false)
+}
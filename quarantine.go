@@ -0,0 +1,399 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// rejectedIDColumn 查看隔离表时返回的行标识（即隔离表的 rowid）
+const rejectedIDColumn = "_rejected_id"
+
+// 隔离表机制：导入追加、类型转换等操作中无法写入目标表的行统一保存到 <表名>_rejected，
+// 保留原始文本和拒绝原因，可在修正后通过 ReapplyRejectedRows 重新写回目标表
+
+// rejectedTableSuffix 隔离表后缀
+const rejectedTableSuffix = "_rejected"
+
+// 隔离表的固定列，其后为目标表的各列（保存原始文本）
+const (
+	rejectedAtColumn     = "_rejected_at"
+	rejectedSourceColumn = "_rejected_source" // 不用 _source，合并导入的表已有同名的来源列（见 import_union.go）
+	rejectedRowColumn    = "_row_num"
+	rejectedReasonColumn = "_reason"
+
+	// legacyRejectedSourceColumn 早期版本使用的来源列名
+	legacyRejectedSourceColumn = "_source"
+)
+
+// rejectedRow 一行被拒绝的数据：raw 与写入时的目标列一一对应
+type rejectedRow struct {
+	row    int
+	raw    []string
+	errors []coercionError
+}
+
+func (r rejectedRow) reason() string {
+	parts := make([]string, len(r.errors))
+	for i, e := range r.errors {
+		parts[i] = fmt.Sprintf("%s：值 %q %v", e.column, e.value, e.err)
+	}
+	return strings.Join(parts, "；")
+}
+
+// rejectedTableName 目标表对应的隔离表名
+func rejectedTableName(tableName string) string {
+	return tableName + rejectedTableSuffix
+}
+
+// ensureRejectedTable 创建隔离表，已存在时补齐缺少的列
+func ensureRejectedTable(tx *sql.Tx, tableName string, columns []string) error {
+	name := rejectedTableName(tableName)
+	defs := []string{
+		quoteIdent(rejectedAtColumn) + " TEXT",
+		quoteIdent(rejectedSourceColumn) + " TEXT",
+		quoteIdent(rejectedRowColumn) + " INTEGER",
+		quoteIdent(rejectedReasonColumn) + " TEXT",
+	}
+	for _, col := range columns {
+		defs = append(defs, quoteIdent(col)+" TEXT")
+	}
+	if _, err := tx.Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)", quoteIdent(name), strings.Join(defs, ", "))); err != nil {
+		return fmt.Errorf("创建隔离表 %s 失败: %v", name, err)
+	}
+
+	infos, err := tableColumnInfos(tx, name)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]bool, len(infos))
+	for _, info := range infos {
+		existing[info.name] = true
+	}
+	// 早期版本的隔离表用 _source 记录来源；目标表本身没有 _source 列时它只能是来源，改为新列名
+	if !existing[rejectedSourceColumn] {
+		alter := "ADD COLUMN " + quoteIdent(rejectedSourceColumn) + " TEXT"
+		if existing[legacyRejectedSourceColumn] && !containsString(columns, legacyRejectedSourceColumn) {
+			alter = "RENAME COLUMN " + quoteIdent(legacyRejectedSourceColumn) + " TO " + quoteIdent(rejectedSourceColumn)
+		}
+		if _, err := tx.Exec(fmt.Sprintf("ALTER TABLE %s %s", quoteIdent(name), alter)); err != nil {
+			return fmt.Errorf("更新隔离表 %s 失败: %v", name, err)
+		}
+	}
+	for _, col := range columns {
+		if existing[col] {
+			continue
+		}
+		if _, err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s TEXT", quoteIdent(name), quoteIdent(col))); err != nil {
+			return fmt.Errorf("更新隔离表 %s 失败: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// quarantineRows 将被拒绝的行连同原因写入隔离表
+func quarantineRows(tx *sql.Tx, tableName string, source string, columns []string, rejects []rejectedRow) error {
+	if len(rejects) == 0 {
+		return nil
+	}
+	if err := ensureRejectedTable(tx, tableName, columns); err != nil {
+		return err
+	}
+
+	all := append([]string{rejectedAtColumn, rejectedSourceColumn, rejectedRowColumn, rejectedReasonColumn}, columns...)
+	values := make([][]interface{}, len(rejects))
+	now := time.Now().Format("2006-01-02 15:04:05")
+	for i, r := range rejects {
+		record := []interface{}{now, source, r.row, r.reason()}
+		for _, v := range r.raw {
+			record = append(record, v)
+		}
+		values[i] = record
+	}
+	return insertRows(tx, rejectedTableName(tableName), all, values, nil)
+}
+
+// isRejectedMetaColumn 是否为隔离表的固定列
+func isRejectedMetaColumn(col string) bool {
+	return col == rejectedAtColumn || col == rejectedSourceColumn || col == rejectedRowColumn || col == rejectedReasonColumn
+}
+
+// rejectedDataColumns 隔离表中保存原始数据的列（去掉固定列）
+func rejectedDataColumns(db sqlExecutor, tableName string) ([]string, error) {
+	infos, err := tableColumnInfos(db, rejectedTableName(tableName))
+	if err != nil {
+		return nil, fmt.Errorf("表 %s 没有被隔离的数据", tableName)
+	}
+	// 尚未迁移的早期隔离表：目标表没有 _source 列时，_source 是来源而不是数据
+	legacy := true
+	for _, info := range infos {
+		if info.name == rejectedSourceColumn {
+			legacy = false
+		}
+	}
+	if legacy {
+		if targetInfos, err := tableColumnInfos(db, tableName); err == nil {
+			for _, info := range targetInfos {
+				if info.name == legacyRejectedSourceColumn {
+					legacy = false
+				}
+			}
+		}
+	}
+	var columns []string
+	for _, info := range infos {
+		if isRejectedMetaColumn(info.name) || legacy && info.name == legacyRejectedSourceColumn {
+			continue
+		}
+		columns = append(columns, info.name)
+	}
+	return columns, nil
+}
+
+// ListRejectedRows 查看表的隔离数据（含拒绝原因），_rejected_id 用于修正和删除
+// wails:export ListRejectedRows
+func (a *App) ListRejectedRows(tableName string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	if _, err := rejectedDataColumns(a.writeDB(), tableName); err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+
+	rows, err := a.readDB().Query(fmt.Sprintf("SELECT rowid AS %s, * FROM %s ORDER BY rowid",
+		quoteIdent(rejectedIDColumn), quoteIdent(rejectedTableName(tableName))))
+	if err != nil {
+		result["error"] = fmt.Sprintf("读取隔离数据失败: %v", err)
+		return result
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		result["error"] = fmt.Sprintf("获取列名失败: %v", err)
+		return result
+	}
+	data, err := scanRowMaps(rows, columns, a.nullValue())
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+
+	result["columns"] = columns
+	result["data"] = data
+	result["total"] = len(data)
+	result["message"] = fmt.Sprintf("表 %s 共有 %d 行隔离数据", tableName, len(data))
+	return result
+}
+
+// UpdateRejectedRow 修正一行隔离数据，values 为 列名 -> 新的原始文本
+// wails:export UpdateRejectedRow
+func (a *App) UpdateRejectedRow(tableName string, rejectedID int, values map[string]string) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	if len(values) == 0 {
+		return "没有需要修改的列"
+	}
+	columns, err := rejectedDataColumns(a.writeDB(), tableName)
+	if err != nil {
+		return err.Error()
+	}
+
+	var sets []string
+	var args []interface{}
+	for col, v := range values {
+		if !containsString(columns, col) {
+			return fmt.Sprintf("隔离表中不存在列 %s", col)
+		}
+		sets = append(sets, quoteIdent(col)+" = ?")
+		args = append(args, v)
+	}
+	args = append(args, rejectedID)
+	res, err := a.writeDB().Exec(fmt.Sprintf("UPDATE %s SET %s WHERE rowid = ?",
+		quoteIdent(rejectedTableName(tableName)), strings.Join(sets, ", ")), args...)
+	if err != nil {
+		return fmt.Sprintf("修改隔离数据失败: %v", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Sprintf("隔离数据中不存在第 %d 行", rejectedID)
+	}
+	return fmt.Sprintf("已修改隔离数据第 %d 行（%d 列）", rejectedID, len(values))
+}
+
+// DiscardRejectedRows 删除隔离数据，ids 为空时清空整个隔离表
+// wails:export DiscardRejectedRows
+func (a *App) DiscardRejectedRows(tableName string, ids []int) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	if _, err := rejectedDataColumns(a.writeDB(), tableName); err != nil {
+		return err.Error()
+	}
+
+	name := rejectedTableName(tableName)
+	if len(ids) == 0 {
+		if _, err := a.writeDB().Exec(fmt.Sprintf("DROP TABLE %s", quoteIdent(name))); err != nil {
+			return fmt.Sprintf("删除隔离表失败: %v", err)
+		}
+		return fmt.Sprintf("已清空表 %s 的隔离数据", tableName)
+	}
+
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	res, err := a.writeDB().Exec(fmt.Sprintf("DELETE FROM %s WHERE rowid IN (%s)",
+		quoteIdent(name), strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")), args...)
+	if err != nil {
+		return fmt.Sprintf("删除隔离数据失败: %v", err)
+	}
+	n, _ := res.RowsAffected()
+	return fmt.Sprintf("已删除 %d 行隔离数据", n)
+}
+
+// ReapplyRejectedRows 按目标表当前的列类型重新转换隔离数据：成功的行写回目标表并移出隔离表，
+// 仍然失败的行更新拒绝原因；隔离表清空后自动删除
+// wails:export ReapplyRejectedRows
+func (a *App) ReapplyRejectedRows(tableName string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+
+	applied, remaining, err := a.reapplyRejected(tableName)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	result["applied"] = applied
+	result["remaining"] = remaining
+	result["message"] = fmt.Sprintf("已将 %d 行写回表 %s，%d 行仍无法转换", applied, tableName, remaining)
+	return result
+}
+
+func (a *App) reapplyRejected(tableName string) (int, int, error) {
+	tx, err := a.writeDB().Begin()
+	if err != nil {
+		return 0, 0, fmt.Errorf("开启事务失败: %v", err)
+	}
+	defer tx.Rollback()
+
+	targets, err := tableColumnInfos(tx, tableName)
+	if err != nil {
+		return 0, 0, err
+	}
+	columns, err := rejectedDataColumns(tx, tableName)
+	if err != nil {
+		return 0, 0, err
+	}
+	declTypes := make(map[string]string, len(targets))
+	for _, t := range targets {
+		declTypes[t.name] = t.declType
+	}
+
+	name := rejectedTableName(tableName)
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = quoteIdent(col)
+	}
+	rows, err := tx.Query(fmt.Sprintf("SELECT rowid, %s FROM %s ORDER BY rowid", strings.Join(quoted, ", "), quoteIdent(name)))
+	if err != nil {
+		return 0, 0, fmt.Errorf("读取隔离数据失败: %v", err)
+	}
+	type pending struct {
+		id     int64
+		values []interface{}
+		reason string
+	}
+	var items []pending
+	emptyAsNull := a.importEmptyAsNull()
+	for rows.Next() {
+		raw := make([]sql.NullString, len(columns))
+		dest := make([]interface{}, len(columns)+1)
+		var id int64
+		dest[0] = &id
+		for i := range raw {
+			dest[i+1] = &raw[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			rows.Close()
+			return 0, 0, fmt.Errorf("读取隔离数据失败: %v", err)
+		}
+
+		item := pending{id: id, values: make([]interface{}, len(columns))}
+		var reasons []string
+		for i, col := range columns {
+			declType, ok := declTypes[col]
+			if !ok {
+				if raw[i].Valid && raw[i].String != "" {
+					reasons = append(reasons, fmt.Sprintf("目标表中已没有列 %s", col))
+				}
+				continue
+			}
+			if !raw[i].Valid {
+				continue
+			}
+			v, err := coerceValue(raw[i].String, declType, emptyAsNull)
+			if err != nil {
+				reasons = append(reasons, fmt.Sprintf("%s：值 %q %v", col, raw[i].String, err))
+				continue
+			}
+			item.values[i] = v
+		}
+		item.reason = strings.Join(reasons, "；")
+		items = append(items, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, 0, fmt.Errorf("读取隔离数据失败: %v", err)
+	}
+
+	var insertColumns []string
+	var insertIdx []int
+	for i, col := range columns {
+		if _, ok := declTypes[col]; ok {
+			insertColumns = append(insertColumns, col)
+			insertIdx = append(insertIdx, i)
+		}
+	}
+
+	applied, remaining := 0, 0
+	var values [][]interface{}
+	for _, item := range items {
+		if item.reason != "" {
+			remaining++
+			if _, err := tx.Exec(fmt.Sprintf("UPDATE %s SET %s = ? WHERE rowid = ?", quoteIdent(name), quoteIdent(rejectedReasonColumn)), item.reason, item.id); err != nil {
+				return 0, 0, fmt.Errorf("更新隔离数据失败: %v", err)
+			}
+			continue
+		}
+		record := make([]interface{}, len(insertIdx))
+		for j, i := range insertIdx {
+			record[j] = item.values[i]
+		}
+		values = append(values, record)
+		if _, err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE rowid = ?", quoteIdent(name)), item.id); err != nil {
+			return 0, 0, fmt.Errorf("删除隔离数据失败: %v", err)
+		}
+		applied++
+	}
+	if len(values) > 0 {
+		if err := insertRows(tx, tableName, insertColumns, values, nil); err != nil {
+			return 0, 0, err
+		}
+	}
+	if remaining == 0 {
+		if _, err := tx.Exec(fmt.Sprintf("DROP TABLE %s", quoteIdent(name))); err != nil {
+			return 0, 0, fmt.Errorf("删除隔离表失败: %v", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("提交事务失败: %v", err)
+	}
+	return applied, remaining, nil
+}
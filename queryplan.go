@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// slowScanRowThreshold 全表扫描超过该行数的表时给出慢查询提示
+const slowScanRowThreshold = 50000
+
+// planScanPattern 匹配 EXPLAIN QUERY PLAN 中的全表扫描，如 "SCAN orders"、"SCAN TABLE orders AS o"
+var planScanPattern = regexp.MustCompile(`^SCAN (?:TABLE )?("(?:[^"]|"")+"|[^\s(]\S*)(?: AS (\S+))?(.*)$`)
+
+// fromPattern 匹配 FROM / JOIN 后的表名及别名（新版 SQLite 的查询计划中只显示别名）
+var fromPattern = regexp.MustCompile(`(?i)\b(?:FROM|JOIN)\s+("(?:[^"]|"")+"|\w+)(?:\s+(?:AS\s+)?("(?:[^"]|"")+"|\w+))?`)
+
+// sqlKeywords 不能作为别名的关键字（紧跟在表名之后时）
+var sqlKeywords = map[string]bool{
+	"where": true, "on": true, "join": true, "left": true, "right": true, "inner": true, "outer": true,
+	"cross": true, "natural": true, "group": true, "order": true, "limit": true, "union": true,
+	"using": true, "having": true, "window": true, "full": true, "except": true, "intersect": true,
+}
+
+// comparisonPattern 粗略匹配条件中参与比较的列：[别名.]列 比较运算符
+var comparisonPattern = regexp.MustCompile(`(?i)(?:("(?:[^"]|"")+"|\w+)\.)?("(?:[^"]|"")+"|\w+)\s*(?:=|<>|!=|<=|>=|<|>|\bIN\b|\bLIKE\b|\bBETWEEN\b)`)
+
+// unquoteIdent 去掉标识符的引号（"name"、`name` 或 [name]）
+func unquoteIdent(s string) string {
+	if len(s) < 2 {
+		return s
+	}
+	switch q := s[0]; {
+	case (q == '"' || q == '`') && s[len(s)-1] == q:
+		return strings.ReplaceAll(s[1:len(s)-1], string([]byte{q, q}), string(q))
+	case q == '[' && s[len(s)-1] == ']':
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// queryPlanWarnings 分析查询计划，对大表的全表扫描给出提示，并根据条件中出现的列建议索引
+// 分析失败（如非查询语句）时不返回提示
+func (a *App) queryPlanWarnings(sqlStr string) []map[string]interface{} {
+	aliases := make(map[string]string)
+	for _, m := range fromPattern.FindAllStringSubmatch(sqlStr, -1) {
+		table := unquoteIdent(m[1])
+		if alias := unquoteIdent(m[2]); alias != "" && !sqlKeywords[strings.ToLower(alias)] {
+			aliases[strings.ToLower(alias)] = table
+		}
+	}
+
+	rows, err := a.readDB().Query("EXPLAIN QUERY PLAN " + sqlStr)
+	if err != nil {
+		return nil
+	}
+	type scan struct{ table, alias, detail string }
+	var scans []scan
+	for rows.Next() {
+		var id, parent, notUsed int
+		var detail string
+		if err := rows.Scan(&id, &parent, &notUsed, &detail); err != nil {
+			break
+		}
+		m := planScanPattern.FindStringSubmatch(detail)
+		// 覆盖索引扫描也要读完整个索引，同样视为全表扫描；CONSTANT ROW、子查询等不是表
+		if m == nil || m[1] == "CONSTANT" {
+			continue
+		}
+		s := scan{table: unquoteIdent(m[1]), alias: unquoteIdent(m[2]), detail: detail}
+		if table, ok := aliases[strings.ToLower(s.table)]; ok && s.alias == "" {
+			s.table, s.alias = table, s.table
+		}
+		scans = append(scans, s)
+	}
+	rows.Close()
+
+	var warnings []map[string]interface{}
+	seen := make(map[string]bool)
+	for _, s := range scans {
+		if seen[s.table+"\x00"+s.alias] {
+			continue
+		}
+		seen[s.table+"\x00"+s.alias] = true
+
+		count, err := a.cachedRowCount(s.table)
+		if err != nil {
+			continue
+		}
+		if count < slowScanRowThreshold {
+			continue
+		}
+
+		warning := map[string]interface{}{
+			"table":          s.table,
+			"rowCount":       count,
+			"detail":         s.detail,
+			"suggestedIndex": "",
+			"message":        fmt.Sprintf("查询对表 %s（%d 行）进行了全表扫描，数据量大时会较慢", s.table, count),
+		}
+		if cols := conditionColumns(a, sqlStr, s.table, s.alias); len(cols) > 0 {
+			quoted := make([]string, len(cols))
+			for i, c := range cols {
+				quoted[i] = quoteIdent(c)
+			}
+			index := fmt.Sprintf("CREATE INDEX %s ON %s (%s)",
+				quoteIdent(sanitizeName("idx_"+s.table+"_"+strings.Join(cols, "_"))), quoteIdent(s.table), strings.Join(quoted, ", "))
+			warning["suggestedIndex"] = index
+			warning["message"] = fmt.Sprintf("%s，可考虑建立索引：%s", warning["message"], index)
+		}
+		warnings = append(warnings, warning)
+	}
+	return warnings
+}
+
+// conditionColumns 找出 SQL 条件中与该表有关的比较列（按表名或别名限定，或未限定但属于该表的列）
+func conditionColumns(a *App, sqlStr string, table string, alias string) []string {
+	columns, err := a.tableColumns(table)
+	if err != nil {
+		return nil
+	}
+	byKey := make(map[string]string, len(columns))
+	for _, c := range columns {
+		byKey[strings.ToLower(c)] = c
+	}
+
+	// 只看 WHERE / ON 之后的部分，避免把 SELECT 列表中的表达式算进来
+	lower := strings.ToLower(sqlStr)
+	start := len(sqlStr)
+	for _, kw := range []string{" where ", " on "} {
+		if idx := strings.Index(lower, kw); idx >= 0 && idx < start {
+			start = idx
+		}
+	}
+	if start == len(sqlStr) {
+		return nil
+	}
+
+	found := make(map[string]bool)
+	var result []string
+	for _, m := range comparisonPattern.FindAllStringSubmatch(sqlStr[start:], -1) {
+		qualifier := strings.ToLower(unquoteIdent(m[1]))
+		if qualifier != "" && qualifier != strings.ToLower(table) && qualifier != strings.ToLower(alias) {
+			continue
+		}
+		col, ok := byKey[strings.ToLower(unquoteIdent(m[2]))]
+		if !ok || found[col] {
+			continue
+		}
+		found[col] = true
+		result = append(result, col)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// readPoolSize 只读连接池保留的空闲连接数
+const readPoolSize = 4
+
+// openReadPool 打开供查询和导出使用的只读连接池：数据库为 WAL 模式，读连接读取已提交的快照，
+// 不会排在导入事务之后等待；连接设置 query_only，误用于写入时直接报错而不是与写连接争锁。
+// 不限制连接数：导出等操作占用一个连接遍历结果时还会读取设置和表结构，限制连接数时并发导出会互相等待
+func openReadPool(readOnly bool) (*sql.DB, error) {
+	dsn := fmt.Sprintf("file:./data.db?_busy_timeout=%d&_query_only=true", sqliteBusyTimeoutMs)
+	if readOnly {
+		dsn += "&mode=ro"
+	}
+	pool, err := sql.Open(sqliteDriverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("打开只读连接失败: %v", err)
+	}
+	pool.SetMaxIdleConns(readPoolSize)
+	if err := pool.Ping(); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("打开只读连接失败: %v", err)
+	}
+	return pool, nil
+}
+
+// readDB 查询和导出使用的连接池；查看分享包时没有单独的只读连接池，使用分享包的连接池
+func (a *App) readDB() *sql.DB {
+	h := a.conn()
+	if h.reader != nil {
+		return h.reader
+	}
+	return h.db
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 内置日历表覆盖的年份范围
+const (
+	referenceCalendarFrom = 2000
+	referenceCalendarTo   = 2040
+)
+
+// referenceColumn 参考表的列定义
+type referenceColumn struct {
+	name     string
+	declType string
+	label    string
+}
+
+// referenceTable 可安装到数据库的内置参考表
+type referenceTable struct {
+	name        string
+	generator   string // 登记在 _app_generated_tables 中的生成方式，为空时为 generatorReference
+	label       string
+	description string
+	columns     []referenceColumn
+	rows        func(a *App) ([][]interface{}, error)
+}
+
+// referenceTables 全部内置参考表，按名称安装为 ref_ 前缀的表
+var referenceTables = []referenceTable{
+	{
+		name:        "ref_regions",
+		label:       "行政区划",
+		description: "省级和地级行政区划（省级带 GB/T 2260 代码），可与拆分出的 _省/_市 列关联",
+		columns: []referenceColumn{
+			{"code", "TEXT", "区划代码"},
+			{"name", "TEXT", "名称"},
+			{"short_name", "TEXT", "简称"},
+			{"level", "TEXT", "级别"},
+			{"province", "TEXT", "所属省级"},
+		},
+		rows: regionReferenceRows,
+	},
+	{
+		name:        "ref_currencies",
+		label:       "币种代码",
+		description: "常用币种的 ISO 4217 字母代码、数字代码和小数位数",
+		columns: []referenceColumn{
+			{"code", "TEXT", "币种代码"},
+			{"name", "TEXT", "名称"},
+			{"numeric_code", "TEXT", "数字代码"},
+			{"minor_unit", "INTEGER", "小数位数"},
+		},
+		rows: currencyReferenceRows,
+	},
+	{
+		name:  "ref_calendar",
+		label: "日历",
+		description: fmt.Sprintf("%d-%d 年逐日日历，节假日和调休按内置的法定节假日安排（%d-%d 年）和节假日设置标注",
+			referenceCalendarFrom, referenceCalendarTo, builtinHolidayFrom, builtinHolidayTo),
+		columns: calendarColumns,
+		rows: func(a *App) ([][]interface{}, error) {
+			from := time.Date(referenceCalendarFrom, 1, 1, 0, 0, 0, 0, time.UTC)
+			to := time.Date(referenceCalendarTo, 12, 31, 0, 0, 0, 0, time.UTC)
+			return a.calendarRows(from, to)
+		},
+	},
+}
+
+// regionReferenceRows 行政区划参考表的数据（来自内置行政区划表）
+func regionReferenceRows(a *App) ([][]interface{}, error) {
+	var rows [][]interface{}
+	for _, p := range regionProvinces {
+		rows = append(rows, []interface{}{p.code, p.name, p.short, "省级", p.name})
+		for _, c := range citiesOf(p) {
+			rows = append(rows, []interface{}{nil, c.name, c.short, "地级", p.name})
+		}
+	}
+	return rows, nil
+}
+
+// currencyData 常用币种：字母代码|名称|数字代码|小数位数
+var currencyData = []string{
+	"CNY|人民币|156|2", "USD|美元|840|2", "EUR|欧元|978|2", "JPY|日元|392|0", "GBP|英镑|826|2",
+	"HKD|港元|344|2", "MOP|澳门元|446|2", "TWD|新台币|901|2", "KRW|韩元|410|0", "SGD|新加坡元|702|2",
+	"AUD|澳大利亚元|036|2", "CAD|加拿大元|124|2", "CHF|瑞士法郎|756|2", "NZD|新西兰元|554|2", "SEK|瑞典克朗|752|2",
+	"NOK|挪威克朗|578|2", "DKK|丹麦克朗|208|2", "RUB|俄罗斯卢布|643|2", "INR|印度卢比|356|2", "THB|泰铢|764|2",
+	"MYR|马来西亚林吉特|458|2", "IDR|印度尼西亚盾|360|2", "PHP|菲律宾比索|608|2", "VND|越南盾|704|0", "AED|阿联酋迪拉姆|784|2",
+	"SAR|沙特里亚尔|682|2", "BRL|巴西雷亚尔|986|2", "MXN|墨西哥比索|484|2", "ZAR|南非兰特|710|2", "TRY|土耳其里拉|949|2",
+	"PLN|波兰兹罗提|985|2", "HUF|匈牙利福林|348|2", "CZK|捷克克朗|203|2", "ILS|以色列新谢克尔|376|2", "KZT|哈萨克斯坦坚戈|398|2",
+	"PKR|巴基斯坦卢比|586|2", "EGP|埃及镑|818|2",
+}
+
+func currencyReferenceRows(a *App) ([][]interface{}, error) {
+	rows := make([][]interface{}, 0, len(currencyData))
+	for _, line := range currencyData {
+		parts := strings.Split(line, "|")
+		minor := int(parts[3][0] - '0')
+		rows = append(rows, []interface{}{parts[0], parts[1], parts[2], minor})
+	}
+	return rows, nil
+}
+
+// installReferenceTable 重新创建参考表并写入数据，同时登记数据字典中的显示名和导入来源；
+// 同名的表不是此前安装的参考表时拒绝覆盖
+func (a *App) installReferenceTable(ref referenceTable) (int, error) {
+	generator := ref.generator
+	if generator == "" {
+		generator = generatorReference
+	}
+	tx, err := a.writeDB().Begin()
+	if err != nil {
+		return 0, fmt.Errorf("开启事务失败: %v", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkGeneratedTarget(tx, ref.name, generator, ""); err != nil {
+		return 0, err
+	}
+	if err := dropTableOrView(tx, ref.name); err != nil {
+		return 0, err
+	}
+	defs := make([]string, len(ref.columns))
+	names := make([]string, len(ref.columns))
+	for i, col := range ref.columns {
+		defs[i] = quoteIdent(col.name) + " " + col.declType
+		names[i] = col.name
+	}
+	if _, err := tx.Exec(fmt.Sprintf("CREATE TABLE %s (%s)", quoteIdent(ref.name), strings.Join(defs, ", "))); err != nil {
+		return 0, fmt.Errorf("创建表 %s 失败: %v", ref.name, err)
+	}
+	rows, err := ref.rows(a)
+	if err != nil {
+		return 0, err
+	}
+	if err := insertRows(tx, ref.name, names, rows, nil); err != nil {
+		return 0, err
+	}
+	if err := recordGeneratedTable(tx, ref.name, generator, ""); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("提交事务失败: %v", err)
+	}
+
+	if err := a.saveDictionaryEntry(ref.name, "", ref.label, ref.description); err != nil {
+		fmt.Printf("保存表 %s 的说明失败: %v\n", ref.name, err)
+	}
+	for _, col := range ref.columns {
+		if err := a.saveDictionaryEntry(ref.name, col.name, col.label, ""); err != nil {
+			fmt.Printf("保存列 %s 的说明失败: %v\n", col.name, err)
+		}
+	}
+	a.recordTableSource(ref.name, "内置参考表")
+	return len(rows), nil
+}
+
+// ListReferenceTables 列出可安装的内置参考表（行政区划、币种代码、日历）及是否已安装
+// wails:export ListReferenceTables
+func (a *App) ListReferenceTables() []map[string]interface{} {
+	list := make([]map[string]interface{}, 0, len(referenceTables))
+	for _, ref := range referenceTables {
+		installed := false
+		if a.writeDB() != nil {
+			_, err := a.tableColumns(ref.name)
+			installed = err == nil
+		}
+		list = append(list, map[string]interface{}{
+			"name":        ref.name,
+			"label":       ref.label,
+			"description": ref.description,
+			"installed":   installed,
+		})
+	}
+	return list
+}
+
+// InstallReferenceTables 安装（或重新安装）内置参考表，names 为空时安装全部
+// wails:export InstallReferenceTables
+func (a *App) InstallReferenceTables(names []string) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	for _, name := range names {
+		found := false
+		for _, ref := range referenceTables {
+			found = found || ref.name == name
+		}
+		if !found {
+			return fmt.Sprintf("不存在内置参考表 %s", name)
+		}
+	}
+
+	var installed []string
+	for _, ref := range referenceTables {
+		if len(names) > 0 && !containsString(names, ref.name) {
+			continue
+		}
+		n, err := a.installReferenceTable(ref)
+		if err != nil {
+			return err.Error()
+		}
+		installed = append(installed, fmt.Sprintf("%s（%d 行）", ref.name, n))
+	}
+	return "已安装参考表: " + strings.Join(installed, "、")
+}
@@ -0,0 +1,462 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// defaultColumnPattern 导入生成的 column1..N 列名，序号对应源表头中的位置
+var defaultColumnPattern = regexp.MustCompile(`^column(\d+)$`)
+
+// sourceHeader 导入时源文件的表头：header 为源表头（按列顺序），columns 为 表列 -> 对应的源表头
+type sourceHeader struct {
+	Header  []string          `json:"header"`
+	Columns map[string]string `json:"columns"`
+}
+
+// positionalHeader 按 column1..N 与表头逐列对应生成 sourceHeader
+func positionalHeader(header []string) sourceHeader {
+	h := sourceHeader{Header: header, Columns: make(map[string]string, len(header))}
+	for i, name := range header {
+		h.Columns[fmt.Sprintf("column%d", i+1)] = strings.TrimSpace(name)
+	}
+	return h
+}
+
+// recordTableHeader 记录表导入时的源表头（在 recordTableSource 之后调用），刷新时据此检测结构变化
+func (a *App) recordTableHeader(tableName string, h sourceHeader) {
+	data, _ := json.Marshal(h)
+	if _, err := a.writeDB().Exec("UPDATE _app_table_sources SET header = ? WHERE table_name = ?", string(data), tableName); err != nil {
+		fmt.Printf("记录表 %s 的源表头失败: %v\n", tableName, err)
+	}
+	if a.setting("header_views") == "true" {
+		if _, _, err := a.createHeaderView(tableName, &h); err != nil {
+			fmt.Printf("创建表 %s 的表头视图失败: %v\n", tableName, err)
+		}
+	}
+}
+
+// schemaDrift 源文件表头与现有表结构的差异；mapping 为 表列 -> 新表头中的下标，addedIdx 为新增列在新表头中的下标
+type schemaDrift struct {
+	added      []string
+	addedIdx   []int
+	removed    []map[string]interface{}
+	renamed    []map[string]interface{}
+	byPosition bool
+	mapping    map[string]int
+}
+
+func (d *schemaDrift) changed() bool {
+	return len(d.added)+len(d.removed)+len(d.renamed) > 0
+}
+
+func (d *schemaDrift) summary() string {
+	if !d.changed() {
+		return "表头未变化"
+	}
+	var parts []string
+	if len(d.added) > 0 {
+		parts = append(parts, fmt.Sprintf("新增 %d 列（%s）", len(d.added), strings.Join(d.added, "、")))
+	}
+	if len(d.removed) > 0 {
+		names := make([]string, len(d.removed))
+		for i, r := range d.removed {
+			names[i] = fmt.Sprint(r["column"])
+			if h := fmt.Sprint(r["header"]); h != "" {
+				names[i] += "/" + h
+			}
+		}
+		parts = append(parts, fmt.Sprintf("删除 %d 列（%s）", len(d.removed), strings.Join(names, "、")))
+	}
+	if len(d.renamed) > 0 {
+		names := make([]string, len(d.renamed))
+		for i, r := range d.renamed {
+			names[i] = fmt.Sprintf("%v→%v", r["from"], r["to"])
+		}
+		parts = append(parts, fmt.Sprintf("重命名 %d 列（%s）", len(d.renamed), strings.Join(names, "、")))
+	}
+	return strings.Join(parts, "，")
+}
+
+func (d *schemaDrift) toMap() map[string]interface{} {
+	return map[string]interface{}{
+		"changed":    d.changed(),
+		"added":      d.added,
+		"removed":    d.removed,
+		"renamed":    d.renamed,
+		"byPosition": d.byPosition,
+		"summary":    d.summary(),
+	}
+}
+
+// detectSchemaDrift 比较新表头与导入时记录的表头：按名称（忽略大小写和全半角）对应，名称找不到但原位置上是一个新名称的视为重命名；
+// 没有记录表头的旧表按 column1..N 的位置对应；源文件中原本就有但未导入的列（见 ImportSelectedColumns）不算新增
+func detectSchemaDrift(tableColumns []string, old *sourceHeader, header []string) *schemaDrift {
+	d := &schemaDrift{mapping: make(map[string]int)}
+	used := make(map[int]bool)
+	if old == nil {
+		d.byPosition = true
+		for _, col := range tableColumns {
+			m := defaultColumnPattern.FindStringSubmatch(col)
+			if m == nil {
+				continue
+			}
+			idx, _ := strconv.Atoi(m[1])
+			if idx-1 < len(header) {
+				d.mapping[col] = idx - 1
+				used[idx-1] = true
+			} else {
+				d.removed = append(d.removed, map[string]interface{}{"column": col, "header": ""})
+			}
+		}
+		for i, name := range header {
+			if !used[i] && strings.TrimSpace(name) != "" {
+				d.added = append(d.added, strings.TrimSpace(name))
+				d.addedIdx = append(d.addedIdx, i)
+			}
+		}
+		return d
+	}
+
+	known := make(map[string]bool, len(old.Header))
+	for _, name := range old.Header {
+		known[foldKey(strings.TrimSpace(name))] = true
+	}
+	newIdx := make(map[string]int, len(header))
+	for i := len(header) - 1; i >= 0; i-- {
+		newIdx[foldKey(strings.TrimSpace(header[i]))] = i
+	}
+	var unresolved []string
+	for _, col := range tableColumns {
+		name, ok := old.Columns[col]
+		if !ok {
+			continue
+		}
+		if idx, found := newIdx[foldKey(name)]; found && !used[idx] {
+			d.mapping[col] = idx
+			used[idx] = true
+		} else {
+			unresolved = append(unresolved, col)
+		}
+	}
+	for _, col := range unresolved {
+		name := old.Columns[col]
+		pos := -1
+		for i, h := range old.Header {
+			if strings.TrimSpace(h) == name {
+				pos = i
+				break
+			}
+		}
+		if pos >= 0 && pos < len(header) && !used[pos] && !known[foldKey(strings.TrimSpace(header[pos]))] {
+			d.mapping[col] = pos
+			used[pos] = true
+			d.renamed = append(d.renamed, map[string]interface{}{"column": col, "from": name, "to": strings.TrimSpace(header[pos])})
+			continue
+		}
+		d.removed = append(d.removed, map[string]interface{}{"column": col, "header": name})
+	}
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		if !used[i] && name != "" && !known[foldKey(name)] {
+			d.added = append(d.added, name)
+			d.addedIdx = append(d.addedIdx, i)
+		}
+	}
+	return d
+}
+
+// readSourceSheet 读取导入来源 文件路径/Sheet名 指向的工作表
+func readSourceSheet(source string) ([][]string, error) {
+	i := strings.LastIndex(source, "/")
+	ext := ""
+	if i > 0 {
+		ext = strings.ToLower(filepath.Ext(source[:i]))
+	}
+	if ext != ".xlsx" && ext != ".xlsm" && ext != ".xlsb" {
+		return nil, fmt.Errorf("只支持刷新从 Excel 工作表导入的表（来源：%s）", source)
+	}
+	filePath, sheetName := source[:i], source[i+1:]
+	if ext == ".xlsb" {
+		sheets, err := readXLSB(filePath)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range sheets {
+			if s.name == sheetName {
+				return s.rows, nil
+			}
+		}
+		return nil, fmt.Errorf("文件 %s 中没有工作表 %s", filePath, sheetName)
+	}
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("Excel 解析失败: %v", err)
+	}
+	defer f.Close()
+	if idx, _ := f.GetSheetIndex(sheetName); idx < 0 {
+		return nil, fmt.Errorf("文件 %s 中没有工作表 %s", filePath, sheetName)
+	}
+	rows, err := f.GetRows(sheetName)
+	if err != nil {
+		return nil, fmt.Errorf("读取 Sheet %s 失败: %v", sheetName, err)
+	}
+	return rows, nil
+}
+
+// RefreshTable 从表记录的导入来源（Excel 文件中的工作表）重新读取数据，保留表结构、索引和数据字典，只替换数据；
+// 源文件的第 i 行原地更新表中的第 i 行，rowid 和表中不来自源文件的列（用户加的列、软删除标记等）保持不变
+// 先比较源表头与导入时的表头，检测新增、删除和重命名的列（drift），strategy 决定如何处理结构变化：
+// fail（缺省）有变化时不刷新，只报告差异；add 为新增的列在表中加列；ignore 忽略新增的列；
+// 两种策略下重命名的列按新位置读取，删除的列保留在表中但写入空值
+// wails:export RefreshTable
+func (a *App) RefreshTable(tableName string, strategy string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	if a.readOnly() {
+		result["error"] = readOnlyMessage
+		return result
+	}
+	strategy = strings.ToLower(strings.TrimSpace(strategy))
+	if strategy == "" {
+		strategy = "fail"
+	}
+	if strategy != "fail" && strategy != "add" && strategy != "ignore" {
+		result["error"] = fmt.Sprintf("不支持的策略 %s，可选 fail、add、ignore", strategy)
+		return result
+	}
+
+	var source, sampling, headerJSON string
+	err := a.readDB().QueryRow("SELECT source, sampling, header FROM _app_table_sources WHERE table_name = ?", tableName).Scan(&source, &sampling, &headerJSON)
+	if err != nil {
+		result["error"] = fmt.Sprintf("表 %s 没有记录导入来源，无法刷新", tableName)
+		return result
+	}
+	if sampling != "" {
+		result["error"] = fmt.Sprintf("表 %s 是抽样导入的（%s），请重新抽样导入", tableName, sampling)
+		return result
+	}
+	var old *sourceHeader
+	if headerJSON != "" {
+		old = &sourceHeader{}
+		if err := json.Unmarshal([]byte(headerJSON), old); err != nil {
+			old = nil
+		}
+	}
+	tableCols, err := a.tableColumns(tableName)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	rows, err := readSourceSheet(source)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	if len(rows) == 0 {
+		result["error"] = fmt.Sprintf("来源 %s 中没有数据，未刷新", source)
+		return result
+	}
+	header, data := rows[0], rows[1:]
+
+	drift := detectSchemaDrift(tableCols, old, header)
+	result["drift"] = drift.toMap()
+	result["strategy"] = strategy
+	if drift.changed() && strategy == "fail" {
+		result["error"] = fmt.Sprintf("源文件结构已变化：%s，未刷新；可选择 add（新增列）或 ignore（忽略新增列）策略后重试", drift.summary())
+		return result
+	}
+
+	// 写入的列：能对应到新表头的已有列和删除的列（写入空值），add 策略下再加上新增的列
+	var columns []string
+	var srcIdx []int
+	recorded := sourceHeader{Header: header, Columns: make(map[string]string)}
+	for _, col := range tableCols {
+		if idx, ok := drift.mapping[col]; ok {
+			columns = append(columns, col)
+			srcIdx = append(srcIdx, idx)
+			recorded.Columns[col] = strings.TrimSpace(header[idx])
+		}
+	}
+	for _, r := range drift.removed {
+		columns = append(columns, r["column"].(string))
+		srcIdx = append(srcIdx, -1)
+	}
+	var newColumns []string
+	if strategy == "add" {
+		next := 1
+		for _, col := range tableCols {
+			if m := defaultColumnPattern.FindStringSubmatch(col); m != nil {
+				if n, _ := strconv.Atoi(m[1]); n >= next {
+					next = n + 1
+				}
+			}
+		}
+		for i, idx := range drift.addedIdx {
+			col := fmt.Sprintf("column%d", next)
+			next++
+			newColumns = append(newColumns, col)
+			columns = append(columns, col)
+			srcIdx = append(srcIdx, idx)
+			recorded.Columns[col] = drift.added[i]
+		}
+	}
+	values := make([][]string, len(data))
+	for r, row := range data {
+		out := make([]string, len(columns))
+		for i, idx := range srcIdx {
+			if idx >= 0 && idx < len(row) {
+				out[i] = row[idx]
+			}
+		}
+		values[r] = out
+	}
+	// 导入时带来源列的表，刷新后按新文件重新填写来源列
+	if containsString(tableCols, sourceRowColumn) {
+		i := strings.LastIndex(source, "/")
+		columns, values = appendSourceColumns(columns, values, source[:i], source[i+1:], 2)
+	}
+
+	journalID := a.beginImportJournal(tableName, len(values))
+	op := a.beginOperation("", "import", fmt.Sprintf("刷新表 %s", tableName))
+	err = withBusyRetry(func() error {
+		return a.replaceTableRows(tableName, columns, newColumns, values, containsString(tableCols, rowHashColumn), op)
+	})
+	a.finishImportJournal(journalID, err)
+	op.finish(fmt.Sprintf("表 %s 刷新完成（%d 行）", tableName, len(values)), err)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	a.recordTableSource(tableName, source)
+	a.recordTableHeader(tableName, recorded)
+
+	result["rows"] = len(values)
+	result["addedColumns"] = newColumns
+	result["message"] = fmt.Sprintf("表 %s 已从 %s 刷新（%d 行）；%s", tableName, source, len(values), drift.summary())
+	return result
+}
+
+// replaceTableRows 在一个事务内为表加上 newColumns 并用新数据替换原有数据（见 mergeStagedRows）：
+// 新数据先写入暂存表，后台优先级时分批写入（见 throttle.go），最后的事务中并入表，失败时表保持不变
+func (a *App) replaceTableRows(tableName string, columns []string, newColumns []string, rows [][]string, withHash bool, op *operation) error {
+	if len(columns) == 0 {
+		return errors.New("刷新失败：源文件中没有可对应到表的列")
+	}
+	background := a.backgroundImport()
+	staging := stagingTableName(tableName)
+	createSQL := a.textTableSQL(staging, columns, withHash)
+	if background {
+		err := a.stageInBatches(staging, createSQL, len(rows), func(tx *sql.Tx, start, end int) error {
+			return a.insertTextRows(tx, staging, columns, rows[start:end], withHash, nil)
+		}, op)
+		if err != nil {
+			return err
+		}
+	}
+	withRowid := a.tableHasRowid(tableName)
+
+	tx, err := a.writeDB().Begin()
+	if err != nil {
+		if background {
+			a.dropStagingTable(staging)
+		}
+		return fmt.Errorf("开启事务失败: %v", err)
+	}
+	fail := func(err error) error {
+		tx.Rollback()
+		if background {
+			a.dropStagingTable(staging)
+		}
+		return err
+	}
+	if !background {
+		// 暂存表在同一事务中创建和删除，失败时随事务回滚
+		if _, err := tx.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", quoteIdent(staging))); err != nil {
+			return fail(fmt.Errorf("删除暂存表 %s 失败: %v", staging, err))
+		}
+		if _, err := tx.Exec(createSQL); err != nil {
+			return fail(fmt.Errorf("创建暂存表 %s 失败: %v", staging, err))
+		}
+		if err := a.insertTextRows(tx, staging, columns, rows, withHash, op); err != nil {
+			return fail(err)
+		}
+	}
+	for _, col := range newColumns {
+		if _, err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", quoteIdent(tableName), quoteIdent(col), a.importColumnType())); err != nil {
+			return fail(fmt.Errorf("添加列 %s 失败: %v", col, err))
+		}
+	}
+	written := columns
+	if withHash {
+		written = append(append([]string(nil), columns...), rowHashColumn)
+	}
+	if err := mergeStagedRows(tx, tableName, staging, written, withRowid); err != nil {
+		return fail(err)
+	}
+	if _, err := tx.Exec(fmt.Sprintf("DROP TABLE %s", quoteIdent(staging))); err != nil {
+		return fail(fmt.Errorf("删除暂存表 %s 失败: %v", staging, err))
+	}
+	if err := tx.Commit(); err != nil {
+		if background {
+			a.dropStagingTable(staging)
+		}
+		return fmt.Errorf("提交事务失败: %v", err)
+	}
+	a.analyzeAfterImport(tableName, len(rows))
+	return nil
+}
+
+// mergeStagedRows 把暂存表中的新数据按行的位置并入表：暂存表第 i 行更新表中按 rowid 排第 i 的行，
+// 多出的新行追加在后面，多出的旧行删除。原地更新保留 rowid 和 columns 以外的列（用户加的列、软删除标记、
+// 补充的查找列等），按 rowid 引用行的批注仍对应源文件中同一位置的行；WITHOUT ROWID 表只能清空后重新写入
+func mergeStagedRows(tx *sql.Tx, tableName string, staging string, columns []string, withRowid bool) error {
+	quoted := make([]string, len(columns))
+	sets := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = quoteIdent(col)
+		sets[i] = fmt.Sprintf("%s = s.%s", quoteIdent(col), quoteIdent(col))
+	}
+	list := strings.Join(quoted, ", ")
+	table, stagingTable := quoteIdent(tableName), quoteIdent(staging)
+	if !withRowid {
+		if _, err := tx.Exec(fmt.Sprintf("DELETE FROM %s", table)); err != nil {
+			return fmt.Errorf("清空表 %s 失败: %v", tableName, err)
+		}
+		if _, err := tx.Exec(fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s ORDER BY rowid", table, list, list, stagingTable)); err != nil {
+			return fmt.Errorf("写入表 %s 失败: %v", tableName, err)
+		}
+		return nil
+	}
+
+	// 暂存表是新建后按顺序写入的，rowid 即行的位置 1..n
+	positions := fmt.Sprintf("(SELECT rowid AS rid, ROW_NUMBER() OVER (ORDER BY rowid) AS pos FROM %s)", table)
+	var existing int
+	if err := tx.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s", table)).Scan(&existing); err != nil {
+		return fmt.Errorf("统计表 %s 的行数失败: %v", tableName, err)
+	}
+	if _, err := tx.Exec(fmt.Sprintf("UPDATE %s SET %s FROM %s AS o, %s AS s WHERE %s.rowid = o.rid AND s.rowid = o.pos",
+		table, strings.Join(sets, ", "), positions, stagingTable, table)); err != nil {
+		return fmt.Errorf("更新表 %s 失败: %v", tableName, err)
+	}
+	if _, err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE rowid IN (SELECT rid FROM %s WHERE pos > (SELECT COUNT(*) FROM %s))",
+		table, positions, stagingTable)); err != nil {
+		return fmt.Errorf("删除表 %s 中多出的行失败: %v", tableName, err)
+	}
+	if _, err := tx.Exec(fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s WHERE rowid > ? ORDER BY rowid",
+		table, list, list, stagingTable), existing); err != nil {
+		return fmt.Errorf("写入表 %s 失败: %v", tableName, err)
+	}
+	return nil
+}
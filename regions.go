@@ -0,0 +1,234 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// regionData 内置行政区划表：省级代码|省级名称|简称|地级单位（逗号分隔，自治州等名称较长的以“全称:简称”给出简称）
+// 直辖市和港澳台不列地级单位，市一级取省级名称
+var regionData = []string{
+	"11|北京市|北京|",
+	"12|天津市|天津|",
+	"13|河北省|河北|石家庄市,唐山市,秦皇岛市,邯郸市,邢台市,保定市,张家口市,承德市,沧州市,廊坊市,衡水市",
+	"14|山西省|山西|太原市,大同市,阳泉市,长治市,晋城市,朔州市,晋中市,运城市,忻州市,临汾市,吕梁市",
+	"15|内蒙古自治区|内蒙古|呼和浩特市,包头市,乌海市,赤峰市,通辽市,鄂尔多斯市,呼伦贝尔市,巴彦淖尔市,乌兰察布市,兴安盟,锡林郭勒盟,阿拉善盟",
+	"21|辽宁省|辽宁|沈阳市,大连市,鞍山市,抚顺市,本溪市,丹东市,锦州市,营口市,阜新市,辽阳市,盘锦市,铁岭市,朝阳市,葫芦岛市",
+	"22|吉林省|吉林|长春市,吉林市,四平市,辽源市,通化市,白山市,松原市,白城市,延边朝鲜族自治州:延边",
+	"23|黑龙江省|黑龙江|哈尔滨市,齐齐哈尔市,鸡西市,鹤岗市,双鸭山市,大庆市,伊春市,佳木斯市,七台河市,牡丹江市,黑河市,绥化市,大兴安岭地区",
+	"31|上海市|上海|",
+	"32|江苏省|江苏|南京市,无锡市,徐州市,常州市,苏州市,南通市,连云港市,淮安市,盐城市,扬州市,镇江市,泰州市,宿迁市",
+	"33|浙江省|浙江|杭州市,宁波市,温州市,嘉兴市,湖州市,绍兴市,金华市,衢州市,舟山市,台州市,丽水市",
+	"34|安徽省|安徽|合肥市,芜湖市,蚌埠市,淮南市,马鞍山市,淮北市,铜陵市,安庆市,黄山市,滁州市,阜阳市,宿州市,六安市,亳州市,池州市,宣城市",
+	"35|福建省|福建|福州市,厦门市,莆田市,三明市,泉州市,漳州市,南平市,龙岩市,宁德市",
+	"36|江西省|江西|南昌市,景德镇市,萍乡市,九江市,新余市,鹰潭市,赣州市,吉安市,宜春市,抚州市,上饶市",
+	"37|山东省|山东|济南市,青岛市,淄博市,枣庄市,东营市,烟台市,潍坊市,济宁市,泰安市,威海市,日照市,临沂市,德州市,聊城市,滨州市,菏泽市",
+	"41|河南省|河南|郑州市,开封市,洛阳市,平顶山市,安阳市,鹤壁市,新乡市,焦作市,濮阳市,许昌市,漯河市,三门峡市,南阳市,商丘市,信阳市,周口市,驻马店市,济源市",
+	"42|湖北省|湖北|武汉市,黄石市,十堰市,宜昌市,襄阳市,鄂州市,荆门市,孝感市,荆州市,黄冈市,咸宁市,随州市,恩施土家族苗族自治州:恩施,仙桃市,潜江市,天门市,神农架林区",
+	"43|湖南省|湖南|长沙市,株洲市,湘潭市,衡阳市,邵阳市,岳阳市,常德市,张家界市,益阳市,郴州市,永州市,怀化市,娄底市,湘西土家族苗族自治州:湘西",
+	"44|广东省|广东|广州市,韶关市,深圳市,珠海市,汕头市,佛山市,江门市,湛江市,茂名市,肇庆市,惠州市,梅州市,汕尾市,河源市,阳江市,清远市,东莞市,中山市,潮州市,揭阳市,云浮市",
+	"45|广西壮族自治区|广西|南宁市,柳州市,桂林市,梧州市,北海市,防城港市,钦州市,贵港市,玉林市,百色市,贺州市,河池市,来宾市,崇左市",
+	"46|海南省|海南|海口市,三亚市,三沙市,儋州市",
+	"50|重庆市|重庆|",
+	"51|四川省|四川|成都市,自贡市,攀枝花市,泸州市,德阳市,绵阳市,广元市,遂宁市,内江市,乐山市,南充市,眉山市,宜宾市,广安市,达州市,雅安市,巴中市,资阳市,阿坝藏族羌族自治州:阿坝,甘孜藏族自治州:甘孜,凉山彝族自治州:凉山",
+	"52|贵州省|贵州|贵阳市,六盘水市,遵义市,安顺市,毕节市,铜仁市,黔西南布依族苗族自治州:黔西南,黔东南苗族侗族自治州:黔东南,黔南布依族苗族自治州:黔南",
+	"53|云南省|云南|昆明市,曲靖市,玉溪市,保山市,昭通市,丽江市,普洱市,临沧市,楚雄彝族自治州:楚雄,红河哈尼族彝族自治州:红河,文山壮族苗族自治州:文山,西双版纳傣族自治州:西双版纳,大理白族自治州:大理,德宏傣族景颇族自治州:德宏,怒江傈僳族自治州:怒江,迪庆藏族自治州:迪庆",
+	"54|西藏自治区|西藏|拉萨市,日喀则市,昌都市,林芝市,山南市,那曲市,阿里地区",
+	"61|陕西省|陕西|西安市,铜川市,宝鸡市,咸阳市,渭南市,延安市,汉中市,榆林市,安康市,商洛市",
+	"62|甘肃省|甘肃|兰州市,嘉峪关市,金昌市,白银市,天水市,武威市,张掖市,平凉市,酒泉市,庆阳市,定西市,陇南市,临夏回族自治州:临夏,甘南藏族自治州:甘南",
+	"63|青海省|青海|西宁市,海东市,海北藏族自治州:海北,黄南藏族自治州:黄南,海南藏族自治州:海南州,果洛藏族自治州:果洛,玉树藏族自治州:玉树,海西蒙古族藏族自治州:海西",
+	"64|宁夏回族自治区|宁夏|银川市,石嘴山市,吴忠市,固原市,中卫市",
+	"65|新疆维吾尔自治区|新疆|乌鲁木齐市,克拉玛依市,吐鲁番市,哈密市,昌吉回族自治州:昌吉,博尔塔拉蒙古自治州:博尔塔拉,巴音郭楞蒙古自治州:巴音郭楞,阿克苏地区,克孜勒苏柯尔克孜自治州:克孜勒苏,喀什地区,和田地区,伊犁哈萨克自治州:伊犁,塔城地区,阿勒泰地区,石河子市",
+	"71|台湾省|台湾|",
+	"81|香港特别行政区|香港|",
+	"82|澳门特别行政区|澳门|",
+}
+
+// region 行政区划中的一个单位
+type region struct {
+	code     string
+	name     string
+	short    string
+	province *region
+}
+
+var (
+	regionProvinces []*region
+	regionCities    []*region
+)
+
+// regionCitySuffix 地级单位名称的通名后缀，去掉后作为简称
+var regionCitySuffix = regexp.MustCompile(`(市|地区|盟|林区)$`)
+
+func init() {
+	for _, line := range regionData {
+		parts := strings.Split(line, "|")
+		province := &region{code: parts[0], name: parts[1], short: parts[2]}
+		regionProvinces = append(regionProvinces, province)
+		if parts[3] == "" {
+			continue
+		}
+		for _, item := range strings.Split(parts[3], ",") {
+			name, short, ok := strings.Cut(item, ":")
+			if !ok {
+				short = regionCitySuffix.ReplaceAllString(name, "")
+			}
+			regionCities = append(regionCities, &region{name: name, short: short, province: province})
+		}
+	}
+}
+
+// districtPattern 区县级单位：以 区、县、旗、县级市 等通名结尾的最短前缀
+var districtPattern = regexp.MustCompile(`^\p{Han}{1,7}?(自治县|自治旗|林区|特区|新区|区|县|旗|市)`)
+
+// 地址拆分生成的伴随列后缀
+const (
+	addressProvinceSuffix = "_省"
+	addressCitySuffix     = "_市"
+	addressDistrictSuffix = "_区县"
+)
+
+// parsedAddress 地址拆分结果
+type parsedAddress struct {
+	province string
+	city     string
+	district string
+}
+
+// matchRegion 在候选单位中查找作为 s 前缀的名称（先全称后简称，较长的优先），返回单位和消耗的字节数
+func matchRegion(s string, candidates []*region, useShort bool) (*region, int) {
+	var best *region
+	bestLen := 0
+	for _, r := range candidates {
+		name := r.name
+		if useShort {
+			name = r.short
+		}
+		if len(name) > bestLen && strings.HasPrefix(s, name) {
+			best, bestLen = r, len(name)
+		}
+	}
+	return best, bestLen
+}
+
+// citiesOf 省级单位下的地级单位
+func citiesOf(province *region) []*region {
+	var cities []*region
+	for _, c := range regionCities {
+		if c.province == province {
+			cities = append(cities, c)
+		}
+	}
+	return cities
+}
+
+// parseAddress 按内置行政区划表将中文地址拆分为 省/市/区县，地址中省略省份时由城市反推
+func parseAddress(addr string) parsedAddress {
+	s := strings.Join(strings.Fields(addr), "")
+	var result parsedAddress
+	var province, city *region
+
+	if p, n := matchRegion(s, regionProvinces, false); p != nil {
+		province, s = p, s[n:]
+	} else if c, n := matchRegion(s, regionCities, false); c != nil {
+		province, city, s = c.province, c, s[n:]
+	} else if p, n := matchRegion(s, regionProvinces, true); p != nil {
+		province, s = p, strings.TrimPrefix(s[n:], "省")
+	} else if c, n := matchRegion(s, regionCities, true); c != nil {
+		province, city, s = c.province, c, strings.TrimPrefix(s[n:], "市")
+	}
+	if province == nil {
+		return result
+	}
+	result.province = province.name
+
+	cities := citiesOf(province)
+	if len(cities) == 0 {
+		// 直辖市：市一级取省级名称，地址中重复的“市辖区”等字样忽略
+		result.city = province.name
+		s = strings.TrimPrefix(s, "市辖区")
+	} else {
+		if city == nil {
+			if c, n := matchRegion(s, cities, false); c != nil {
+				city, s = c, s[n:]
+			} else if c, n := matchRegion(s, cities, true); c != nil {
+				city, s = c, strings.TrimPrefix(s[n:], "市")
+			}
+		}
+		if city != nil {
+			result.city = city.name
+		}
+	}
+
+	if m := districtPattern.FindString(s); m != "" {
+		result.district = m
+	}
+	return result
+}
+
+// addressPartFunc 生成 ADDRESS_PROVINCE/ADDRESS_CITY/ADDRESS_DISTRICT SQL 函数，无法识别时返回 NULL
+func addressPartFunc(part func(parsedAddress) string) func(interface{}) interface{} {
+	return func(v interface{}) interface{} {
+		s, ok := udfText(v)
+		if !ok {
+			return nil
+		}
+		if p := part(parseAddress(s)); p != "" {
+			return p
+		}
+		return nil
+	}
+}
+
+// SplitAddressColumn 将地址列拆分为 <列名>_省、<列名>_市、<列名>_区县 三列（已存在时重新计算），便于按地区汇总
+// 查询中也可直接使用 ADDRESS_PROVINCE/ADDRESS_CITY/ADDRESS_DISTRICT 函数
+// wails:export SplitAddressColumn
+func (a *App) SplitAddressColumn(tableName string, column string) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+
+	columns, err := a.tableColumns(tableName)
+	if err != nil {
+		return err.Error()
+	}
+	if !containsString(columns, column) {
+		return fmt.Sprintf("表 %s 中不存在列 %s", tableName, column)
+	}
+
+	tx, err := a.writeDB().Begin()
+	if err != nil {
+		return fmt.Sprintf("开启事务失败: %v", err)
+	}
+	defer tx.Rollback()
+
+	parts := []struct{ suffix, fn string }{
+		{addressProvinceSuffix, "ADDRESS_PROVINCE"},
+		{addressCitySuffix, "ADDRESS_CITY"},
+		{addressDistrictSuffix, "ADDRESS_DISTRICT"},
+	}
+	var sets []string
+	for _, part := range parts {
+		name := column + part.suffix
+		if !containsString(columns, name) {
+			if _, err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s TEXT", quoteIdent(tableName), quoteIdent(name))); err != nil {
+				return fmt.Sprintf("添加列 %s 失败: %v", name, err)
+			}
+		}
+		sets = append(sets, fmt.Sprintf("%s = %s(%s)", quoteIdent(name), part.fn, quoteIdent(column)))
+	}
+	if _, err := tx.Exec(fmt.Sprintf("UPDATE %s SET %s", quoteIdent(tableName), strings.Join(sets, ", "))); err != nil {
+		return fmt.Sprintf("拆分地址失败: %v", err)
+	}
+
+	var total, matched int
+	if err := tx.QueryRow(fmt.Sprintf("SELECT COUNT(%s), COUNT(%s) FROM %s",
+		quoteIdent(column), quoteIdent(column+addressProvinceSuffix), quoteIdent(tableName))).Scan(&total, &matched); err != nil {
+		return fmt.Sprintf("统计拆分结果失败: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Sprintf("提交事务失败: %v", err)
+	}
+	return fmt.Sprintf("已拆分表 %s 的地址列 %s：%d 个地址中识别出省份的 %d 个", tableName, column, total, matched)
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// staleTablesEvent 启动时发现长期未使用的表时发送给前端的事件，数据同 GetStaleTables，由前端请用户确认后调用 DropStaleTables
+const staleTablesEvent = "stale-tables"
+
+// defaultStaleDays 未开启 stale_table_days 设置时 GetStaleTables 使用的天数
+const defaultStaleDays = 30
+
+// keepTableTag 带有此标签的表不会被列为长期未使用的表
+const keepTableTag = "保留"
+
+// staleTimeLayout 使用时间的记录格式
+const staleTimeLayout = "2006-01-02 15:04:05"
+
+// derivedTableSuffixes 由原表生成的视图和表的后缀，清理原表时一并删除
+var derivedTableSuffixes = []string{headerViewSuffix, cleanViewSuffix, typedTableSuffix}
+
+// touchTables 记录表的最近使用时间（导入、查询时调用），失败只记录日志
+func (a *App) touchTables(tables ...string) {
+	if a.writeDB() == nil || a.readOnly() {
+		return
+	}
+	now := time.Now().Format(staleTimeLayout)
+	for _, table := range tables {
+		if _, err := a.writeDB().Exec(`INSERT INTO _app_table_usage (table_name, last_used_at) VALUES (?, ?)
+			ON CONFLICT(table_name) DO UPDATE SET last_used_at = excluded.last_used_at`, table, now); err != nil {
+			fmt.Printf("记录表 %s 的使用时间失败: %v\n", table, err)
+		}
+	}
+}
+
+// touchQueryTables 记录 SQL 读取的表（视图展开为其底层表）的使用时间
+func (a *App) touchQueryTables(sqlStr string) {
+	stmt := parseStatement(strings.TrimSpace(sqlStr))
+	if stmt.multiple {
+		return
+	}
+	lineage, err := a.queryLineage(stmt.text)
+	if err != nil {
+		return
+	}
+	tables := make([]string, 0, len(lineage))
+	for table := range lineage {
+		if !strings.HasPrefix(table, "_app_") {
+			tables = append(tables, table)
+		}
+	}
+	a.touchTables(tables...)
+}
+
+// staleTable 长期未使用的表
+type staleTable struct {
+	name     string
+	lastUsed time.Time
+	rows     int64
+	source   string
+}
+
+// staleTables 列出超过 days 天未导入、未被查询的表；带 keepTableTag 标签的表和派生的视图/类型化表不列出
+// 没有使用记录的表（如功能上线前导入的表）从首次检查时开始计时
+func (a *App) staleTables(days int) ([]staleTable, error) {
+	names, err := a.userTables()
+	if err != nil {
+		return nil, err
+	}
+	if !a.readOnly() {
+		if _, err := a.writeDB().Exec(`INSERT OR IGNORE INTO _app_table_usage (table_name, last_used_at)
+			SELECT name, ? FROM sqlite_master WHERE type = 'table'`, time.Now().Format(staleTimeLayout)); err != nil {
+			return nil, fmt.Errorf("记录表的使用时间失败: %v", err)
+		}
+	}
+	cutoff := time.Now().AddDate(0, 0, -days)
+	var stale []staleTable
+	for _, name := range names {
+		if a.isDerivedTable(name) {
+			continue
+		}
+		if tags, _ := a.tableTags(name); containsString(tags, keepTableTag) {
+			continue
+		}
+		var lastUsed, source string
+		a.readDB().QueryRow(`SELECT MAX(COALESCE(u.last_used_at, ''), COALESCE(s.imported_at, '')), COALESCE(s.source, '')
+			FROM (SELECT ? AS name) t LEFT JOIN _app_table_usage u ON u.table_name = t.name
+			LEFT JOIN _app_table_sources s ON s.table_name = t.name`, name).Scan(&lastUsed, &source)
+		used, err := time.ParseInLocation(staleTimeLayout, lastUsed, time.Local)
+		if err != nil || !used.Before(cutoff) {
+			continue
+		}
+		t := staleTable{name: name, lastUsed: used, source: source}
+		a.readDB().QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s", quoteIdent(name))).Scan(&t.rows)
+		stale = append(stale, t)
+	}
+	return stale, nil
+}
+
+// isDerivedTable 判断是否为由其他表生成的表（如 sheet1_typed），这类表随原表一起清理
+func (a *App) isDerivedTable(name string) bool {
+	for _, suffix := range derivedTableSuffixes {
+		base, ok := strings.CutSuffix(name, suffix)
+		if !ok || base == "" {
+			continue
+		}
+		if _, err := a.tableColumns(base); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// staleDays 清理策略的天数：参数大于 0 时使用参数，否则取 stale_table_days 设置，设置为 0 时使用 defaultStaleDays
+func (a *App) staleDays(days int) int {
+	if days > 0 {
+		return days
+	}
+	if n := a.settingInt("stale_table_days"); n > 0 {
+		return n
+	}
+	return defaultStaleDays
+}
+
+// staleReport GetStaleTables 和启动检查返回的报告
+func (a *App) staleReport(days int) map[string]interface{} {
+	result := make(map[string]interface{})
+	stale, err := a.staleTables(days)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	tables := make([]map[string]interface{}, len(stale))
+	var totalRows int64
+	for i, t := range stale {
+		tables[i] = map[string]interface{}{
+			"table":      t.name,
+			"lastUsedAt": t.lastUsed.Format(staleTimeLayout),
+			"idleDays":   int(time.Since(t.lastUsed).Hours() / 24),
+			"rows":       t.rows,
+			"source":     t.source,
+		}
+		totalRows += t.rows
+	}
+	result["days"] = days
+	result["tables"] = tables
+	result["message"] = fmt.Sprintf("%d 张表超过 %d 天未导入或查询（共 %d 行）", len(stale), days, totalRows)
+	if len(stale) == 0 {
+		result["message"] = fmt.Sprintf("没有超过 %d 天未使用的表", days)
+	}
+	return result
+}
+
+// checkStaleTablesOnStartup 开启 stale_table_days 设置时，启动后检查长期未使用的表并通知前端确认是否清理
+func (a *App) checkStaleTablesOnStartup(ctx context.Context) {
+	days := a.settingInt("stale_table_days")
+	if a.writeDB() == nil || a.readOnly() || days <= 0 {
+		return
+	}
+	report := a.staleReport(days)
+	if tables, _ := report["tables"].([]map[string]interface{}); len(tables) > 0 {
+		runtime.EventsEmit(ctx, staleTablesEvent, report)
+	}
+}
+
+// GetStaleTables 列出超过 days 天未导入、未被查询的表（days <= 0 时取 stale_table_days 设置，未设置时为 30 天），
+// 每项包含 table、lastUsedAt、idleDays、rows 和 source；带“保留”标签的表不会列出
+// wails:export GetStaleTables
+func (a *App) GetStaleTables(days int) map[string]interface{} {
+	if a.writeDB() == nil {
+		return map[string]interface{}{"error": "错误：数据库连接未初始化，请重启应用！"}
+	}
+	return a.staleReport(a.staleDays(days))
+}
+
+// DropStaleTables 删除用户在 GetStaleTables 报告中确认的表，连同其表头视图、清洗视图、类型化表和元数据；
+// 删除前重新检查，期间被使用过的表不会删除；释放的空间供后续导入复用，执行 VACUUM 可缩小数据库文件
+// wails:export DropStaleTables
+func (a *App) DropStaleTables(tables []string, days int) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	if a.readOnly() {
+		return readOnlyMessage
+	}
+	if len(tables) == 0 {
+		return "请选择要删除的表"
+	}
+	days = a.staleDays(days)
+	stale, err := a.staleTables(days)
+	if err != nil {
+		return err.Error()
+	}
+	isStale := make(map[string]bool, len(stale))
+	for _, t := range stale {
+		isStale[t.name] = true
+	}
+
+	var dropped, skipped []string
+	tx, err := a.writeDB().Begin()
+	if err != nil {
+		return fmt.Sprintf("开启事务失败: %v", err)
+	}
+	for _, table := range tables {
+		if !isStale[table] {
+			skipped = append(skipped, table)
+			continue
+		}
+		for _, name := range append([]string{table}, derivedNames(table)...) {
+			if err := dropTableOrView(tx, name); err != nil {
+				tx.Rollback()
+				return fmt.Sprintf("删除 %s 失败: %v", name, err)
+			}
+			for _, meta := range []string{"_app_table_sources", "_app_table_usage", "_app_table_tags", "_app_table_folders", "_app_dictionary", "_app_header_translations", "_app_cell_comments"} {
+				if _, err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE table_name = ?", meta), name); err != nil {
+					tx.Rollback()
+					return fmt.Sprintf("清除表 %s 的元数据失败: %v", name, err)
+				}
+			}
+			if _, err := tx.Exec("DELETE FROM _app_typed_layers WHERE typed_table = ?", name); err != nil {
+				tx.Rollback()
+				return fmt.Sprintf("清除表 %s 的元数据失败: %v", name, err)
+			}
+			if _, err := tx.Exec("DELETE FROM _app_type_errors WHERE typed_table = ?", name); err != nil {
+				tx.Rollback()
+				return fmt.Sprintf("清除表 %s 的元数据失败: %v", name, err)
+			}
+		}
+		dropped = append(dropped, table)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Sprintf("提交事务失败: %v", err)
+	}
+	a.recordSchemaVersion("清理长期未使用的表")
+	message := fmt.Sprintf("已删除 %d 张长期未使用的表", len(dropped))
+	if len(dropped) > 0 {
+		message += "：" + strings.Join(dropped, "、")
+	}
+	if len(skipped) > 0 {
+		message += fmt.Sprintf("；%s 最近被使用过或不存在，已跳过", strings.Join(skipped, "、"))
+	}
+	return message
+}
+
+// derivedNames 原表的派生视图和表名
+func derivedNames(table string) []string {
+	names := make([]string, len(derivedTableSuffixes))
+	for i, suffix := range derivedTableSuffixes {
+		names[i] = table + suffix
+	}
+	return names
+}
@@ -0,0 +1,341 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// 往返导出写入的隐藏列：源表的 rowid 和导出时该行的整行哈希（用于发现导出后源表中被修改的行）
+const (
+	roundTripKeyColumn     = "_row_key"
+	roundTripVersionColumn = "_row_version"
+)
+
+// roundTripSheetName 往返导出文件中记录来源表和列的隐藏工作表
+const roundTripSheetName = "RoundTrip"
+
+// maxReportedConflicts 回填结果中最多列出的冲突明细数
+const maxReportedConflicts = 50
+
+// roundTripInfo 往返导出的来源表和数据列（不含两个隐藏列）
+type roundTripInfo struct {
+	table   string
+	columns []string
+}
+
+// writeRoundTripSheet 在导出文件中添加隐藏的 RoundTrip 页：来源表、导出时间、数据列和对应的表头
+func (a *App) writeRoundTripSheet(f *excelize.File, info *roundTripInfo) error {
+	if _, err := f.NewSheet(roundTripSheetName); err != nil {
+		return err
+	}
+	columnsRow := []interface{}{"列"}
+	headersRow := []interface{}{"表头"}
+	for i, h := range a.exportHeaders(info.columns) {
+		columnsRow = append(columnsRow, info.columns[i])
+		headersRow = append(headersRow, h)
+	}
+	rows := [][]interface{}{
+		{"表", info.table},
+		{"导出时间", time.Now().Format("2006-01-02 15:04:05")},
+		columnsRow,
+		headersRow,
+	}
+	for i, row := range rows {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		if err := f.SetSheetRow(roundTripSheetName, cell, &row); err != nil {
+			return err
+		}
+	}
+	return f.SetSheetVisible(roundTripSheetName, false)
+}
+
+// readRoundTripSheet 读取往返导出文件的来源表、数据列和表头
+func readRoundTripSheet(f *excelize.File) (*roundTripInfo, []string, error) {
+	rows, err := f.GetRows(roundTripSheetName)
+	if err != nil || len(rows) < 4 || len(rows[0]) < 2 || len(rows[2]) < 2 {
+		return nil, nil, fmt.Errorf("该文件不是往返导出（ExportForRoundTrip）生成的文件")
+	}
+	info := &roundTripInfo{table: rows[0][1], columns: rows[2][1:]}
+	return info, rows[3][1:], nil
+}
+
+// hideColumns 隐藏导出工作表中的指定列
+func hideColumns(f *excelize.File, sheet string, columns []string, hidden []string) error {
+	for i, col := range columns {
+		if containsString(hidden, col) {
+			name, _ := excelize.ColumnNumberToName(i + 1)
+			if err := f.SetColVisible(sheet, name, false); err != nil {
+				return fmt.Errorf("隐藏列 %s 失败: %v", col, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ExportForRoundTrip 导出表供他人修改后回填：在 ExportFillTemplate 的基础上增加隐藏的行键和行版本列，
+// 收回的文件用 ReimportFilled 按行键把修改写回源表；options 同 ExportFillTemplate（设置了锁定列时行键列同样锁定）
+// wails:export ExportForRoundTrip
+func (a *App) ExportForRoundTrip(tableName string, options map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	all, err := a.tableColumns(tableName)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	var columns, quoted []string
+	for _, col := range all {
+		if col != deletedFlagColumn && col != rowHashColumn {
+			columns = append(columns, col)
+			quoted = append(quoted, quoteIdent(col))
+		}
+	}
+	where := ""
+	if containsString(all, deletedFlagColumn) {
+		where = fmt.Sprintf(" WHERE %s = 0", quoteIdent(deletedFlagColumn))
+	}
+	sqlStr := fmt.Sprintf("SELECT rowid AS %s, ROW_HASH(%s) AS %s, %s FROM %s%s",
+		quoteIdent(roundTripKeyColumn), strings.Join(quoted, ", "), quoteIdent(roundTripVersionColumn),
+		strings.Join(quoted, ", "), quoteIdent(tableName), where)
+
+	opts, err := a.templateOptions(sqlStr, columns, options)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	keys := []string{roundTripKeyColumn, roundTripVersionColumn}
+	opts.hidden = keys
+	if opts.protect != nil {
+		opts.protect.locked = append(opts.protect.locked, keys...)
+	}
+	opts.roundTrip = &roundTripInfo{table: tableName, columns: columns}
+	return a.startExportJob(sqlStr, tableName+"_待填写", opts)
+}
+
+// roundTripConflict 回填时未写入的一行及原因
+type roundTripConflict struct {
+	row     int
+	key     string
+	reason  string
+	columns []string
+}
+
+func (c roundTripConflict) toMap() map[string]interface{} {
+	return map[string]interface{}{"row": c.row, "key": c.key, "reason": c.reason, "columns": c.columns}
+}
+
+// ReimportFilled 回填 ExportForRoundTrip 导出后被修改的文件：按隐藏的行键找到源表中的行，把修改过的单元格写回；
+// 行键为空的行作为新增行插入。以下情况不写入并作为冲突返回：源表中该行已删除、导出后源表中该行已被修改（与填写的修改可能冲突）、
+// 取值无法转换为列类型。全部写入在一个事务中完成；
+// 返回 {table, updated, inserted, unchanged, conflicts: [{row, key, reason, columns}], message}
+// wails:export ReimportFilled
+func (a *App) ReimportFilled(filePath string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	if a.readOnly() {
+		result["error"] = readOnlyMessage
+		return result
+	}
+	filePath, err := a.chooseFile(filePath, "选择填写后的 Excel 文件", "*.xlsx", "Excel 文件")
+	if err != nil {
+		result["error"] = fmt.Sprintf("文件选择失败: %v", err)
+		return result
+	}
+	if filePath == "" {
+		result["error"] = "未选择文件"
+		return result
+	}
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		result["error"] = fmt.Sprintf("Excel 解析失败: %v", err)
+		return result
+	}
+	defer f.Close()
+
+	info, headers, err := readRoundTripSheet(f)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	rows, err := f.GetRows(exportSheetName)
+	if err != nil || len(rows) == 0 {
+		result["error"] = fmt.Sprintf("读取工作表 %s 失败: %v", exportSheetName, err)
+		return result
+	}
+	// 前两列为行键和行版本，其后按导出时的顺序为数据列
+	header := rows[0]
+	for i, h := range headers {
+		if i+2 >= len(header) || strings.TrimSpace(header[i+2]) != strings.TrimSpace(h) {
+			result["error"] = fmt.Sprintf("第 %d 列的表头应为 %s，文件中的列顺序或表头已被修改，无法回填", i+3, h)
+			return result
+		}
+	}
+
+	targets, err := tableColumnInfos(a.writeDB(), info.table)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	declTypes := make(map[string]string, len(targets))
+	for _, t := range targets {
+		declTypes[t.name] = t.declType
+	}
+	var columns, quoted []string
+	var srcIdx []int
+	for i, col := range info.columns {
+		if _, ok := declTypes[col]; ok {
+			columns = append(columns, col)
+			quoted = append(quoted, quoteIdent(col))
+			srcIdx = append(srcIdx, i+2)
+		}
+	}
+	if len(columns) == 0 {
+		result["error"] = fmt.Sprintf("表 %s 中已没有导出时的列，无法回填", info.table)
+		return result
+	}
+
+	normalizeCN := a.setting("normalize_cn_numbers") == "true"
+	emptyAsNull := a.importEmptyAsNull()
+	// cellValue 按列类型转换填写的文本，与导入时的规范化一致；(NULL) 标记视为 NULL
+	cellValue := func(row []string, src int, col string) (interface{}, error) {
+		text := ""
+		if src < len(row) {
+			text = row[src]
+		}
+		if text == nullMarker {
+			return nil, nil
+		}
+		if columnAffinity(declTypes[col]) == "TEXT" && text != "" {
+			text = normalizeDate(text)
+			if normalizeCN {
+				text = normalizeChineseNumber(text)
+			}
+		}
+		return coerceValue(text, declTypes[col], emptyAsNull)
+	}
+
+	tx, err := a.writeDB().Begin()
+	if err != nil {
+		result["error"] = fmt.Sprintf("开启事务失败: %v", err)
+		return result
+	}
+	defer tx.Rollback()
+
+	// 源表删除了导出时的列时行版本必然不同，修改过的行都会作为冲突返回；导出后被软删除的行按已删除处理
+	selectSQL := fmt.Sprintf("SELECT ROW_HASH(%s), %s FROM %s WHERE rowid = ?", strings.Join(quoted, ", "), strings.Join(quoted, ", "), quoteIdent(info.table))
+	if _, ok := declTypes[deletedFlagColumn]; ok {
+		selectSQL += fmt.Sprintf(" AND %s = 0", quoteIdent(deletedFlagColumn))
+	}
+	insertSQL := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", quoteIdent(info.table), strings.Join(quoted, ", "), strings.TrimSuffix(strings.Repeat("?,", len(columns)), ","))
+	var conflicts []roundTripConflict
+	updated, inserted, unchanged := 0, 0, 0
+	for r, row := range rows[1:] {
+		rowNum := r + 2
+		if strings.TrimSpace(strings.Join(row, "")) == "" {
+			continue
+		}
+		key, version := "", ""
+		if len(row) > 0 {
+			key = strings.TrimSpace(row[0])
+		}
+		if len(row) > 1 {
+			version = strings.TrimSpace(row[1])
+		}
+
+		values := make([]interface{}, len(columns))
+		var bad []string
+		for i, col := range columns {
+			v, err := cellValue(row, srcIdx[i], col)
+			if err != nil {
+				bad = append(bad, col)
+			}
+			values[i] = v
+		}
+		if len(bad) > 0 {
+			conflicts = append(conflicts, roundTripConflict{row: rowNum, key: key, reason: "取值无法转换为列类型", columns: bad})
+			continue
+		}
+
+		if key == "" {
+			if _, err := tx.Exec(insertSQL, values...); err != nil {
+				result["error"] = fmt.Sprintf("插入第 %d 行失败: %v", rowNum, err)
+				return result
+			}
+			inserted++
+			continue
+		}
+
+		current := make([]interface{}, len(columns)+1)
+		ptrs := make([]interface{}, len(current))
+		for i := range current {
+			ptrs[i] = &current[i]
+		}
+		err := tx.QueryRow(selectSQL, key).Scan(ptrs...)
+		if err == sql.ErrNoRows {
+			conflicts = append(conflicts, roundTripConflict{row: rowNum, key: key, reason: "源表中该行已被删除"})
+			continue
+		}
+		if err != nil {
+			result["error"] = fmt.Sprintf("读取源表第 %s 行失败: %v", key, err)
+			return result
+		}
+
+		var changed []string
+		var sets []string
+		var args []interface{}
+		for i, col := range columns {
+			if sqlValueText(values[i]) != sqlValueText(current[i+1]) {
+				changed = append(changed, col)
+				sets = append(sets, quoteIdent(col)+" = ?")
+				args = append(args, values[i])
+			}
+		}
+		if len(changed) == 0 {
+			unchanged++
+			continue
+		}
+		if sqlValueText(current[0]) != version {
+			conflicts = append(conflicts, roundTripConflict{row: rowNum, key: key, reason: "导出后源表中该行已被修改", columns: changed})
+			continue
+		}
+		args = append(args, key)
+		if _, err := tx.Exec(fmt.Sprintf("UPDATE %s SET %s WHERE rowid = ?", quoteIdent(info.table), strings.Join(sets, ", ")), args...); err != nil {
+			result["error"] = fmt.Sprintf("更新第 %d 行失败: %v", rowNum, err)
+			return result
+		}
+		updated++
+	}
+	if err := tx.Commit(); err != nil {
+		result["error"] = fmt.Sprintf("提交事务失败: %v", err)
+		return result
+	}
+
+	list := make([]map[string]interface{}, 0, len(conflicts))
+	for i, c := range conflicts {
+		if i >= maxReportedConflicts {
+			break
+		}
+		list = append(list, c.toMap())
+	}
+	result["table"] = info.table
+	result["updated"] = updated
+	result["inserted"] = inserted
+	result["unchanged"] = unchanged
+	result["conflicts"] = list
+	result["conflictCount"] = len(conflicts)
+	result["message"] = fmt.Sprintf("已回填到表 %s：修改 %d 行，新增 %d 行，未变化 %d 行", info.table, updated, inserted, unchanged)
+	if len(conflicts) > 0 {
+		result["message"] = fmt.Sprintf("%s；%d 行存在冲突未写入，见 conflicts", result["message"], len(conflicts))
+	}
+	return result
+}
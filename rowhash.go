@@ -0,0 +1,99 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// rowHashColumn 保存整行哈希的隐藏列
+const rowHashColumn = "_row_hash"
+
+// rowHash 计算一行取值的哈希（SHA-256 前 16 字节的十六进制）
+// 每个值带长度前缀，NULL 与空字符串得到不同的结果；数值按文本形式参与计算，与 ROW_HASH SQL 函数一致
+func rowHash(values []interface{}) string {
+	h := sha256.New()
+	var buf [8]byte
+	for _, v := range values {
+		text, ok := udfText(v)
+		if !ok {
+			h.Write([]byte{0})
+			continue
+		}
+		h.Write([]byte{1})
+		binary.LittleEndian.PutUint64(buf[:], uint64(len(text)))
+		h.Write(buf[:])
+		h.Write([]byte(text))
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// rowHashFunc SQL 函数 ROW_HASH(col1, col2, ...)，用于在查询中计算或重新计算整行哈希
+func rowHashFunc(args ...interface{}) string {
+	return rowHash(args)
+}
+
+// AddRowHash 为已有表补充（或重新计算）_row_hash 整行哈希列，并建立索引便于比对和查重
+// 计算过程通过 operation-progress 事件报告阶段（见 progress.go）
+// wails:export AddRowHash
+func (a *App) AddRowHash(tableName string) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	op := a.beginOperation("", "maintenance", fmt.Sprintf("计算表 %s 的行哈希", tableName))
+	message, err := a.addRowHash(tableName, op)
+	if err != nil {
+		op.finish(err.Error(), err)
+		return err.Error()
+	}
+	op.finish(message, nil)
+	return message
+}
+
+func (a *App) addRowHash(tableName string, op *operation) (string, error) {
+	columns, err := a.tableColumns(tableName)
+	if err != nil {
+		return "", err
+	}
+	var quoted []string
+	hasHash := false
+	for _, col := range columns {
+		if col == rowHashColumn {
+			hasHash = true
+			continue
+		}
+		quoted = append(quoted, quoteIdent(col))
+	}
+
+	op.progress("hash", 0, 0, "正在计算行哈希")
+	tx, err := a.writeDB().Begin()
+	if err != nil {
+		return "", fmt.Errorf("开启事务失败: %v", err)
+	}
+	defer tx.Rollback()
+
+	if !hasHash {
+		if _, err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s TEXT", quoteIdent(tableName), quoteIdent(rowHashColumn))); err != nil {
+			return "", fmt.Errorf("添加列 %s 失败: %v", rowHashColumn, err)
+		}
+	}
+	res, err := tx.Exec(fmt.Sprintf("UPDATE %s SET %s = ROW_HASH(%s)", quoteIdent(tableName), quoteIdent(rowHashColumn), strings.Join(quoted, ", ")))
+	if err != nil {
+		return "", fmt.Errorf("计算行哈希失败: %v", err)
+	}
+	if _, err := tx.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (%s)",
+		quoteIdent("idx_"+tableName+rowHashColumn), quoteIdent(tableName), quoteIdent(rowHashColumn))); err != nil {
+		return "", fmt.Errorf("创建索引失败: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("提交事务失败: %v", err)
+	}
+
+	rows, _ := res.RowsAffected()
+	var duplicates int
+	a.readDB().QueryRow(fmt.Sprintf("SELECT COALESCE(SUM(n - 1), 0) FROM (SELECT COUNT(*) AS n FROM %s GROUP BY %s HAVING n > 1)",
+		quoteIdent(tableName), quoteIdent(rowHashColumn))).Scan(&duplicates)
+	return fmt.Sprintf("已为表 %s 计算 %d 行的行哈希（重复行 %d 行）", tableName, rows, duplicates), nil
+}
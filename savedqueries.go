@@ -0,0 +1,243 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// savedQuery 已保存的查询
+type savedQuery struct {
+	name      string
+	sql       string
+	rules     []highlightRule
+	layout    []columnLayout
+	formats   []columnFormat
+	updatedAt string
+}
+
+// loadSavedQuery 读取已保存的查询及其高亮规则、列设置和列格式
+func (a *App) loadSavedQuery(name string) (savedQuery, error) {
+	q := savedQuery{name: name}
+	var rulesJSON, layoutJSON, formatsJSON string
+	err := a.readDB().QueryRow("SELECT sql, rules, layout, formats, updated_at FROM _app_saved_queries WHERE name = ?", name).
+		Scan(&q.sql, &rulesJSON, &layoutJSON, &formatsJSON, &q.updatedAt)
+	if err != nil {
+		return q, fmt.Errorf("已保存的查询 %s 不存在", name)
+	}
+	var specs []map[string]interface{}
+	if err := json.Unmarshal([]byte(rulesJSON), &specs); err != nil {
+		return q, fmt.Errorf("查询 %s 的高亮规则已损坏: %v", name, err)
+	}
+	if q.rules, err = parseHighlightRules(specs); err != nil {
+		return q, err
+	}
+	specs = nil
+	if err := json.Unmarshal([]byte(layoutJSON), &specs); err != nil {
+		return q, fmt.Errorf("查询 %s 的列设置已损坏: %v", name, err)
+	}
+	if q.layout, err = parseColumnLayout(specs); err != nil {
+		return q, err
+	}
+	specs = nil
+	if err := json.Unmarshal([]byte(formatsJSON), &specs); err != nil {
+		return q, fmt.Errorf("查询 %s 的列格式已损坏: %v", name, err)
+	}
+	q.formats, err = parseColumnFormats(specs)
+	return q, err
+}
+
+// layoutMaps 列设置的前端表示
+func (q savedQuery) layoutMaps() []map[string]interface{} {
+	layout := make([]map[string]interface{}, len(q.layout))
+	for i, c := range q.layout {
+		layout[i] = c.toMap()
+	}
+	return layout
+}
+
+// pinnedColumns 列设置中标记为固定（且可见）的列，导出时移到最左侧并冻结
+func (q savedQuery) pinnedColumns() []string {
+	var pinned []string
+	for _, c := range q.layout {
+		if c.pinned && c.visible {
+			pinned = append(pinned, c.column)
+		}
+	}
+	return pinned
+}
+
+// savableQuery 语句能否保存为查询：保存的查询会被执行、分页和用于导出，只能是单条 SELECT；
+// PRAGMA、EXPLAIN 和带 RETURNING 的修改语句虽然返回结果集，也不能保存
+func savableQuery(stmt sqlStatement) bool {
+	return stmt.kind == stmtSelect && !stmt.multiple
+}
+
+// SaveQuery 保存查询及其结果表格的条件高亮规则（同名覆盖），rules 格式见 parseHighlightRules
+// wails:export SaveQuery
+func (a *App) SaveQuery(name string, sqlStr string, rules []map[string]interface{}) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "请输入查询名称"
+	}
+	stmt := parseStatement(sqlStr)
+	if !savableQuery(stmt) {
+		return "只能保存单条 SELECT 查询语句"
+	}
+	parsed, err := parseHighlightRules(rules)
+	if err != nil {
+		return err.Error()
+	}
+	specs := make([]map[string]interface{}, len(parsed))
+	for i, r := range parsed {
+		specs[i] = r.toMap()
+	}
+	rulesJSON, err := json.Marshal(specs)
+	if err != nil {
+		return fmt.Sprintf("保存高亮规则失败: %v", err)
+	}
+	if _, err := a.writeDB().Exec(`INSERT INTO _app_saved_queries (name, sql, rules, updated_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET sql = excluded.sql, rules = excluded.rules, updated_at = excluded.updated_at`,
+		name, stmt.text, string(rulesJSON), time.Now().Format("2006-01-02 15:04:05")); err != nil {
+		return fmt.Sprintf("保存查询失败: %v", err)
+	}
+	return fmt.Sprintf("已保存查询 %s（%d 条高亮规则）", name, len(parsed))
+}
+
+// ListSavedQueries 列出已保存的查询（含高亮规则、列设置和列格式），按名称排序
+// wails:export ListSavedQueries
+func (a *App) ListSavedQueries() []map[string]interface{} {
+	list := []map[string]interface{}{}
+	if a.writeDB() == nil {
+		return list
+	}
+	rows, err := a.readDB().Query("SELECT name FROM _app_saved_queries ORDER BY name")
+	if err != nil {
+		fmt.Printf("读取已保存的查询失败: %v\n", err)
+		return list
+	}
+	var names []string
+	for rows.Next() {
+		var name string
+		if rows.Scan(&name) == nil {
+			names = append(names, name)
+		}
+	}
+	rows.Close()
+
+	for _, name := range names {
+		item := map[string]interface{}{"name": name}
+		q, err := a.loadSavedQuery(name)
+		if err != nil {
+			item["error"] = err.Error()
+		}
+		rules := make([]map[string]interface{}, len(q.rules))
+		for i, r := range q.rules {
+			rules[i] = r.toMap()
+		}
+		item["sql"] = q.sql
+		item["rules"] = rules
+		item["layout"] = q.layoutMaps()
+		item["formats"] = columnFormatMaps(q.formats)
+		item["updatedAt"] = q.updatedAt
+		list = append(list, item)
+	}
+	return list
+}
+
+// DeleteSavedQuery 删除已保存的查询
+// wails:export DeleteSavedQuery
+func (a *App) DeleteSavedQuery(name string) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	res, err := a.writeDB().Exec("DELETE FROM _app_saved_queries WHERE name = ?", name)
+	if err != nil {
+		return fmt.Sprintf("删除查询失败: %v", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Sprintf("已保存的查询 %s 不存在", name)
+	}
+	return fmt.Sprintf("已删除查询 %s", name)
+}
+
+// SetSavedQueryLayout 保存查询结果的列顺序、显示和宽度 [{column, visible, width}]，重启后保留，导出时同样生效
+// wails:export SetSavedQueryLayout
+func (a *App) SetSavedQueryLayout(name string, layout []map[string]interface{}) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	parsed, err := parseColumnLayout(layout)
+	if err != nil {
+		return err.Error()
+	}
+	specs := make([]map[string]interface{}, len(parsed))
+	for i, c := range parsed {
+		specs[i] = c.toMap()
+	}
+	layoutJSON, err := json.Marshal(specs)
+	if err != nil {
+		return fmt.Sprintf("保存列设置失败: %v", err)
+	}
+	res, err := a.writeDB().Exec("UPDATE _app_saved_queries SET layout = ?, updated_at = ? WHERE name = ?",
+		string(layoutJSON), time.Now().Format("2006-01-02 15:04:05"), name)
+	if err != nil {
+		return fmt.Sprintf("保存列设置失败: %v", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Sprintf("已保存的查询 %s 不存在", name)
+	}
+	return fmt.Sprintf("已保存查询 %s 的列设置", name)
+}
+
+// RunSavedQuery 执行已保存的查询并分页返回结果，附带高亮规则 rules、当前页命中的 highlights、列设置 layout 和列格式 formats
+// columns 已按列设置排序并去掉隐藏列
+// wails:export RunSavedQuery
+func (a *App) RunSavedQuery(name string, pageNum int, pageSize int) map[string]interface{} {
+	if a.writeDB() == nil {
+		return map[string]interface{}{"error": "错误：数据库连接未初始化，请重启应用！"}
+	}
+	q, err := a.loadSavedQuery(name)
+	if err != nil {
+		return map[string]interface{}{"error": err.Error()}
+	}
+	result := a.ExecuteSQLWithPage(q.sql, pageNum, pageSize)
+	if _, failed := result["error"]; failed {
+		return result
+	}
+	rules := make([]map[string]interface{}, len(q.rules))
+	for i, r := range q.rules {
+		rules[i] = r.toMap()
+	}
+	data, _ := result["data"].([]map[string]interface{})
+	result["rules"] = rules
+	result["highlights"] = evaluateHighlights(q.rules, data)
+	if columns, ok := result["columns"].([]string); ok {
+		result["columns"] = layoutColumns(columns, q.layout)
+	}
+	result["layout"] = q.layoutMaps()
+	result["formats"] = columnFormatMaps(q.formats)
+	result["sql"] = q.sql
+	return result
+}
+
+// ExportSavedQuery 在后台导出已保存的查询，导出的 Excel 按列设置排列列、设置列宽、冻结固定列，按列格式设置数字格式，并按高亮规则设置单元格颜色；返回值同 ExportExcelBySQL
+// wails:export ExportSavedQuery
+func (a *App) ExportSavedQuery(name string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	q, err := a.loadSavedQuery(name)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+
+	return a.startExportJob(q.sql, name, exportOptions{rules: q.rules, layout: q.layout, pinned: q.pinnedColumns(), formats: q.formats})
+}
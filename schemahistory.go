@@ -0,0 +1,316 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// schemaHistoryLimit 结构版本最多保留的条数，超出时删除最早的版本
+const schemaHistoryLimit = 500
+
+// schemaReasonMaxLen 版本说明中 SQL 的最大长度（超出截断）
+const schemaReasonMaxLen = 200
+
+// schemaColumn 结构快照中的一列
+type schemaColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// schemaTable 结构快照中的一张表
+type schemaTable struct {
+	Table   string         `json:"table"`
+	Columns []schemaColumn `json:"columns"`
+}
+
+// currentSchema 当前全部用户表的结构（按表名排序）
+func (a *App) currentSchema() ([]schemaTable, error) {
+	names, err := a.userTables()
+	if err != nil {
+		return nil, err
+	}
+	schema := make([]schemaTable, 0, len(names))
+	for _, name := range names {
+		infos, err := tableColumnInfos(a.writeDB(), name)
+		if err != nil {
+			return nil, err
+		}
+		t := schemaTable{Table: name, Columns: make([]schemaColumn, len(infos))}
+		for i, c := range infos {
+			t.Columns[i] = schemaColumn{Name: c.name, Type: c.declType}
+		}
+		schema = append(schema, t)
+	}
+	return schema, nil
+}
+
+// schemaAutoReason 提交钩子发现结构变化、自动记录的版本说明；之后调用方以更具体的说明记录同一结构时替换它
+const schemaAutoReason = "结构变化"
+
+// schemaRecordMu 串行化 recordSchemaVersion，自动记录和调用方同时记录同一结构时只记一个版本
+var schemaRecordMu sync.Mutex
+
+// schemaChanged 提交钩子发现用户表、视图、索引或触发器的结构变化时发出通知
+var schemaChanged = make(chan struct{}, 1)
+
+// noteSchemaChanged 在提交钩子中调用，schema 为本次提交是否修改了结构
+func noteSchemaChanged(schema bool) {
+	if !schema {
+		return
+	}
+	select {
+	case schemaChanged <- struct{}{}:
+	default:
+	}
+}
+
+// watchSchemaChanges 结构变化提交后记录结构版本，直到 ctx 结束；覆盖重建表、分区、类型化表、日历表等
+// 没有自己调用 recordSchemaVersion 的 DDL。等待一段时间再读取，才能读到提交后的结构
+func (a *App) watchSchemaChanges(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-schemaChanged:
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(dataChangeDebounce):
+		}
+		a.recordSchemaVersion(schemaAutoReason)
+	}
+}
+
+// recordSchemaVersion 导入或执行 DDL 后记录结构快照，与最近一个版本相同时不记录
+// （最近一个版本是自动记录的时，改用这次更具体的说明）；失败只记录日志
+func (a *App) recordSchemaVersion(reason string) {
+	if a.writeDB() == nil || a.readOnly() {
+		return
+	}
+	schemaRecordMu.Lock()
+	defer schemaRecordMu.Unlock()
+	schema, err := a.currentSchema()
+	if err != nil {
+		fmt.Printf("记录结构版本失败: %v\n", err)
+		return
+	}
+	data, _ := json.Marshal(schema)
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:16])
+	if len([]rune(reason)) > schemaReasonMaxLen {
+		reason = string([]rune(reason)[:schemaReasonMaxLen]) + "…"
+	}
+	var lastID int
+	var last, lastReason string
+	a.readDB().QueryRow("SELECT id, hash, reason FROM _app_schema_versions ORDER BY id DESC LIMIT 1").Scan(&lastID, &last, &lastReason)
+	if last == hash {
+		if lastReason == schemaAutoReason && reason != schemaAutoReason {
+			if _, err := a.writeDB().Exec("UPDATE _app_schema_versions SET reason = ? WHERE id = ?", reason, lastID); err != nil {
+				fmt.Printf("记录结构版本说明失败: %v\n", err)
+			}
+		}
+		return
+	}
+	if _, err := a.writeDB().Exec("INSERT INTO _app_schema_versions (schema, hash, reason, created_at) VALUES (?, ?, ?, ?)",
+		string(data), hash, reason, time.Now().Format("2006-01-02 15:04:05")); err != nil {
+		fmt.Printf("记录结构版本失败: %v\n", err)
+		return
+	}
+	if _, err := a.writeDB().Exec("DELETE FROM _app_schema_versions WHERE id <= (SELECT MAX(id) FROM _app_schema_versions) - ?", schemaHistoryLimit); err != nil {
+		fmt.Printf("清理结构版本失败: %v\n", err)
+	}
+}
+
+// loadSchemaVersion 读取结构版本，id <= 0 表示当前结构
+func (a *App) loadSchemaVersion(id int) ([]schemaTable, string, error) {
+	if id <= 0 {
+		schema, err := a.currentSchema()
+		return schema, "当前", err
+	}
+	var data, createdAt string
+	err := a.readDB().QueryRow("SELECT schema, created_at FROM _app_schema_versions WHERE id = ?", id).Scan(&data, &createdAt)
+	if err == sql.ErrNoRows {
+		return nil, "", fmt.Errorf("结构版本 %d 不存在", id)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("读取结构版本失败: %v", err)
+	}
+	var schema []schemaTable
+	if err := json.Unmarshal([]byte(data), &schema); err != nil {
+		return nil, "", fmt.Errorf("结构版本 %d 已损坏: %v", id, err)
+	}
+	return schema, createdAt, nil
+}
+
+// schemaDiff 两个结构版本之间的差异
+type schemaDiff struct {
+	addedTables   []string
+	removedTables []string
+	changed       []map[string]interface{}
+}
+
+// diffSchemas 比较两个结构：新增、删除的表，以及表内新增、删除的列和声明类型的变化
+func diffSchemas(from []schemaTable, to []schemaTable) schemaDiff {
+	var d schemaDiff
+	old := make(map[string]schemaTable, len(from))
+	for _, t := range from {
+		old[t.Table] = t
+	}
+	seen := make(map[string]bool, len(to))
+	for _, t := range to {
+		seen[t.Table] = true
+		before, ok := old[t.Table]
+		if !ok {
+			d.addedTables = append(d.addedTables, t.Table)
+			continue
+		}
+		oldTypes := make(map[string]string, len(before.Columns))
+		for _, c := range before.Columns {
+			oldTypes[c.Name] = c.Type
+		}
+		added, removed := []string{}, []string{}
+		typeChanges := []map[string]interface{}{}
+		newCols := make(map[string]bool, len(t.Columns))
+		for _, c := range t.Columns {
+			newCols[c.Name] = true
+			prev, ok := oldTypes[c.Name]
+			if !ok {
+				added = append(added, c.Name)
+			} else if !strings.EqualFold(prev, c.Type) {
+				typeChanges = append(typeChanges, map[string]interface{}{"column": c.Name, "from": prev, "to": c.Type})
+			}
+		}
+		for _, c := range before.Columns {
+			if !newCols[c.Name] {
+				removed = append(removed, c.Name)
+			}
+		}
+		if len(added)+len(removed)+len(typeChanges) > 0 {
+			d.changed = append(d.changed, map[string]interface{}{
+				"table": t.Table, "addedColumns": added, "removedColumns": removed, "typeChanges": typeChanges,
+			})
+		}
+	}
+	for _, t := range from {
+		if !seen[t.Table] {
+			d.removedTables = append(d.removedTables, t.Table)
+		}
+	}
+	sort.Strings(d.addedTables)
+	sort.Strings(d.removedTables)
+	return d
+}
+
+// summary 差异的文字说明
+func (d schemaDiff) summary() string {
+	var parts []string
+	if len(d.addedTables) > 0 {
+		parts = append(parts, "新增表 "+strings.Join(d.addedTables, "、"))
+	}
+	if len(d.removedTables) > 0 {
+		parts = append(parts, "删除表 "+strings.Join(d.removedTables, "、"))
+	}
+	if len(d.changed) > 0 {
+		names := make([]string, len(d.changed))
+		for i, c := range d.changed {
+			names[i] = c["table"].(string)
+		}
+		parts = append(parts, "结构变化的表 "+strings.Join(names, "、"))
+	}
+	if len(parts) == 0 {
+		return "结构没有变化"
+	}
+	return strings.Join(parts, "；")
+}
+
+// GetSchemaHistory 列出最近的结构版本（最近的在前）：id、reason（导入或 DDL 语句）、createdAt、tableCount、
+// summary（相对上一个版本的变化）；导入和执行 DDL 后结构有变化时自动记录
+// wails:export GetSchemaHistory
+func (a *App) GetSchemaHistory(limit int) []map[string]interface{} {
+	history := []map[string]interface{}{}
+	if a.writeDB() == nil {
+		return history
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+	// 多取一个更早的版本，用于计算最后一项的变化
+	rows, err := a.readDB().Query("SELECT id, schema, reason, created_at FROM _app_schema_versions ORDER BY id DESC LIMIT ?", limit+1)
+	if err != nil {
+		fmt.Printf("读取结构版本失败: %v\n", err)
+		return history
+	}
+	defer rows.Close()
+	var schemas [][]schemaTable
+	for rows.Next() {
+		var id int64
+		var data, reason, createdAt string
+		if err := rows.Scan(&id, &data, &reason, &createdAt); err != nil {
+			fmt.Printf("读取结构版本失败: %v\n", err)
+			return history
+		}
+		var schema []schemaTable
+		json.Unmarshal([]byte(data), &schema)
+		schemas = append(schemas, schema)
+		history = append(history, map[string]interface{}{
+			"id": id, "reason": reason, "createdAt": createdAt, "tableCount": len(schema),
+		})
+	}
+	for i := range history {
+		if i+1 < len(schemas) {
+			history[i]["summary"] = diffSchemas(schemas[i+1], schemas[i]).summary()
+		} else {
+			history[i]["summary"] = "最早记录的结构"
+		}
+	}
+	if len(history) > limit {
+		history = history[:limit]
+	}
+	return history
+}
+
+// DiffSchemas 比较两个结构版本（v2 <= 0 表示当前结构）：addedTables、removedTables、
+// changedTables [{table, addedColumns, removedColumns, typeChanges: [{column, from, to}]}] 和 message
+// wails:export DiffSchemas
+func (a *App) DiffSchemas(v1 int, v2 int) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	if v1 <= 0 {
+		result["error"] = "请选择要比较的结构版本"
+		return result
+	}
+	from, fromTime, err := a.loadSchemaVersion(v1)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	to, toTime, err := a.loadSchemaVersion(v2)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	d := diffSchemas(from, to)
+	changed := d.changed
+	if changed == nil {
+		changed = []map[string]interface{}{}
+	}
+	result["addedTables"] = append([]string{}, d.addedTables...)
+	result["removedTables"] = append([]string{}, d.removedTables...)
+	result["changedTables"] = changed
+	result["from"] = fromTime
+	result["to"] = toTime
+	result["message"] = fmt.Sprintf("%s → %s：%s", fromTime, toTime, d.summary())
+	return result
+}
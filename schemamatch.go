@@ -0,0 +1,274 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// schemaMatchSampleRows 比较取值分布时每列读取的非空值行数
+const schemaMatchSampleRows = 5000
+
+// schemaMatchTopValues 比较取值重合度时每列取出现最多的值的个数
+const schemaMatchTopValues = 200
+
+// schemaMatchThreshold 列对应的最低得分（0~1）
+const schemaMatchThreshold = 0.5
+
+// matchColumn 参与匹配的一列：names 为列名、数据字典显示名和导入时的源表头，其余为抽样得到的取值特征
+type matchColumn struct {
+	name        string
+	names       []string
+	values      int
+	numeric     float64 // 可解析为数字的比例
+	dates       float64 // 可识别为日期的比例
+	avgLength   float64
+	distinct    float64 // 去重值占比
+	top         map[string]bool
+	hasSamples  bool
+	displayName string
+}
+
+// loadMatchColumns 读取表（或查询）各列的名称和取值特征
+func (a *App) loadMatchColumns(table string) ([]matchColumn, error) {
+	from, columns, err := a.resolveSource(table)
+	if err != nil {
+		return nil, err
+	}
+	labels, _ := a.dictionaryEntries(table)
+	headers := map[string]string{}
+	if h, _ := a.loadSourceHeader(table); h != nil {
+		headers = h.Columns
+	}
+
+	result := make([]matchColumn, 0, len(columns))
+	for _, col := range columns {
+		c := matchColumn{name: col, names: []string{col}, displayName: col, top: make(map[string]bool)}
+		for _, alias := range []string{labels[col].label, headers[col]} {
+			if alias != "" && !containsString(c.names, alias) {
+				c.names = append(c.names, alias)
+				if c.displayName == col {
+					c.displayName = alias
+				}
+			}
+		}
+
+		q := quoteIdent(col)
+		rows, err := a.readDB().Query(fmt.Sprintf(`SELECT v, COUNT(*) FROM (SELECT %s AS v FROM %s WHERE %s IS NOT NULL AND TRIM(%s) <> '' LIMIT %d)
+			GROUP BY v ORDER BY COUNT(*) DESC`, q, from, q, q, schemaMatchSampleRows))
+		if err != nil {
+			return nil, fmt.Errorf("读取列 %s 的取值失败: %v", col, err)
+		}
+		distinct, totalLength := 0, 0
+		for rows.Next() {
+			var v interface{}
+			var n int
+			if err := rows.Scan(&v, &n); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("读取列 %s 的取值失败: %v", col, err)
+			}
+			text := strings.TrimSpace(sqlValueText(v))
+			distinct++
+			c.values += n
+			totalLength += len([]rune(text)) * n
+			// 按规范化后的写法比较取值，100.50 与 100.5、2024/1/2 与 2024-01-02 视为相同
+			key := foldKey(text)
+			if number, ok := parseNumberText(text); ok {
+				c.numeric += float64(n)
+				key = strconv.FormatFloat(number, 'g', -1, 64)
+			} else if date := normalizeDate(text); isoDatePattern.MatchString(date) {
+				c.dates += float64(n)
+				key = date
+			}
+			if len(c.top) < schemaMatchTopValues {
+				c.top[key] = true
+			}
+		}
+		rows.Close()
+		if c.values > 0 {
+			c.hasSamples = true
+			c.numeric /= float64(c.values)
+			c.dates /= float64(c.values)
+			c.avgLength = float64(totalLength) / float64(c.values)
+			c.distinct = float64(distinct) / float64(c.values)
+		}
+		result = append(result, c)
+	}
+	return result, nil
+}
+
+// nameSimilarity 两列名称的相似度（取各别名两两比较的最大值）：忽略大小写、全半角和标点后相同为 1，
+// 一方包含另一方为 0.8，否则按编辑距离折算
+func nameSimilarity(a, b matchColumn) float64 {
+	best := 0.0
+	for _, x := range a.names {
+		for _, y := range b.names {
+			kx, ky := valueFingerprint(x), valueFingerprint(y)
+			kx, ky = strings.ReplaceAll(kx, " ", ""), strings.ReplaceAll(ky, " ", "")
+			if kx == "" || ky == "" {
+				continue
+			}
+			score := 0.0
+			switch {
+			case kx == ky:
+				score = 1
+			case strings.Contains(kx, ky) || strings.Contains(ky, kx):
+				score = 0.8
+			default:
+				n := max(len([]rune(kx)), len([]rune(ky)))
+				score = 1 - float64(levenshtein(kx, ky))/float64(n)
+			}
+			best = max(best, score)
+		}
+	}
+	return best
+}
+
+// valueSimilarity 取值分布的相似度：常见取值的重合度，以及数字/日期比例、平均长度、去重占比等特征的接近程度
+func valueSimilarity(a, b matchColumn) (overlap float64, profile float64) {
+	common := 0
+	for v := range a.top {
+		if b.top[v] {
+			common++
+		}
+	}
+	if n := min(len(a.top), len(b.top)); n > 0 {
+		overlap = float64(common) / float64(n)
+	}
+	diff := func(x, y float64) float64 {
+		return min(1, math.Abs(x-y))
+	}
+	lengthDiff := 0.0
+	if m := max(a.avgLength, b.avgLength); m > 0 {
+		lengthDiff = diff(a.avgLength, b.avgLength) / m
+	}
+	profile = 1 - (diff(a.numeric, b.numeric)+diff(a.dates, b.dates)+diff(a.distinct, b.distinct)+lengthDiff)/4
+	return overlap, profile
+}
+
+// MatchSchemas 对齐两张表（或两条查询）的列：综合列名相似度（含数据字典显示名和导入时的源表头，column1..N 的表也能对齐）
+// 与取值分布（常见取值的重合度、数字/日期比例、长度等），一一对应得分最高的列
+// 返回 mapping（A 表列 -> B 表列，可直接用作 BuildJoin 的连接键或追加导入的列映射）、matches（每对列的得分和依据）、
+// unmatchedA、unmatchedB，以及按 A 表列名合并两表的 unionSQL（B 表中没有对应的列为 NULL）
+// wails:export MatchSchemas
+func (a *App) MatchSchemas(tableA string, tableB string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	colsA, err := a.loadMatchColumns(tableA)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	colsB, err := a.loadMatchColumns(tableB)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+
+	type candidate struct {
+		i, j                          int
+		score, name, overlap, profile float64
+	}
+	var candidates []candidate
+	for i, ca := range colsA {
+		for j, cb := range colsB {
+			c := candidate{i: i, j: j, name: nameSimilarity(ca, cb)}
+			if ca.hasSamples && cb.hasSamples {
+				c.overlap, c.profile = valueSimilarity(ca, cb)
+				c.score = 0.5*c.name + 0.25*c.overlap + 0.25*c.profile
+				// 名称不相关但取值高度重合（如 column3 与 客户名称）时以取值为准
+				c.score = max(c.score, 0.9*c.overlap*c.profile)
+			} else {
+				c.score = c.name
+			}
+			if c.score >= schemaMatchThreshold {
+				candidates = append(candidates, c)
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(x, y int) bool { return candidates[x].score > candidates[y].score })
+
+	usedA := make(map[int]bool)
+	usedB := make(map[int]bool)
+	mapping := make(map[string]string)
+	matches := []map[string]interface{}{}
+	for _, c := range candidates {
+		if usedA[c.i] || usedB[c.j] {
+			continue
+		}
+		usedA[c.i], usedB[c.j] = true, true
+		ca, cb := colsA[c.i], colsB[c.j]
+		mapping[ca.name] = cb.name
+		var reasons []string
+		switch {
+		case c.name == 1:
+			reasons = append(reasons, "名称相同")
+		case c.name >= 0.6:
+			reasons = append(reasons, "名称相近")
+		}
+		if c.overlap > 0 {
+			reasons = append(reasons, fmt.Sprintf("常见取值重合 %.0f%%", c.overlap*100))
+		}
+		if c.profile >= 0.8 {
+			reasons = append(reasons, "取值类型和长度相近")
+		}
+		matches = append(matches, map[string]interface{}{
+			"a":       ca.name,
+			"b":       cb.name,
+			"labelA":  ca.displayName,
+			"labelB":  cb.displayName,
+			"score":   float64(int(c.score*100+0.5)) / 100,
+			"reasons": reasons,
+		})
+	}
+	sort.SliceStable(matches, func(x, y int) bool {
+		return columnIndex(colsA, matches[x]["a"].(string)) < columnIndex(colsA, matches[y]["a"].(string))
+	})
+
+	unmatchedA := []string{}
+	selectA := make([]string, len(colsA))
+	selectB := make([]string, len(colsA))
+	for i, c := range colsA {
+		if !usedA[i] {
+			unmatchedA = append(unmatchedA, c.name)
+		}
+		selectA[i] = quoteIdent(c.name)
+		if b, ok := mapping[c.name]; ok {
+			selectB[i] = fmt.Sprintf("%s AS %s", quoteIdent(b), quoteIdent(c.name))
+		} else {
+			selectB[i] = "NULL AS " + quoteIdent(c.name)
+		}
+	}
+	unmatchedB := []string{}
+	for j, c := range colsB {
+		if !usedB[j] {
+			unmatchedB = append(unmatchedB, c.name)
+		}
+	}
+	fromA, _, _ := a.resolveSource(tableA)
+	fromB, _, _ := a.resolveSource(tableB)
+
+	result["mapping"] = mapping
+	result["matches"] = matches
+	result["unmatchedA"] = unmatchedA
+	result["unmatchedB"] = unmatchedB
+	result["unionSQL"] = fmt.Sprintf("SELECT %s FROM %s\nUNION ALL\nSELECT %s FROM %s",
+		strings.Join(selectA, ", "), fromA, strings.Join(selectB, ", "), fromB)
+	result["message"] = fmt.Sprintf("对齐了 %d 对列，A 表 %d 列、B 表 %d 列未找到对应", len(mapping), len(unmatchedA), len(unmatchedB))
+	return result
+}
+
+// columnIndex 列在匹配列表中的位置
+func columnIndex(cols []matchColumn, name string) int {
+	for i, c := range cols {
+		if c.name == name {
+			return i
+		}
+	}
+	return len(cols)
+}
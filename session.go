@@ -0,0 +1,49 @@
+package main
+
+import "sync"
+
+// querySession 当前查询的 SQL 和分页状态（翻页、导出当前结果、按页定位时使用）；
+// 前端可能同时发起查询、翻页和导出，绑定方法在各自的 goroutine 中执行，读写都经过 mu
+type querySession struct {
+	mu       sync.Mutex
+	sql      string // 当前执行的 SQL（修改类语句不保存）
+	page     int    // 当前页码
+	pageSize int    // 当前页大小
+}
+
+func newQuerySession(pageSize int) *querySession {
+	return &querySession{page: 1, pageSize: pageSize}
+}
+
+// set 记录最近执行的查询及其分页，三者一起更新，避免读到一半新一半旧的状态
+func (s *querySession) set(sqlStr string, page int, pageSize int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sql, s.page, s.pageSize = sqlStr, page, pageSize
+}
+
+// setPageSize 修改页大小（如读取 page_size 设置后）
+func (s *querySession) setPageSize(pageSize int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pageSize = pageSize
+}
+
+// snapshot 当前的 SQL、页码和页大小
+func (s *querySession) snapshot() (string, int, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sql, s.page, s.pageSize
+}
+
+// currentSQL 当前执行的 SQL
+func (s *querySession) currentSQL() string {
+	sqlStr, _, _ := s.snapshot()
+	return sqlStr
+}
+
+// currentPageSize 当前页大小
+func (s *querySession) currentPageSize() int {
+	_, _, pageSize := s.snapshot()
+	return pageSize
+}
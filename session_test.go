@@ -0,0 +1,281 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestApp 在临时目录中创建应用和一张 500 行的表 t，测试结束时关闭连接
+func newTestApp(t *testing.T) *App {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	a := NewApp()
+	t.Cleanup(func() { a.Shutdown(context.Background()) })
+	if a.writeDB() == nil {
+		t.Fatal("数据库连接未初始化")
+	}
+	if _, err := a.writeDB().Exec("CREATE TABLE t (id INTEGER, name TEXT)"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.writeDB().Exec(`WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < 500)
+		INSERT INTO t SELECT i, 'row ' || i FROM n`); err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+// waitExport 等待后台导出任务结束，返回任务快照
+func waitExport(t *testing.T, a *App, jobID string) map[string]interface{} {
+	t.Helper()
+	deadline := time.Now().Add(30 * time.Second)
+	for time.Now().Before(deadline) {
+		job := a.GetExportJob(jobID)
+		if job["status"] != "running" {
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("导出任务 %s 超时", jobID)
+	return nil
+}
+
+// sessionQuery 第 i 个并发查询：SQL 中带上页码，便于检查会话中的 SQL 和页码来自同一次调用
+func sessionQuery(i int) (string, int) {
+	page := i%5 + 1
+	return fmt.Sprintf("SELECT *, 'page %d' AS tag FROM t WHERE id %% 5 <> %d", page, i%7), page
+}
+
+func TestConcurrentQueriesPagingAndExports(t *testing.T) {
+	a := newTestApp(t)
+	dir := t.TempDir()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 256)
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sqlStr, page := sessionQuery(i)
+			for round := 0; round < 3; round++ {
+				result := a.ExecuteSQLWithPage(sqlStr, page, 10)
+				if msg, failed := result["error"]; failed {
+					errs <- fmt.Errorf("查询 %d 失败: %v", i, msg)
+					return
+				}
+				if n := len(result["data"].([]map[string]interface{})); n != 10 {
+					errs <- fmt.Errorf("查询 %d 第 %d 页返回 %d 行", i, page, n)
+				}
+			}
+		}(i)
+	}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			result := a.launchExportJob("SELECT * FROM t", filepath.Join(dir, fmt.Sprintf("export-%d.xlsx", i)), exportOptions{})
+			if !exportStarted(result) {
+				errs <- fmt.Errorf("导出 %d 未开始: %v", i, result["error"])
+				return
+			}
+			job := waitExport(t, a, result["jobId"].(string))
+			if job["status"] != "done" || job["rows"] != 500 {
+				errs <- fmt.Errorf("导出 %d 结果不正确: %v", i, job)
+			}
+		}(i)
+	}
+	// 读取会话状态的同时不断有新的查询写入
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				sqlStr, page, pageSize := a.session.snapshot()
+				if sqlStr == "" {
+					continue
+				}
+				if pageSize != 10 || !strings.Contains(sqlStr, fmt.Sprintf("'page %d'", page)) {
+					errs <- fmt.Errorf("会话状态不一致: %q 第 %d 页，每页 %d 行", sqlStr, page, pageSize)
+					return
+				}
+				a.GetCurrentSQL()
+				a.SetIncludeDeleted(j%2 == 0)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestQueriesDuringConnectionSwitch(t *testing.T) {
+	a := newTestApp(t)
+	dir := t.TempDir()
+	pkg := filepath.Join(dir, "share.db")
+	if msg := a.ExportViewerPackage(pkg, "共享", []string{"t"}, nil); !strings.Contains(msg, "成功") {
+		t.Fatalf("导出分享包失败: %s", msg)
+	}
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	errs := make(chan error, 256)
+	// 查询和导出在切换连接时可以失败（如分享包已关闭），但不能用到已关闭的连接
+	checkClosed := func(what string, msg interface{}) {
+		if s := fmt.Sprint(msg); strings.Contains(s, "database is closed") {
+			errs <- fmt.Errorf("%s 使用了已关闭的连接: %s", what, s)
+		}
+	}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				sqlStr, page := sessionQuery(i)
+				result := a.ExecuteSQLWithPage(sqlStr, page, 10)
+				checkClosed("查询", result["error"])
+				a.readOnly()
+				a.viewer()
+			}
+		}(i)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			result := a.launchExportJob("SELECT * FROM t", filepath.Join(dir, fmt.Sprintf("export-%d.xlsx", i)), exportOptions{})
+			if exportStarted(result) {
+				job := waitExport(t, a, result["jobId"].(string))
+				checkClosed("导出", job["message"])
+			}
+		}
+	}()
+
+	for i := 0; i < 3; i++ {
+		if result := a.OpenViewerPackage(pkg); result["error"] != nil {
+			t.Errorf("打开分享包失败: %v", result["error"])
+		}
+		time.Sleep(20 * time.Millisecond)
+		if !a.readOnly() || a.viewer() == nil {
+			t.Error("打开分享包后应为只读并记录分享包")
+		}
+		a.CloseViewerPackage()
+		time.Sleep(20 * time.Millisecond)
+		if err := a.reconnect(context.Background()); err != nil {
+			t.Errorf("重连失败: %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	close(stop)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+	if a.readOnly() || a.viewer() != nil {
+		t.Error("关闭分享包后应恢复可写的 data.db")
+	}
+}
+
+func TestCachedQueriesDuringCommits(t *testing.T) {
+	a := newTestApp(t)
+	if msg := a.SetSetting("query_cache", "true"); strings.HasPrefix(msg, "错误") {
+		t.Fatalf("开启查询缓存失败: %s", msg)
+	}
+	if _, err := a.writeDB().Exec("CREATE TABLE counter (n INTEGER)"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.writeDB().Exec("INSERT INTO counter VALUES (0)"); err != nil {
+		t.Fatal(err)
+	}
+	const counterSQL = "SELECT n FROM counter"
+	readCounter := func() int64 {
+		t.Helper()
+		entry, err := a.queryFullResult(counterSQL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return entry.data[0]["n"].(int64)
+	}
+
+	// 占住写连接：提交钩子已经触发，连接没有归还连接池，这期间的查询结果不能缓存
+	conn, err := a.writeDB().Conn(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.ExecContext(context.Background(), "UPDATE counter SET n = 1"); err != nil {
+		t.Fatal(err)
+	}
+	readCounter()
+	if a.cache.len() != 0 {
+		t.Error("有提交未完成时不应缓存查询结果")
+	}
+	conn.Close()
+	if n := readCounter(); n != 1 || a.cache.len() != 1 {
+		t.Errorf("提交完成后应缓存最新结果，得到 n=%d，缓存 %d 条", n, a.cache.len())
+	}
+
+	// 写入与缓存查询并发：提交返回后开始的查询不能读到提交前的值
+	var last int64
+	var mu sync.Mutex
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	errs := make(chan error, 16)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				mu.Lock()
+				want := last
+				mu.Unlock()
+				entry, err := a.queryFullResult(counterSQL)
+				if err != nil {
+					errs <- err
+					return
+				}
+				if n := entry.data[0]["n"].(int64); n < want {
+					select {
+					case errs <- fmt.Errorf("提交 %d 完成后查询读到了旧值 %d", want, n):
+					default:
+					}
+					return
+				}
+			}
+		}()
+	}
+	for i := int64(2); i < 300; i++ {
+		if _, err := a.writeDB().Exec("UPDATE counter SET n = ?", i); err != nil {
+			t.Fatal(err)
+		}
+		mu.Lock()
+		last = i
+		mu.Unlock()
+	}
+	close(stop)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
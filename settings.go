@@ -0,0 +1,332 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// settingDefinition 设置项定义：默认值、说明、取值校验，以及需要同步到运行时状态的设置项的 apply 回调
+// secret 为 true 的设置项（如密码）在 GetSettings 中不返回明文；check 为需要查询数据库的校验，恢复默认值时也会执行
+type settingDefinition struct {
+	defaultValue string
+	description  string
+	validate     func(string) error
+	check        func(a *App, value string) error
+	apply        func(string)
+	secret       bool
+}
+
+// oneOf 生成枚举型设置项的校验函数
+func oneOf(options ...string) func(string) error {
+	return func(v string) error {
+		for _, opt := range options {
+			if v == opt {
+				return nil
+			}
+		}
+		return fmt.Errorf("取值必须为 %v 之一", options)
+	}
+}
+
+// intRange 生成整数型设置项的校验函数
+func intRange(min, max int) func(string) error {
+	return func(v string) error {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < min || n > max {
+			return fmt.Errorf("取值必须为 %d-%d 之间的整数", min, max)
+		}
+		return nil
+	}
+}
+
+// settingDefinitions 全部已知设置项
+var settingDefinitions = map[string]settingDefinition{
+	"default_collation": {
+		defaultValue: "BINARY",
+		description:  "导入新表时文本列的默认排序规则（BINARY 按字节，PINYIN 按拼音，FOLD 忽略大小写和全半角）",
+		validate:     oneOf("BINARY", "PINYIN", "FOLD"),
+	},
+	"timezone": {
+		defaultValue: "Local",
+		description:  "日期解析使用的时区（IANA 名称，如 Asia/Shanghai；Local 表示系统时区）",
+		validate:     validateTimezone,
+		apply:        setDateLocation,
+	},
+	"locale": {
+		defaultValue: "zh-CN",
+		description:  "日期格式区域：zh-CN 年-月-日，en-US 月/日/年，en-GB 日/月/年；影响歧义日期解析、FORMAT_DATE 和导出格式",
+		validate:     oneOf("zh-CN", "en-US", "en-GB"),
+		apply:        setDateLocale,
+	},
+	"normalize_dates": {
+		defaultValue: "false",
+		description:  "导入时将日期文本（如 2023/1/5、2023年1月5日）统一转为 YYYY-MM-DD 格式",
+		validate:     oneOf("true", "false"),
+	},
+	"export_header_labels": {
+		defaultValue: "false",
+		description:  "导出 Excel 时使用数据字典中的列显示名作为表头",
+		validate:     oneOf("true", "false"),
+	},
+	"export_header_language": {
+		defaultValue: "",
+		description:  "导出表头使用的语言（如 en-US），按 SetHeaderTranslations 设置的译名替换列名，未翻译的列按 export_header_labels 处理；留空表示不翻译",
+		validate:     validateHeaderLanguage,
+	},
+	"import_source_columns": {
+		defaultValue: "false",
+		description:  "导入 Excel 工作表时为每行追加 _source_file、_source_sheet、_source_row 来源列，便于追溯到原工作簿中的单元格",
+		validate:     oneOf("true", "false"),
+	},
+	"null_policy": {
+		defaultValue: "empty",
+		description:  "NULL 与空字符串的处理：empty 导入空单元格为空字符串、结果中 NULL 显示为空；null 导入为 NULL 并原样保留；marker 导入为 NULL，结果和导出中显示为 (NULL)",
+		validate:     oneOf("empty", "null", "marker"),
+	},
+	"normalize_booleans": {
+		defaultValue: "false",
+		description:  "导入后自动将布尔型列（是/否、Y/N、TRUE/FALSE、1/0）转为 INTEGER 0/1",
+		validate:     oneOf("true", "false"),
+	},
+	"import_hyperlinks": {
+		defaultValue: "false",
+		description:  "导入 Excel 时将单元格超链接地址保存到 <列名>_url 伴随列",
+		validate:     oneOf("true", "false"),
+	},
+	"import_comments": {
+		defaultValue: "false",
+		description:  "导入 Excel 时将单元格批注保存到批注表（按 表/行/列 关联）",
+		validate:     oneOf("true", "false"),
+	},
+	"normalize_cn_numbers": {
+		defaultValue: "false",
+		description:  "导入时将中文数字（如 一万二千、3.5万）转为阿拉伯数字",
+		validate:     oneOf("true", "false"),
+	},
+	"export_provenance": {
+		defaultValue: "false",
+		description:  "导出 Excel 时附加隐藏的 About 页，记录生成时间、SQL、来源表和导入文件，便于追溯数据出处",
+		validate:     oneOf("true", "false"),
+	},
+	"row_hash": {
+		defaultValue: "false",
+		description:  "导入时为每行计算整行哈希并保存到 _row_hash 列，用于增量导入、表比对和重复行检测",
+		validate:     oneOf("true", "false"),
+	},
+	"header_views": {
+		defaultValue: "false",
+		description:  "导入和刷新后自动创建以原表头为列名的视图 <表名>_headers，可直接用原表头查询",
+		validate:     oneOf("true", "false"),
+	},
+	"clean_views": {
+		defaultValue: "false",
+		description:  "导入和刷新后自动创建清洗视图 <表名>_clean（去除首尾空白、日期统一为 ISO 格式、数字列转为数值），原表保持原样",
+		validate:     oneOf("true", "false"),
+	},
+	"typed_layer": {
+		defaultValue: "false",
+		description:  "导入和刷新后在原始 TEXT 表之外生成类型化表 <表名>_typed，转换失败的值可追溯到原始单元格文本",
+		validate:     oneOf("true", "false"),
+	},
+	"analyze_rows": {
+		defaultValue: "10000",
+		description:  "导入行数达到该值时自动更新表的统计信息（ANALYZE），使多表连接选用合适的顺序；0 表示不自动更新",
+		validate:     intRange(0, 1000000000),
+	},
+	"import_priority": {
+		defaultValue: "normal",
+		description:  "导入优先级：normal 在一个事务中尽快写入；background 分批写入暂存表并在批间让出、最后一次并入目标表，导入大文件时界面和查询保持流畅；两种方式中途失败时目标表都保持不变",
+		validate:     oneOf("normal", "background"),
+	},
+	"stale_table_days": {
+		defaultValue: "0",
+		description:  "启动时提示清理超过该天数未导入、未被查询的表（删除前需确认，带“保留”标签的表除外）；0 表示不提示",
+		validate:     intRange(0, 3650),
+	},
+	"base_currency": {
+		defaultValue: "CNY",
+		description:  "汇率表的基准币种（ISO 4217 代码），汇率均表示 1 单位外币折合多少基准币种；汇率表中已有汇率时不能修改",
+		validate:     validateCurrencyCode,
+		check:        (*App).checkBaseCurrency,
+		apply:        setBaseCurrency,
+	},
+	"query_cache": {
+		defaultValue: "true",
+		description:  "缓存最近的查询结果，翻页或切换回同一查询时无需重新执行；数据有任何修改时自动失效",
+		validate:     oneOf("true", "false"),
+	},
+	"os_notifications": {
+		defaultValue: "true",
+		description:  "窗口在后台时，耗时较长的导入、导出完成或失败后发送系统通知",
+		validate:     oneOf("true", "false"),
+	},
+	"page_size": {
+		defaultValue: "20",
+		description:  "查询结果每页默认显示的行数",
+		validate:     intRange(1, 10000),
+	},
+	"preview_rows": {
+		defaultValue: "100",
+		description:  "表预览默认显示的行数",
+		validate:     intRange(1, 100000),
+	},
+	"max_preview_rows": {
+		defaultValue: "1000",
+		description:  "表预览最多显示的行数",
+		validate:     intRange(1, 100000),
+	},
+	"pdf_preview_rows": {
+		defaultValue: "50",
+		description:  "PDF 表格预览显示的行数",
+		validate:     intRange(1, 10000),
+	},
+	"cell_display_length": {
+		defaultValue: "2000",
+		description:  "结果表格中单元格文本最多显示的字符数，超出部分截断，可单独查看完整内容",
+		validate:     intRange(50, 1000000),
+	},
+	"memory_limit_mb": {
+		defaultValue: "2048",
+		description:  "内存上限（MB）：接近上限时大结果不再缓存、全量读取提前终止，导出改为流式写入",
+		validate:     validateMemoryLimit,
+		apply:        setMemoryLimit,
+	},
+	"smtp_host": {
+		defaultValue: "",
+		description:  "发送邮件使用的 SMTP 服务器地址（如 smtp.example.com），为空表示不启用邮件发送",
+	},
+	"smtp_port": {
+		defaultValue: "587",
+		description:  "SMTP 端口：587/25 使用 STARTTLS（服务器支持时），465 使用 SSL",
+		validate:     validatePort,
+	},
+	"smtp_username": {
+		defaultValue: "",
+		description:  "SMTP 登录用户名，为空表示无需认证",
+	},
+	"smtp_password": {
+		defaultValue: "",
+		description:  "SMTP 登录密码或授权码",
+		secret:       true,
+	},
+	"smtp_from": {
+		defaultValue: "",
+		description:  "发件人地址，为空时使用 SMTP 用户名",
+	},
+}
+
+// setting 读取设置值，未设置或读取失败时返回默认值
+func (a *App) setting(key string) string {
+	def := settingDefinitions[key]
+	if a.writeDB() == nil {
+		return def.defaultValue
+	}
+	var value string
+	err := a.readDB().QueryRow("SELECT value FROM _app_settings WHERE key = ?", key).Scan(&value)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			fmt.Printf("读取设置 %s 失败: %v\n", key, err)
+		}
+		return def.defaultValue
+	}
+	return value
+}
+
+// settingInt 读取整数型设置，取值无效时返回默认值
+func (a *App) settingInt(key string) int {
+	if n, err := strconv.Atoi(a.setting(key)); err == nil {
+		return n
+	}
+	n, _ := strconv.Atoi(settingDefinitions[key].defaultValue)
+	return n
+}
+
+// applySettings 将带 apply 回调的设置项同步到运行时状态（启动时调用）
+func (a *App) applySettings() {
+	for key, def := range settingDefinitions {
+		if def.apply != nil {
+			def.apply(a.setting(key))
+		}
+	}
+}
+
+// GetSettings 获取全部设置项（含默认值和说明）
+// wails:export GetSettings
+func (a *App) GetSettings() []map[string]interface{} {
+	keys := make([]string, 0, len(settingDefinitions))
+	for key := range settingDefinitions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	settings := make([]map[string]interface{}, 0, len(keys))
+	for _, key := range keys {
+		def := settingDefinitions[key]
+		value := a.setting(key)
+		if def.secret && value != "" {
+			value = "******"
+		}
+		settings = append(settings, map[string]interface{}{
+			"key":          key,
+			"value":        value,
+			"defaultValue": def.defaultValue,
+			"description":  def.description,
+		})
+	}
+	return settings
+}
+
+// SetSetting 修改设置项，value 为空时恢复默认值
+// wails:export SetSetting
+func (a *App) SetSetting(key string, value string) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+
+	def, ok := settingDefinitions[key]
+	if !ok {
+		return fmt.Sprintf("未知的设置项: %s", key)
+	}
+
+	if value == "" {
+		if def.check != nil {
+			if err := def.check(a, def.defaultValue); err != nil {
+				return fmt.Sprintf("设置 %s 无法恢复默认值: %v", key, err)
+			}
+		}
+		if _, err := a.writeDB().Exec("DELETE FROM _app_settings WHERE key = ?", key); err != nil {
+			return fmt.Sprintf("保存设置失败: %v", err)
+		}
+		if def.apply != nil {
+			def.apply(def.defaultValue)
+		}
+		return fmt.Sprintf("设置 %s 已恢复默认值 %s", key, def.defaultValue)
+	}
+
+	if def.secret && value == "******" {
+		return fmt.Sprintf("设置 %s 未修改", key)
+	}
+	if def.validate != nil {
+		if err := def.validate(value); err != nil {
+			return fmt.Sprintf("设置 %s 无效: %v", key, err)
+		}
+	}
+	if def.check != nil {
+		if err := def.check(a, value); err != nil {
+			return fmt.Sprintf("设置 %s 无法修改: %v", key, err)
+		}
+	}
+	_, err := a.writeDB().Exec(
+		"INSERT INTO _app_settings (key, value) VALUES (?, ?) ON CONFLICT(key) DO UPDATE SET value = excluded.value",
+		key, value,
+	)
+	if err != nil {
+		return fmt.Sprintf("保存设置失败: %v", err)
+	}
+	if def.apply != nil {
+		def.apply(value)
+	}
+	return fmt.Sprintf("设置 %s 已保存为 %s", key, value)
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// deletedFlagColumn 软删除标记列：1 表示已标记删除，行数据仍保留在表中可随时恢复
+const deletedFlagColumn = "_deleted"
+
+// liveSource 表的查询来源：表中有软删除标记列且未开启“包含已删除行”时，返回过滤掉已删除行（并隐藏标记列）的子查询
+func (a *App) liveSource(tableName string, columns []string) (string, []string) {
+	if a.includeDeleted.Load() || !containsString(columns, deletedFlagColumn) {
+		return quoteIdent(tableName), columns
+	}
+	return liveSubquery(tableName, columns, false)
+}
+
+// liveSubquery 过滤掉已删除行的子查询，withRowid 时同时选出 rowid，外层查询仍可按 rowid 引用行；
+// 子查询内以 main. 引用原表，liveSQL 不会再次替换
+func liveSubquery(tableName string, columns []string, withRowid bool) (string, []string) {
+	visible := make([]string, 0, len(columns))
+	quoted := make([]string, 0, len(columns)+1)
+	if withRowid {
+		quoted = append(quoted, "rowid")
+	}
+	for _, col := range columns {
+		if col != deletedFlagColumn {
+			visible = append(visible, col)
+			quoted = append(quoted, quoteIdent(col))
+		}
+	}
+	from := fmt.Sprintf("(SELECT %s FROM main.%s WHERE %s = 0)", strings.Join(quoted, ", "), quoteIdent(tableName), quoteIdent(deletedFlagColumn))
+	return from, visible
+}
+
+// liveSQL 执行、翻页和导出查询语句时，把其中直接引用的带软删除标记的表替换为 liveSource 子查询，
+// 与按表名查看时一样隐藏已删除的行；语句引用了 rowid 时子查询同时选出 rowid。
+// 带库名的引用（如 main.orders）保持原样，可用于查看已删除的行；重复调用的结果不变
+func (a *App) liveSQL(sqlStr string) string {
+	if a.includeDeleted.Load() {
+		return sqlStr
+	}
+	stmt := parseStatement(sqlStr)
+	if stmt.kind != stmtSelect || stmt.multiple {
+		return sqlStr
+	}
+	refs := tableReferences(sqlStr)
+	if len(refs) == 0 {
+		return sqlStr
+	}
+
+	tokens, _, _ := scanSQL(sqlStr)
+	usesRowid := false
+	// WITH 子句中的 name [(列...)] AS (...)：CTE 与表同名时引用的是 CTE
+	ctes := make(map[string]bool)
+	inWith := tokens[0].word == "WITH"
+	prev := ""
+	for _, tok := range tokens {
+		switch tok.word {
+		case "ROWID", "OID", "_ROWID_":
+			usesRowid = true
+		}
+		if !inWith || tok.depth != 0 {
+			continue
+		}
+		switch tok.word {
+		case "SELECT", "VALUES":
+			inWith = false
+		case "AS":
+			ctes[prev] = true
+		}
+		prev = tok.word
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, ref := range refs {
+		if ctes[strings.ToUpper(ref.name)] {
+			continue
+		}
+		columns, err := a.tableColumns(ref.name)
+		if err != nil || !containsString(columns, deletedFlagColumn) {
+			continue
+		}
+		from, _ := liveSubquery(ref.name, columns, usesRowid && a.tableHasRowid(ref.name))
+		b.WriteString(sqlStr[last:ref.start])
+		b.WriteString(from)
+		if !ref.aliased {
+			b.WriteString(" AS " + quoteIdent(ref.name))
+		}
+		last = ref.end
+	}
+	if last == 0 {
+		return sqlStr
+	}
+	b.WriteString(sqlStr[last:])
+	return b.String()
+}
+
+// tableHasRowid 是否为带 rowid 的普通表（视图和 WITHOUT ROWID 表没有 rowid，有名为 rowid 的列时 rowid 指该列）
+func (a *App) tableHasRowid(tableName string) bool {
+	var createSQL string
+	if err := a.readDB().QueryRow("SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ? COLLATE NOCASE", tableName).Scan(&createSQL); err != nil {
+		return false
+	}
+	return createSQLHasRowid(createSQL)
+}
+
+// createSQLHasRowid 按建表语句判断表是否有可用的 rowid（不是 WITHOUT ROWID 表，也没有名为 rowid 的列）
+func createSQLHasRowid(createSQL string) bool {
+	defs, options, err := splitTableDefinition(createSQL)
+	if err != nil || withoutRowid(options) {
+		return false
+	}
+	for _, def := range defs {
+		if name, _, _, ok := splitColumnDefinition(def); ok && strings.EqualFold(name, "rowid") {
+			return false
+		}
+	}
+	return true
+}
+
+// setRowsDeleted 设置指定 rowid 行的软删除标记，rowIDs 为空时作用于全部行
+func (a *App) setRowsDeleted(tableName string, rowIDs []int, deleted bool) (int64, error) {
+	columns, err := a.tableColumns(tableName)
+	if err != nil {
+		return 0, err
+	}
+	if !containsString(columns, deletedFlagColumn) {
+		if !deleted {
+			return 0, nil
+		}
+		if _, err := a.writeDB().Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s INTEGER NOT NULL DEFAULT 0",
+			quoteIdent(tableName), quoteIdent(deletedFlagColumn))); err != nil {
+			return 0, fmt.Errorf("添加列 %s 失败: %v", deletedFlagColumn, err)
+		}
+	}
+
+	flag := 0
+	if deleted {
+		flag = 1
+	}
+	query := fmt.Sprintf("UPDATE %s SET %s = %d WHERE %s <> %d",
+		quoteIdent(tableName), quoteIdent(deletedFlagColumn), flag, quoteIdent(deletedFlagColumn), flag)
+	if len(rowIDs) > 0 {
+		ids := make([]string, len(rowIDs))
+		for i, id := range rowIDs {
+			ids[i] = fmt.Sprint(id)
+		}
+		query += fmt.Sprintf(" AND rowid IN (%s)", strings.Join(ids, ", "))
+	}
+	res, err := a.writeDB().Exec(query)
+	if err != nil {
+		return 0, fmt.Errorf("更新删除标记失败: %v", err)
+	}
+	return res.RowsAffected()
+}
+
+// MarkRowsDeleted 软删除：将指定 rowid 的行标记为已删除（写入隐藏的 _deleted 列），数据仍保留可恢复
+// wails:export MarkRowsDeleted
+func (a *App) MarkRowsDeleted(tableName string, rowIDs []int) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	if len(rowIDs) == 0 {
+		return "请选择要删除的行"
+	}
+	n, err := a.setRowsDeleted(tableName, rowIDs, true)
+	if err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("已将表 %s 的 %d 行标记为删除，可通过恢复操作撤销", tableName, n)
+}
+
+// RestoreRows 恢复软删除的行，rowIDs 为空时恢复表中全部已删除行
+// wails:export RestoreRows
+func (a *App) RestoreRows(tableName string, rowIDs []int) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	n, err := a.setRowsDeleted(tableName, rowIDs, false)
+	if err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("已恢复表 %s 的 %d 行", tableName, n)
+}
+
+// SetIncludeDeleted 设置表预览、表格筛选和汇总等按表名查询时是否包含已软删除的行（包含时显示 _deleted 列）
+// wails:export SetIncludeDeleted
+func (a *App) SetIncludeDeleted(include bool) string {
+	a.includeDeleted.Store(include)
+	if include {
+		return "查询结果将包含已删除的行"
+	}
+	return "查询结果将隐藏已删除的行"
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// 开启 import_source_columns 时追加到导入行的来源列：源文件名、工作表名和 Excel 行号
+const (
+	sourceFileColumn  = "_source_file"
+	sourceSheetColumn = "_source_sheet"
+	sourceRowColumn   = "_source_row"
+)
+
+// sourceColumns 全部来源列
+var sourceColumns = []string{sourceFileColumn, sourceSheetColumn, sourceRowColumn}
+
+// importSourceColumns 导入时是否追加来源列（import_source_columns 设置）
+func (a *App) importSourceColumns() bool {
+	return a.setting("import_source_columns") == "true"
+}
+
+// appendSourceColumns 为每行追加来源列，firstRow 为 rows[0] 在工作表中的行号；
+// 行先补齐到 len(columns)，使来源列落在固定位置
+func appendSourceColumns(columns []string, rows [][]string, filePath string, sheetName string, firstRow int) ([]string, [][]string) {
+	newColumns := append(append([]string{}, columns...), sourceColumns...)
+	file := filepath.Base(filePath)
+	out := make([][]string, len(rows))
+	for i, row := range rows {
+		line := make([]string, len(newColumns))
+		copy(line, row[:min(len(row), len(columns))])
+		line[len(columns)] = file
+		line[len(columns)+1] = sheetName
+		line[len(columns)+2] = strconv.Itoa(firstRow + i)
+		out[i] = line
+	}
+	return newColumns, out
+}
+
+// TraceSourceCell 由导入时记录的来源列定位某行某列在原工作簿中的单元格：返回 file、sheet、row 和 cell（如 C12）
+// 只适用于开启 import_source_columns 后导入的表；column 为 columnN 形式的列时才能给出单元格地址
+// wails:export TraceSourceCell
+func (a *App) TraceSourceCell(tableName string, rowid int64, column string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	columns, err := a.tableColumns(tableName)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	for _, col := range sourceColumns {
+		if !containsString(columns, col) {
+			result["error"] = fmt.Sprintf("表 %s 导入时未记录来源列，请开启 import_source_columns 设置后重新导入", tableName)
+			return result
+		}
+	}
+	var file, sheet, row string
+	err = a.readDB().QueryRow(fmt.Sprintf("SELECT COALESCE(%s, ''), COALESCE(%s, ''), COALESCE(%s, '') FROM %s WHERE rowid = ?",
+		quoteIdent(sourceFileColumn), quoteIdent(sourceSheetColumn), quoteIdent(sourceRowColumn), quoteIdent(tableName)), rowid).
+		Scan(&file, &sheet, &row)
+	if err != nil {
+		result["error"] = fmt.Sprintf("表 %s 中不存在 rowid 为 %d 的行", tableName, rowid)
+		return result
+	}
+	result["file"] = file
+	result["sheet"] = sheet
+	result["row"] = row
+	result["message"] = fmt.Sprintf("%s / %s 第 %s 行", file, sheet, row)
+	if m := defaultColumnPattern.FindStringSubmatch(column); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		line, _ := strconv.Atoi(row)
+		if cell, err := excelize.CoordinatesToCellName(n, line); err == nil {
+			result["cell"] = cell
+			result["message"] = fmt.Sprintf("%s / %s!%s", file, sheet, cell)
+		}
+	}
+	return result
+}
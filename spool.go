@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// 临时文件中保存的值类型
+const (
+	spoolNull uint8 = iota
+	spoolInt
+	spoolFloat
+	spoolText
+	spoolTime
+	spoolBool
+)
+
+// spoolValue 临时文件中的一个单元格值
+type spoolValue struct {
+	Kind  uint8
+	Int   int64
+	Float float64
+	Text  string
+}
+
+// rowSpool 暂存在磁盘临时文件中的查询结果：先一次性读出并写入临时文件（尽快释放数据库读锁），
+// 再逐行读回，内存中只保留当前行
+type rowSpool struct {
+	path    string
+	columns []string
+	count   int
+}
+
+// spoolQuery 执行查询并将全部结果写入临时文件
+func spoolQuery(ctx context.Context, q contextQueryer, sqlStr string) (*rowSpool, error) {
+	rows, err := q.QueryContext(ctx, sqlStr)
+	if err != nil {
+		return nil, fmt.Errorf("SQL 执行失败: %v", err)
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("获取列名失败: %v", err)
+	}
+
+	file, err := os.CreateTemp("", "export-spool-*.gob")
+	if err != nil {
+		return nil, fmt.Errorf("创建临时文件失败: %v", err)
+	}
+	spool := &rowSpool{path: file.Name(), columns: columns}
+	fail := func(err error) (*rowSpool, error) {
+		file.Close()
+		spool.close()
+		return nil, err
+	}
+
+	w := bufio.NewWriter(file)
+	enc := gob.NewEncoder(w)
+	values := make([]interface{}, len(columns))
+	ptrs := make([]interface{}, len(columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	encoded := make([]spoolValue, len(columns))
+	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return fail(err)
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return fail(fmt.Errorf("读取数据失败: %v", err))
+		}
+		for i, v := range values {
+			encoded[i] = encodeSpoolValue(v)
+		}
+		if err := enc.Encode(encoded); err != nil {
+			return fail(fmt.Errorf("写入临时文件失败: %v", err))
+		}
+		spool.count++
+	}
+	if err := rows.Err(); err != nil {
+		return fail(fmt.Errorf("遍历数据失败: %v", err))
+	}
+	if err := w.Flush(); err != nil {
+		return fail(fmt.Errorf("写入临时文件失败: %v", err))
+	}
+	if err := file.Close(); err != nil {
+		spool.close()
+		return nil, fmt.Errorf("写入临时文件失败: %v", err)
+	}
+	return spool, nil
+}
+
+func encodeSpoolValue(v interface{}) spoolValue {
+	switch x := v.(type) {
+	case nil:
+		return spoolValue{Kind: spoolNull}
+	case int64:
+		return spoolValue{Kind: spoolInt, Int: x}
+	case float64:
+		return spoolValue{Kind: spoolFloat, Float: x}
+	case bool:
+		if x {
+			return spoolValue{Kind: spoolBool, Int: 1}
+		}
+		return spoolValue{Kind: spoolBool}
+	case []byte:
+		return spoolValue{Kind: spoolText, Text: string(x)}
+	case string:
+		return spoolValue{Kind: spoolText, Text: x}
+	case time.Time:
+		return spoolValue{Kind: spoolTime, Text: x.Format(time.RFC3339Nano)}
+	default:
+		return spoolValue{Kind: spoolText, Text: fmt.Sprint(x)}
+	}
+}
+
+func (v spoolValue) decode(nullValue interface{}) interface{} {
+	switch v.Kind {
+	case spoolInt:
+		return v.Int
+	case spoolFloat:
+		return v.Float
+	case spoolText:
+		return v.Text
+	case spoolBool:
+		return v.Int != 0
+	case spoolTime:
+		t, _ := time.Parse(time.RFC3339Nano, v.Text)
+		return t
+	default:
+		return nullValue
+	}
+}
+
+// each 按顺序逐行读回结果，每行转为 列名 -> 值 的 map（NULL 转为 nullValue）
+func (s *rowSpool) each(nullValue interface{}, fn func(row map[string]interface{}) error) error {
+	file, err := os.Open(s.path)
+	if err != nil {
+		return fmt.Errorf("读取临时文件失败: %v", err)
+	}
+	defer file.Close()
+	dec := gob.NewDecoder(bufio.NewReader(file))
+	for {
+		var encoded []spoolValue
+		if err := dec.Decode(&encoded); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("读取临时文件失败: %v", err)
+		}
+		row := make(map[string]interface{}, len(s.columns))
+		for i, col := range s.columns {
+			if i < len(encoded) {
+				row[col] = encoded[i].decode(nullValue)
+			} else {
+				row[col] = nullValue
+			}
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+}
+
+// close 删除临时文件
+func (s *rowSpool) close() {
+	os.Remove(s.path)
+}
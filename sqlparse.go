@@ -0,0 +1,471 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// SQL 语句类型
+const (
+	stmtSelect      = "select"      // SELECT / VALUES / WITH ... SELECT
+	stmtInsert      = "insert"      // INSERT / REPLACE
+	stmtUpdate      = "update"      // UPDATE
+	stmtDelete      = "delete"      // DELETE
+	stmtDDL         = "ddl"         // CREATE / DROP / ALTER
+	stmtPragma      = "pragma"      // PRAGMA
+	stmtExplain     = "explain"     // EXPLAIN
+	stmtTransaction = "transaction" // BEGIN / COMMIT / ROLLBACK / SAVEPOINT / RELEASE / END
+	stmtOther       = "other"       // ATTACH、VACUUM、ANALYZE 等
+)
+
+// sqlStatement 对用户 SQL 的轻量预解析结果
+type sqlStatement struct {
+	text      string // 去掉末尾分号和注释后的语句
+	kind      string // 语句类型，见 stmt* 常量
+	hasLimit  bool   // 顶层是否已有 LIMIT
+	returning bool   // 顶层是否带 RETURNING
+	multiple  bool   // 是否包含多条语句
+}
+
+// isQuery 语句是否返回结果集
+func (s sqlStatement) isQuery() bool {
+	return s.kind == stmtSelect || s.kind == stmtExplain || s.kind == stmtPragma || s.returning
+}
+
+// isWrite 语句是否修改数据或结构
+func (s sqlStatement) isWrite() bool {
+	switch s.kind {
+	case stmtInsert, stmtUpdate, stmtDelete, stmtDDL, stmtTransaction, stmtOther:
+		return true
+	}
+	return false
+}
+
+// sqlToken 顶层（不在括号内）的关键字/标识符
+type sqlToken struct {
+	word  string // 大写形式
+	depth int
+	pos   int // 在语句中的起始位置
+}
+
+// skipSQLLiteral s[i] 开始一个字符串、引号标识符或注释时返回其后的位置，comment 表示是注释；否则 ok 为 false
+func skipSQLLiteral(s string, i int) (next int, comment bool, ok bool) {
+	c := s[i]
+	switch {
+	case c == '\'' || c == '"' || c == '`':
+		j := i + 1
+		for j < len(s) {
+			if s[j] == c {
+				if j+1 < len(s) && s[j+1] == c {
+					j += 2
+					continue
+				}
+				break
+			}
+			j++
+		}
+		return min(j+1, len(s)), false, true
+	case c == '[':
+		j := strings.IndexByte(s[i:], ']')
+		if j < 0 {
+			return len(s), false, true
+		}
+		return i + j + 1, false, true
+	case c == '-' && i+1 < len(s) && s[i+1] == '-':
+		j := strings.IndexByte(s[i:], '\n')
+		if j < 0 {
+			return len(s), true, true
+		}
+		return i + j + 1, true, true
+	case c == '/' && i+1 < len(s) && s[i+1] == '*':
+		j := strings.Index(s[i+2:], "*/")
+		if j < 0 {
+			return len(s), true, true
+		}
+		return i + j + 4, true, true
+	}
+	return i, false, false
+}
+
+// lexSQL 依次报告 SQL 中的词法单元 s[start:end]（跳过空白），visit 返回 false 时停止；kind 为
+// w（单词）、n（数字）、s（字符串）、i（引号标识符）、c（注释）、p（符号，比较运算符和 || 为两个字符，其余一个字符）
+func lexSQL(s string, visit func(kind byte, start int, end int) bool) {
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		kind, j := byte('p'), i+1
+		switch next, comment, ok := skipSQLLiteral(s, i); {
+		case ok:
+			j = next
+			switch {
+			case comment:
+				kind = 'c'
+			case c == '\'':
+				kind = 's'
+			default:
+				kind = 'i'
+			}
+		case unicode.IsSpace(rune(c)):
+			i++
+			continue
+		case c >= '0' && c <= '9' || c == '.' && i+1 < len(s) && s[i+1] >= '0' && s[i+1] <= '9':
+			kind, j = 'n', i
+			for j < len(s) && (s[j] >= '0' && s[j] <= '9' || s[j] == '.' || s[j] == 'e' || s[j] == 'E' ||
+				(s[j] == '+' || s[j] == '-') && (s[j-1] == 'e' || s[j-1] == 'E')) {
+				j++
+			}
+			// 以数字开头的名称（如 2024_sales）按单词处理
+			if j < len(s) && isWordByte(s[j]) {
+				kind = 'w'
+				for j < len(s) && isWordByte(s[j]) {
+					j++
+				}
+			}
+		case isWordByte(c):
+			kind, j = 'w', i
+			for j < len(s) && isWordByte(s[j]) {
+				j++
+			}
+		case i+1 < len(s):
+			switch s[i : i+2] {
+			case "<=", ">=", "<>", "!=", "==", "||":
+				j = i + 2
+			}
+		}
+		if !visit(kind, i, j) {
+			return
+		}
+		i = j
+	}
+}
+
+// sqlLiteralText 字符串或引号标识符的内容：去掉两端的引号（或方括号），还原转义的引号
+func sqlLiteralText(lit string) string {
+	if lit == "" {
+		return lit
+	}
+	switch q := lit[0]; q {
+	case '[':
+		return strings.TrimSuffix(lit[1:], "]")
+	case '\'', '"', '`':
+		body := lit[1:]
+		if strings.HasSuffix(body, string(q)) {
+			body = body[:len(body)-1]
+		}
+		return strings.ReplaceAll(body, string([]byte{q, q}), string(q))
+	}
+	return lit
+}
+
+// scanSQL 扫描 SQL：跳过字符串、引号标识符和注释，返回单词列表和第一个顶层分号的位置（没有时为 -1）
+// end 为去掉末尾空白和注释后语句内容的结束位置
+func scanSQL(s string) (tokens []sqlToken, semicolon int, end int) {
+	semicolon = -1
+	depth := 0
+	lexSQL(s, func(kind byte, start int, stop int) bool {
+		switch kind {
+		case 'c':
+			return true
+		case 'w', 'n':
+			tokens = append(tokens, sqlToken{word: strings.ToUpper(s[start:stop]), depth: depth, pos: start})
+		case 'p':
+			switch s[start] {
+			case ';':
+				semicolon = start
+				return false
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+		}
+		end = stop
+		return true
+	})
+	return tokens, semicolon, end
+}
+
+// splitTableDefinition 拆分 CREATE TABLE 语句：defs 为括号内按顶层逗号拆分的列定义和表约束，
+// options 为右括号及之后的部分（如 ") WITHOUT ROWID"、") STRICT"）
+func splitTableDefinition(createSQL string) (defs []string, options string, err error) {
+	depth, start := 0, -1
+	for i := 0; i < len(createSQL); {
+		if next, _, ok := skipSQLLiteral(createSQL, i); ok {
+			i = next
+			continue
+		}
+		switch createSQL[i] {
+		case '(':
+			depth++
+			if depth == 1 && start < 0 {
+				start = i + 1
+			}
+		case ',':
+			if depth == 1 {
+				defs = append(defs, strings.TrimSpace(createSQL[start:i]))
+				start = i + 1
+			}
+		case ')':
+			depth--
+			if depth == 0 && start >= 0 {
+				defs = append(defs, strings.TrimSpace(createSQL[start:i]))
+				return defs, createSQL[i:], nil
+			}
+		}
+		i++
+	}
+	return nil, "", fmt.Errorf("无法解析表定义: %s", createSQL)
+}
+
+// withoutRowid 表选项中是否有 WITHOUT ROWID
+func withoutRowid(options string) bool {
+	tokens, _, _ := scanSQL(options)
+	for i := 0; i+1 < len(tokens); i++ {
+		if tokens[i].word == "WITHOUT" && tokens[i+1].word == "ROWID" {
+			return true
+		}
+	}
+	return false
+}
+
+// columnConstraintWords 列定义中类型之后可能出现的约束关键字
+var columnConstraintWords = map[string]bool{
+	"CONSTRAINT": true, "PRIMARY": true, "NOT": true, "NULL": true, "UNIQUE": true, "CHECK": true,
+	"DEFAULT": true, "COLLATE": true, "REFERENCES": true, "GENERATED": true, "AS": true,
+}
+
+// tableConstraintWords 表约束的起始关键字
+var tableConstraintWords = map[string]bool{"CONSTRAINT": true, "PRIMARY": true, "UNIQUE": true, "CHECK": true, "FOREIGN": true}
+
+// splitColumnDefinition 拆分一条列定义为列名（去掉引号）、声明类型和其后的约束；表约束返回 ok 为 false
+func splitColumnDefinition(def string) (name string, declType string, constraints string, ok bool) {
+	nameEnd := 0
+	if next, comment, quoted := skipSQLLiteral(def, 0); quoted && !comment {
+		nameEnd = next
+		name = unquoteIdent(def[:next])
+	} else {
+		for nameEnd < len(def) && isWordByte(def[nameEnd]) {
+			nameEnd++
+		}
+		name = def[:nameEnd]
+		if tableConstraintWords[strings.ToUpper(name)] {
+			return "", "", "", false
+		}
+	}
+	rest := def[nameEnd:]
+	tokens, _, _ := scanSQL(rest)
+	typeEnd := len(rest)
+	for _, t := range tokens {
+		if t.depth == 0 && columnConstraintWords[t.word] {
+			typeEnd = t.pos
+			break
+		}
+	}
+	return name, strings.TrimSpace(rest[:typeEnd]), strings.TrimSpace(rest[typeEnd:]), name != ""
+}
+
+// tableReference 语句中 FROM / JOIN 之后直接引用的表（不含子查询、表值函数和带库名的引用）
+type tableReference struct {
+	name    string // 表名（去掉引号）
+	start   int    // 表名在语句中的起止位置
+	end     int
+	aliased bool // 表名之后有别名
+}
+
+// tableReferences 找出语句中直接引用的表，按出现位置排序；FROM 之后以逗号分隔的多个表都会列出
+func tableReferences(sqlStr string) []tableReference {
+	tokens, _, _ := scanSQL(sqlStr)
+	var refs []tableReference
+	for i, tok := range tokens {
+		// IS [NOT] DISTINCT FROM 是比较运算符
+		if tok.word != "FROM" && tok.word != "JOIN" || tok.word == "FROM" && i > 0 && tokens[i-1].word == "DISTINCT" {
+			continue
+		}
+		pos := tok.pos + len(tok.word)
+		for {
+			ref, next, ok := scanTableReference(sqlStr, pos)
+			if !ok {
+				break
+			}
+			if ref.name != "" {
+				refs = append(refs, ref)
+			}
+			pos = skipSQLSpace(sqlStr, next)
+			if tok.word != "FROM" || pos >= len(sqlStr) || sqlStr[pos] != ',' {
+				break
+			}
+			pos++
+		}
+	}
+	return refs
+}
+
+// scanTableReference 解析 pos 处的表引用及其别名，返回引用之后的位置；
+// 不是表名（如子查询）时 ok 为 false，带库名或无法替换的引用（INDEXED BY）返回空的 name
+func scanTableReference(s string, pos int) (ref tableReference, next int, ok bool) {
+	start := skipSQLSpace(s, pos)
+	name, end, ok := scanIdentifier(s, start)
+	if !ok {
+		return ref, pos, false
+	}
+	after := skipSQLSpace(s, end)
+	if after < len(s) && s[after] == '(' {
+		return ref, pos, false // 表值函数
+	}
+	if after < len(s) && s[after] == '.' {
+		_, end, ok = scanIdentifier(s, skipSQLSpace(s, after+1))
+		return ref, end, ok
+	}
+	ref = tableReference{name: name, start: start, end: end}
+	alias, aliasEnd, ok := scanIdentifier(s, after)
+	if !ok {
+		return ref, end, true
+	}
+	switch word := strings.ToUpper(alias); {
+	case word == "AS":
+		_, aliasEnd, _ = scanIdentifier(s, skipSQLSpace(s, aliasEnd))
+	case word == "INDEXED" || word == "NOT":
+		return tableReference{}, end, true
+	case isWordByte(s[after]) && sqlKeywords[strings.ToLower(alias)]:
+		return ref, end, true
+	}
+	ref.aliased = true
+	return ref, aliasEnd, true
+}
+
+// scanIdentifier 解析 pos 处的标识符（可带引号），返回去掉引号的名称和其后的位置
+func scanIdentifier(s string, pos int) (name string, end int, ok bool) {
+	if pos >= len(s) {
+		return "", pos, false
+	}
+	if next, comment, quoted := skipSQLLiteral(s, pos); quoted {
+		if comment {
+			return "", pos, false
+		}
+		return unquoteIdent(s[pos:next]), next, true
+	}
+	end = pos
+	for end < len(s) && isWordByte(s[end]) {
+		end++
+	}
+	return s[pos:end], end, end > pos
+}
+
+// skipSQLSpace 跳过空白和注释
+func skipSQLSpace(s string, pos int) int {
+	for pos < len(s) {
+		if next, comment, ok := skipSQLLiteral(s, pos); ok && comment {
+			pos = next
+			continue
+		}
+		if !unicode.IsSpace(rune(s[pos])) {
+			break
+		}
+		pos++
+	}
+	return pos
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// parseStatement 预解析用户 SQL：去掉末尾分号和注释，识别语句类型和顶层 LIMIT / RETURNING
+func parseStatement(sqlStr string) sqlStatement {
+	tokens, semicolon, end := scanSQL(sqlStr)
+	stmt := sqlStatement{text: strings.TrimSpace(sqlStr[:end]), kind: stmtOther}
+	// 分号之后（跳过空语句和注释）还有内容即为多条语句
+	for rest := sqlStr[semicolon+1:]; semicolon >= 0; {
+		restTokens, next, restEnd := scanSQL(rest)
+		if len(restTokens) > 0 || restEnd > 0 {
+			stmt.multiple = true
+			break
+		}
+		if next < 0 {
+			break
+		}
+		rest = rest[next+1:]
+	}
+
+	for i, tok := range tokens {
+		if tok.depth != 0 {
+			continue
+		}
+		if i == 0 {
+			switch tok.word {
+			case "SELECT", "VALUES":
+				stmt.kind = stmtSelect
+			case "INSERT", "REPLACE":
+				stmt.kind = stmtInsert
+			case "UPDATE":
+				stmt.kind = stmtUpdate
+			case "DELETE":
+				stmt.kind = stmtDelete
+			case "CREATE", "DROP", "ALTER":
+				stmt.kind = stmtDDL
+			case "PRAGMA":
+				stmt.kind = stmtPragma
+			case "EXPLAIN":
+				stmt.kind = stmtExplain
+			case "BEGIN", "COMMIT", "ROLLBACK", "SAVEPOINT", "RELEASE", "END":
+				stmt.kind = stmtTransaction
+			case "WITH":
+				// CTE 的定义都在括号内，顶层第一个 DML 关键字即主语句
+				for _, next := range tokens[1:] {
+					if next.depth != 0 {
+						continue
+					}
+					switch next.word {
+					case "SELECT", "VALUES":
+						stmt.kind = stmtSelect
+					case "INSERT", "REPLACE":
+						stmt.kind = stmtInsert
+					case "UPDATE":
+						stmt.kind = stmtUpdate
+					case "DELETE":
+						stmt.kind = stmtDelete
+					default:
+						continue
+					}
+					break
+				}
+			}
+			continue
+		}
+		switch tok.word {
+		case "LIMIT":
+			stmt.hasLimit = true
+		case "RETURNING":
+			stmt.returning = true
+		}
+	}
+	return stmt
+}
+
+// wrappable 语句能否作为子查询包装（用于分页、计数）
+func (s sqlStatement) wrappable() error {
+	if s.multiple {
+		return fmt.Errorf("一次只能分页执行一条 SQL 语句")
+	}
+	if s.kind != stmtSelect {
+		return fmt.Errorf("只有查询语句可以分页")
+	}
+	return nil
+}
+
+// pagedSQL 生成分页查询：原语句作为子查询，原有的 LIMIT 和 CTE 在子查询内保持原样生效
+func (s sqlStatement) pagedSQL(limit int, offset int) (string, error) {
+	if err := s.wrappable(); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("SELECT * FROM (\n%s\n) LIMIT %d OFFSET %d", s.text, limit, offset), nil
+}
+
+// countSQL 生成统计总行数的查询
+func (s sqlStatement) countSQL() (string, error) {
+	if err := s.wrappable(); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("SELECT COUNT(*) FROM (\n%s\n)", s.text), nil
+}
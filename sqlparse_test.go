@@ -0,0 +1,335 @@
+package main
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func TestParseStatement(t *testing.T) {
+	tests := []struct {
+		name      string
+		sql       string
+		text      string
+		kind      string
+		hasLimit  bool
+		returning bool
+		multiple  bool
+	}{
+		{
+			name: "简单查询",
+			sql:  "SELECT * FROM t",
+			text: "SELECT * FROM t",
+			kind: stmtSelect,
+		},
+		{
+			name: "末尾分号和空白",
+			sql:  "  select * from t ;  \n",
+			text: "select * from t",
+			kind: stmtSelect,
+		},
+		{
+			name: "末尾行注释",
+			sql:  "SELECT * FROM t -- 全部订单",
+			text: "SELECT * FROM t",
+			kind: stmtSelect,
+		},
+		{
+			name: "分号后的注释和空语句",
+			sql:  "SELECT * FROM t; /* 结束 */ ;; -- 备注",
+			text: "SELECT * FROM t",
+			kind: stmtSelect,
+		},
+		{
+			name: "字符串中的分号和关键字",
+			sql:  "SELECT 'a; DELETE FROM t LIMIT 1' AS s FROM t",
+			text: "SELECT 'a; DELETE FROM t LIMIT 1' AS s FROM t",
+			kind: stmtSelect,
+		},
+		{
+			name: "字符串中的转义引号",
+			sql:  "SELECT 'it''s; LIMIT 5' FROM t;",
+			text: "SELECT 'it''s; LIMIT 5' FROM t",
+			kind: stmtSelect,
+		},
+		{
+			name: "引号标识符中的关键字",
+			sql:  `SELECT "limit", [returning], ` + "`;`" + ` FROM t`,
+			text: `SELECT "limit", [returning], ` + "`;`" + ` FROM t`,
+			kind: stmtSelect,
+		},
+		{
+			name: "注释中的分号和关键字",
+			sql:  "SELECT * /* ; LIMIT 10 */ FROM t -- ; RETURNING",
+			text: "SELECT * /* ; LIMIT 10 */ FROM t",
+			kind: stmtSelect,
+		},
+		{
+			name:     "已有 LIMIT",
+			sql:      "SELECT * FROM t ORDER BY id LIMIT 10",
+			text:     "SELECT * FROM t ORDER BY id LIMIT 10",
+			kind:     stmtSelect,
+			hasLimit: true,
+		},
+		{
+			name:     "已有 LIMIT OFFSET",
+			sql:      "SELECT * FROM t LIMIT 10 OFFSET 20;",
+			text:     "SELECT * FROM t LIMIT 10 OFFSET 20",
+			kind:     stmtSelect,
+			hasLimit: true,
+		},
+		{
+			name: "子查询中的 LIMIT 不算顶层",
+			sql:  "SELECT * FROM (SELECT * FROM t LIMIT 5)",
+			text: "SELECT * FROM (SELECT * FROM t LIMIT 5)",
+			kind: stmtSelect,
+		},
+		{
+			name: "CTE 查询",
+			sql:  "WITH x AS (SELECT * FROM t LIMIT 3) SELECT * FROM x",
+			text: "WITH x AS (SELECT * FROM t LIMIT 3) SELECT * FROM x",
+			kind: stmtSelect,
+		},
+		{
+			name: "带列名的递归 CTE",
+			sql:  "WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < 5) SELECT i FROM n",
+			text: "WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < 5) SELECT i FROM n",
+			kind: stmtSelect,
+		},
+		{
+			name: "CTE 修改语句",
+			sql:  "WITH x AS (SELECT id FROM t) DELETE FROM t WHERE id IN (SELECT id FROM x)",
+			text: "WITH x AS (SELECT id FROM t) DELETE FROM t WHERE id IN (SELECT id FROM x)",
+			kind: stmtDelete,
+		},
+		{
+			name: "VALUES",
+			sql:  "VALUES (1), (2)",
+			text: "VALUES (1), (2)",
+			kind: stmtSelect,
+		},
+		{
+			name:      "RETURNING",
+			sql:       "UPDATE t SET v = 1 WHERE id = 2 RETURNING *;",
+			text:      "UPDATE t SET v = 1 WHERE id = 2 RETURNING *",
+			kind:      stmtUpdate,
+			returning: true,
+		},
+		{
+			name:      "INSERT RETURNING",
+			sql:       "INSERT INTO t (v) VALUES ('x') RETURNING id",
+			text:      "INSERT INTO t (v) VALUES ('x') RETURNING id",
+			kind:      stmtInsert,
+			returning: true,
+		},
+		{
+			name: "字符串中的 RETURNING",
+			sql:  "INSERT INTO t (v) VALUES ('RETURNING')",
+			text: "INSERT INTO t (v) VALUES ('RETURNING')",
+			kind: stmtInsert,
+		},
+		{
+			name:     "多条语句",
+			sql:      "SELECT 1; SELECT 2",
+			text:     "SELECT 1",
+			kind:     stmtSelect,
+			multiple: true,
+		},
+		{
+			name:     "分号后还有修改语句",
+			sql:      "SELECT * FROM t; DELETE FROM t;",
+			text:     "SELECT * FROM t",
+			kind:     stmtSelect,
+			multiple: true,
+		},
+		{
+			name: "DDL",
+			sql:  "CREATE TABLE x (a TEXT)",
+			text: "CREATE TABLE x (a TEXT)",
+			kind: stmtDDL,
+		},
+		{
+			name: "PRAGMA",
+			sql:  "pragma table_info(t)",
+			text: "pragma table_info(t)",
+			kind: stmtPragma,
+		},
+		{
+			name: "EXPLAIN",
+			sql:  "EXPLAIN QUERY PLAN SELECT * FROM t",
+			text: "EXPLAIN QUERY PLAN SELECT * FROM t",
+			kind: stmtExplain,
+		},
+		{
+			name: "事务语句",
+			sql:  "BEGIN;",
+			text: "BEGIN",
+			kind: stmtTransaction,
+		},
+		{
+			name: "其他语句",
+			sql:  "VACUUM",
+			text: "VACUUM",
+			kind: stmtOther,
+		},
+		{
+			name: "只有注释",
+			sql:  "-- 什么也没有",
+			text: "",
+			kind: stmtOther,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseStatement(tt.sql)
+			if got.text != tt.text {
+				t.Errorf("text = %q，期望 %q", got.text, tt.text)
+			}
+			if got.kind != tt.kind {
+				t.Errorf("kind = %q，期望 %q", got.kind, tt.kind)
+			}
+			if got.hasLimit != tt.hasLimit {
+				t.Errorf("hasLimit = %v，期望 %v", got.hasLimit, tt.hasLimit)
+			}
+			if got.returning != tt.returning {
+				t.Errorf("returning = %v，期望 %v", got.returning, tt.returning)
+			}
+			if got.multiple != tt.multiple {
+				t.Errorf("multiple = %v，期望 %v", got.multiple, tt.multiple)
+			}
+		})
+	}
+}
+
+func TestPagedAndCountSQL(t *testing.T) {
+	tests := []struct {
+		name    string
+		sql     string
+		paged   string
+		count   string
+		wantErr bool
+	}{
+		{
+			name:  "简单查询",
+			sql:   "SELECT * FROM t;",
+			paged: "SELECT * FROM (\nSELECT * FROM t\n) LIMIT 20 OFFSET 40",
+			count: "SELECT COUNT(*) FROM (\nSELECT * FROM t\n)",
+		},
+		{
+			name:  "末尾注释不会注释掉外层",
+			sql:   "SELECT * FROM t -- 备注",
+			paged: "SELECT * FROM (\nSELECT * FROM t\n) LIMIT 20 OFFSET 40",
+			count: "SELECT COUNT(*) FROM (\nSELECT * FROM t\n)",
+		},
+		{
+			name:  "原有 LIMIT OFFSET 在子查询内生效",
+			sql:   "SELECT * FROM t ORDER BY id LIMIT 100 OFFSET 5",
+			paged: "SELECT * FROM (\nSELECT * FROM t ORDER BY id LIMIT 100 OFFSET 5\n) LIMIT 20 OFFSET 40",
+			count: "SELECT COUNT(*) FROM (\nSELECT * FROM t ORDER BY id LIMIT 100 OFFSET 5\n)",
+		},
+		{
+			name:  "CTE",
+			sql:   "WITH x AS (SELECT 1 AS a) SELECT a FROM x",
+			paged: "SELECT * FROM (\nWITH x AS (SELECT 1 AS a) SELECT a FROM x\n) LIMIT 20 OFFSET 40",
+			count: "SELECT COUNT(*) FROM (\nWITH x AS (SELECT 1 AS a) SELECT a FROM x\n)",
+		},
+		{
+			name:    "多条语句不能包装",
+			sql:     "SELECT 1; SELECT 2",
+			wantErr: true,
+		},
+		{
+			name:    "RETURNING 不能包装",
+			sql:     "DELETE FROM t RETURNING *",
+			wantErr: true,
+		},
+		{
+			name:    "修改语句不能包装",
+			sql:     "UPDATE t SET v = 1",
+			wantErr: true,
+		},
+		{
+			name:    "PRAGMA 不能包装",
+			sql:     "PRAGMA table_info(t)",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt := parseStatement(tt.sql)
+			paged, pagedErr := stmt.pagedSQL(20, 40)
+			count, countErr := stmt.countSQL()
+			if tt.wantErr {
+				if pagedErr == nil || countErr == nil {
+					t.Fatalf("期望不能包装，得到 %q / %q", paged, count)
+				}
+				return
+			}
+			if pagedErr != nil || countErr != nil {
+				t.Fatalf("包装失败: %v / %v", pagedErr, countErr)
+			}
+			if paged != tt.paged {
+				t.Errorf("pagedSQL = %q，期望 %q", paged, tt.paged)
+			}
+			if count != tt.count {
+				t.Errorf("countSQL = %q，期望 %q", count, tt.count)
+			}
+		})
+	}
+}
+
+func TestPagedSQLExecutes(t *testing.T) {
+	db, err := sql.Open(scratchDriverName, ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec("CREATE TABLE t (id INTEGER, v TEXT)"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 50; i++ {
+		if _, err := db.Exec("INSERT INTO t VALUES (?, ?)", i, "a;b"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		sql   string
+		total int
+		page  int // 第 2 页（每页 20 行）的行数
+	}{
+		{"SELECT * FROM t;", 50, 20},
+		{"SELECT * FROM t WHERE v = 'a;b' -- 备注", 50, 20},
+		{"SELECT * FROM t ORDER BY id LIMIT 30", 30, 10},
+		{"SELECT * FROM t ORDER BY id LIMIT 30 OFFSET 25", 25, 5},
+		{"WITH x AS (SELECT * FROM t WHERE id <= 15) SELECT * FROM x", 15, 0},
+	}
+	for _, tt := range tests {
+		stmt := parseStatement(tt.sql)
+		count, err := stmt.countSQL()
+		if err != nil {
+			t.Fatalf("%s: %v", tt.sql, err)
+		}
+		var total int
+		if err := db.QueryRow(count).Scan(&total); err != nil {
+			t.Fatalf("%s: 执行 %q 失败: %v", tt.sql, count, err)
+		}
+		if total != tt.total {
+			t.Errorf("%s: 总行数 %d，期望 %d", tt.sql, total, tt.total)
+		}
+
+		paged, _ := stmt.pagedSQL(20, 20)
+		rows, err := db.Query(paged)
+		if err != nil {
+			t.Fatalf("%s: 执行 %q 失败: %v", tt.sql, paged, err)
+		}
+		n := 0
+		for rows.Next() {
+			n++
+		}
+		rows.Close()
+		if n != tt.page {
+			t.Errorf("%s: 第 2 页 %d 行，期望 %d", tt.sql, n, tt.page)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// maxFuzzyClusterValues 去重值不超过该数量时才在指纹分组之后再按编辑距离合并相近的值（两两比较）
+const maxFuzzyClusterValues = 2000
+
+// valueFingerprint 值的指纹（同 OpenRefine 的 fingerprint 键）：忽略大小写、全半角、标点和词序
+func valueFingerprint(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsPunct(r) || unicode.IsSymbol(r) {
+			return ' '
+		}
+		return r
+	}, foldKey(s))
+	tokens := strings.Fields(s)
+	sort.Strings(tokens)
+	unique := tokens[:0]
+	for i, t := range tokens {
+		if i == 0 || t != tokens[i-1] {
+			unique = append(unique, t)
+		}
+	}
+	return strings.Join(unique, " ")
+}
+
+// levenshtein 按字符（rune）计算编辑距离
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// similarKeys 两个指纹是否足够接近：允许的编辑距离随长度增加（每 6 个字符 1 处），且至少 1 处
+func similarKeys(a, b string) bool {
+	n := min(len([]rune(a)), len([]rune(b)))
+	if n < 3 {
+		return false
+	}
+	limit := max(1, n/6)
+	if d := len([]rune(a)) - len([]rune(b)); d > limit || -d > limit {
+		return false
+	}
+	return levenshtein(a, b) <= limit
+}
+
+// ClusterSimilarValues 对文本列的取值做模糊聚类：先按指纹分组（大小写、全半角、标点、词序不同视为同一值），
+// 去重值不多时再合并编辑距离相近的分组；每组给出建议的标准值（出现次数最多的写法），供 ApplyStandardization 使用
+// wails:export ClusterSimilarValues
+func (a *App) ClusterSimilarValues(tableName string, column string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+
+	columns, err := a.tableColumns(tableName)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	if !containsString(columns, column) {
+		result["error"] = fmt.Sprintf("表 %s 中不存在列 %s", tableName, column)
+		return result
+	}
+
+	from, _ := a.liveSource(tableName, columns)
+	rows, err := a.readDB().Query(fmt.Sprintf("SELECT CAST(%s AS TEXT) AS v, COUNT(*) FROM %s WHERE TRIM(COALESCE(%s, '')) <> '' GROUP BY v",
+		quoteIdent(column), from, quoteIdent(column)))
+	if err != nil {
+		result["error"] = fmt.Sprintf("读取取值失败: %v", err)
+		return result
+	}
+	type valueCount struct {
+		value string
+		count int
+	}
+	var values []valueCount
+	for rows.Next() {
+		var vc valueCount
+		if err := rows.Scan(&vc.value, &vc.count); err != nil {
+			rows.Close()
+			result["error"] = fmt.Sprintf("读取取值失败: %v", err)
+			return result
+		}
+		values = append(values, vc)
+	}
+	rows.Close()
+
+	// 按指纹分组，再用并查集合并相近的指纹
+	groups := make(map[string][]valueCount)
+	var keys []string
+	for _, vc := range values {
+		key := valueFingerprint(vc.value)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], vc)
+	}
+	parent := make([]int, len(keys))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	fuzzy := len(values) <= maxFuzzyClusterValues
+	if fuzzy {
+		for i := range keys {
+			for j := i + 1; j < len(keys); j++ {
+				if similarKeys(keys[i], keys[j]) {
+					parent[find(j)] = find(i)
+				}
+			}
+		}
+	}
+	merged := make(map[int][]valueCount)
+	for i, key := range keys {
+		root := find(i)
+		merged[root] = append(merged[root], groups[key]...)
+	}
+
+	var clusters []map[string]interface{}
+	for _, members := range merged {
+		if len(members) < 2 {
+			continue
+		}
+		sort.Slice(members, func(i, j int) bool {
+			if members[i].count != members[j].count {
+				return members[i].count > members[j].count
+			}
+			return members[i].value < members[j].value
+		})
+		total := 0
+		items := make([]map[string]interface{}, len(members))
+		for i, m := range members {
+			total += m.count
+			items[i] = map[string]interface{}{"value": m.value, "count": m.count}
+		}
+		clusters = append(clusters, map[string]interface{}{
+			"suggested": members[0].value,
+			"values":    items,
+			"rowCount":  total,
+		})
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		ci, cj := clusters[i]["rowCount"].(int), clusters[j]["rowCount"].(int)
+		if ci != cj {
+			return ci > cj
+		}
+		return clusters[i]["suggested"].(string) < clusters[j]["suggested"].(string)
+	})
+
+	result["table"] = tableName
+	result["column"] = column
+	result["clusters"] = clusters
+	result["distinctValues"] = len(values)
+	result["fuzzy"] = fuzzy
+	message := fmt.Sprintf("列 %s 共 %d 个不同取值，发现 %d 组相似值", column, len(values), len(clusters))
+	if !fuzzy {
+		message += fmt.Sprintf("（不同取值超过 %d 个，仅按指纹分组）", maxFuzzyClusterValues)
+	}
+	result["message"] = message
+	return result
+}
+
+// ApplyStandardization 按 原值 -> 标准值 的映射改写列中的取值（通常来自 ClusterSimilarValues 的结果），在一个事务中完成
+// wails:export ApplyStandardization
+func (a *App) ApplyStandardization(tableName string, column string, mapping map[string]string) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	if len(mapping) == 0 {
+		return "请指定要替换的取值"
+	}
+
+	columns, err := a.tableColumns(tableName)
+	if err != nil {
+		return err.Error()
+	}
+	if !containsString(columns, column) {
+		return fmt.Sprintf("表 %s 中不存在列 %s", tableName, column)
+	}
+
+	tx, err := a.writeDB().Begin()
+	if err != nil {
+		return fmt.Sprintf("开启事务失败: %v", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(fmt.Sprintf("UPDATE %s SET %s = ? WHERE CAST(%s AS TEXT) = ?",
+		quoteIdent(tableName), quoteIdent(column), quoteIdent(column)))
+	if err != nil {
+		return fmt.Sprintf("预编译更新语句失败: %v", err)
+	}
+	defer stmt.Close()
+
+	var changed int64
+	replaced := 0
+	for from, to := range mapping {
+		if from == to {
+			continue
+		}
+		res, err := stmt.Exec(to, from)
+		if err != nil {
+			return fmt.Sprintf("替换取值 %s 失败: %v", from, err)
+		}
+		n, _ := res.RowsAffected()
+		changed += n
+		replaced++
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Sprintf("提交事务失败: %v", err)
+	}
+	return fmt.Sprintf("已将列 %s 中 %d 个取值统一为标准值，共修改 %d 行", column, replaced, changed)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// analyzeTable 对表执行 ANALYZE，更新 sqlite_stat1 中的统计信息，供查询规划器选择连接顺序和索引
+func (a *App) analyzeTable(tableName string) error {
+	if _, err := a.writeDB().Exec("ANALYZE " + quoteIdent(tableName)); err != nil {
+		return fmt.Errorf("更新表 %s 的统计信息失败: %v", tableName, err)
+	}
+	return nil
+}
+
+// analyzeAfterImport 导入的行数达到 analyze_rows 设置时更新表的统计信息；失败只记录日志，不影响导入结果
+func (a *App) analyzeAfterImport(tableName string, rowCount int) {
+	threshold := a.settingInt("analyze_rows")
+	if threshold <= 0 || rowCount < threshold {
+		return
+	}
+	if err := a.analyzeTable(tableName); err != nil {
+		fmt.Println(err)
+	}
+}
+
+// UpdateStatistics 更新表的统计信息（ANALYZE），tableName 为空时更新整个数据库
+// 大表导入后会按 analyze_rows 设置自动更新；手动修改大量数据或新建索引后可调用此方法，使多表连接选用合适的顺序
+// wails:export UpdateStatistics
+func (a *App) UpdateStatistics(tableName string) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	if a.readOnly() {
+		return readOnlyMessage
+	}
+	start := time.Now()
+	if tableName == "" {
+		if _, err := a.writeDB().Exec("ANALYZE"); err != nil {
+			return fmt.Sprintf("更新统计信息失败: %v", err)
+		}
+		return fmt.Sprintf("已更新全部表的统计信息（耗时 %s）", time.Since(start).Round(time.Millisecond))
+	}
+	if _, err := a.tableColumns(tableName); err != nil {
+		return err.Error()
+	}
+	if err := a.analyzeTable(tableName); err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("已更新表 %s 的统计信息（耗时 %s）", tableName, time.Since(start).Round(time.Millisecond))
+}
@@ -0,0 +1,37 @@
+package main
+
+// 操作结果的 status：前端据此区分成功、用户取消对话框和真正的失败，不必比对提示文字
+const (
+	statusSuccess   = "success"
+	statusCancelled = "cancelled"
+	statusError     = "error"
+)
+
+// status 为 error 时的 code
+const (
+	codeDBNotReady   = "db_not_ready"  // 数据库连接未初始化
+	codeInvalidInput = "invalid_input" // 参数无效（如 SQL 为空）
+	codeDialogFailed = "dialog_failed" // 文件对话框打开失败
+	codeImportFailed = "import_failed" // 解析或写入导入文件失败
+	codeExportFailed = "export_failed" // 启动导出失败
+)
+
+// successResult status 为 success 的结果
+func successResult(message string) map[string]interface{} {
+	return map[string]interface{}{"status": statusSuccess, "message": message}
+}
+
+// cancelledResult 用户关闭了文件对话框，没有执行任何操作；不含 error 字段
+func cancelledResult(message string) map[string]interface{} {
+	return map[string]interface{}{"status": statusCancelled, "message": message}
+}
+
+// errorResult status 为 error 的结果，error 字段与其他接口一致，保留给只检查 error 的调用方
+func errorResult(code string, message string) map[string]interface{} {
+	return map[string]interface{}{"status": statusError, "code": code, "error": message, "message": message}
+}
+
+// exportStarted 导出结果是否表示已开始导出（不是取消或失败）
+func exportStarted(result map[string]interface{}) bool {
+	return result["status"] == statusSuccess
+}
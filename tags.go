@@ -0,0 +1,215 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// tableTags 读取表的标签（按字母排序）
+func (a *App) tableTags(tableName string) ([]string, error) {
+	rows, err := a.readDB().Query("SELECT tag FROM _app_table_tags WHERE table_name = ? ORDER BY tag", tableName)
+	if err != nil {
+		return nil, fmt.Errorf("读取表标签失败: %v", err)
+	}
+	defer rows.Close()
+
+	tags := []string{}
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, fmt.Errorf("读取表标签失败: %v", err)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+// tableFolder 读取表所在文件夹，未设置时返回空字符串
+func (a *App) tableFolder(tableName string) string {
+	var folder string
+	a.readDB().QueryRow("SELECT folder FROM _app_table_folders WHERE table_name = ?", tableName).Scan(&folder)
+	return folder
+}
+
+// SetTableTags 覆盖设置表的标签（空列表表示清除全部标签）
+// wails:export SetTableTags
+func (a *App) SetTableTags(tableName string, tags []string) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	if _, err := a.tableColumns(tableName); err != nil {
+		return err.Error()
+	}
+
+	tx, err := a.writeDB().Begin()
+	if err != nil {
+		return fmt.Sprintf("开启事务失败: %v", err)
+	}
+	if _, err := tx.Exec("DELETE FROM _app_table_tags WHERE table_name = ?", tableName); err != nil {
+		tx.Rollback()
+		return fmt.Sprintf("保存表标签失败: %v", err)
+	}
+	saved := 0
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		if _, err := tx.Exec("INSERT OR IGNORE INTO _app_table_tags (table_name, tag) VALUES (?, ?)", tableName, tag); err != nil {
+			tx.Rollback()
+			return fmt.Sprintf("保存表标签失败: %v", err)
+		}
+		saved++
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Sprintf("提交事务失败: %v", err)
+	}
+	return fmt.Sprintf("已为表 %s 设置 %d 个标签", tableName, saved)
+}
+
+// ListTablesByTag 列出带有指定标签的表
+// wails:export ListTablesByTag
+func (a *App) ListTablesByTag(tag string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+
+	rows, err := a.readDB().Query(`SELECT t.table_name FROM _app_table_tags t
+		JOIN sqlite_master m ON m.type = 'table' AND m.name = t.table_name
+		WHERE t.tag = ? ORDER BY t.table_name`, strings.TrimSpace(tag))
+	if err != nil {
+		result["error"] = fmt.Sprintf("读取表标签失败: %v", err)
+		return result
+	}
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			result["error"] = fmt.Sprintf("读取表标签失败: %v", err)
+			return result
+		}
+		names = append(names, name)
+	}
+	rows.Close()
+
+	tables, err := a.tableSummaries(names)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	result["tables"] = tables
+	result["message"] = fmt.Sprintf("标签 %s 下共 %d 张表", tag, len(tables))
+	return result
+}
+
+// ListTags 列出全部标签及使用次数
+// wails:export ListTags
+func (a *App) ListTags() map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+
+	rows, err := a.readDB().Query("SELECT tag, COUNT(*) FROM _app_table_tags GROUP BY tag ORDER BY tag")
+	if err != nil {
+		result["error"] = fmt.Sprintf("读取表标签失败: %v", err)
+		return result
+	}
+	defer rows.Close()
+
+	var tags []map[string]interface{}
+	for rows.Next() {
+		var tag string
+		var count int
+		if err := rows.Scan(&tag, &count); err != nil {
+			result["error"] = fmt.Sprintf("读取表标签失败: %v", err)
+			return result
+		}
+		tags = append(tags, map[string]interface{}{"tag": tag, "count": count})
+	}
+	result["tags"] = tags
+	return result
+}
+
+// SetTableFolder 将表移动到文件夹（层级用 / 分隔），folder 为空表示移出文件夹
+// wails:export SetTableFolder
+func (a *App) SetTableFolder(tableName string, folder string) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	if _, err := a.tableColumns(tableName); err != nil {
+		return err.Error()
+	}
+
+	// 规范化路径：去除多余的分隔符和空白段
+	var parts []string
+	for _, part := range strings.Split(folder, "/") {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	folder = strings.Join(parts, "/")
+
+	var err error
+	if folder == "" {
+		_, err = a.writeDB().Exec("DELETE FROM _app_table_folders WHERE table_name = ?", tableName)
+	} else {
+		_, err = a.writeDB().Exec(`INSERT INTO _app_table_folders (table_name, folder) VALUES (?, ?)
+			ON CONFLICT(table_name) DO UPDATE SET folder = excluded.folder`, tableName, folder)
+	}
+	if err != nil {
+		return fmt.Sprintf("保存表文件夹失败: %v", err)
+	}
+	if folder == "" {
+		return fmt.Sprintf("已将表 %s 移出文件夹", tableName)
+	}
+	return fmt.Sprintf("已将表 %s 移动到文件夹 %s", tableName, folder)
+}
+
+// GetTableTree 按文件夹分组返回全部表，未归档的表放在空文件夹下
+// wails:export GetTableTree
+func (a *App) GetTableTree() map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+
+	names, err := a.userTables()
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	tables, err := a.tableSummaries(names)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+
+	grouped := make(map[string][]map[string]interface{})
+	for _, table := range tables {
+		folder := table["folder"].(string)
+		grouped[folder] = append(grouped[folder], table)
+	}
+	folders := make([]string, 0, len(grouped))
+	for folder := range grouped {
+		folders = append(folders, folder)
+	}
+	sort.Strings(folders)
+
+	tree := make([]map[string]interface{}, 0, len(folders))
+	for _, folder := range folders {
+		tree = append(tree, map[string]interface{}{
+			"folder": folder,
+			"tables": grouped[folder],
+		})
+	}
+	result["folders"] = tree
+	result["message"] = fmt.Sprintf("共 %d 个文件夹，%d 张表", len(folders), len(tables))
+	return result
+}
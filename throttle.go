@@ -0,0 +1,73 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"runtime"
+	"time"
+)
+
+// importBackgroundBatch 后台优先级导入时每批写入的行数，每批单独提交并释放写锁
+const importBackgroundBatch = 2000
+
+// importBackgroundYield 后台优先级导入时两批之间让出的时间，使界面操作、保存设置等写入能及时拿到写锁
+const importBackgroundYield = 20 * time.Millisecond
+
+// backgroundImport 是否按后台优先级导入（import_priority 设置）
+func (a *App) backgroundImport() bool {
+	return a.setting("import_priority") == "background"
+}
+
+// importStagingSuffix 后台优先级导入的暂存表后缀：数据先分批写入暂存表，最后在一个事务中替换或追加到目标表，
+// 导入过程中目标表保持原样，中途失败时目标表不变；暂存表的写入不算数据变化（见 dataversion.go），
+// 不会每提交一批就使查询缓存失效并通知前端
+const importStagingSuffix = "__import"
+
+// stagingTableName 目标表对应的暂存表名
+func stagingTableName(tableName string) string {
+	return tableName + importStagingSuffix
+}
+
+// stageInBatches 用 createSQL 新建暂存表 staging，按 importBackgroundBatch 把 total 行分批写入，每批一个事务，
+// 批间让出 CPU 和写锁；insert 写入 [start, end) 行。失败时删除暂存表
+func (a *App) stageInBatches(staging string, createSQL string, total int, insert func(tx *sql.Tx, start, end int) error, op *operation) (err error) {
+	if _, err := a.writeDB().Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", quoteIdent(staging))); err != nil {
+		return fmt.Errorf("删除暂存表 %s 失败: %v", staging, err)
+	}
+	if _, err := a.writeDB().Exec(createSQL); err != nil {
+		return fmt.Errorf("创建暂存表 %s 失败: %v", staging, err)
+	}
+	defer func() {
+		if err != nil {
+			a.dropStagingTable(staging)
+		}
+	}()
+	for start := 0; start < total; start += importBackgroundBatch {
+		if err := op.canceled(); err != nil {
+			return err
+		}
+		end := min(start+importBackgroundBatch, total)
+		tx, err := a.writeDB().Begin()
+		if err != nil {
+			return fmt.Errorf("开启事务失败: %v", err)
+		}
+		if err := insert(tx, start, end); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("写入第 %d-%d 行失败: %v", start+1, end, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("提交事务失败: %v", err)
+		}
+		op.progress("write", end, total, fmt.Sprintf("已写入 %d/%d 行（后台优先级）", end, total))
+		runtime.Gosched()
+		time.Sleep(importBackgroundYield)
+	}
+	return nil
+}
+
+// dropStagingTable 删除导入失败后留下的暂存表
+func (a *App) dropStagingTable(staging string) {
+	if _, err := a.writeDB().Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", quoteIdent(staging))); err != nil {
+		fmt.Printf("删除暂存表 %s 失败: %v\n", staging, err)
+	}
+}
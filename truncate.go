@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// truncateLongCells 截断超过 maxLen 个字符的文本单元格（maxLen 取 cell_display_length 设置），返回新的行数据和被截断的单元格 [{row, column, length}]（row 为页内行号，length 为原字符数）
+// 只复制包含过长单元格的行，不修改原数据（原数据可能来自结果缓存）
+func truncateLongCells(data []map[string]interface{}, columns []string, maxLen int) ([]map[string]interface{}, []map[string]interface{}) {
+	var truncated []map[string]interface{}
+	out := data
+	for rowIdx, row := range data {
+		var copied map[string]interface{}
+		for _, col := range columns {
+			s, ok := row[col].(string)
+			if !ok || len(s) <= maxLen {
+				continue
+			}
+			n := utf8.RuneCountInString(s)
+			if n <= maxLen {
+				continue
+			}
+			if copied == nil {
+				copied = make(map[string]interface{}, len(row))
+				for k, v := range row {
+					copied[k] = v
+				}
+				if len(truncated) == 0 {
+					out = append([]map[string]interface{}(nil), data...)
+				}
+				out[rowIdx] = copied
+			}
+			copied[col] = truncateRunes(s, maxLen) + "…"
+			truncated = append(truncated, map[string]interface{}{"row": rowIdx, "column": col, "length": n})
+		}
+	}
+	return out, truncated
+}
+
+// truncateRunes 取字符串的前 n 个字符
+func truncateRunes(s string, n int) string {
+	for i := range s {
+		if n == 0 {
+			return s[:i]
+		}
+		n--
+	}
+	return s
+}
+
+// GetCellValue 读取结果中某个单元格的完整值（用于查看被截断的长文本）
+// source 为表名或查询语句（与显示结果时相同），row 为结果中的行号（从 0 开始，即 (页码-1)×每页行数+页内行号）
+// wails:export GetCellValue
+func (a *App) GetCellValue(source string, row int, column string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	if row < 0 {
+		result["error"] = "行号不能为负数"
+		return result
+	}
+
+	// 结果已缓存时直接读取，保证与显示的数据一致
+	stmt := parseStatement(source)
+	if a.cache != nil {
+		if entry, ok := a.cache.get(a.liveSQL(stmt.text)); ok {
+			if row >= len(entry.data) {
+				result["error"] = fmt.Sprintf("第 %d 行超出结果范围（共 %d 行）", row+1, len(entry.data))
+				return result
+			}
+			v, ok := entry.data[row][column]
+			if !ok {
+				result["error"] = fmt.Sprintf("列 %s 不存在", column)
+				return result
+			}
+			result["value"] = v
+			return result
+		}
+	}
+
+	query, err := a.cellRowSQL(source, row)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	rows, err := a.readDB().Query(query)
+	if err != nil {
+		result["error"] = fmt.Sprintf("第 %d 行读取失败: %v", row+1, err)
+		return result
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		result["error"] = fmt.Sprintf("获取列名失败: %v", err)
+		return result
+	}
+	if !containsString(columns, column) {
+		result["error"] = fmt.Sprintf("列 %s 不存在", column)
+		return result
+	}
+	data, err := scanRowMaps(rows, columns, a.nullValue())
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	if len(data) == 0 {
+		result["error"] = fmt.Sprintf("第 %d 行不存在", row+1)
+		return result
+	}
+	result["value"] = data[0][column]
+	return result
+}
+
+// cellRowSQL 读取 source 结果中第 row 行（从 0 开始）的语句。查询语句用与分页显示时相同的 pagedSQL 包装，
+// 查询计划和行的顺序与显示的一致（只选一列时 SQLite 可能改用覆盖索引，没有 ORDER BY 的查询行序随之改变）；
+// 带 rowid 的表按 rowid 排序定位，视图和 WITHOUT ROWID 表按表的自然顺序
+func (a *App) cellRowSQL(source string, row int) (string, error) {
+	if columns, err := a.tableColumns(source); err == nil {
+		from, visible := a.liveSource(source, columns)
+		if !a.tableHasRowid(source) {
+			return fmt.Sprintf("SELECT * FROM %s LIMIT 1 OFFSET %d", from, row), nil
+		}
+		if from != quoteIdent(source) {
+			from, _ = liveSubquery(source, columns, true)
+		}
+		quoted := make([]string, len(visible))
+		for i, col := range visible {
+			quoted[i] = quoteIdent(col)
+		}
+		return fmt.Sprintf("SELECT %s FROM %s ORDER BY rowid LIMIT 1 OFFSET %d", strings.Join(quoted, ", "), from, row), nil
+	}
+	stmt := parseStatement(source)
+	if err := stmt.wrappable(); err != nil {
+		return "", fmt.Errorf("%s 不是表名，也不是可用的查询语句", source)
+	}
+	stmt.text = a.liveSQL(stmt.text)
+	return stmt.pagedSQL(1, row)
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// compareOperators 参与检查的比较运算符，值为 true 表示范围比较（按文本比较时结果会出错）
+var compareOperators = map[string]bool{"<": true, ">": true, "<=": true, ">=": true, "=": false, "==": false, "<>": false, "!=": false}
+
+// numberLiteralAt 位置 i 处的数字（可带正负号），返回数字文本和下一个位置
+func (c *lintContext) numberLiteralAt(i int) (string, int, bool) {
+	t := c.tokens
+	if i < len(t) && t[i].kind == 'n' {
+		return t[i].text, i + 1, true
+	}
+	if i+1 < len(t) && (t[i].text == "-" || t[i].text == "+") && t[i+1].kind == 'n' {
+		return t[i].text + t[i+1].text, i + 2, true
+	}
+	return "", i, false
+}
+
+// numberLiteralBefore 以位置 end（不含）结尾的数字（可带负号）
+func (c *lintContext) numberLiteralBefore(end int) (string, bool) {
+	t := c.tokens
+	if end < 1 || t[end-1].kind != 'n' {
+		return "", false
+	}
+	number := t[end-1].text
+	// 前面是运算数时 "-" 是减号而不是负号
+	if end >= 2 && t[end-2].text == "-" && (end < 3 || t[end-3].kind == 'p' && t[end-3].text != ")" || t[end-3].kind == 'w' && !t[end-3].isIdent()) {
+		number = "-" + number
+	}
+	return number, true
+}
+
+// textComparison 一处文本列与数字的比较
+type textComparison struct {
+	table     string
+	column    string
+	ref       string   // SQL 中的写法（可能带表别名）
+	affinity  string   // TEXT 或 BLOB
+	numbers   []string // 参与比较的数字
+	ranged    bool     // 是否为范围比较（<、>、BETWEEN）
+	rewrite   string   // 改写为 CAST 后的条件
+	operation string   // 原条件中的运算
+}
+
+// comparisonColumn 位置 end（不含）之前的列引用（跳过 NOT），解析为文本亲和或无类型的表列
+func (c *lintContext) comparisonColumn(end int, depth int) (textComparison, bool) {
+	if end >= 1 && c.tokens[end-1].isWord("NOT") {
+		end--
+	}
+	qualifier, column, _, ok := c.columnBefore(end)
+	if !ok {
+		return textComparison{}, false
+	}
+	return c.textColumn(qualifier, column, depth)
+}
+
+// textColumn 把列引用解析为文本亲和或无类型的表列
+func (c *lintContext) textColumn(qualifier string, column string, depth int) (textComparison, bool) {
+	table, info, ok := c.resolve(qualifier, column, depth)
+	if !ok {
+		return textComparison{}, false
+	}
+	affinity := columnAffinity(info.declType)
+	if affinity != "TEXT" && affinity != "BLOB" {
+		return textComparison{}, false
+	}
+	ref := info.name
+	if qualifier != "" {
+		ref = qualifier + "." + info.name
+	}
+	return textComparison{table: table, column: info.name, ref: ref, affinity: affinity}, true
+}
+
+// findTextComparisons 找出文本列与数字字面量的比较：运算符（两侧均可）、BETWEEN、IN 列表
+// 文本亲和的列与数字做范围比较时数字先转为文本再逐字符比较（'9' > '100'）；无类型的列中文本值与数字永远不相等，且数字总是小于文本；
+// 文本亲和的列用 =、IN 与数字比较时数字会转为文本，通常能得到预期结果，不提示
+func (c *lintContext) findTextComparisons() []textComparison {
+	var found []textComparison
+	t := c.tokens
+	keep := func(cmp textComparison) {
+		if cmp.ranged || cmp.affinity == "BLOB" {
+			found = append(found, cmp)
+		}
+	}
+	for i, tok := range t {
+		switch {
+		case tok.kind == 'p':
+			ranged, isCompare := compareOperators[tok.text]
+			if !isCompare {
+				continue
+			}
+			// 列 运算符 数字
+			if number, _, ok := c.numberLiteralAt(i + 1); ok {
+				if cmp, ok := c.comparisonColumn(i, tok.depth); ok {
+					cmp.numbers, cmp.ranged, cmp.operation = []string{number}, ranged, tok.text
+					cmp.rewrite = fmt.Sprintf("CAST(%s AS REAL) %s %s", cmp.ref, tok.text, number)
+					keep(cmp)
+					continue
+				}
+			}
+			// 数字 运算符 列
+			number, ok := c.numberLiteralBefore(i)
+			if !ok {
+				continue
+			}
+			qualifier, column, _, ok := c.columnAt(i + 1)
+			if !ok {
+				continue
+			}
+			if cmp, ok := c.textColumn(qualifier, column, tok.depth); ok {
+				cmp.numbers, cmp.ranged, cmp.operation = []string{number}, ranged, tok.text
+				cmp.rewrite = fmt.Sprintf("%s %s CAST(%s AS REAL)", number, tok.text, cmp.ref)
+				keep(cmp)
+			}
+		case tok.isWord("BETWEEN"):
+			low, next, okLow := c.numberLiteralAt(i + 1)
+			if !okLow || next >= len(t) || !t[next].isWord("AND") {
+				continue
+			}
+			high, _, okHigh := c.numberLiteralAt(next + 1)
+			if !okHigh {
+				continue
+			}
+			if cmp, ok := c.comparisonColumn(i, tok.depth); ok {
+				cmp.numbers, cmp.ranged, cmp.operation = []string{low, high}, true, "BETWEEN"
+				cmp.rewrite = fmt.Sprintf("CAST(%s AS REAL) BETWEEN %s AND %s", cmp.ref, low, high)
+				keep(cmp)
+			}
+		case tok.isWord("IN") && i+1 < len(t) && t[i+1].text == "(":
+			var numbers []string
+			for j := i + 2; j < len(t) && t[j].depth > tok.depth; j++ {
+				if t[j].depth != tok.depth+1 {
+					continue
+				}
+				if t[j].isWord("SELECT", "WITH", "VALUES") {
+					numbers = nil
+					break
+				}
+				if number, next, ok := c.numberLiteralAt(j); ok {
+					numbers = append(numbers, number)
+					j = next - 1
+				}
+			}
+			if len(numbers) == 0 {
+				continue
+			}
+			if cmp, ok := c.comparisonColumn(i, tok.depth); ok {
+				cmp.numbers, cmp.operation = numbers, "IN"
+				cmp.rewrite = fmt.Sprintf("CAST(%s AS REAL) IN (%s)", cmp.ref, strings.Join(numbers, ", "))
+				keep(cmp)
+			}
+		}
+	}
+	return found
+}
+
+// textNumberComparisons 文本列与数字比较的提示，附带 CAST 改写（cast）和列类型转换建议（convert，可直接传给 ConvertColumnType）
+func (c *lintContext) textNumberComparisons() []map[string]interface{} {
+	var warnings []map[string]interface{}
+	for _, cmp := range c.findTextComparisons() {
+		targetType := "INTEGER"
+		for _, n := range cmp.numbers {
+			if strings.ContainsAny(n, ".eE") {
+				targetType = "REAL"
+			}
+		}
+		numbers := strings.Join(cmp.numbers, "、")
+		message := fmt.Sprintf("列 %s 是文本类型，与数字 %s 做 %s 比较时按文本逐字符比较（如 '9' > '100'），结果可能不正确", cmp.ref, numbers, cmp.operation)
+		if cmp.affinity == "BLOB" {
+			message = fmt.Sprintf("列 %s 没有声明类型，其中的文本值与数字 %s 永远不相等，且数字总是小于文本，%s 比较的结果可能不正确", cmp.ref, numbers, cmp.operation)
+		}
+		warnings = append(warnings, map[string]interface{}{
+			"rule":       lintTextNumber,
+			"table":      cmp.table,
+			"column":     cmp.column,
+			"message":    message,
+			"suggestion": fmt.Sprintf("改写为 %s，或将列 %s 转换为 %s 类型后按数值比较", cmp.rewrite, cmp.column, targetType),
+			"cast":       cmp.rewrite,
+			"convert":    map[string]interface{}{"table": cmp.table, "column": cmp.column, "targetType": targetType},
+		})
+	}
+	return warnings
+}
+
+// typeComparisonWarnings 执行查询时附带的文本列与数字比较提示（分析失败时不返回提示）
+func (a *App) typeComparisonWarnings(sqlStr string) []map[string]interface{} {
+	return newLintContext(a, sqlStr).textNumberComparisons()
+}
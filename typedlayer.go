@@ -0,0 +1,242 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// typedTableSuffix 类型化表名后缀：原始表 sheet1 的类型化表为 sheet1_typed
+const typedTableSuffix = "_typed"
+
+// typedRawRowIDColumn 类型化表中指向原始表 rowid 的列，据此找回每个值的原始单元格文本
+const typedRawRowIDColumn = "_raw_rowid"
+
+// typedKindMinRatio 推断类型化表的列类型时，抽样值中可转换的最低比例；其余无法转换的值写为 NULL 并记录转换错误
+const typedKindMinRatio = 0.9
+
+// typedDeclTypes 推断出的类型对应的声明类型
+var typedDeclTypes = map[string]string{"number": "NUMERIC", "date": "DATE", "text": "TEXT"}
+
+// buildTypedLayer 由原始 TEXT 表重建类型化表 <表名>_typed：每列按抽样推断的类型转换，_raw_rowid 指向原始行；
+// 无法转换的值（包括数字列中以 0 开头的编码）写为 NULL，原始文本记录在 _app_type_errors 中。返回各列的声明类型、行数和转换错误数
+func (a *App) buildTypedLayer(rawTable string) (string, map[string]string, int, int, error) {
+	typedTable := rawTable + typedTableSuffix
+	var kind string
+	if a.readDB().QueryRow("SELECT type FROM sqlite_master WHERE name = ?", typedTable).Scan(&kind) == nil {
+		var owner string
+		if a.readDB().QueryRow("SELECT raw_table FROM _app_typed_layers WHERE typed_table = ?", typedTable).Scan(&owner) != nil || owner != rawTable {
+			return "", nil, 0, 0, fmt.Errorf("已存在名为 %s 的表或视图，无法创建类型化表", typedTable)
+		}
+	}
+	columns, err := a.tableColumns(rawTable)
+	if err != nil {
+		return "", nil, 0, 0, err
+	}
+	var dataColumns []string
+	declTypes := make(map[string]string)
+	defs := []string{quoteIdent(typedRawRowIDColumn) + " INTEGER PRIMARY KEY"}
+	for _, col := range columns {
+		if col == deletedFlagColumn || col == rowHashColumn {
+			continue
+		}
+		kind, err := a.inferCleanKind(rawTable, col, typedKindMinRatio)
+		if err != nil {
+			return "", nil, 0, 0, err
+		}
+		dataColumns = append(dataColumns, col)
+		declTypes[col] = typedDeclTypes[kind]
+		defs = append(defs, quoteIdent(col)+" "+declTypes[col])
+	}
+	if len(dataColumns) == 0 {
+		return "", nil, 0, 0, fmt.Errorf("表 %s 没有可转换的列", rawTable)
+	}
+	where := ""
+	if containsString(columns, deletedFlagColumn) {
+		where = fmt.Sprintf(" WHERE %s = 0", quoteIdent(deletedFlagColumn))
+	}
+
+	tx, err := a.writeDB().Begin()
+	if err != nil {
+		return "", nil, 0, 0, fmt.Errorf("开启事务失败: %v", err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", quoteIdent(typedTable))); err != nil {
+		return "", nil, 0, 0, fmt.Errorf("删除旧表 %s 失败: %v", typedTable, err)
+	}
+	if _, err := tx.Exec(fmt.Sprintf("CREATE TABLE %s (%s)", quoteIdent(typedTable), strings.Join(defs, ", "))); err != nil {
+		return "", nil, 0, 0, fmt.Errorf("创建表 %s 失败: %v", typedTable, err)
+	}
+	if _, err := tx.Exec("DELETE FROM _app_type_errors WHERE typed_table = ?", typedTable); err != nil {
+		return "", nil, 0, 0, fmt.Errorf("清除旧的转换错误失败: %v", err)
+	}
+
+	quoted := make([]string, len(dataColumns))
+	for i, col := range dataColumns {
+		quoted[i] = quoteIdent(col)
+	}
+	insertStmt, err := tx.Prepare(fmt.Sprintf("INSERT INTO %s (%s, %s) VALUES (?%s)", quoteIdent(typedTable),
+		quoteIdent(typedRawRowIDColumn), strings.Join(quoted, ", "), strings.Repeat(", ?", len(dataColumns))))
+	if err != nil {
+		return "", nil, 0, 0, fmt.Errorf("准备插入语句失败: %v", err)
+	}
+	defer insertStmt.Close()
+	errorStmt, err := tx.Prepare(`INSERT INTO _app_type_errors (typed_table, raw_rowid, column_name, raw_value, target_type, error)
+		VALUES (?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return "", nil, 0, 0, fmt.Errorf("准备插入语句失败: %v", err)
+	}
+	defer errorStmt.Close()
+
+	rows, err := tx.Query(fmt.Sprintf("SELECT rowid, %s FROM %s%s", strings.Join(quoted, ", "), quoteIdent(rawTable), where))
+	if err != nil {
+		return "", nil, 0, 0, fmt.Errorf("读取表 %s 失败: %v", rawTable, err)
+	}
+	defer rows.Close()
+	emptyAsNull := a.importEmptyAsNull()
+	rowCount, errorCount := 0, 0
+	raw := make([]interface{}, len(dataColumns)+1)
+	ptrs := make([]interface{}, len(raw))
+	for i := range raw {
+		ptrs[i] = &raw[i]
+	}
+	values := make([]interface{}, len(raw))
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return "", nil, 0, 0, fmt.Errorf("读取表 %s 失败: %v", rawTable, err)
+		}
+		values[0] = raw[0]
+		for i, col := range dataColumns {
+			v := raw[i+1]
+			if v == nil {
+				values[i+1] = nil
+				continue
+			}
+			text := sqlValueText(v)
+			converted, convErr := coerceValue(strings.TrimSpace(text), declTypes[col], emptyAsNull)
+			// 数字列中混有少量以 0 开头的编码（如 00123）时，转换为数字会丢失前导零，同样按转换错误记录
+			if convErr == nil && declTypes[col] == typedDeclTypes["number"] && isCodeText(strings.TrimSpace(text)) {
+				converted, convErr = nil, fmt.Errorf("以 0 开头的编码，转换为数字会丢失前导零")
+			}
+			if convErr != nil {
+				errorCount++
+				if _, err := errorStmt.Exec(typedTable, raw[0], col, text, declTypes[col], convErr.Error()); err != nil {
+					return "", nil, 0, 0, fmt.Errorf("记录转换错误失败: %v", err)
+				}
+			}
+			values[i+1] = converted
+		}
+		if _, err := insertStmt.Exec(values...); err != nil {
+			return "", nil, 0, 0, fmt.Errorf("写入表 %s 失败: %v", typedTable, err)
+		}
+		rowCount++
+	}
+	if err := rows.Err(); err != nil {
+		return "", nil, 0, 0, fmt.Errorf("读取表 %s 失败: %v", rawTable, err)
+	}
+	rows.Close()
+
+	typesJSON, _ := json.Marshal(declTypes)
+	if _, err := tx.Exec(`INSERT INTO _app_typed_layers (typed_table, raw_table, column_types, row_count, error_count, built_at)
+		VALUES (?, ?, ?, ?, ?, ?) ON CONFLICT(typed_table) DO UPDATE SET raw_table = excluded.raw_table, column_types = excluded.column_types,
+		row_count = excluded.row_count, error_count = excluded.error_count, built_at = excluded.built_at`,
+		typedTable, rawTable, string(typesJSON), rowCount, errorCount, time.Now().Format("2006-01-02 15:04:05")); err != nil {
+		return "", nil, 0, 0, fmt.Errorf("记录类型化表失败: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return "", nil, 0, 0, fmt.Errorf("提交事务失败: %v", err)
+	}
+	return typedTable, declTypes, rowCount, errorCount, nil
+}
+
+// BuildTypedLayer 为原始 TEXT 表生成类型化表 <表名>_typed（开启 typed_layer 设置时导入和刷新后自动生成）：
+// 原始表保持原样，类型化表的数字、日期列可直接计算和比较，_raw_rowid 列指向原始表的 rowid；
+// 无法转换的值写为 NULL，可用 GetTypeConversionErrors 查看其原始单元格文本
+// wails:export BuildTypedLayer
+func (a *App) BuildTypedLayer(tableName string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	if a.readOnly() {
+		result["error"] = readOnlyMessage
+		return result
+	}
+	typedTable, declTypes, rowCount, errorCount, err := a.buildTypedLayer(tableName)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	result["typedTable"] = typedTable
+	result["columnTypes"] = declTypes
+	result["rowCount"] = rowCount
+	result["errorCount"] = errorCount
+	message := fmt.Sprintf("已生成类型化表 %s（%d 行）", typedTable, rowCount)
+	if errorCount > 0 {
+		message += fmt.Sprintf("，%d 个值无法转换已写为 NULL，可查看转换错误追溯原始文本", errorCount)
+	}
+	result["message"] = message
+	return result
+}
+
+// GetTypeConversionErrors 查看类型化表的转换错误（tableName 可以是原始表或类型化表）：
+// 每条包含 rawRowid（原始表 rowid）、column、rawValue（原始单元格文本）、targetType 和 error
+// wails:export GetTypeConversionErrors
+func (a *App) GetTypeConversionErrors(tableName string, limit int) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	var typedTable, rawTable, builtAt string
+	var rowCount, errorCount int
+	err := a.readDB().QueryRow(`SELECT typed_table, raw_table, row_count, error_count, built_at FROM _app_typed_layers
+		WHERE typed_table = ? OR raw_table = ?`, tableName, tableName).Scan(&typedTable, &rawTable, &rowCount, &errorCount, &builtAt)
+	if err == sql.ErrNoRows {
+		result["error"] = fmt.Sprintf("表 %s 还没有生成类型化表", tableName)
+		return result
+	}
+	if err != nil {
+		result["error"] = fmt.Sprintf("读取类型化表记录失败: %v", err)
+		return result
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+	rows, err := a.readDB().Query(`SELECT raw_rowid, column_name, raw_value, target_type, error FROM _app_type_errors
+		WHERE typed_table = ? ORDER BY raw_rowid, id LIMIT ?`, typedTable, limit)
+	if err != nil {
+		result["error"] = fmt.Sprintf("读取转换错误失败: %v", err)
+		return result
+	}
+	defer rows.Close()
+	errorsList := []map[string]interface{}{}
+	for rows.Next() {
+		var rowid int64
+		var column, rawValue, targetType, message string
+		if err := rows.Scan(&rowid, &column, &rawValue, &targetType, &message); err != nil {
+			result["error"] = fmt.Sprintf("读取转换错误失败: %v", err)
+			return result
+		}
+		errorsList = append(errorsList, map[string]interface{}{
+			"rawRowid":   rowid,
+			"column":     column,
+			"rawValue":   rawValue,
+			"targetType": targetType,
+			"error":      message,
+		})
+	}
+	result["typedTable"] = typedTable
+	result["rawTable"] = rawTable
+	result["rowCount"] = rowCount
+	result["errorCount"] = errorCount
+	result["builtAt"] = builtAt
+	result["errors"] = errorsList
+	// 按 _raw_rowid 关联原始表，并排查看原始文本与转换结果
+	result["lineageSQL"] = fmt.Sprintf("SELECT r.*, t.* FROM %s r JOIN %s t ON t.%s = r.rowid",
+		quoteIdent(rawTable), quoteIdent(typedTable), quoteIdent(typedRawRowIDColumn))
+	return result
+}
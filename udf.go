@@ -0,0 +1,21 @@
+package main
+
+import "strconv"
+
+// udfText 将自定义 SQL 函数的参数转为文本，参数为 NULL 时返回 false
+func udfText(v interface{}) (string, bool) {
+	switch x := v.(type) {
+	case string:
+		return x, true
+	case []byte:
+		if x == nil {
+			return "", false
+		}
+		return string(x), true
+	case int64:
+		return strconv.FormatInt(x, 10), true
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64), true
+	}
+	return "", false
+}
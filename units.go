@@ -0,0 +1,108 @@
+package main
+
+import "strings"
+
+// unitDef 计量单位：所属量纲及换算到该量纲基准单位（米、千克、升、平方米、秒）的系数
+type unitDef struct {
+	dimension string
+	factor    float64
+}
+
+// units 支持的计量单位，键为小写的单位名（含中文名称和常见写法）
+var units = map[string]unitDef{
+	// 长度，基准为米
+	"mm": {"length", 0.001}, "毫米": {"length", 0.001},
+	"cm": {"length", 0.01}, "厘米": {"length", 0.01},
+	"dm": {"length", 0.1}, "分米": {"length", 0.1},
+	"m": {"length", 1}, "米": {"length", 1},
+	"km": {"length", 1000}, "千米": {"length", 1000}, "公里": {"length", 1000},
+	"in": {"length", 0.0254}, "inch": {"length", 0.0254}, "英寸": {"length", 0.0254},
+	"ft": {"length", 0.3048}, "英尺": {"length", 0.3048},
+	"yd": {"length", 0.9144}, "码": {"length", 0.9144},
+	"mi": {"length", 1609.344}, "英里": {"length", 1609.344},
+	"寸": {"length", 1.0 / 30}, "尺": {"length", 1.0 / 3}, "丈": {"length", 10.0 / 3}, "里": {"length", 500},
+	// 质量，基准为千克
+	"mg": {"mass", 1e-6}, "毫克": {"mass", 1e-6},
+	"g": {"mass", 0.001}, "克": {"mass", 0.001},
+	"kg": {"mass", 1}, "千克": {"mass", 1}, "公斤": {"mass", 1},
+	"t": {"mass", 1000}, "吨": {"mass", 1000},
+	"两": {"mass", 0.05}, "斤": {"mass", 0.5},
+	"lb": {"mass", 0.45359237}, "磅": {"mass", 0.45359237},
+	"oz": {"mass", 0.028349523125}, "盎司": {"mass", 0.028349523125},
+	// 体积，基准为升
+	"ml": {"volume", 0.001}, "毫升": {"volume", 0.001},
+	"l": {"volume", 1}, "升": {"volume", 1},
+	"m3": {"volume", 1000}, "立方米": {"volume", 1000}, "方": {"volume", 1000},
+	"gal": {"volume", 3.785411784}, "加仑": {"volume", 3.785411784},
+	// 面积，基准为平方米
+	"m2": {"area", 1}, "平方米": {"area", 1}, "平米": {"area", 1},
+	"km2": {"area", 1e6}, "平方公里": {"area", 1e6}, "平方千米": {"area", 1e6},
+	"ha": {"area", 10000}, "公顷": {"area", 10000},
+	"亩":    {"area", 10000.0 / 15},
+	"sqft": {"area", 0.09290304}, "平方英尺": {"area", 0.09290304},
+	// 时间，基准为秒
+	"s": {"time", 1}, "秒": {"time", 1},
+	"min": {"time", 60}, "分钟": {"time", 60},
+	"h": {"time", 3600}, "小时": {"time", 3600},
+	"d": {"time", 86400}, "day": {"time", 86400}, "天": {"time", 86400},
+}
+
+// convertTemperature 温度换算（摄氏度 C、华氏度 F、开尔文 K），单位不是温度时返回 false
+func convertTemperature(v float64, from, to string) (float64, bool) {
+	scale := func(u string) string {
+		switch u {
+		case "c", "℃", "摄氏度":
+			return "c"
+		case "f", "℉", "华氏度":
+			return "f"
+		case "k", "开尔文":
+			return "k"
+		}
+		return ""
+	}
+	f, t := scale(from), scale(to)
+	if f == "" || t == "" {
+		return 0, false
+	}
+	switch f {
+	case "f":
+		v = (v - 32) * 5 / 9
+	case "k":
+		v -= 273.15
+	}
+	switch t {
+	case "f":
+		v = v*9/5 + 32
+	case "k":
+		v += 273.15
+	}
+	return v, true
+}
+
+// convertUnitFunc SQL 函数 CONVERT_UNIT(value, from, to)：同一量纲内换算计量单位，如 CONVERT_UNIT(3, '斤', 'kg')
+// 值无法解析、单位未知或量纲不一致时返回 NULL
+func convertUnitFunc(value, from, to interface{}) interface{} {
+	text, ok := udfText(value)
+	if !ok {
+		return nil
+	}
+	v, ok := parseNumberText(text)
+	if !ok {
+		return nil
+	}
+	fromText, ok1 := udfText(from)
+	toText, ok2 := udfText(to)
+	if !ok1 || !ok2 {
+		return nil
+	}
+	fromKey, toKey := strings.TrimSpace(foldKey(fromText)), strings.TrimSpace(foldKey(toText))
+	if r, ok := convertTemperature(v, fromKey, toKey); ok {
+		return r
+	}
+	f, ok1 := units[fromKey]
+	t, ok2 := units[toKey]
+	if !ok1 || !ok2 || f.dimension != t.dimension {
+		return nil
+	}
+	return v * f.factor / t.factor
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// validationSheetName 保存下拉选项的隐藏工作表（选项较多、超出 Excel 内联列表的 255 字符上限时使用）
+const validationSheetName = "Lists"
+
+// maxValidationValues 一列下拉列表最多的选项数，超过时不适合做成下拉列表
+const maxValidationValues = 500
+
+// columnValidation 导出模板中一列的下拉列表：values 为空时取该列现有的不同取值
+type columnValidation struct {
+	column     string
+	values     []string
+	allowBlank bool
+}
+
+// parseValidations 解析前端传来的 [{column, values, allowBlank}]，allowBlank 缺省为 true
+func parseValidations(specs []map[string]interface{}) ([]columnValidation, error) {
+	var vs []columnValidation
+	for _, spec := range specs {
+		v := columnValidation{allowBlank: true}
+		v.column, _ = spec["column"].(string)
+		if v.column == "" {
+			return nil, fmt.Errorf("下拉列表缺少列名")
+		}
+		if list, ok := spec["values"].([]interface{}); ok {
+			for _, item := range list {
+				if text := strings.TrimSpace(fmt.Sprint(item)); text != "" && !containsString(v.values, text) {
+					v.values = append(v.values, text)
+				}
+			}
+		}
+		if allow, ok := spec["allowBlank"].(bool); ok {
+			v.allowBlank = allow
+		}
+		vs = append(vs, v)
+	}
+	return vs, nil
+}
+
+// fillValidationValues 为未指定选项的列取查询结果中现有的不同取值
+func (a *App) fillValidationValues(sqlStr string, columns []string, vs []columnValidation) error {
+	for i := range vs {
+		v := &vs[i]
+		if !containsString(columns, v.column) {
+			return fmt.Errorf("下拉列表的列 %s 不在结果中", v.column)
+		}
+		if len(v.values) > 0 {
+			continue
+		}
+		col := quoteIdent(v.column)
+		rows, err := a.readDB().Query(fmt.Sprintf("SELECT DISTINCT %s FROM (\n%s\n) WHERE %s IS NOT NULL AND TRIM(%s) <> '' ORDER BY 1 LIMIT %d",
+			col, sqlStr, col, col, maxValidationValues+1))
+		if err != nil {
+			return fmt.Errorf("读取列 %s 的取值失败: %v", v.column, err)
+		}
+		for rows.Next() {
+			var value interface{}
+			if rows.Scan(&value) == nil {
+				v.values = append(v.values, sqlValueText(value))
+			}
+		}
+		rows.Close()
+		if len(v.values) == 0 {
+			return fmt.Errorf("列 %s 没有可用作下拉选项的取值，请直接指定选项", v.column)
+		}
+	}
+	for _, v := range vs {
+		if len(v.values) > maxValidationValues {
+			return fmt.Errorf("列 %s 的取值超过 %d 个，不适合做成下拉列表", v.column, maxValidationValues)
+		}
+	}
+	return nil
+}
+
+// applyValidations 为导出工作表的对应列（从第 2 行到工作表末尾，含用于填写的空行）添加下拉列表，
+// 输入列表之外的值时拒绝；选项超出内联上限时写入隐藏的 Lists 工作表并引用其区域
+func applyValidations(f *excelize.File, sheet string, columns []string, vs []columnValidation) error {
+	listCol := 0
+	for _, v := range vs {
+		idx := -1
+		for i, col := range columns {
+			if col == v.column {
+				idx = i
+			}
+		}
+		if idx < 0 {
+			continue
+		}
+		name, _ := excelize.ColumnNumberToName(idx + 1)
+		dv := excelize.NewDataValidation(v.allowBlank)
+		dv.SetSqref(fmt.Sprintf("%s2:%s%d", name, name, excelMaxRows))
+		dv.SetError(excelize.DataValidationErrorStyleStop, "输入无效", fmt.Sprintf("请从下拉列表中选择 %s 的取值", v.column))
+		if err := dv.SetDropList(v.values); err != nil {
+			// 内联列表超出长度上限，改为引用隐藏工作表中的一列
+			if listCol == 0 {
+				if _, err := f.NewSheet(validationSheetName); err != nil {
+					return err
+				}
+				if err := f.SetSheetVisible(validationSheetName, false); err != nil {
+					return err
+				}
+			}
+			listCol++
+			listName, _ := excelize.ColumnNumberToName(listCol)
+			for i, value := range v.values {
+				f.SetCellStr(validationSheetName, fmt.Sprintf("%s%d", listName, i+1), value)
+			}
+			dv.SetSqrefDropList(fmt.Sprintf("%s!$%s$1:$%s$%d", validationSheetName, listName, listName, len(v.values)))
+		}
+		if err := f.AddDataValidation(sheet, dv); err != nil {
+			return fmt.Errorf("添加列 %s 的下拉列表失败: %v", v.column, err)
+		}
+	}
+	return nil
+}
+
+// templateOptions 解析填写模板的选项：validations 为各列的下拉列表，lockedColumns 和 password 为工作表保护（见 ExportFillTemplate）
+func (a *App) templateOptions(sqlStr string, columns []string, options map[string]interface{}) (exportOptions, error) {
+	var opts exportOptions
+	specs, _ := options["validations"].([]interface{})
+	validationSpecs := make([]map[string]interface{}, 0, len(specs))
+	for _, spec := range specs {
+		if m, ok := spec.(map[string]interface{}); ok {
+			validationSpecs = append(validationSpecs, m)
+		}
+	}
+	var err error
+	if opts.validations, err = parseValidations(validationSpecs); err != nil {
+		return opts, err
+	}
+	if err := a.fillValidationValues(sqlStr, columns, opts.validations); err != nil {
+		return opts, err
+	}
+	if locked, _ := options["lockedColumns"].([]interface{}); len(locked) > 0 {
+		opts.protect = &sheetProtection{}
+		opts.protect.password, _ = options["password"].(string)
+		for _, item := range locked {
+			col, _ := item.(string)
+			if !containsString(columns, col) {
+				return opts, fmt.Errorf("锁定的列 %v 不在结果中", item)
+			}
+			opts.protect.locked = append(opts.protect.locked, col)
+		}
+	}
+	return opts, nil
+}
+
+// ExportFillTemplate 导出供他人填写的模板：在 ExportExcelBySQL 的基础上，按 options.validations
+// [{column, values, allowBlank}] 为列添加下拉列表（values 为空时取该列现有的不同取值），
+// 填写的值限制在列表内，收回后重新导入时不会混入拼写不一致的取值；
+// options.lockedColumns 不为空时保护工作表，这些列（如主键、公式）只读，其余列可以编辑，options.password 为取消保护的密码（可为空）；
+// 返回值同 ExportExcelBySQL
+// wails:export ExportFillTemplate
+func (a *App) ExportFillTemplate(sqlStr string, options map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+	stmt := parseStatement(strings.TrimSpace(sqlStr))
+	if stmt.text == "" {
+		result["error"] = "错误：SQL 语句不能为空！"
+		return result
+	}
+	if err := stmt.wrappable(); err != nil {
+		result["error"] = fmt.Sprintf("导出模板需要单条查询语句: %v", err)
+		return result
+	}
+	rows, err := a.readDB().Query(fmt.Sprintf("SELECT * FROM (\n%s\n) LIMIT 0", a.liveSQL(stmt.text)))
+	if err != nil {
+		result["error"] = fmt.Sprintf("SQL 执行失败: %v", err)
+		return result
+	}
+	columns, _ := rows.Columns()
+	rows.Close()
+
+	opts, err := a.templateOptions(stmt.text, columns, options)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	return a.startExportJob(stmt.text, "填写模板", opts)
+}
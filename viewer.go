@@ -0,0 +1,285 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// viewerManifestTable 只读分享包中记录清单的表，只存在于导出的分享包中
+const viewerManifestTable = "_app_viewer_manifest"
+
+// viewerManifest 只读分享包的清单：包含的表和精选查询
+type viewerManifest struct {
+	Title     string   `json:"title"`
+	CreatedAt string   `json:"createdAt"`
+	Tables    []string `json:"tables"`
+	Queries   []string `json:"queries"`
+	ReadOnly  bool     `json:"readOnly"`
+}
+
+// viewerSession 打开的只读分享包；关闭时恢复原来的数据库连接和只读状态
+type viewerSession struct {
+	path     string
+	manifest viewerManifest
+	previous *dbHandles // 打开分享包前的连接和只读状态，分享包打开期间保持不关闭
+}
+
+// ExportViewerPackage 将所选表和精选的已保存查询打包为一个只读分享包（.db）：
+// 包内只保留所选表及其元数据和所选查询，并附带清单；接收者用 OpenViewerPackage 打开后可以浏览数据、运行精选查询，但不能修改数据
+// savePath 为空时弹出保存对话框
+// wails:export ExportViewerPackage
+func (a *App) ExportViewerPackage(savePath string, title string, tables []string, queries []string) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	if a.viewer() != nil {
+		return "当前正在查看只读分享包，请先关闭分享包"
+	}
+	if len(tables) == 0 {
+		return "请选择要打包的表"
+	}
+	for _, t := range tables {
+		if _, err := a.tableColumns(t); err != nil {
+			return err.Error()
+		}
+	}
+	for _, name := range queries {
+		if _, err := a.loadSavedQuery(name); err != nil {
+			return err.Error()
+		}
+	}
+
+	if savePath == "" {
+		var err error
+		savePath, err = runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+			Title:           "导出只读分享包",
+			DefaultFilename: "只读分享.db",
+			Filters:         []runtime.FileFilter{{Pattern: "*.db;*.sqlite", DisplayName: "SQLite 数据库"}},
+		})
+		if err != nil {
+			return fmt.Sprintf("文件保存失败: %v", err)
+		}
+		if savePath == "" {
+			return "取消导出"
+		}
+	}
+	if err := a.checkBackupTarget(savePath); err != nil {
+		return err.Error()
+	}
+	if err := checkDiskSpace(filepath.Dir(savePath), databaseFileSize()); err != nil {
+		return err.Error()
+	}
+	// 覆盖之前导出的只读文件时先恢复写权限
+	os.Chmod(savePath, 0o644)
+
+	manifest := viewerManifest{
+		Title:     strings.TrimSpace(title),
+		CreatedAt: time.Now().Format("2006-01-02 15:04:05"),
+		Tables:    tables,
+		Queries:   append([]string{}, queries...),
+		ReadOnly:  true,
+	}
+	if manifest.Title == "" {
+		manifest.Title = strings.TrimSuffix(filepath.Base(savePath), filepath.Ext(savePath))
+	}
+	op := a.beginOperation("", "export", "导出只读分享包 "+manifest.Title)
+	if err := a.buildViewerPackage(savePath, manifest); err != nil {
+		op.finish(err.Error(), err)
+		return err.Error()
+	}
+	message := fmt.Sprintf("只读分享包导出成功: %s（%d 张表、%d 个查询）", savePath, len(tables), len(queries))
+	op.finish(message, nil)
+	return message
+}
+
+// buildViewerPackage 复制数据库并在临时文件上裁剪到所选表、写回所选查询和清单，完成后替换目标文件并设为只读
+func (a *App) buildViewerPackage(path string, manifest viewerManifest) error {
+	if err := a.backupDatabase(path, func(tmpPath string) error {
+		return a.fillViewerPackage(tmpPath, manifest)
+	}); err != nil {
+		return err
+	}
+	if err := os.Chmod(path, 0o444); err != nil {
+		fmt.Printf("设置分享包只读属性失败: %v\n", err)
+	}
+	return nil
+}
+
+// fillViewerPackage 把数据库副本裁剪到所选表，写回所选查询和清单
+func (a *App) fillViewerPackage(path string, manifest viewerManifest) error {
+	// 裁剪会清空已保存的查询，之后再写回所选的查询
+	if err := pruneDatabaseCopy(path, manifest.Tables); err != nil {
+		return err
+	}
+	db, err := sql.Open(scratchDriverName, path)
+	if err != nil {
+		return fmt.Errorf("打开分享包失败: %v", err)
+	}
+	defer db.Close()
+	// 只读打开时不会生成 -wal / -shm 文件，分享包保持为单个文件
+	if _, err := db.Exec("PRAGMA journal_mode = DELETE"); err != nil {
+		return fmt.Errorf("设置分享包日志模式失败: %v", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("开启事务失败: %v", err)
+	}
+	for _, name := range manifest.Queries {
+		var sqlStr, rules, layout, formats, updatedAt string
+		if err := a.readDB().QueryRow("SELECT sql, rules, layout, formats, updated_at FROM _app_saved_queries WHERE name = ?", name).
+			Scan(&sqlStr, &rules, &layout, &formats, &updatedAt); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("已保存的查询 %s 不存在", name)
+		}
+		if _, err := tx.Exec("INSERT INTO _app_saved_queries (name, sql, rules, layout, formats, updated_at) VALUES (?, ?, ?, ?, ?, ?)",
+			name, sqlStr, rules, layout, formats, updatedAt); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("写入查询 %s 失败: %v", name, err)
+		}
+		// 查询引用了未打包的表时在分享包中无法运行
+		if err := prepareTx(tx, sqlStr); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("查询 %s 在分享包中无法运行（是否引用了未打包的表？）: %v", name, err)
+		}
+	}
+	data, _ := json.Marshal(manifest)
+	if _, err := tx.Exec(fmt.Sprintf("CREATE TABLE %s (manifest TEXT NOT NULL)", viewerManifestTable)); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("写入分享包清单失败: %v", err)
+	}
+	if _, err := tx.Exec(fmt.Sprintf("INSERT INTO %s (manifest) VALUES (?)", viewerManifestTable), string(data)); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("写入分享包清单失败: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("提交事务失败: %v", err)
+	}
+	return nil
+}
+
+// prepareTx 在事务中编译语句但不执行
+func prepareTx(tx *sql.Tx, sqlStr string) error {
+	stmt, err := tx.Prepare(sqlStr)
+	if err != nil {
+		return err
+	}
+	return stmt.Close()
+}
+
+// openViewerDatabase 以只读、不可变方式打开分享包并读取清单
+func openViewerDatabase(path string) (*sql.DB, viewerManifest, error) {
+	var manifest viewerManifest
+	if _, err := os.Stat(path); err != nil {
+		return nil, manifest, fmt.Errorf("分享包 %s 不存在", path)
+	}
+	dsn := "file:" + filepath.ToSlash(path) + "?mode=ro&immutable=1&_query_only=true"
+	db, err := sql.Open(sqliteDriverName, dsn)
+	if err != nil {
+		return nil, manifest, fmt.Errorf("打开分享包失败: %v", err)
+	}
+	var data string
+	if err := db.QueryRow(fmt.Sprintf("SELECT manifest FROM %s LIMIT 1", viewerManifestTable)).Scan(&data); err != nil {
+		db.Close()
+		return nil, manifest, fmt.Errorf("%s 不是只读分享包", filepath.Base(path))
+	}
+	if err := json.Unmarshal([]byte(data), &manifest); err != nil {
+		db.Close()
+		return nil, manifest, fmt.Errorf("分享包清单已损坏: %v", err)
+	}
+	return db, manifest, nil
+}
+
+// OpenViewerPackage 打开只读分享包：之后的浏览、查询和导出都针对分享包进行，所有修改被拒绝，
+// 关闭分享包（CloseViewerPackage）后回到 data.db；path 为空时弹出文件选择对话框
+// 返回 {path, title, createdAt, tables, queries, message}
+// wails:export OpenViewerPackage
+func (a *App) OpenViewerPackage(path string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.viewer() != nil {
+		result["error"] = "已打开只读分享包，请先关闭当前分享包"
+		return result
+	}
+	if path == "" {
+		var err error
+		path, err = runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+			Title:   "打开只读分享包",
+			Filters: []runtime.FileFilter{{Pattern: "*.db;*.sqlite", DisplayName: "SQLite 数据库"}},
+		})
+		if err != nil {
+			result["error"] = fmt.Sprintf("文件选择失败: %v", err)
+			return result
+		}
+		if path == "" {
+			result["error"] = "取消打开"
+			return result
+		}
+	}
+	db, manifest, err := openViewerDatabase(path)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+
+	a.switchMu.Lock()
+	defer a.switchMu.Unlock()
+	if a.viewer() != nil {
+		db.Close()
+		result["error"] = "已打开只读分享包，请先关闭当前分享包"
+		return result
+	}
+	// 连接、只读状态和分享包一起切换，并发的调用不会看到分享包连接配上可写状态
+	session := &viewerSession{path: path, manifest: manifest}
+	session.previous = a.swapHandles(&dbHandles{db: db, readOnly: true, viewer: session})
+	a.cache.clear()
+	a.stats.clear()
+	result = a.viewerInfo(session)
+	result["message"] = fmt.Sprintf("已打开只读分享包「%s」：%d 张表、%d 个查询，不能修改数据", manifest.Title, len(manifest.Tables), len(manifest.Queries))
+	return result
+}
+
+// CloseViewerPackage 关闭只读分享包，回到 data.db；分享包上仍在进行的查询和导出结束后才关闭分享包文件
+// wails:export CloseViewerPackage
+func (a *App) CloseViewerPackage() string {
+	a.switchMu.Lock()
+	defer a.switchMu.Unlock()
+	v := a.viewer()
+	if v == nil {
+		return "当前没有打开只读分享包"
+	}
+	packageHandles := a.swapHandles(v.previous)
+	a.cache.clear()
+	a.stats.clear()
+	retireHandles(packageHandles)
+	return fmt.Sprintf("已关闭只读分享包「%s」", v.manifest.Title)
+}
+
+// GetViewerPackage 当前打开的只读分享包 {open, path, title, createdAt, tables, queries}，未打开时 open 为 false
+// wails:export GetViewerPackage
+func (a *App) GetViewerPackage() map[string]interface{} {
+	v := a.viewer()
+	if v == nil {
+		return map[string]interface{}{"open": false}
+	}
+	return a.viewerInfo(v)
+}
+
+// viewerInfo 打开的分享包及其清单
+func (a *App) viewerInfo(v *viewerSession) map[string]interface{} {
+	m := v.manifest
+	return map[string]interface{}{
+		"open":      true,
+		"path":      v.path,
+		"title":     m.Title,
+		"createdAt": m.CreatedAt,
+		"tables":    append([]string{}, m.Tables...),
+		"queries":   append([]string{}, m.Queries...),
+	}
+}
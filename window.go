@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CumulativeSum 累计求和：按 orderColumn 排序逐行累加 valueColumn，结果增加一列 <valueColumn>_累计
+// partitionColumn 不为空时按该列分组分别累计（如按科目、按物料的流水账余额）
+// wails:export CumulativeSum
+func (a *App) CumulativeSum(tableName string, orderColumn string, valueColumn string, partitionColumn string) map[string]interface{} {
+	result := make(map[string]interface{})
+	if a.writeDB() == nil {
+		result["error"] = "错误：数据库连接未初始化，请重启应用！"
+		return result
+	}
+
+	columns, err := a.tableColumns(tableName)
+	if err != nil {
+		result["error"] = err.Error()
+		return result
+	}
+	check := []string{orderColumn, valueColumn}
+	if partitionColumn != "" {
+		check = append(check, partitionColumn)
+	}
+	for _, col := range check {
+		if !containsString(columns, col) {
+			result["error"] = fmt.Sprintf("表 %s 中不存在列 %s", tableName, col)
+			return result
+		}
+	}
+
+	from, columns := a.liveSource(tableName, columns)
+	totalName := valueColumn + "_累计"
+	for i := 2; containsString(columns, totalName); i++ {
+		totalName = fmt.Sprintf("%s_累计%d", valueColumn, i)
+	}
+
+	over := "ORDER BY " + quoteIdent(orderColumn)
+	orderBy := quoteIdent(orderColumn)
+	if partitionColumn != "" {
+		over = "PARTITION BY " + quoteIdent(partitionColumn) + " " + over
+		orderBy = quoteIdent(partitionColumn) + ", " + orderBy
+	}
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = quoteIdent(col)
+	}
+	sqlText := fmt.Sprintf("SELECT %s,\n       SUM(%s) OVER (%s ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) AS %s\nFROM %s\nORDER BY %s",
+		strings.Join(quoted, ", "), quoteIdent(valueColumn), over, quoteIdent(totalName), from, orderBy)
+
+	pageSize := a.session.currentPageSize()
+	if pageSize <= 0 {
+		pageSize = a.settingInt("page_size")
+	}
+	result = a.ExecuteSQLWithPage(sqlText, 1, pageSize)
+	if _, failed := result["error"]; failed {
+		return result
+	}
+	result["sql"] = sqlText
+	result["totalColumn"] = totalName
+	return result
+}
@@ -0,0 +1,377 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// workspaceConfigVersion 工作区配置文件格式版本
+const workspaceConfigVersion = 1
+
+// workspaceConfig 工作区配置文件（JSON）：设置、已保存的查询、数据字典、节假日和汇率，不含业务数据
+type workspaceConfig struct {
+	Version       int                 `json:"version"`
+	ExportedAt    string              `json:"exportedAt"`
+	Settings      map[string]string   `json:"settings"`
+	SavedQueries  []workspaceQuery    `json:"savedQueries"`
+	Dictionary    []workspaceDictItem `json:"dictionary"`
+	Holidays      []workspaceHoliday  `json:"holidays"`
+	ExchangeRates []workspaceRate     `json:"exchangeRates"`
+}
+
+type workspaceQuery struct {
+	Name   string                   `json:"name"`
+	SQL    string                   `json:"sql"`
+	Rules  []map[string]interface{} `json:"rules"`
+	Layout []map[string]interface{} `json:"layout"`
+	// Formats 列格式，旧版本导出的配置中没有此项
+	Formats []map[string]interface{} `json:"formats,omitempty"`
+}
+
+type workspaceDictItem struct {
+	Table       string `json:"table"`
+	Column      string `json:"column"`
+	Label       string `json:"label"`
+	Description string `json:"description"`
+}
+
+type workspaceHoliday struct {
+	Date      string `json:"date"`
+	Name      string `json:"name"`
+	IsWorkday bool   `json:"isWorkday"`
+}
+
+type workspaceRate struct {
+	Currency string  `json:"currency"`
+	Date     string  `json:"date"`
+	Rate     float64 `json:"rate"`
+}
+
+// ExportWorkspaceConfig 将工作区配置导出为 JSON 文件，便于团队共享统一的分析环境
+// 包括修改过的设置（不含密码等敏感设置）、已保存的查询（含高亮规则和列设置）、数据字典、节假日和汇率
+// wails:export ExportWorkspaceConfig
+func (a *App) ExportWorkspaceConfig() string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	cfg, err := a.workspaceConfig()
+	if err != nil {
+		return fmt.Sprintf("读取工作区配置失败: %v", err)
+	}
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("生成配置文件失败: %v", err)
+	}
+
+	savePath, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "导出工作区配置",
+		DefaultFilename: "工作区配置.json",
+		Filters:         []runtime.FileFilter{{Pattern: "*.json", DisplayName: "JSON 文件"}},
+	})
+	if err != nil {
+		return fmt.Sprintf("文件保存失败: %v", err)
+	}
+	if savePath == "" {
+		return "取消导出"
+	}
+	if err := os.WriteFile(savePath, data, 0644); err != nil {
+		return fmt.Sprintf("写入配置文件失败: %v", err)
+	}
+	return fmt.Sprintf("工作区配置已导出: %s（%d 项设置，%d 个查询，%d 条字典，%d 个节假日，%d 条汇率）",
+		savePath, len(cfg.Settings), len(cfg.SavedQueries), len(cfg.Dictionary), len(cfg.Holidays), len(cfg.ExchangeRates))
+}
+
+// workspaceConfig 读取当前工作区配置
+func (a *App) workspaceConfig() (workspaceConfig, error) {
+	cfg := workspaceConfig{
+		Version:       workspaceConfigVersion,
+		ExportedAt:    time.Now().Format("2006-01-02 15:04:05"),
+		Settings:      make(map[string]string),
+		SavedQueries:  []workspaceQuery{},
+		Dictionary:    []workspaceDictItem{},
+		Holidays:      []workspaceHoliday{},
+		ExchangeRates: []workspaceRate{},
+	}
+
+	rows, err := a.readDB().Query("SELECT key, value FROM _app_settings ORDER BY key")
+	if err != nil {
+		return cfg, err
+	}
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			rows.Close()
+			return cfg, err
+		}
+		if def, ok := settingDefinitions[key]; ok && !def.secret {
+			cfg.Settings[key] = value
+		}
+	}
+	rows.Close()
+
+	var names []string
+	rows, err = a.readDB().Query("SELECT name FROM _app_saved_queries ORDER BY name")
+	if err != nil {
+		return cfg, err
+	}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return cfg, err
+		}
+		names = append(names, name)
+	}
+	rows.Close()
+	for _, name := range names {
+		q, err := a.loadSavedQuery(name)
+		if err != nil {
+			return cfg, err
+		}
+		rules := make([]map[string]interface{}, len(q.rules))
+		for i, r := range q.rules {
+			rules[i] = r.toMap()
+		}
+		cfg.SavedQueries = append(cfg.SavedQueries, workspaceQuery{Name: name, SQL: q.sql, Rules: rules, Layout: q.layoutMaps(), Formats: columnFormatMaps(q.formats)})
+	}
+
+	rows, err = a.readDB().Query("SELECT table_name, column_name, label, description FROM _app_dictionary ORDER BY table_name, column_name")
+	if err != nil {
+		return cfg, err
+	}
+	for rows.Next() {
+		var d workspaceDictItem
+		if err := rows.Scan(&d.Table, &d.Column, &d.Label, &d.Description); err != nil {
+			rows.Close()
+			return cfg, err
+		}
+		cfg.Dictionary = append(cfg.Dictionary, d)
+	}
+	rows.Close()
+
+	rows, err = a.readDB().Query("SELECT date, name, is_workday FROM _app_holidays ORDER BY date")
+	if err != nil {
+		return cfg, err
+	}
+	for rows.Next() {
+		var h workspaceHoliday
+		if err := rows.Scan(&h.Date, &h.Name, &h.IsWorkday); err != nil {
+			rows.Close()
+			return cfg, err
+		}
+		cfg.Holidays = append(cfg.Holidays, h)
+	}
+	rows.Close()
+
+	rows, err = a.readDB().Query("SELECT currency, date, rate FROM _app_exchange_rates ORDER BY currency, date")
+	if err != nil {
+		return cfg, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var r workspaceRate
+		if err := rows.Scan(&r.Currency, &r.Date, &r.Rate); err != nil {
+			return cfg, err
+		}
+		cfg.ExchangeRates = append(cfg.ExchangeRates, r)
+	}
+	return cfg, rows.Err()
+}
+
+// ImportWorkspaceConfig 导入 ExportWorkspaceConfig 生成的配置文件（filePath 为空时弹出选择框）
+// 同名的设置、查询、字典条目、节假日和汇率会被覆盖，其余保留；全部校验通过后在一个事务中写入
+// wails:export ImportWorkspaceConfig
+func (a *App) ImportWorkspaceConfig(filePath string) string {
+	if a.writeDB() == nil {
+		return "错误：数据库连接未初始化，请重启应用！"
+	}
+	if a.readOnly() {
+		return readOnlyMessage
+	}
+	filePath, err := a.chooseFile(filePath, "选择工作区配置文件", "*.json", "JSON 文件")
+	if err != nil {
+		return fmt.Sprintf("文件选择失败: %v", err)
+	}
+	if filePath == "" {
+		return "未选择文件"
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Sprintf("读取配置文件失败: %v", err)
+	}
+	var cfg workspaceConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return fmt.Sprintf("配置文件格式错误: %v", err)
+	}
+	if cfg.Version > workspaceConfigVersion {
+		return fmt.Sprintf("配置文件版本 %d 高于当前支持的版本 %d，请升级应用", cfg.Version, workspaceConfigVersion)
+	}
+
+	var skipped []string
+	keys := make([]string, 0, len(cfg.Settings))
+	for key := range cfg.Settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	settings := make(map[string]string, len(keys))
+	for _, key := range keys {
+		value := cfg.Settings[key]
+		def, ok := settingDefinitions[key]
+		switch {
+		case !ok:
+			skipped = append(skipped, fmt.Sprintf("未知的设置项 %s", key))
+		case def.secret:
+			skipped = append(skipped, fmt.Sprintf("敏感设置 %s", key))
+		case def.validate != nil && def.validate(value) != nil:
+			skipped = append(skipped, fmt.Sprintf("设置 %s 的取值 %s 无效", key, value))
+		default:
+			if def.check != nil {
+				if err := def.check(a, value); err != nil {
+					skipped = append(skipped, fmt.Sprintf("设置 %s 无法修改: %v", key, err))
+					continue
+				}
+			}
+			settings[key] = value
+		}
+	}
+	// 配置文件中的汇率以导出时的基准币种计价，导入后的基准币种不同（如 base_currency 因已有汇率被跳过）时不能写入
+	if len(cfg.ExchangeRates) > 0 {
+		configBase, ok := cfg.Settings["base_currency"]
+		if !ok {
+			configBase = settingDefinitions["base_currency"].defaultValue
+		}
+		base, ok := settings["base_currency"]
+		if !ok {
+			exchangeRates.RLock()
+			base = exchangeRates.base
+			exchangeRates.RUnlock()
+		}
+		if !strings.EqualFold(configBase, base) {
+			return fmt.Sprintf("导入失败：配置文件中的汇率以 %s 为基准，与导入后的基准币种 %s 不同，请先统一基准币种", strings.ToUpper(configBase), base)
+		}
+	}
+
+	// 查询、规则和列设置先全部校验，任何一项有误都不写入
+	queries := make([]workspaceQueryRow, 0, len(cfg.SavedQueries))
+	for _, q := range cfg.SavedQueries {
+		name := strings.TrimSpace(q.Name)
+		stmt := parseStatement(q.SQL)
+		if name == "" || !savableQuery(stmt) {
+			return fmt.Sprintf("导入失败：查询 %q 的名称或 SQL 无效", q.Name)
+		}
+		rules, err := parseHighlightRules(q.Rules)
+		if err != nil {
+			return fmt.Sprintf("导入失败：查询 %s 的高亮规则无效: %v", name, err)
+		}
+		layout, err := parseColumnLayout(q.Layout)
+		if err != nil {
+			return fmt.Sprintf("导入失败：查询 %s 的列设置无效: %v", name, err)
+		}
+		formats, err := parseColumnFormats(q.Formats)
+		if err != nil {
+			return fmt.Sprintf("导入失败：查询 %s 的列格式无效: %v", name, err)
+		}
+		saved := savedQuery{rules: rules, layout: layout}
+		ruleSpecs := make([]map[string]interface{}, len(rules))
+		for i, r := range rules {
+			ruleSpecs[i] = r.toMap()
+		}
+		rulesJSON, _ := json.Marshal(ruleSpecs)
+		layoutJSON, _ := json.Marshal(saved.layoutMaps())
+		formatsJSON, _ := json.Marshal(columnFormatMaps(formats))
+		queries = append(queries, workspaceQueryRow{name, stmt.text, string(rulesJSON), string(layoutJSON), string(formatsJSON)})
+	}
+	for _, h := range cfg.Holidays {
+		if _, err := time.Parse("2006-01-02", h.Date); err != nil {
+			return fmt.Sprintf("导入失败：节假日日期 %s 格式错误（应为 YYYY-MM-DD）", h.Date)
+		}
+	}
+	for _, r := range cfg.ExchangeRates {
+		if err := validateCurrencyCode(r.Currency); err != nil {
+			return fmt.Sprintf("导入失败：%v", err)
+		}
+		if _, err := time.Parse("2006-01-02", r.Date); err != nil || r.Rate <= 0 {
+			return fmt.Sprintf("导入失败：%s 的汇率 %s=%v 无效", r.Currency, r.Date, r.Rate)
+		}
+	}
+
+	tx, err := a.writeDB().Begin()
+	if err != nil {
+		return fmt.Sprintf("开启事务失败: %v", err)
+	}
+	if err := importWorkspace(tx, settings, queries, cfg); err != nil {
+		tx.Rollback()
+		return fmt.Sprintf("导入失败: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Sprintf("提交事务失败: %v", err)
+	}
+
+	for key, value := range settings {
+		if apply := settingDefinitions[key].apply; apply != nil {
+			apply(value)
+		}
+	}
+	if err := a.loadExchangeRates(); err != nil {
+		fmt.Println(err)
+	}
+	a.cache.clear()
+
+	message := fmt.Sprintf("已导入工作区配置：%d 项设置，%d 个查询，%d 条字典，%d 个节假日，%d 条汇率",
+		len(settings), len(queries), len(cfg.Dictionary), len(cfg.Holidays), len(cfg.ExchangeRates))
+	if len(skipped) > 0 {
+		message += "\n已跳过：" + strings.Join(skipped, "；")
+	}
+	return message
+}
+
+// workspaceQueryRow 校验并规范化后待写入 _app_saved_queries 的查询
+type workspaceQueryRow struct{ name, sql, rules, layout, formats string }
+
+// importWorkspace 在事务中写入设置、已保存的查询、数据字典、节假日和汇率
+func importWorkspace(tx *sql.Tx, settings map[string]string, queries []workspaceQueryRow, cfg workspaceConfig) error {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	for _, q := range queries {
+		if _, err := tx.Exec(`INSERT INTO _app_saved_queries (name, sql, rules, layout, formats, updated_at) VALUES (?, ?, ?, ?, ?, ?)
+			ON CONFLICT(name) DO UPDATE SET sql = excluded.sql, rules = excluded.rules, layout = excluded.layout,
+				formats = excluded.formats, updated_at = excluded.updated_at`,
+			q.name, q.sql, q.rules, q.layout, q.formats, now); err != nil {
+			return err
+		}
+	}
+	for key, value := range settings {
+		if _, err := tx.Exec("INSERT INTO _app_settings (key, value) VALUES (?, ?) ON CONFLICT(key) DO UPDATE SET value = excluded.value",
+			key, value); err != nil {
+			return err
+		}
+	}
+	for _, d := range cfg.Dictionary {
+		if _, err := tx.Exec(`INSERT INTO _app_dictionary (table_name, column_name, label, description) VALUES (?, ?, ?, ?)
+			ON CONFLICT(table_name, column_name) DO UPDATE SET label = excluded.label, description = excluded.description`,
+			d.Table, d.Column, d.Label, d.Description); err != nil {
+			return err
+		}
+	}
+	for _, h := range cfg.Holidays {
+		if _, err := tx.Exec(`INSERT INTO _app_holidays (date, name, is_workday) VALUES (?, ?, ?)
+			ON CONFLICT(date) DO UPDATE SET name = excluded.name, is_workday = excluded.is_workday`,
+			h.Date, h.Name, h.IsWorkday); err != nil {
+			return err
+		}
+	}
+	for _, r := range cfg.ExchangeRates {
+		if _, err := tx.Exec(`INSERT INTO _app_exchange_rates (currency, date, rate) VALUES (?, ?, ?)
+			ON CONFLICT(currency, date) DO UPDATE SET rate = excluded.rate`,
+			r.Currency, r.Date, r.Rate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
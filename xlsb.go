@@ -0,0 +1,337 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// .xlsb（Excel 二进制工作簿）读取器：只解析单元格取值，不解析样式，日期按序列号数值读出
+
+// BIFF12 记录类型
+const (
+	xlsbRowHdr      = 0x00
+	xlsbCellBlank   = 0x01
+	xlsbCellRk      = 0x02
+	xlsbCellError   = 0x03
+	xlsbCellBool    = 0x04
+	xlsbCellReal    = 0x05
+	xlsbCellSt      = 0x06
+	xlsbCellIsst    = 0x07
+	xlsbFmlaString  = 0x08
+	xlsbFmlaNum     = 0x09
+	xlsbFmlaBool    = 0x0A
+	xlsbFmlaError   = 0x0B
+	xlsbSSTItem     = 0x13
+	xlsbBundleSheet = 0x9C
+)
+
+// xlsbSheet 读取出的一个工作表
+type xlsbSheet struct {
+	name string
+	rows [][]string
+}
+
+// importXLSB 将 .xlsb 工作簿的每个 Sheet 导入为 sheet1..N 表，规则与 xlsx 导入一致
+func (a *App) importXLSB(filePath string) (string, error) {
+	sheets, err := readXLSB(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	successCount := 0
+	for sheetIdx, sheet := range sheets {
+		if len(sheet.rows) == 0 {
+			continue
+		}
+		tableName := fmt.Sprintf("sheet%d", sheetIdx+1)
+		columns, dataRows := defaultColumnNames(len(sheet.rows[0])), sheet.rows[1:]
+		if a.importSourceColumns() {
+			columns, dataRows = appendSourceColumns(columns, dataRows, filePath, sheet.name, 2)
+		}
+		if err := a.writeTable(tableName, columns, dataRows); err != nil {
+			return "", err
+		}
+		a.recordTableSource(tableName, filePath+"/"+sheet.name)
+		a.recordTableHeader(tableName, positionalHeader(sheet.rows[0]))
+		successCount++
+	}
+
+	return fmt.Sprintf("成功导入 %d 个 Sheet 到数据库（共 %d 个 Sheet）", successCount, len(sheets)), nil
+}
+
+// xlsbRecord 一条 BIFF12 记录
+type xlsbRecord struct {
+	typ  int
+	data []byte
+}
+
+// readXLSBRecords 逐条读取 BIFF12 记录：类型和长度均为变长整数（每字节 7 位，高位为续位）
+func readXLSBRecords(r io.Reader, fn func(rec xlsbRecord) error) error {
+	br := &byteReader{r: r}
+	for {
+		typ, err := br.varint(2)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		size, err := br.varint(4)
+		if err != nil {
+			return err
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(br.r, data); err != nil {
+			return err
+		}
+		if err := fn(xlsbRecord{typ: typ, data: data}); err != nil {
+			return err
+		}
+	}
+}
+
+type byteReader struct {
+	r   io.Reader
+	buf [1]byte
+}
+
+func (b *byteReader) varint(maxBytes int) (int, error) {
+	v := 0
+	for i := 0; i < maxBytes; i++ {
+		if _, err := io.ReadFull(b.r, b.buf[:]); err != nil {
+			if i > 0 && err == io.EOF {
+				return 0, io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+		v |= int(b.buf[0]&0x7F) << (7 * i)
+		if b.buf[0]&0x80 == 0 {
+			break
+		}
+	}
+	return v, nil
+}
+
+// xlsbWideString 读取 XLWideString（4 字节字符数 + UTF-16LE），返回字符串和占用的字节数
+func xlsbWideString(data []byte) (string, int, bool) {
+	if len(data) < 4 {
+		return "", 0, false
+	}
+	n := binary.LittleEndian.Uint32(data)
+	if n == 0xFFFFFFFF {
+		return "", 4, true
+	}
+	end := 4 + int(n)*2
+	if end > len(data) {
+		return "", 0, false
+	}
+	units := make([]uint16, n)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(data[4+i*2:])
+	}
+	return string(utf16.Decode(units)), end, true
+}
+
+// xlsbRK 解码 RK 数值：bit0 表示除以 100，bit1 表示 30 位整数，否则为 float64 的高 30 位
+func xlsbRK(rk uint32) float64 {
+	var v float64
+	if rk&0x02 != 0 {
+		v = float64(int32(rk) >> 2)
+	} else {
+		v = math.Float64frombits(uint64(rk&0xFFFFFFFC) << 32)
+	}
+	if rk&0x01 != 0 {
+		v /= 100
+	}
+	return v
+}
+
+func formatXLSBNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+var xlsbErrors = map[byte]string{
+	0x00: "#NULL!", 0x07: "#DIV/0!", 0x0F: "#VALUE!", 0x17: "#REF!",
+	0x1D: "#NAME?", 0x24: "#NUM!", 0x2A: "#N/A", 0x2B: "#GETTING_DATA",
+}
+
+// readXLSB 读取 .xlsb 文件中全部工作表的单元格文本
+func readXLSB(filePath string) ([]xlsbSheet, error) {
+	zr, err := zip.OpenReader(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("xlsb 解析失败: %v", err)
+	}
+	defer zr.Close()
+
+	files := make(map[string]*zip.File)
+	for _, zf := range zr.File {
+		files[strings.ToLower(zf.Name)] = zf
+	}
+	open := func(name string) (io.ReadCloser, error) {
+		zf, ok := files[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("xlsb 解析失败: 缺少 %s", name)
+		}
+		return zf.Open()
+	}
+
+	// 工作簿关系：rId -> 工作表路径
+	rels := make(map[string]string)
+	if rc, err := open("xl/_rels/workbook.bin.rels"); err == nil {
+		var doc struct {
+			Relationships []struct {
+				ID     string `xml:"Id,attr"`
+				Target string `xml:"Target,attr"`
+			} `xml:"Relationship"`
+		}
+		err = xml.NewDecoder(rc).Decode(&doc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("xlsb 解析失败: %v", err)
+		}
+		for _, rel := range doc.Relationships {
+			target := rel.Target
+			if strings.HasPrefix(target, "/") {
+				target = strings.TrimPrefix(target, "/")
+			} else {
+				target = path.Join("xl", target)
+			}
+			rels[rel.ID] = target
+		}
+	}
+
+	// 工作表列表
+	type sheetRef struct{ name, target string }
+	var sheetRefs []sheetRef
+	rc, err := open("xl/workbook.bin")
+	if err != nil {
+		return nil, err
+	}
+	err = readXLSBRecords(rc, func(rec xlsbRecord) error {
+		if rec.typ != xlsbBundleSheet || len(rec.data) < 8 {
+			return nil
+		}
+		relID, n, ok := xlsbWideString(rec.data[8:])
+		if !ok {
+			return nil
+		}
+		name, _, ok := xlsbWideString(rec.data[8+n:])
+		if !ok {
+			return nil
+		}
+		sheetRefs = append(sheetRefs, sheetRef{name: name, target: rels[relID]})
+		return nil
+	})
+	rc.Close()
+	if err != nil {
+		return nil, fmt.Errorf("xlsb 解析失败: %v", err)
+	}
+
+	// 共享字符串表
+	var sst []string
+	if rc, err := open("xl/sharedStrings.bin"); err == nil {
+		err = readXLSBRecords(rc, func(rec xlsbRecord) error {
+			if rec.typ == xlsbSSTItem && len(rec.data) > 1 {
+				s, _, _ := xlsbWideString(rec.data[1:])
+				sst = append(sst, s)
+			}
+			return nil
+		})
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("xlsb 解析失败: %v", err)
+		}
+	}
+
+	var sheets []xlsbSheet
+	for _, ref := range sheetRefs {
+		if ref.target == "" {
+			continue
+		}
+		rc, err := open(ref.target)
+		if err != nil {
+			// 图表页等非工作表没有对应的 .bin 数据
+			continue
+		}
+		rows, err := readXLSBSheet(rc, sst)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("读取 Sheet %s 失败: %v", ref.name, err)
+		}
+		sheets = append(sheets, xlsbSheet{name: ref.name, rows: rows})
+	}
+	return sheets, nil
+}
+
+// readXLSBSheet 读取单个工作表，返回与 excelize GetRows 一致的二维文本（去除行尾空单元格）
+func readXLSBSheet(r io.Reader, sst []string) ([][]string, error) {
+	var rows [][]string
+	current := -1
+	err := readXLSBRecords(r, func(rec xlsbRecord) error {
+		if rec.typ == xlsbRowHdr {
+			if len(rec.data) >= 4 {
+				current = int(binary.LittleEndian.Uint32(rec.data))
+			}
+			return nil
+		}
+		if rec.typ < xlsbCellBlank || rec.typ > xlsbFmlaError || current < 0 || len(rec.data) < 8 {
+			return nil
+		}
+
+		col := int(binary.LittleEndian.Uint32(rec.data))
+		value := rec.data[8:]
+		var text string
+		switch rec.typ {
+		case xlsbCellBlank:
+			return nil
+		case xlsbCellRk:
+			if len(value) >= 4 {
+				text = formatXLSBNumber(xlsbRK(binary.LittleEndian.Uint32(value)))
+			}
+		case xlsbCellReal, xlsbFmlaNum:
+			if len(value) >= 8 {
+				text = formatXLSBNumber(math.Float64frombits(binary.LittleEndian.Uint64(value)))
+			}
+		case xlsbCellBool, xlsbFmlaBool:
+			if len(value) >= 1 {
+				text = "FALSE"
+				if value[0] != 0 {
+					text = "TRUE"
+				}
+			}
+		case xlsbCellError, xlsbFmlaError:
+			if len(value) >= 1 {
+				text = xlsbErrors[value[0]]
+			}
+		case xlsbCellSt, xlsbFmlaString:
+			text, _, _ = xlsbWideString(value)
+		case xlsbCellIsst:
+			if len(value) >= 4 {
+				if idx := int(binary.LittleEndian.Uint32(value)); idx < len(sst) {
+					text = sst[idx]
+				}
+			}
+		}
+		if text == "" {
+			return nil
+		}
+
+		for len(rows) <= current {
+			rows = append(rows, nil)
+		}
+		for len(rows[current]) <= col {
+			rows[current] = append(rows[current], "")
+		}
+		rows[current][col] = text
+		return nil
+	})
+	return rows, err
+}